@@ -307,6 +307,227 @@ unixcomm_recv(struct unixcomm *hndl, uint8_t *buffer, size_t buflen,
 	return ret;
 }
 
+struct drpc_frame_hdr {
+	uint32_t	id; /** ID of the message the frame belongs to */
+	uint32_t	total; /** total length of the message */
+	uint32_t	offset; /** offset of the frame's data in the message */
+};
+
+static void
+put_le32(uint8_t *buf, uint32_t val)
+{
+	buf[0] = val & 0xff;
+	buf[1] = (val >> 8) & 0xff;
+	buf[2] = (val >> 16) & 0xff;
+	buf[3] = (val >> 24) & 0xff;
+}
+
+static uint32_t
+get_le32(const uint8_t *buf)
+{
+	return (uint32_t)buf[0] | ((uint32_t)buf[1] << 8) |
+	       ((uint32_t)buf[2] << 16) | ((uint32_t)buf[3] << 24);
+}
+
+static void
+frame_hdr_pack(struct drpc_frame_hdr *hdr, uint8_t *packet)
+{
+	packet[0] = DRPC_FRAME_MAGIC;
+	packet[1] = DRPC_FRAME_VERSION;
+	packet[2] = 0;
+	packet[3] = 0;
+	put_le32(&packet[4], hdr->id);
+	put_le32(&packet[8], hdr->total);
+	put_le32(&packet[12], hdr->offset);
+}
+
+static bool
+is_frame(uint8_t *packet, size_t packet_len)
+{
+	return packet_len > 0 && packet[0] == DRPC_FRAME_MAGIC;
+}
+
+static int
+frame_hdr_unpack(uint8_t *packet, size_t packet_len,
+		 struct drpc_frame_hdr *hdr)
+{
+	if (packet_len < DRPC_FRAME_HDR_SIZE) {
+		D_ERROR("dRPC frame too short (%zu bytes)\n", packet_len);
+		return -DER_PROTO;
+	}
+
+	if (packet[1] != DRPC_FRAME_VERSION) {
+		D_ERROR("Unsupported dRPC frame version %u\n", packet[1]);
+		return -DER_PROTO;
+	}
+
+	hdr->id = get_le32(&packet[4]);
+	hdr->total = get_le32(&packet[8]);
+	hdr->offset = get_le32(&packet[12]);
+	return 0;
+}
+
+/*
+ * Send a message on the dRPC connection, splitting it into frames if it
+ * doesn't fit in a single packet.
+ */
+static int
+drpc_send_msg(struct drpc *ctx, uint8_t *msg, size_t msg_len)
+{
+	struct drpc_frame_hdr	hdr;
+	uint8_t			*packet;
+	size_t			data_len;
+	int			rc = 0;
+
+	if (msg_len <= UNIXCOMM_MAXMSGSIZE)
+		return unixcomm_send(ctx->comm, msg, msg_len, NULL);
+
+	if (msg_len > UINT32_MAX) {
+		D_ERROR("dRPC message too large (%zu bytes)\n", msg_len);
+		return -DER_INVAL;
+	}
+
+	D_ALLOC(packet, UNIXCOMM_MAXMSGSIZE);
+	if (packet == NULL)
+		return -DER_NOMEM;
+
+	hdr.id = ++ctx->last_msg_id;
+	hdr.total = msg_len;
+	for (hdr.offset = 0; hdr.offset < msg_len; hdr.offset += data_len) {
+		data_len = min(msg_len - hdr.offset, DRPC_FRAME_DATA_SIZE);
+
+		frame_hdr_pack(&hdr, packet);
+		memcpy(packet + DRPC_FRAME_HDR_SIZE, msg + hdr.offset,
+		       data_len);
+		rc = unixcomm_send(ctx->comm, packet,
+				   DRPC_FRAME_HDR_SIZE + data_len, NULL);
+		if (rc != 0)
+			break;
+	}
+
+	D_FREE(packet);
+	return rc;
+}
+
+/*
+ * Append the data of a frame to the message being reassembled, growing the
+ * message buffer as needed rather than trusting the total length claimed by
+ * the sender up front.
+ */
+static int
+append_frame_data(uint8_t **buf, size_t *buf_size, size_t received,
+		  uint8_t *data, size_t data_len, size_t total)
+{
+	uint8_t	*new_buf;
+	size_t	new_size;
+
+	if (received + data_len > *buf_size) {
+		new_size = max(*buf_size * 2, received + data_len);
+		new_size = min(new_size, total);
+
+		D_REALLOC(new_buf, *buf, new_size);
+		if (new_buf == NULL)
+			return -DER_NOMEM;
+		*buf = new_buf;
+		*buf_size = new_size;
+	}
+
+	memcpy(*buf + received, data, data_len);
+	return 0;
+}
+
+/*
+ * Receive a message on the dRPC connection, reassembling it if it was split
+ * into frames. A peer sends one message at a time on a connection, so the
+ * frames of a message must arrive one after the other.
+ *
+ * On success the caller is responsible for freeing the message.
+ */
+static int
+drpc_recv_msg(struct drpc *ctx, uint8_t **msg, size_t *msg_len)
+{
+	struct drpc_frame_hdr	first;
+	struct drpc_frame_hdr	hdr;
+	uint8_t			*packet;
+	uint8_t			*buf = NULL;
+	size_t			buf_size = 0;
+	size_t			received = 0;
+	size_t			data_len;
+	ssize_t			packet_len = 0;
+	int			rc;
+
+	D_ALLOC(packet, UNIXCOMM_MAXMSGSIZE);
+	if (packet == NULL)
+		return -DER_NOMEM;
+
+	rc = unixcomm_recv(ctx->comm, packet, UNIXCOMM_MAXMSGSIZE, &packet_len);
+	if (rc != 0)
+		D_GOTO(out, rc);
+
+	if (!is_frame(packet, packet_len)) {
+		*msg = packet;
+		*msg_len = packet_len;
+		return 0;
+	}
+
+	rc = frame_hdr_unpack(packet, packet_len, &first);
+	if (rc != 0)
+		D_GOTO(out, rc);
+
+	if (first.total > DRPC_MAX_MSG_SIZE) {
+		D_ERROR("dRPC message size %u exceeds limit %d\n",
+			first.total, DRPC_MAX_MSG_SIZE);
+		D_GOTO(out, rc = -DER_PROTO);
+	}
+
+	hdr = first;
+	for (;;) {
+		data_len = packet_len - DRPC_FRAME_HDR_SIZE;
+		if (hdr.id != first.id || hdr.total != first.total ||
+		    hdr.offset != received || data_len == 0 ||
+		    received + data_len > first.total) {
+			D_ERROR("dRPC frame for message %u out of sequence "
+				"(message %u offset %u, %zu bytes, %zu of %u "
+				"received)\n", first.id, hdr.id, hdr.offset,
+				data_len, received, first.total);
+			D_GOTO(out, rc = -DER_PROTO);
+		}
+
+		rc = append_frame_data(&buf, &buf_size, received,
+				       packet + DRPC_FRAME_HDR_SIZE, data_len,
+				       first.total);
+		if (rc != 0)
+			D_GOTO(out, rc);
+		received += data_len;
+
+		if (received == first.total)
+			break;
+
+		rc = unixcomm_recv(ctx->comm, packet, UNIXCOMM_MAXMSGSIZE,
+				   &packet_len);
+		if (rc != 0)
+			D_GOTO(out, rc);
+
+		if (!is_frame(packet, packet_len)) {
+			D_ERROR("Expected frame for dRPC message %u\n",
+				first.id);
+			D_GOTO(out, rc = -DER_PROTO);
+		}
+
+		rc = frame_hdr_unpack(packet, packet_len, &hdr);
+		if (rc != 0)
+			D_GOTO(out, rc);
+	}
+
+	*msg = buf;
+	*msg_len = received;
+	buf = NULL;
+out:
+	D_FREE(buf);
+	D_FREE(packet);
+	return rc;
+}
+
 static int
 drpc_marshal_call(Drpc__Call *msg, uint8_t **bytes)
 {
@@ -357,8 +578,7 @@ drpc_call(struct drpc *ctx, int flags, Drpc__Call *msg,
 	uint8_t		*messagePb;
 	uint8_t		*responseBuf;
 	int		pbLen;
-	ssize_t		sent;
-	ssize_t		recv = 0;
+	size_t		recv = 0;
 	int		ret;
 
 	msg->sequence = ctx->sequence++;
@@ -366,7 +586,7 @@ drpc_call(struct drpc *ctx, int flags, Drpc__Call *msg,
 	if (pbLen < 0)
 		return pbLen;
 
-	ret = unixcomm_send(ctx->comm, messagePb, pbLen, &sent);
+	ret = drpc_send_msg(ctx, messagePb, pbLen);
 	D_FREE(messagePb);
 
 	if (ret < 0)
@@ -379,15 +599,9 @@ drpc_call(struct drpc *ctx, int flags, Drpc__Call *msg,
 		return 0;
 	}
 
-	D_ALLOC(responseBuf, UNIXCOMM_MAXMSGSIZE);
-	if (!responseBuf)
-		return -DER_NOMEM;
-
-	ret = unixcomm_recv(ctx->comm, responseBuf, UNIXCOMM_MAXMSGSIZE, &recv);
-	if (ret < 0) {
-		D_FREE(responseBuf);
+	ret = drpc_recv_msg(ctx, &responseBuf, &recv);
+	if (ret < 0)
 		return ret;
-	}
 	response = drpc_unmarshal_response(responseBuf, recv);
 	D_FREE(responseBuf);
 
@@ -401,6 +615,7 @@ init_drpc_ctx(struct drpc *ctx, struct unixcomm *comm, drpc_handler_t handler)
 	ctx->comm = comm;
 	ctx->handler = handler;
 	ctx->sequence = 0;
+	ctx->last_msg_id = 0;
 	ctx->ref_count = 1;
 }
 
@@ -532,7 +747,7 @@ send_response(struct drpc *ctx, Drpc__Response *response)
 		return -DER_NOMEM;
 
 	drpc__response__pack(response, buffer);
-	rc = unixcomm_send(ctx->comm, buffer, buffer_len, NULL);
+	rc = drpc_send_msg(ctx, buffer, buffer_len);
 
 	D_FREE(buffer);
 	return rc;
@@ -543,19 +758,11 @@ get_incoming_call(struct drpc *ctx, Drpc__Call **call)
 {
 	int		rc;
 	uint8_t		*buffer;
-	size_t		buffer_size = UNIXCOMM_MAXMSGSIZE;
-	ssize_t		message_len = 0;
+	size_t		message_len = 0;
 
-	D_ALLOC(buffer, buffer_size);
-	if (buffer == NULL)
-		return -DER_NOMEM;
-
-	rc = unixcomm_recv(ctx->comm, buffer, buffer_size,
-				&message_len);
-	if (rc != DER_SUCCESS) {
-		D_FREE(buffer);
+	rc = drpc_recv_msg(ctx, &buffer, &message_len);
+	if (rc != DER_SUCCESS)
 		return rc;
-	}
 
 	*call = drpc__call__unpack(NULL, message_len, buffer);
 	D_FREE(buffer);
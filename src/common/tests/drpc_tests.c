@@ -334,6 +334,159 @@ test_drpc_call_with_sync_flag_fails_on_recvmsg_fail(void **state)
 	free_drpc(ctx);
 }
 
+/*
+ * dRPC framing unit tests
+ */
+static uint32_t
+get_le32(uint8_t *buf)
+{
+	return (uint32_t)buf[0] | ((uint32_t)buf[1] << 8) |
+	       ((uint32_t)buf[2] << 16) | ((uint32_t)buf[3] << 24);
+}
+
+static void
+put_le32(uint8_t *buf, uint32_t val)
+{
+	buf[0] = val & 0xff;
+	buf[1] = (val >> 8) & 0xff;
+	buf[2] = (val >> 16) & 0xff;
+	buf[3] = (val >> 24) & 0xff;
+}
+
+/* Mock a frame coming in as packet i from recvmsg */
+static void
+mock_frame_in_recvmsg(int i, uint32_t id, uint32_t total, uint32_t offset,
+		      uint8_t *data, size_t data_len)
+{
+	uint8_t *packet = recvmsg_packets[i];
+
+	packet[0] = DRPC_FRAME_MAGIC;
+	packet[1] = DRPC_FRAME_VERSION;
+	put_le32(&packet[4], id);
+	put_le32(&packet[8], total);
+	put_le32(&packet[12], offset);
+	memcpy(&packet[DRPC_FRAME_HDR_SIZE], data, data_len);
+	recvmsg_packet_lens[i] = DRPC_FRAME_HDR_SIZE + data_len;
+	if (recvmsg_packet_count < i + 1)
+		recvmsg_packet_count = i + 1;
+}
+
+/* Mock a message split into frames coming in from recvmsg */
+static int
+mock_framed_msg_in_recvmsg(uint8_t *msg, size_t msg_len)
+{
+	size_t	offset;
+	size_t	data_len;
+	int	i = 0;
+
+	for (offset = 0; offset < msg_len; offset += data_len) {
+		data_len = min(msg_len - offset, DRPC_FRAME_DATA_SIZE);
+		mock_frame_in_recvmsg(i++, 1, msg_len, offset, msg + offset,
+				      data_len);
+	}
+
+	return i;
+}
+
+static void
+assert_sent_as_frames(uint8_t *msg, size_t msg_len)
+{
+	size_t	offset;
+	size_t	data_len;
+	int	i = 0;
+
+	for (offset = 0; offset < msg_len; offset += data_len) {
+		uint8_t *packet = sendmsg_packets[i];
+
+		data_len = min(msg_len - offset, DRPC_FRAME_DATA_SIZE);
+		assert_int_equal(sendmsg_packet_lens[i],
+				 DRPC_FRAME_HDR_SIZE + data_len);
+		assert_int_equal(packet[0], DRPC_FRAME_MAGIC);
+		assert_int_equal(packet[1], DRPC_FRAME_VERSION);
+		assert_int_equal(get_le32(&packet[4]), 1);
+		assert_int_equal(get_le32(&packet[8]), msg_len);
+		assert_int_equal(get_le32(&packet[12]), offset);
+		assert_memory_equal(&packet[DRPC_FRAME_HDR_SIZE],
+				    msg + offset, data_len);
+		i++;
+	}
+
+	assert_int_equal(sendmsg_call_count, i);
+}
+
+static Drpc__Call *
+new_large_drpc_call(void)
+{
+	Drpc__Call *call = new_drpc_call();
+
+	/* too large for a single packet */
+	call->body.len = 2 * UNIXCOMM_MAXMSGSIZE;
+	D_ALLOC(call->body.data, call->body.len);
+	memset(call->body.data, 'c', call->body.len);
+
+	return call;
+}
+
+static void
+test_drpc_call_sends_large_call_as_frames(void **state)
+{
+	struct drpc	*ctx = new_drpc_with_fd(3);
+	Drpc__Response	*resp = NULL;
+	Drpc__Call	*call = new_large_drpc_call();
+	size_t		expected_msg_size;
+	uint8_t		*expected_msg;
+
+	assert_int_equal(drpc_call(ctx, 0, call, &resp), DER_SUCCESS);
+
+	expected_msg_size = drpc__call__get_packed_size(call);
+	D_ALLOC(expected_msg, expected_msg_size);
+	drpc__call__pack(call, expected_msg);
+
+	assert_sent_as_frames(expected_msg, expected_msg_size);
+	assert_int_equal(ctx->last_msg_id, 1);
+
+	D_FREE(expected_msg);
+	drpc__response__free_unpacked(resp, NULL);
+	drpc__call__free_unpacked(call, NULL);
+	free_drpc(ctx);
+}
+
+static void
+test_drpc_call_with_sync_flag_gets_framed_response(void **state)
+{
+	struct drpc	*ctx = new_drpc_with_fd(1);
+	Drpc__Response	*resp = NULL;
+	Drpc__Call	*call = new_drpc_call();
+	Drpc__Response	*expected_resp = new_drpc_response();
+	size_t		msg_size;
+	uint8_t		*msg;
+	int		packets;
+
+	expected_resp->body.len = 2 * UNIXCOMM_MAXMSGSIZE;
+	D_ALLOC(expected_resp->body.data, expected_resp->body.len);
+	memset(expected_resp->body.data, 'r', expected_resp->body.len);
+
+	msg_size = drpc__response__get_packed_size(expected_resp);
+	D_ALLOC(msg, msg_size);
+	drpc__response__pack(expected_resp, msg);
+	packets = mock_framed_msg_in_recvmsg(msg, msg_size);
+
+	assert_int_equal(drpc_call(ctx, R_SYNC, call, &resp), DER_SUCCESS);
+
+	assert_int_equal(recvmsg_call_count, packets);
+	assert_non_null(resp);
+	assert_int_equal(resp->status, expected_resp->status);
+	assert_int_equal(resp->body.len, expected_resp->body.len);
+	assert_memory_equal(resp->body.data, expected_resp->body.data,
+			    expected_resp->body.len);
+
+	D_FREE(msg);
+	drpc__response__free_unpacked(resp, NULL);
+	drpc__response__free_unpacked(expected_resp, NULL);
+	drpc__call__free_unpacked(call, NULL);
+	free_drpc(ctx);
+}
+
 /*
  * drpc_listen unit tests
  */
@@ -614,6 +767,139 @@ test_drpc_recv_call_success(void **state)
 	drpc_call_free(expected_call);
 }
 
+static void
+test_drpc_recv_call_reassembles_frames(void **state)
+{
+	struct drpc	*ctx = new_drpc_with_fd(6);
+	Drpc__Call	*call = NULL;
+	Drpc__Call	*expected_call = new_large_drpc_call();
+	size_t		msg_size;
+	uint8_t		*msg;
+	int		packets;
+
+	msg_size = drpc__call__get_packed_size(expected_call);
+	D_ALLOC(msg, msg_size);
+	drpc__call__pack(expected_call, msg);
+	packets = mock_framed_msg_in_recvmsg(msg, msg_size);
+
+	assert_int_equal(drpc_recv_call(ctx, &call), 0);
+
+	assert_int_equal(recvmsg_call_count, packets);
+	assert_non_null(call);
+	assert_int_equal(call->module, expected_call->module);
+	assert_int_equal(call->method, expected_call->method);
+	assert_int_equal(call->body.len, expected_call->body.len);
+	assert_memory_equal(call->body.data, expected_call->body.data,
+			    expected_call->body.len);
+
+	D_FREE(msg);
+	free_drpc(ctx);
+	drpc_call_free(call);
+	drpc_call_free(expected_call);
+}
+
+static void
+assert_drpc_recv_call_fails_with_frames(void)
+{
+	struct drpc	*ctx = new_drpc_with_fd(6);
+	Drpc__Call	*call = NULL;
+
+	assert_int_equal(drpc_recv_call(ctx, &call), -DER_PROTO);
+	assert_null(call);
+
+	free_drpc(ctx);
+}
+
+static void
+test_drpc_recv_call_frame_out_of_sequence(void **state)
+{
+	uint8_t data[10];
+
+	memset(data, 1, sizeof(data));
+	mock_frame_in_recvmsg(0, 1, 30, 0, data, sizeof(data));
+	mock_frame_in_recvmsg(1, 1, 30, 20, data, sizeof(data));
+
+	assert_drpc_recv_call_fails_with_frames();
+	assert_int_equal(recvmsg_call_count, 2);
+}
+
+static void
+test_drpc_recv_call_frame_from_other_message(void **state)
+{
+	uint8_t data[10];
+
+	memset(data, 1, sizeof(data));
+	mock_frame_in_recvmsg(0, 1, 30, 0, data, sizeof(data));
+	mock_frame_in_recvmsg(1, 2, 30, 10, data, sizeof(data));
+
+	assert_drpc_recv_call_fails_with_frames();
+}
+
+static void
+test_drpc_recv_call_frame_past_end_of_message(void **state)
+{
+	uint8_t data[21];
+
+	memset(data, 1, sizeof(data));
+	mock_frame_in_recvmsg(0, 1, 30, 0, data, 10);
+	/* one byte more than the rest of the message */
+	mock_frame_in_recvmsg(1, 1, 30, 10, data, 21);
+
+	assert_drpc_recv_call_fails_with_frames();
+}
+
+static void
+test_drpc_recv_call_unframed_packet_in_message(void **state)
+{
+	uint8_t data[10];
+
+	memset(data, 1, sizeof(data));
+	mock_frame_in_recvmsg(0, 1, 30, 0, data, sizeof(data));
+	recvmsg_packets[1][0] = 0x0a; /* protobuf field 1 */
+	recvmsg_packet_lens[1] = 20;
+	recvmsg_packet_count = 2;
+
+	assert_drpc_recv_call_fails_with_frames();
+}
+
+static void
+test_drpc_recv_call_frame_too_short(void **state)
+{
+	uint8_t data[10];
+
+	memset(data, 1, sizeof(data));
+	mock_frame_in_recvmsg(0, 1, 30, 0, data, sizeof(data));
+	recvmsg_packet_lens[0] = DRPC_FRAME_HDR_SIZE - 1;
+
+	assert_drpc_recv_call_fails_with_frames();
+}
+
+static void
+test_drpc_recv_call_frame_bad_version(void **state)
+{
+	uint8_t data[10];
+
+	memset(data, 1, sizeof(data));
+	mock_frame_in_recvmsg(0, 1, 10, 0, data, sizeof(data));
+	recvmsg_packets[0][1] = DRPC_FRAME_VERSION + 1;
+
+	assert_drpc_recv_call_fails_with_frames();
+}
+
+static void
+test_drpc_recv_call_framed_message_too_large(void **state)
+{
+	uint8_t data[10];
+
+	memset(data, 1, sizeof(data));
+	mock_frame_in_recvmsg(0, 1, DRPC_MAX_MSG_SIZE + 1, 0, data,
+			      sizeof(data));
+
+	assert_drpc_recv_call_fails_with_frames();
+	/* gave up without waiting for the rest of the message */
+	assert_int_equal(recvmsg_call_count, 1);
+}
+
 /*
  * drpc_send_resp unit tests
  */
@@ -698,6 +984,31 @@ test_drpc_send_response_success(void **state)
 	drpc_response_free(resp);
 }
 
+static void
+test_drpc_send_response_large_sends_frames(void **state)
+{
+	struct drpc	*ctx = new_drpc_with_fd(6);
+	Drpc__Response	*resp = new_drpc_response();
+	size_t		expected_msg_size;
+	uint8_t		*expected_msg;
+
+	resp->body.len = 2 * UNIXCOMM_MAXMSGSIZE;
+	D_ALLOC(resp->body.data, resp->body.len);
+	memset(resp->body.data, 'r', resp->body.len);
+
+	assert_int_equal(drpc_send_response(ctx, resp), DER_SUCCESS);
+
+	expected_msg_size = drpc__response__get_packed_size(resp);
+	D_ALLOC(expected_msg, expected_msg_size);
+	drpc__response__pack(resp, expected_msg);
+
+	assert_sent_as_frames(expected_msg, expected_msg_size);
+
+	D_FREE(expected_msg);
+	free_drpc(ctx);
+	drpc_response_free(resp);
+}
+
 /*
  * drpc_call_create/free tests
  */
@@ -845,6 +1156,8 @@ main(void)
 		DRPC_UTEST(test_drpc_call_with_no_flags_returns_async),
 		DRPC_UTEST(test_drpc_call_with_sync_flag_gets_socket_response),
 		DRPC_UTEST(test_drpc_call_with_sync_flag_fails_on_recvmsg_fail),
+		DRPC_UTEST(test_drpc_call_sends_large_call_as_frames),
+		DRPC_UTEST(test_drpc_call_with_sync_flag_gets_framed_response),
 		DRPC_UTEST(test_drpc_listen_fails_with_null_path),
 		DRPC_UTEST(test_drpc_listen_fails_with_null_handler),
 		DRPC_UTEST(test_drpc_listen_success),
@@ -863,11 +1176,20 @@ main(void)
 		DRPC_UTEST(test_drpc_recv_call_recvmsg_would_block),
 		DRPC_UTEST(test_drpc_recv_call_malformed),
 		DRPC_UTEST(test_drpc_recv_call_success),
+		DRPC_UTEST(test_drpc_recv_call_reassembles_frames),
+		DRPC_UTEST(test_drpc_recv_call_frame_out_of_sequence),
+		DRPC_UTEST(test_drpc_recv_call_frame_from_other_message),
+		DRPC_UTEST(test_drpc_recv_call_frame_past_end_of_message),
+		DRPC_UTEST(test_drpc_recv_call_unframed_packet_in_message),
+		DRPC_UTEST(test_drpc_recv_call_frame_too_short),
+		DRPC_UTEST(test_drpc_recv_call_frame_bad_version),
+		DRPC_UTEST(test_drpc_recv_call_framed_message_too_large),
 		DRPC_UTEST(test_drpc_send_response_null_ctx),
 		DRPC_UTEST(test_drpc_send_response_bad_handler),
 		DRPC_UTEST(test_drpc_send_response_null_resp),
 		DRPC_UTEST(test_drpc_send_response_sendmsg_fails),
 		DRPC_UTEST(test_drpc_send_response_success),
+		DRPC_UTEST(test_drpc_send_response_large_sends_frames),
 		cmocka_unit_test(test_drpc_call_create_null_ctx),
 		cmocka_unit_test(test_drpc_call_create_free),
 		cmocka_unit_test(test_drpc_call_free_null),
@@ -211,6 +211,8 @@ mock_sendmsg_setup(void)
 	sendmsg_msg_iov_base_ptr = NULL;
 	sendmsg_msg_iov_len = 0;
 	memset(&sendmsg_msg_content, 0, sizeof(sendmsg_msg_content));
+	memset(sendmsg_packets, 0, sizeof(sendmsg_packets));
+	memset(sendmsg_packet_lens, 0, sizeof(sendmsg_packet_lens));
 	sendmsg_flags = 0;
 }
 
@@ -221,6 +223,9 @@ const struct msghdr *sendmsg_msg_ptr; /* saved address */
 void *sendmsg_msg_iov_base_ptr; /* saved ptr address */
 size_t sendmsg_msg_iov_len; /* saved iov len */
 uint8_t sendmsg_msg_content[UNIXCOMM_MAXMSGSIZE]; /* copied into iov */
+/* copies of the first MOCK_MAX_PACKETS packets sent */
+uint8_t sendmsg_packets[MOCK_MAX_PACKETS][UNIXCOMM_MAXMSGSIZE];
+size_t sendmsg_packet_lens[MOCK_MAX_PACKETS];
 int sendmsg_flags; /* saved input */
 ssize_t
 sendmsg(int sockfd, const struct msghdr *msg, int flags)
@@ -234,6 +239,14 @@ sendmsg(int sockfd, const struct msghdr *msg, int flags)
 				msg->msg_iov[0].iov_len);
 		sendmsg_msg_iov_base_ptr = msg->msg_iov[0].iov_base;
 		sendmsg_msg_iov_len = msg->msg_iov[0].iov_len;
+
+		if (sendmsg_call_count <= MOCK_MAX_PACKETS) {
+			memcpy(sendmsg_packets[sendmsg_call_count - 1],
+			       msg->msg_iov[0].iov_base,
+			       msg->msg_iov[0].iov_len);
+			sendmsg_packet_lens[sendmsg_call_count - 1] =
+				msg->msg_iov[0].iov_len;
+		}
 	}
 	sendmsg_flags = flags;
 	return sendmsg_return;
@@ -249,6 +262,9 @@ mock_recvmsg_setup(void)
 	recvmsg_msg_iov_base_ptr = NULL;
 	recvmsg_msg_iov_len = 0;
 	memset(recvmsg_msg_content, 0, sizeof(recvmsg_msg_content));
+	memset(recvmsg_packets, 0, sizeof(recvmsg_packets));
+	memset(recvmsg_packet_lens, 0, sizeof(recvmsg_packet_lens));
+	recvmsg_packet_count = 0;
 	recvmsg_flags = 0;
 }
 
@@ -259,6 +275,10 @@ struct msghdr *recvmsg_msg_ptr; /* saved ptr address */
 void *recvmsg_msg_iov_base_ptr; /* saved ptr address */
 size_t recvmsg_msg_iov_len; /* saved iov len */
 uint8_t recvmsg_msg_content[UNIXCOMM_MAXMSGSIZE]; /* copied into iov */
+/* if recvmsg_packet_count is set, packets returned one per call instead */
+uint8_t recvmsg_packets[MOCK_MAX_PACKETS][UNIXCOMM_MAXMSGSIZE];
+size_t recvmsg_packet_lens[MOCK_MAX_PACKETS];
+int recvmsg_packet_count;
 int recvmsg_flags; /* saved input */
 ssize_t
 recvmsg(int sockfd, struct msghdr *msg, int flags)
@@ -267,6 +287,20 @@ recvmsg(int sockfd, struct msghdr *msg, int flags)
 
 	recvmsg_sockfd = sockfd;
 	recvmsg_msg_ptr = msg;
+	recvmsg_flags = flags;
+	if (recvmsg_packet_count > 0 && msg != NULL) {
+		int i = recvmsg_call_count - 1;
+
+		recvmsg_msg_iov_base_ptr = msg->msg_iov[0].iov_base;
+		recvmsg_msg_iov_len = msg->msg_iov[0].iov_len;
+		if (i >= recvmsg_packet_count)
+			return 0; /* peer closed the connection */
+
+		memcpy(msg->msg_iov[0].iov_base, recvmsg_packets[i],
+		       recvmsg_packet_lens[i]);
+		return recvmsg_packet_lens[i];
+	}
+
 	if (msg != NULL) {
 		/*
 		 * Making an assumption that the size of the IOV
@@ -277,7 +311,6 @@ recvmsg(int sockfd, struct msghdr *msg, int flags)
 		recvmsg_msg_iov_base_ptr = msg->msg_iov[0].iov_base;
 		recvmsg_msg_iov_len = msg->msg_iov[0].iov_len;
 	}
-	recvmsg_flags = flags;
 	return recvmsg_return;
 }
 
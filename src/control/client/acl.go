@@ -0,0 +1,319 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+
+package client
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Special principal names, see DAOS_ACL_PRINCIPAL_* in daos_security.h.
+const (
+	PrincipalOwner      = "OWNER@"
+	PrincipalOwnerGroup = "GROUP@"
+	PrincipalEveryone   = "EVERYONE@"
+
+	// maxPrincipalLen mirrors DAOS_ACL_MAX_PRINCIPAL_LEN.
+	maxPrincipalLen = 255
+	aceFieldCount   = 4
+	aceFieldSep     = ":"
+)
+
+// Access types, flags and permissions in the order in which they are written
+// in the short string format by daos_ace_to_str().
+const (
+	aceAccessAllow = 'A'
+	aceAccessAudit = 'U'
+	aceAccessAlarm = 'L'
+
+	aceFlagGroup       = 'G'
+	aceFlagSuccess     = 'S'
+	aceFlagFail        = 'F'
+	aceFlagPoolInherit = 'P'
+
+	acePermRead  = 'r'
+	acePermWrite = 'w'
+)
+
+var (
+	aceAccessTypes = []rune{aceAccessAllow, aceAccessAudit, aceAccessAlarm}
+	aceFlags       = []rune{aceFlagGroup, aceFlagSuccess, aceFlagFail, aceFlagPoolInherit}
+	acePerms       = []rune{acePermRead, acePermWrite}
+)
+
+// AccessControlEntry is the parsed form of a single Access Control Entry in
+// the short string format "<access types>:<flags>:<principal>:<permissions>".
+type AccessControlEntry struct {
+	AccessTypes string
+	Flags       string
+	Principal   string
+	Perms       string
+}
+
+// normalizeACEField checks that every character in the field is one of the
+// allowed values and returns them in canonical order.
+func normalizeACEField(field, desc string, allowed []rune) (string, error) {
+	for _, ch := range field {
+		if !strings.ContainsRune(string(allowed), ch) {
+			return "", errors.Errorf("invalid %s %q (valid: %q)",
+				desc, ch, string(allowed))
+		}
+	}
+
+	var builder strings.Builder
+	for _, ch := range allowed {
+		if strings.ContainsRune(field, ch) {
+			builder.WriteRune(ch)
+		}
+	}
+
+	return builder.String(), nil
+}
+
+// isSpecialPrincipal checks whether the principal is one of the special
+// principals that don't refer to a named user or group.
+func isSpecialPrincipal(principal string) bool {
+	switch principal {
+	case PrincipalOwner, PrincipalOwnerGroup, PrincipalEveryone:
+		return true
+	}
+	return false
+}
+
+// ValidatePrincipal checks that the principal name is in the "name@domain"
+// format expected by the DAOS security library. The domain may be empty.
+func ValidatePrincipal(principal string) error {
+	if principal == "" {
+		return errors.New("empty principal")
+	}
+	if len(principal) > maxPrincipalLen {
+		return errors.Errorf("principal longer than %d characters",
+			maxPrincipalLen)
+	}
+
+	sep := strings.Index(principal, "@")
+	switch {
+	case sep < 0:
+		return errors.Errorf("principal %q must be in the format name@[domain]",
+			principal)
+	case sep == 0:
+		return errors.Errorf("principal %q has no name before '@'", principal)
+	case strings.Count(principal, "@") > 1:
+		return errors.Errorf("principal %q contains more than one '@'",
+			principal)
+	}
+
+	return nil
+}
+
+// ParseACE parses and validates an Access Control Entry in short string format.
+func ParseACE(str string) (*AccessControlEntry, error) {
+	fields := strings.Split(str, aceFieldSep)
+	if len(fields) != aceFieldCount {
+		return nil, errors.Errorf("ACE %q must have %d fields separated by %q",
+			str, aceFieldCount, aceFieldSep)
+	}
+
+	ace := &AccessControlEntry{Principal: fields[2]}
+
+	var err error
+	if ace.AccessTypes, err = normalizeACEField(fields[0], "access type", aceAccessTypes); err != nil {
+		return nil, err
+	}
+	if ace.Flags, err = normalizeACEField(fields[1], "flag", aceFlags); err != nil {
+		return nil, err
+	}
+	if ace.Perms, err = normalizeACEField(fields[3], "permission", acePerms); err != nil {
+		return nil, err
+	}
+
+	if err := ace.validate(); err != nil {
+		return nil, errors.WithMessagef(err, "ACE %q", str)
+	}
+
+	return ace, nil
+}
+
+// IsGroup checks whether the entry applies to a group principal.
+func (ace *AccessControlEntry) IsGroup() bool {
+	return strings.ContainsRune(ace.Flags, aceFlagGroup)
+}
+
+// validate applies the same consistency rules as daos_ace_is_valid().
+func (ace *AccessControlEntry) validate() error {
+	if ace.AccessTypes == "" {
+		return errors.New("no access type")
+	}
+
+	if err := ValidatePrincipal(ace.Principal); err != nil {
+		return err
+	}
+
+	switch ace.Principal {
+	case PrincipalOwnerGroup:
+		if !ace.IsGroup() {
+			return errors.Errorf("principal %s requires the %q flag",
+				PrincipalOwnerGroup, aceFlagGroup)
+		}
+	case PrincipalOwner, PrincipalEveryone:
+		if ace.IsGroup() {
+			return errors.Errorf("principal %s can't have the %q flag",
+				ace.Principal, aceFlagGroup)
+		}
+	}
+
+	isAlert := strings.ContainsAny(ace.AccessTypes,
+		string([]rune{aceAccessAudit, aceAccessAlarm}))
+	hasAlertFlags := strings.ContainsAny(ace.Flags,
+		string([]rune{aceFlagSuccess, aceFlagFail}))
+	if isAlert != hasAlertFlags {
+		return errors.Errorf("%q and %q flags are required for, and only valid with, access types %q and %q",
+			aceFlagSuccess, aceFlagFail, aceAccessAudit, aceAccessAlarm)
+	}
+
+	return nil
+}
+
+// PrincipalKey returns a string that uniquely identifies the principal the
+// entry applies to. A user and a group with the same name are different
+// principals.
+func (ace *AccessControlEntry) PrincipalKey() string {
+	if ace.IsGroup() && !isSpecialPrincipal(ace.Principal) {
+		return "g:" + ace.Principal
+	}
+	return "u:" + ace.Principal
+}
+
+// String returns the entry in canonical short string format.
+func (ace *AccessControlEntry) String() string {
+	return strings.Join([]string{
+		ace.AccessTypes, ace.Flags, ace.Principal, ace.Perms,
+	}, aceFieldSep)
+}
+
+// HasOwnerEntry checks whether the AccessControlList contains an entry for the
+// special OWNER@ principal.
+func (acl *AccessControlList) HasOwnerEntry() bool {
+	if acl == nil {
+		return false
+	}
+
+	for _, entry := range acl.Entries {
+		ace, err := ParseACE(entry)
+		if err == nil && ace.Principal == PrincipalOwner {
+			return true
+		}
+	}
+	return false
+}
+
+// ACLChange describes an entry for a principal that exists in both ACLs being
+// compared but with different contents.
+type ACLChange struct {
+	Old string
+	New string
+}
+
+// ACLDiff describes the entries that would be added, changed or removed if a
+// proposed ACL were applied to a pool.
+type ACLDiff struct {
+	Added   []string
+	Changed []ACLChange
+	Removed []string
+}
+
+// Empty checks whether applying the proposed ACL would change anything.
+func (d *ACLDiff) Empty() bool {
+	return d == nil ||
+		(len(d.Added) == 0 && len(d.Changed) == 0 && len(d.Removed) == 0)
+}
+
+// parseACEsByPrincipal parses all entries of the ACL and returns them keyed by
+// principal, along with the principal keys in the original order.
+func parseACEsByPrincipal(acl *AccessControlList) (map[string]*AccessControlEntry, []string, error) {
+	aces := make(map[string]*AccessControlEntry)
+	var order []string
+
+	if acl == nil {
+		return aces, order, nil
+	}
+
+	for _, entry := range acl.Entries {
+		ace, err := ParseACE(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key := ace.PrincipalKey()
+		if _, exists := aces[key]; exists {
+			return nil, nil, errors.Errorf("duplicate entry for principal %s",
+				ace.Principal)
+		}
+		aces[key] = ace
+		order = append(order, key)
+	}
+
+	return aces, order, nil
+}
+
+// DiffACL compares a proposed ACL against the current ACL of a pool. When
+// overwrite is true the comparison matches the semantics of PoolOverwriteACL,
+// and entries missing from the proposed ACL are reported as removed.
+// Otherwise it matches PoolUpdateACL, which never removes entries.
+func DiffACL(current, proposed *AccessControlList, overwrite bool) (*ACLDiff, error) {
+	curACEs, curOrder, err := parseACEsByPrincipal(current)
+	if err != nil {
+		return nil, errors.WithMessage(err, "current ACL")
+	}
+	newACEs, newOrder, err := parseACEsByPrincipal(proposed)
+	if err != nil {
+		return nil, errors.WithMessage(err, "proposed ACL")
+	}
+
+	diff := &ACLDiff{}
+	for _, key := range newOrder {
+		newACE := newACEs[key]
+		curACE, exists := curACEs[key]
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, newACE.String())
+		case curACE.String() != newACE.String():
+			diff.Changed = append(diff.Changed, ACLChange{
+				Old: curACE.String(),
+				New: newACE.String(),
+			})
+		}
+	}
+
+	if overwrite {
+		for _, key := range curOrder {
+			if _, exists := newACEs[key]; !exists {
+				diff.Removed = append(diff.Removed, curACEs[key].String())
+			}
+		}
+	}
+
+	return diff, nil
+}
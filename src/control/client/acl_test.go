@@ -0,0 +1,273 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+
+package client
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestParseACE(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ace    string
+		expACE *AccessControlEntry
+		expErr error
+	}{
+		"owner": {
+			ace:    "A::OWNER@:rw",
+			expACE: &AccessControlEntry{AccessTypes: "A", Principal: "OWNER@", Perms: "rw"},
+		},
+		"owner group": {
+			ace:    "A:G:GROUP@:r",
+			expACE: &AccessControlEntry{AccessTypes: "A", Flags: "G", Principal: "GROUP@", Perms: "r"},
+		},
+		"named group with domain": {
+			ace:    "A:G:readers@CORP:r",
+			expACE: &AccessControlEntry{AccessTypes: "A", Flags: "G", Principal: "readers@CORP", Perms: "r"},
+		},
+		"no permissions": {
+			ace:    "A::user@:",
+			expACE: &AccessControlEntry{AccessTypes: "A", Principal: "user@"},
+		},
+		"canonical ordering": {
+			ace: "LUA:PFS:EVERYONE@:wr",
+			expACE: &AccessControlEntry{
+				AccessTypes: "AUL", Flags: "SFP", Principal: "EVERYONE@", Perms: "rw",
+			},
+		},
+		"empty": {
+			ace:    "",
+			expErr: errors.New("must have 4 fields"),
+		},
+		"too many fields": {
+			ace:    "A::user@:rw:",
+			expErr: errors.New("must have 4 fields"),
+		},
+		"no access type": {
+			ace:    "::user@:rw",
+			expErr: errors.New("no access type"),
+		},
+		"invalid access type": {
+			ace:    "D::user@:rw",
+			expErr: errors.New("invalid access type 'D'"),
+		},
+		"invalid flag": {
+			ace:    "A:X:user@:rw",
+			expErr: errors.New("invalid flag 'X'"),
+		},
+		"invalid permission": {
+			ace:    "A::user@:rwx",
+			expErr: errors.New("invalid permission 'x'"),
+		},
+		"empty principal": {
+			ace:    "A:::rw",
+			expErr: errors.New("empty principal"),
+		},
+		"principal without @": {
+			ace:    "A::user:rw",
+			expErr: errors.New("must be in the format name@[domain]"),
+		},
+		"principal without name": {
+			ace:    "A::@domain:rw",
+			expErr: errors.New("has no name before '@'"),
+		},
+		"principal with multiple @": {
+			ace:    "A::user@dom@ain:rw",
+			expErr: errors.New("contains more than one '@'"),
+		},
+		"GROUP@ without group flag": {
+			ace:    "A::GROUP@:rw",
+			expErr: errors.New("requires the 'G' flag"),
+		},
+		"OWNER@ with group flag": {
+			ace:    "A:G:OWNER@:rw",
+			expErr: errors.New("can't have the 'G' flag"),
+		},
+		"audit without success/fail flag": {
+			ace:    "U::user@:rw",
+			expErr: errors.New("flags are required for"),
+		},
+		"allow with success flag": {
+			ace:    "A:S:user@:rw",
+			expErr: errors.New("flags are required for"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ace, err := ParseACE(tc.ace)
+
+			common.CmpErr(t, tc.expErr, err)
+			if diff := cmp.Diff(tc.expACE, ace); diff != "" {
+				t.Fatalf("unexpected ACE (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestAccessControlEntry_PrincipalKey(t *testing.T) {
+	user, _ := ParseACE("A::admin@:rw")
+	group, _ := ParseACE("A:G:admin@:rw")
+	ownerGroup, _ := ParseACE("A:G:GROUP@:rw")
+
+	if user.PrincipalKey() == group.PrincipalKey() {
+		t.Fatal("user and group with the same name should have different keys")
+	}
+	common.AssertEqual(t, ownerGroup.PrincipalKey(), "u:GROUP@", "special principal key")
+}
+
+func TestAccessControlList_HasOwnerEntry(t *testing.T) {
+	for name, tc := range map[string]struct {
+		acl       *AccessControlList
+		expResult bool
+	}{
+		"nil": {},
+		"empty": {
+			acl: &AccessControlList{},
+		},
+		"owner user only": {
+			acl: &AccessControlList{Owner: "bob@"},
+		},
+		"no owner entry": {
+			acl: &AccessControlList{
+				Entries: []string{"A:G:GROUP@:rw", "A::bob@:rw"},
+			},
+		},
+		"owner entry": {
+			acl: &AccessControlList{
+				Entries: []string{"A:G:GROUP@:rw", "A::OWNER@:rw"},
+			},
+			expResult: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			common.AssertEqual(t, tc.acl.HasOwnerEntry(), tc.expResult, "result didn't match")
+		})
+	}
+}
+
+func TestDiffACL(t *testing.T) {
+	current := &AccessControlList{
+		Entries: []string{
+			"A::OWNER@:rw",
+			"A:G:GROUP@:r",
+			"A::alice@:r",
+			"A:G:readers@:r",
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		current   *AccessControlList
+		proposed  *AccessControlList
+		overwrite bool
+		expDiff   *ACLDiff
+		expErr    error
+	}{
+		"identical": {
+			current:   current,
+			proposed:  current,
+			overwrite: true,
+			expDiff:   &ACLDiff{},
+		},
+		"same entries in different format": {
+			current: current,
+			proposed: &AccessControlList{
+				Entries: []string{
+					"A:G:readers@:r",
+					"A::alice@:r",
+					"A:G:GROUP@:r",
+					"A::OWNER@:wr",
+				},
+			},
+			overwrite: true,
+			expDiff:   &ACLDiff{},
+		},
+		"overwrite": {
+			current: current,
+			proposed: &AccessControlList{
+				Entries: []string{
+					"A::OWNER@:rw",
+					"A::alice@:rw",
+					"A::readers@:r",
+				},
+			},
+			overwrite: true,
+			expDiff: &ACLDiff{
+				Added: []string{"A::readers@:r"},
+				Changed: []ACLChange{
+					{Old: "A::alice@:r", New: "A::alice@:rw"},
+				},
+				Removed: []string{"A:G:GROUP@:r", "A:G:readers@:r"},
+			},
+		},
+		"update": {
+			current: current,
+			proposed: &AccessControlList{
+				Entries: []string{
+					"A::alice@:rw",
+					"A::bob@:r",
+				},
+			},
+			expDiff: &ACLDiff{
+				Added: []string{"A::bob@:r"},
+				Changed: []ACLChange{
+					{Old: "A::alice@:r", New: "A::alice@:rw"},
+				},
+			},
+		},
+		"nil current": {
+			proposed: &AccessControlList{
+				Entries: []string{"A::OWNER@:rw"},
+			},
+			overwrite: true,
+			expDiff: &ACLDiff{
+				Added: []string{"A::OWNER@:rw"},
+			},
+		},
+		"invalid proposed": {
+			current: current,
+			proposed: &AccessControlList{
+				Entries: []string{"A::OWNER@:rwx"},
+			},
+			expErr: errors.New("proposed ACL: invalid permission"),
+		},
+		"duplicate in current": {
+			current: &AccessControlList{
+				Entries: []string{"A::bob@:r", "A::bob@:rw"},
+			},
+			expErr: errors.New("current ACL: duplicate entry for principal bob@"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			diff, err := DiffACL(tc.current, tc.proposed, tc.overwrite)
+
+			common.CmpErr(t, tc.expErr, err)
+			if diff := cmp.Diff(tc.expDiff, diff); diff != "" {
+				t.Fatalf("unexpected diff (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package client
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+)
+
+// CertInfo describes a certificate in use by a server.
+type CertInfo struct {
+	Subject   string
+	Issuer    string
+	NotBefore time.Time
+	NotAfter  time.Time
+	DNSNames  []string
+}
+
+// CertStatus describes the certificates in use by a server.
+type CertStatus struct {
+	Insecure bool
+	Cert     *CertInfo
+	CA       *CertInfo
+	Error    string
+}
+
+func certInfoFromPB(pb *ctlpb.CertInfo) (*CertInfo, error) {
+	if pb == nil {
+		return nil, nil
+	}
+
+	notBefore, err := time.Parse(time.RFC3339, pb.Notbefore)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse certificate start time")
+	}
+	notAfter, err := time.Parse(time.RFC3339, pb.Notafter)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse certificate expiry time")
+	}
+
+	return &CertInfo{
+		Subject:   pb.Subject,
+		Issuer:    pb.Issuer,
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+		DNSNames:  pb.Dnsnames,
+	}, nil
+}
+
+// CertStatus returns details of the certificates in use by each connected
+// server, result values are of type *CertStatus.
+func (c *connList) CertStatus() ResultMap {
+	c.log.Debugf("CertStatus() Received")
+	return c.makeRequests(&ctlpb.CertStatusReq{}, certStatusRequest)
+}
+
+func certStatusRequest(mc Control, req interface{}, ch chan ClientResult) {
+	statusReq, ok := req.(*ctlpb.CertStatusReq)
+	if !ok {
+		err := errors.Errorf(msgTypeAssert, &ctlpb.CertStatusReq{}, req)
+		ch <- ClientResult{mc.getAddress(), nil, err}
+		return // type err
+	}
+
+	resp, err := mc.getCtlClient().CertStatus(context.Background(), statusReq)
+	if err != nil {
+		ch <- ClientResult{mc.getAddress(), nil, err} // return comms error
+		return
+	}
+	mc.logger().Debugf("gRPC received: %s", resp)
+
+	status := &CertStatus{Insecure: resp.Insecure, Error: resp.Error}
+	if status.Cert, err = certInfoFromPB(resp.Cert); err == nil {
+		status.CA, err = certInfoFromPB(resp.Ca)
+	}
+	ch <- ClientResult{mc.getAddress(), status, err}
+}
@@ -29,13 +29,16 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/pkg/errors"
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/security"
+	"github.com/daos-stack/daos/src/control/security/auth"
 )
 
 const (
@@ -44,6 +47,10 @@ const (
 	defaultConfigPath = "etc/daos.yml"
 	defaultSystemName = "daos_server"
 	defaultPort       = 10001
+
+	defaultCredentialCacheTTL           = time.Minute
+	defaultCredentialCacheSize          = 1024
+	defaultCredentialCacheStatsInterval = time.Hour
 )
 
 // External interface provides methods to support various os operations.
@@ -67,32 +74,51 @@ func (e *ext) Getenv(key string) string {
 
 // Configuration contains all known configuration variables available to the client
 type Configuration struct {
-	SystemName      string   `yaml:"name"`
-	AccessPoints    []string `yaml:"access_points"`
-	Port            int      `yaml:"port"`
-	HostList        []string `yaml:"hostlist"`
-	RuntimeDir      string   `yaml:"runtime_dir"`
-	HostFile        string   `yaml:"host_file"`
-	LogFile         string   `yaml:"log_file"`
-	LogFileFormat   string   `yaml:"log_file_format"`
+	SystemName    string   `yaml:"name"`
+	AccessPoints  []string `yaml:"access_points"`
+	Port          int      `yaml:"port"`
+	HostList      []string `yaml:"hostlist"`
+	RuntimeDir    string   `yaml:"runtime_dir"`
+	HostFile      string   `yaml:"host_file"`
+	LogFile       string   `yaml:"log_file"`
+	LogFileFormat string   `yaml:"log_file_format"`
+	// LogRotation limits the size of the daos_agent log file.
+	LogRotation     logging.LogRotation `yaml:"log_rotation,omitempty"`
 	Path            string
 	TransportConfig *security.TransportConfig `yaml:"transport_config"`
-	Ext             External
+	// CredentialCacheTTL is how long daos_agent reuses the user and group
+	// names resolved for a client's credentials; zero disables the cache.
+	CredentialCacheTTL  time.Duration `yaml:"credential_cache_ttl"`
+	CredentialCacheSize int           `yaml:"credential_cache_size"`
+	// CredentialCacheStatsInterval is how often daos_agent logs the
+	// credential cache counters; zero disables the periodic report.
+	CredentialCacheStatsInterval time.Duration `yaml:"credential_cache_stats_interval"`
+	// IdentityMapping controls the ACL principal names daos_agent puts
+	// in client credentials.
+	IdentityMapping *auth.IdentityMapConfig `yaml:"identity_mapping"`
+	// DrpcMaxMessageSize is the size of the largest dRPC call daos_agent
+	// accepts from a client.
+	DrpcMaxMessageSize int `yaml:"drpc_max_message_size"`
+	Ext                External
 }
 
 // newDefaultConfiguration creates a new instance of configuration struct
 // populated with defaults.
 func newDefaultConfiguration(ext External) *Configuration {
 	return &Configuration{
-		SystemName:      defaultSystemName,
-		AccessPoints:    []string{fmt.Sprintf("localhost:%d", defaultPort)},
-		Port:            defaultPort,
-		HostList:        []string{fmt.Sprintf("localhost:%d", defaultPort)},
-		RuntimeDir:      defaultRuntimeDir,
-		LogFile:         defaultLogFile,
-		Path:            defaultConfigPath,
-		TransportConfig: security.DefaultClientTransportConfig(),
-		Ext:             ext,
+		SystemName:                   defaultSystemName,
+		AccessPoints:                 []string{fmt.Sprintf("localhost:%d", defaultPort)},
+		Port:                         defaultPort,
+		HostList:                     []string{fmt.Sprintf("localhost:%d", defaultPort)},
+		RuntimeDir:                   defaultRuntimeDir,
+		LogFile:                      defaultLogFile,
+		Path:                         defaultConfigPath,
+		TransportConfig:              security.DefaultClientTransportConfig(),
+		CredentialCacheTTL:           defaultCredentialCacheTTL,
+		CredentialCacheSize:          defaultCredentialCacheSize,
+		CredentialCacheStatsInterval: defaultCredentialCacheStatsInterval,
+		DrpcMaxMessageSize:           drpc.DefaultMaxMessageSize,
+		Ext:                          ext,
 	}
 }
 
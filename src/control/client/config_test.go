@@ -29,10 +29,14 @@ import (
 	"os"
 	"path"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
 
 	"github.com/daos-stack/daos/src/control/client"
 	"github.com/daos-stack/daos/src/control/common"
 	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security/auth"
 )
 
 func getDefaultConfig(t *testing.T) *client.Configuration {
@@ -126,6 +130,82 @@ func TestLoadConfigFromFile(t *testing.T) {
 		"loaded config doesn't match written config")
 }
 
+func TestLoadConfigIdentityMapping(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	testFile := getTestFile(t)
+	defer os.Remove(testFile.Name())
+
+	_, err := testFile.WriteString(`
+identity_mapping:
+  domain: CORP
+  users:
+  - id: 0
+    principal: root@LOCAL
+  - match: '(.*)_adm'
+    principal: $1@ADMIN
+  exclude_groups: ['wheel']
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFile.Close()
+
+	cfg, err := client.GetConfig(log, testFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rootUID := uint32(0)
+	expected := &auth.IdentityMapConfig{
+		Domain: "CORP",
+		Users: []auth.PrincipalRule{
+			{ID: &rootUID, Principal: "root@LOCAL"},
+			{Match: "(.*)_adm", Principal: "$1@ADMIN"},
+		},
+		ExcludeGroups: []string{"wheel"},
+	}
+	if diff := cmp.Diff(expected, cfg.IdentityMapping); diff != "" {
+		t.Fatalf("unexpected identity mapping (-want, +got):\n%s\n", diff)
+	}
+}
+
+func TestLoadConfigLogRotation(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	testFile := getTestFile(t)
+	defer os.Remove(testFile.Name())
+
+	_, err := testFile.WriteString(`
+log_rotation:
+  max_size_mb: 100
+  max_age: 168h
+  max_backups: 5
+  compress: true
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testFile.Close()
+
+	cfg, err := client.GetConfig(log, testFile.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := logging.LogRotation{
+		MaxSizeMB:  100,
+		MaxAge:     168 * time.Hour,
+		MaxBackups: 5,
+		Compress:   true,
+	}
+	if diff := cmp.Diff(expected, cfg.LogRotation); diff != "" {
+		t.Fatalf("unexpected log rotation (-want, +got):\n%s\n", diff)
+	}
+}
+
 func TestLoadConfigFailures(t *testing.T) {
 	log, buf := logging.NewTestLogger(t.Name())
 	defer common.ShowBufferOnFailure(t, buf)
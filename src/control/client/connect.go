@@ -60,6 +60,7 @@ func chooseServiceLeader(cs []Control) (Control, error) {
 // connected clients (controllers).
 type Connect interface {
 	BioHealthQuery(*mgmtpb.BioHealthReq) ResultQueryMap
+	CertStatus() ResultMap
 	ClearConns() ResultMap
 	ConnectClients(Addresses) ResultMap
 	GetActiveConns(ResultMap) ResultMap
@@ -74,6 +75,7 @@ type Connect interface {
 	PoolOverwriteACL(PoolOverwriteACLReq) (*PoolOverwriteACLResp, error)
 	PoolUpdateACL(PoolUpdateACLReq) (*PoolUpdateACLResp, error)
 	PoolDeleteACL(PoolDeleteACLReq) (*PoolDeleteACLResp, error)
+	SetLogLevel(SetLogLevelReq) ResultMap
 	SetTransportConfig(*security.TransportConfig)
 	SmdListDevs(*mgmtpb.SmdDevReq) ResultSmdMap
 	SmdListPools(*mgmtpb.SmdPoolReq) ResultSmdMap
@@ -85,6 +87,9 @@ type Connect interface {
 	SystemQuery() (system.Members, error)
 	SystemStart() error
 	SystemStop(SystemStopReq) (system.MemberResults, error)
+	SystemEvents(SystemEventsReq, func(*system.Event)) error
+	SystemExclude([]uint32) (system.MemberResults, error)
+	SystemReintegrate([]uint32) (system.MemberResults, error)
 	LeaderQuery(LeaderQueryReq) (*LeaderQueryResp, error)
 	ListPools(ListPoolsReq) (*ListPoolsResp, error)
 }
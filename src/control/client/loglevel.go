@@ -0,0 +1,90 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package client
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+)
+
+// SetLogLevelReq contains the log levels to set on each connected server,
+// empty values leave the corresponding level unchanged.
+type SetLogLevelReq struct {
+	Control string   // control plane level, control_log_mask syntax
+	Engine  string   // I/O server mask, D_LOG_MASK syntax
+	Ranks   []uint32 // only set mask for these ranks, all if empty
+}
+
+// EngineLogMask describes the log mask that was in effect for an I/O server
+// rank before it was changed.
+type EngineLogMask struct {
+	Rank     uint32
+	PrevMask string
+	Error    string
+}
+
+// SetLogLevelResp contains the log levels that were in effect on a server
+// before they were changed.
+type SetLogLevelResp struct {
+	PrevControl string
+	Engines     []*EngineLogMask
+}
+
+// SetLogLevel changes the log levels of each connected server without
+// restarting, result values are of type *SetLogLevelResp.
+func (c *connList) SetLogLevel(req SetLogLevelReq) ResultMap {
+	c.log.Debugf("SetLogLevel(%+v) Received", req)
+	return c.makeRequests(&ctlpb.SetLogLevelReq{
+		Control: req.Control,
+		Engine:  req.Engine,
+		Ranks:   req.Ranks,
+	}, setLogLevelRequest)
+}
+
+func setLogLevelRequest(mc Control, req interface{}, ch chan ClientResult) {
+	levelReq, ok := req.(*ctlpb.SetLogLevelReq)
+	if !ok {
+		err := errors.Errorf(msgTypeAssert, &ctlpb.SetLogLevelReq{}, req)
+		ch <- ClientResult{mc.getAddress(), nil, err}
+		return // type err
+	}
+
+	resp, err := mc.getCtlClient().SetLogLevel(context.Background(), levelReq)
+	if err != nil {
+		ch <- ClientResult{mc.getAddress(), nil, err} // return comms error
+		return
+	}
+	mc.logger().Debugf("gRPC received: %s", resp)
+
+	result := &SetLogLevelResp{PrevControl: resp.Prevcontrol}
+	for _, engine := range resp.Engines {
+		result.Engines = append(result.Engines, &EngineLogMask{
+			Rank:     engine.Rank,
+			PrevMask: engine.Prevmask,
+			Error:    engine.Error,
+		})
+	}
+	ch <- ClientResult{mc.getAddress(), result, nil}
+}
@@ -176,6 +176,34 @@ func (m *mockMgmtCtlClient) SystemStop(ctx context.Context, req *ctlpb.SystemSto
 	return &ctlpb.SystemStopResp{}, nil
 }
 
+type mgmtCtlSystemEventsClient struct {
+	grpc.ClientStream
+}
+
+func (m *mgmtCtlSystemEventsClient) Recv() (*ctlpb.SystemEvent, error) {
+	return nil, io.EOF
+}
+
+func (m *mockMgmtCtlClient) SystemEvents(ctx context.Context, req *ctlpb.SystemEventsReq, o ...grpc.CallOption) (ctlpb.MgmtCtl_SystemEventsClient, error) {
+	return &mgmtCtlSystemEventsClient{}, nil
+}
+
+func (m *mockMgmtCtlClient) SystemExclude(ctx context.Context, req *ctlpb.SystemExcludeReq, o ...grpc.CallOption) (*ctlpb.SystemExcludeResp, error) {
+	return &ctlpb.SystemExcludeResp{}, nil
+}
+
+func (m *mockMgmtCtlClient) CertStatus(ctx context.Context, req *ctlpb.CertStatusReq, o ...grpc.CallOption) (*ctlpb.CertStatusResp, error) {
+	return &ctlpb.CertStatusResp{}, nil
+}
+
+func (m *mockMgmtCtlClient) SetLogLevel(ctx context.Context, req *ctlpb.SetLogLevelReq, o ...grpc.CallOption) (*ctlpb.SetLogLevelResp, error) {
+	return &ctlpb.SetLogLevelResp{}, nil
+}
+
+func (m *mockMgmtCtlClient) SystemReintegrate(ctx context.Context, req *ctlpb.SystemReintegrateReq, o ...grpc.CallOption) (*ctlpb.SystemReintegrateResp, error) {
+	return &ctlpb.SystemReintegrateResp{}, nil
+}
+
 func (m *mockMgmtCtlClient) SystemStart(ctx context.Context, req *ctlpb.SystemStartReq, o ...grpc.CallOption) (*ctlpb.SystemStartResp, error) {
 	return &ctlpb.SystemStartResp{}, nil
 }
@@ -314,6 +342,14 @@ func (m *mockMgmtSvcClient) KillRank(ctx context.Context, req *mgmtpb.KillRankRe
 	return &mgmtpb.DaosResp{}, nil
 }
 
+func (m *mockMgmtSvcClient) PingRank(ctx context.Context, req *mgmtpb.PingRankReq, o ...grpc.CallOption) (*mgmtpb.DaosResp, error) {
+	return &mgmtpb.DaosResp{}, nil
+}
+
+func (m *mockMgmtSvcClient) ReportEvent(ctx context.Context, req *mgmtpb.ReportEventReq, o ...grpc.CallOption) (*mgmtpb.DaosResp, error) {
+	return &mgmtpb.DaosResp{}, nil
+}
+
 func (m *mockMgmtSvcClient) StartRanks(ctx context.Context, req *mgmtpb.StartRanksReq, o ...grpc.CallOption) (*mgmtpb.StartRanksResp, error) {
 	return &mgmtpb.StartRanksResp{}, nil
 }
@@ -24,6 +24,9 @@
 package client
 
 import (
+	"io"
+	"time"
+
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
@@ -107,6 +110,101 @@ func (c *connList) SystemQuery() (system.Members, error) {
 	return proto.MembersFromPB(c.log, rpcResp.Members)
 }
 
+// SystemEventsReq contains the inputs for the system events command.
+type SystemEventsReq struct {
+	Since  time.Time // only events after this time, all if zero
+	Ranks  []uint32  // only events for these ranks, all if empty
+	Follow bool      // keep receiving events as they are recorded
+}
+
+// SystemEvents retrieves events recorded in the system event log, calling
+// eventFn for each event as it is received. If Follow is set in the request,
+// only returns on error.
+//
+// Isolate protobuf encapsulation in client and don't expose to calling code.
+func (c *connList) SystemEvents(req SystemEventsReq, eventFn func(*system.Event)) error {
+	mc, err := chooseServiceLeader(c.controllers)
+	if err != nil {
+		return err
+	}
+
+	rpcReq := &ctlpb.SystemEventsReq{Ranks: req.Ranks, Follow: req.Follow}
+	if !req.Since.IsZero() {
+		rpcReq.Since = req.Since.Format(time.RFC3339Nano)
+	}
+
+	c.log.Debugf("DAOS system events request: %s\n", rpcReq)
+
+	stream, err := mc.getCtlClient().SystemEvents(context.Background(), rpcReq)
+	if err != nil {
+		return err
+	}
+
+	for {
+		pbEvent, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, msgStreamRecv, stream)
+		}
+
+		event, err := proto.EventFromPB(pbEvent)
+		if err != nil {
+			return err
+		}
+		eventFn(event)
+	}
+}
+
+// SystemExclude will exclude the given ranks from the DAOS system, marking
+// them as evicted and refusing join attempts until they are reintegrated.
+//
+// Isolate protobuf encapsulation in client and don't expose to calling code.
+func (c *connList) SystemExclude(ranks []uint32) (system.MemberResults, error) {
+	mc, err := chooseServiceLeader(c.controllers)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcReq := &ctlpb.SystemExcludeReq{Ranks: ranks}
+
+	c.log.Debugf("DAOS system exclude request: %s\n", rpcReq)
+
+	rpcResp, err := mc.getCtlClient().SystemExclude(context.Background(), rpcReq)
+	if err != nil {
+		return nil, err
+	}
+
+	c.log.Debugf("DAOS system exclude response: %s\n", rpcResp)
+
+	return proto.MemberResultsFromPB(c.log, rpcResp.Results), nil
+}
+
+// SystemReintegrate will reintegrate previously excluded ranks into the DAOS
+// system, allowing them to rejoin.
+//
+// Isolate protobuf encapsulation in client and don't expose to calling code.
+func (c *connList) SystemReintegrate(ranks []uint32) (system.MemberResults, error) {
+	mc, err := chooseServiceLeader(c.controllers)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcReq := &ctlpb.SystemReintegrateReq{Ranks: ranks}
+
+	c.log.Debugf("DAOS system reintegrate request: %s\n", rpcReq)
+
+	rpcResp, err := mc.getCtlClient().SystemReintegrate(context.Background(), rpcReq)
+	if err != nil {
+		return nil, err
+	}
+
+	c.log.Debugf("DAOS system reintegrate response: %s\n", rpcResp)
+
+	return proto.MemberResultsFromPB(c.log, rpcResp.Results), nil
+}
+
 // KillRank Will terminate server running at given rank on pool specified by
 // uuid. Request will only be issued to a single access point.
 //
@@ -0,0 +1,204 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
+	"github.com/daos-stack/daos/src/control/security/auth"
+)
+
+// credCacheKey identifies the client socket credentials that an identity
+// was resolved for.
+type credCacheKey struct {
+	uid uint32
+	gid uint32
+	ctx string
+}
+
+type credCacheEntry struct {
+	identity *auth.Sys
+	expires  time.Time
+}
+
+// credCacheStats holds the counters reported for a credentialCache.
+type credCacheStats struct {
+	Entries   int
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+func (s credCacheStats) String() string {
+	return fmt.Sprintf("%d entries, %d hits, %d misses, %d evictions",
+		s.Entries, s.Hits, s.Misses, s.Evictions)
+}
+
+// credentialCache holds the AuthSys identities resolved from client socket
+// credentials so that the user and group lookups, which may go to a remote
+// directory service, are not repeated for every client connection.
+//
+// Signed credentials are not cached because each one carries a nonce that
+// the server accepts only once; only the identity they are issued for is.
+type credentialCache struct {
+	sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[credCacheKey]*credCacheEntry
+	stats      credCacheStats
+	now        func() time.Time
+}
+
+// newCredentialCache returns a cache holding at most maxEntries identities
+// for ttl each. The TTL must be shorter than the validity of the credentials
+// issued from the cached identities.
+func newCredentialCache(ttl time.Duration, maxEntries int) (*credentialCache, error) {
+	if ttl <= 0 || ttl >= auth.CredentialValidity {
+		return nil, errors.Errorf("credential cache TTL %s must be greater than 0 and less than %s",
+			ttl, auth.CredentialValidity)
+	}
+	if maxEntries <= 0 {
+		return nil, errors.Errorf("credential cache size %d must be greater than 0", maxEntries)
+	}
+
+	return &credentialCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[credCacheKey]*credCacheEntry),
+		now:        time.Now,
+	}, nil
+}
+
+// Get returns the identity cached for the domain info credentials, calling
+// resolve to look it up if there is no unexpired entry. Failed lookups are
+// not cached.
+func (c *credentialCache) Get(creds *security.DomainInfo, resolve func() (*auth.Sys, error)) (*auth.Sys, error) {
+	if creds == nil {
+		return resolve()
+	}
+	key := credCacheKey{uid: creds.Uid(), gid: creds.Gid(), ctx: creds.Ctx()}
+
+	c.Lock()
+	now := c.now()
+	if entry, found := c.entries[key]; found && now.Before(entry.expires) {
+		c.stats.Hits++
+		c.Unlock()
+		return entry.identity, nil
+	}
+	c.stats.Misses++
+	c.Unlock()
+
+	// Lookups are done without the lock held so that a slow directory
+	// service only delays the clients waiting on it.
+	identity, err := resolve()
+	if err != nil {
+		return nil, err
+	}
+	identity = proto.Clone(identity).(*auth.Sys)
+
+	c.Lock()
+	defer c.Unlock()
+	if _, found := c.entries[key]; !found {
+		c.makeRoom(now)
+	}
+	c.entries[key] = &credCacheEntry{
+		identity: identity,
+		expires:  now.Add(c.ttl),
+	}
+
+	return identity, nil
+}
+
+// makeRoom drops expired entries and, if the cache is still full, the entry
+// closest to expiry. Must be called with the lock held.
+func (c *credentialCache) makeRoom(now time.Time) {
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+
+	var oldestKey credCacheKey
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+			c.stats.Evictions++
+			continue
+		}
+		if oldest.IsZero() || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+
+	if len(c.entries) >= c.maxEntries {
+		delete(c.entries, oldestKey)
+		c.stats.Evictions++
+	}
+}
+
+// Flush drops all cached identities, so that changes to users and groups
+// take effect for the next client connection, and returns how many were
+// dropped.
+func (c *credentialCache) Flush() int {
+	c.Lock()
+	defer c.Unlock()
+
+	flushed := len(c.entries)
+	c.entries = make(map[credCacheKey]*credCacheEntry)
+
+	return flushed
+}
+
+// Stats returns the current cache counters.
+func (c *credentialCache) Stats() credCacheStats {
+	c.Lock()
+	defer c.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.entries)
+
+	return stats
+}
+
+// LogStats logs the cache counters every interval until the context is
+// done, so that the cache's effectiveness can be followed in the agent log.
+func (c *credentialCache) LogStats(ctx context.Context, log logging.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			log.Infof("credential cache: %s", c.Stats())
+		}
+	}
+}
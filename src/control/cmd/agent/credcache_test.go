@@ -0,0 +1,232 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
+	"github.com/daos-stack/daos/src/control/security/auth"
+)
+
+func TestNewCredentialCache(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ttl    time.Duration
+		size   int
+		expErr error
+	}{
+		"valid": {
+			ttl:  time.Minute,
+			size: 10,
+		},
+		"zero ttl": {
+			size:   10,
+			expErr: errors.New("credential cache TTL 0s must be greater than 0 and less than 5m0s"),
+		},
+		"ttl as long as credential validity": {
+			ttl:    auth.CredentialValidity,
+			size:   10,
+			expErr: errors.New("credential cache TTL 5m0s must be greater than 0 and less than 5m0s"),
+		},
+		"zero size": {
+			ttl:    time.Minute,
+			expErr: errors.New("credential cache size 0 must be greater than 0"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := newCredentialCache(tc.ttl, tc.size)
+			common.CmpErr(t, tc.expErr, err)
+		})
+	}
+}
+
+func testDomainInfo(uid, gid uint32) *security.DomainInfo {
+	return security.InitDomainInfo(&syscall.Ucred{Uid: uid, Gid: gid}, "test")
+}
+
+func TestCredentialCache_Get(t *testing.T) {
+	start := time.Date(2019, 11, 20, 10, 0, 0, 0, time.UTC)
+
+	type lookup struct {
+		uid      uint32
+		after    time.Duration
+		resolved bool
+	}
+
+	for name, tc := range map[string]struct {
+		size     int
+		resolve  error
+		lookups  []lookup
+		expStats credCacheStats
+	}{
+		"miss then hit": {
+			size: 2,
+			lookups: []lookup{
+				{uid: 1, resolved: true},
+				{uid: 1, after: time.Second},
+			},
+			expStats: credCacheStats{Entries: 1, Hits: 1, Misses: 1},
+		},
+		"different uids": {
+			size: 2,
+			lookups: []lookup{
+				{uid: 1, resolved: true},
+				{uid: 2, resolved: true},
+				{uid: 1},
+				{uid: 2},
+			},
+			expStats: credCacheStats{Entries: 2, Hits: 2, Misses: 2},
+		},
+		"expired": {
+			size: 2,
+			lookups: []lookup{
+				{uid: 1, resolved: true},
+				{uid: 1, after: time.Minute, resolved: true},
+			},
+			expStats: credCacheStats{Entries: 1, Misses: 2},
+		},
+		"full evicts oldest": {
+			size: 2,
+			lookups: []lookup{
+				{uid: 1, resolved: true},
+				{uid: 2, after: time.Second, resolved: true},
+				{uid: 3, after: 2 * time.Second, resolved: true},
+				{uid: 2, after: 2 * time.Second},
+				{uid: 1, after: 2 * time.Second, resolved: true},
+			},
+			expStats: credCacheStats{Entries: 2, Hits: 1, Misses: 4, Evictions: 2},
+		},
+		"full evicts expired": {
+			size: 2,
+			lookups: []lookup{
+				{uid: 1, resolved: true},
+				{uid: 2, resolved: true},
+				{uid: 3, after: time.Minute, resolved: true},
+			},
+			expStats: credCacheStats{Entries: 1, Misses: 3, Evictions: 2},
+		},
+		"failed lookup not cached": {
+			size:    2,
+			resolve: errors.New("lookup failed"),
+			lookups: []lookup{
+				{uid: 1, resolved: true},
+				{uid: 1, resolved: true},
+			},
+			expStats: credCacheStats{Misses: 2},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cache, err := newCredentialCache(time.Minute, tc.size)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, l := range tc.lookups {
+				cache.now = func() time.Time { return start.Add(l.after) }
+
+				resolved := false
+				identity, err := cache.Get(testDomainInfo(l.uid, 0), func() (*auth.Sys, error) {
+					resolved = true
+					if tc.resolve != nil {
+						return nil, tc.resolve
+					}
+					return &auth.Sys{Machinename: t.Name(), Secctx: string(rune('a' + l.uid))}, nil
+				})
+				common.CmpErr(t, tc.resolve, err)
+				common.AssertEqual(t, resolved, l.resolved, fmt.Sprintf("unexpected resolve for lookup %d", i))
+				if err == nil && identity.GetSecctx() != string(rune('a'+l.uid)) {
+					t.Fatalf("lookup %d returned identity for wrong uid: %+v", i, identity)
+				}
+			}
+
+			if diff := cmp.Diff(tc.expStats, cache.Stats()); diff != "" {
+				t.Fatalf("unexpected stats (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestCredentialCache_Flush(t *testing.T) {
+	cache, err := newCredentialCache(time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolves := 0
+	resolve := func() (*auth.Sys, error) {
+		resolves++
+		return &auth.Sys{}, nil
+	}
+
+	for uid := uint32(0); uid < 3; uid++ {
+		if _, err := cache.Get(testDomainInfo(uid, 0), resolve); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	common.AssertEqual(t, cache.Flush(), 3, "wrong number flushed")
+	common.AssertEqual(t, cache.Stats().Entries, 0, "entries remain after flush")
+
+	if _, err := cache.Get(testDomainInfo(0, 0), resolve); err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, resolves, 4, "identity not resolved again after flush")
+}
+
+func TestCredentialCache_LogStats(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	cache, err := newCredentialCache(time.Minute, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.LogStats(ctx, log, time.Millisecond)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(buf.String(), "credential cache: 0 entries") {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for cache stats to be logged")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-done
+}
@@ -39,6 +39,8 @@ import (
 	"github.com/daos-stack/daos/src/control/common"
 	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
+	"github.com/daos-stack/daos/src/control/security/auth"
 )
 
 var daosVersion string
@@ -148,7 +150,7 @@ func agentMain(log *logging.LeveledLogger, opts *cliOptions) error {
 	log.Debugf("Full socket path is now: %s", sockPath)
 
 	if config.LogFile != "" {
-		f, err := common.AppendFile(config.LogFile)
+		f, err := logging.NewRotatingFile(config.LogFile, config.LogRotation)
 		if err != nil {
 			log.Errorf("Failure creating log file: %s", err)
 			return err
@@ -156,10 +158,17 @@ func agentMain(log *logging.LeveledLogger, opts *cliOptions) error {
 		defer f.Close()
 		log.Infof("Using logfile: %s", config.LogFile)
 
+		// Reopen the log file on SIGHUP so that it can be rotated
+		// externally.
+		reopenSignals := make(chan os.Signal, 1)
+		signal.Notify(reopenSignals, syscall.SIGHUP)
+		go f.ReopenOn(ctx, reopenSignals)
+
 		// Create an additional set of loggers which append everything
 		// to the specified file.
 		log.WithErrorLogger(logging.NewErrorLogger("agent", f)).
 			WithInfoLogger(logging.NewInfoLogger("agent", f)).
+			WithWarnLogger(logging.NewWarnLogger("agent", f)).
 			WithDebugLogger(logging.NewDebugLogger(f))
 	}
 
@@ -168,18 +177,65 @@ func agentMain(log *logging.LeveledLogger, opts *cliOptions) error {
 		return errors.Wrap(err, "Unable to load Cerificate Data")
 	}
 
+	// Reload certificates on SIGHUP or when the files are replaced.
+	reloadSignals := make(chan os.Signal, 1)
+	signal.Notify(reloadSignals, syscall.SIGHUP)
+	go security.NewCertReloader(log, config.TransportConfig).Start(ctx, reloadSignals)
+
 	// Setup signal handlers so we can block till we get SIGINT or SIGTERM
 	signals := make(chan os.Signal, 1)
 	finish := make(chan bool, 1)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
 
+	if config.DrpcMaxMessageSize < drpc.MaxMsgSize {
+		return errors.Errorf("drpc_max_message_size must be at least %d bytes",
+			drpc.MaxMsgSize)
+	}
 	drpcServer, err := drpc.NewDomainSocketServer(ctx, log, sockPath)
 	if err != nil {
 		log.Errorf("Unable to create socket server: %v", err)
 		return err
 	}
+	drpcServer.WithMaxMessageSize(config.DrpcMaxMessageSize)
+
+	mapper, err := auth.NewIdentityMapper(config.IdentityMapping)
+	if err != nil {
+		return errors.Wrap(err, "invalid identity_mapping")
+	}
+
+	secMod := NewSecurityModule(log, config.TransportConfig).WithIdentityMapper(mapper)
+	if config.CredentialCacheTTL > 0 {
+		credCache, err := newCredentialCache(config.CredentialCacheTTL, config.CredentialCacheSize)
+		if err != nil {
+			return err
+		}
+		secMod.WithCredentialCache(credCache)
+		defer func() {
+			log.Infof("credential cache: %s", credCache.Stats())
+		}()
+		if config.CredentialCacheStatsInterval > 0 {
+			go credCache.LogStats(ctx, log, config.CredentialCacheStatsInterval)
+		}
+
+		// Flush the cache on SIGUSR1 so that user and group changes
+		// take effect without waiting for the cached entries to expire.
+		flushSignals := make(chan os.Signal, 1)
+		signal.Notify(flushSignals, syscall.SIGUSR1)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-flushSignals:
+					stats := credCache.Stats()
+					log.Infof("flushed %d cached credentials (%s)",
+						credCache.Flush(), stats)
+				}
+			}
+		}()
+	}
 
-	drpcServer.RegisterRPCModule(NewSecurityModule(log, config.TransportConfig))
+	drpcServer.RegisterRPCModule(secMod)
 	drpcServer.RegisterRPCModule(&mgmtModule{
 		log:  log,
 		sys:  config.SystemName,
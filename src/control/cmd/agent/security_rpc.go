@@ -84,9 +84,11 @@ func (e *external) LookupGroupID(gid uint32) (*user.Group, error) {
 
 // SecurityModule is the security drpc module struct
 type SecurityModule struct {
-	log    logging.Logger
-	ext    auth.UserExt
-	config *security.TransportConfig
+	log       logging.Logger
+	ext       auth.UserExt
+	config    *security.TransportConfig
+	credCache *credentialCache
+	mapper    *auth.IdentityMapper
 }
 
 //NewSecurityModule creates a new module with the given initialized TransportConfig
@@ -99,6 +101,20 @@ func NewSecurityModule(log logging.Logger, tc *security.TransportConfig) *Securi
 	return &mod
 }
 
+// WithCredentialCache sets the cache used to avoid repeating user and group
+// lookups for each client connection.
+func (m *SecurityModule) WithCredentialCache(cache *credentialCache) *SecurityModule {
+	m.credCache = cache
+	return m
+}
+
+// WithIdentityMapper sets the mapping from local user and group names to the
+// ACL principal names in client credentials.
+func (m *SecurityModule) WithIdentityMapper(mapper *auth.IdentityMapper) *SecurityModule {
+	m.mapper = mapper
+	return m
+}
+
 // HandleCall is the handler for calls to the SecurityModule
 func (m *SecurityModule) HandleCall(session *drpc.Session, method int32, body []byte) ([]byte, error) {
 	if method == drpc.MethodRequestCredentials {
@@ -129,16 +145,35 @@ func (m *SecurityModule) getCredential(session *drpc.Session) ([]byte, error) {
 		return m.credRespWithStatus(drpc.DaosInvalidInput)
 	}
 
-	cred, err := auth.AuthSysRequestFromCreds(m.ext, info, signingKey)
+	identity, err := m.getIdentity(info)
 	if err != nil {
 		m.log.Errorf("Failed to get AuthSys struct: %s", err)
 		return m.credRespWithStatus(drpc.DaosMiscError)
 	}
 
+	cred, err := auth.CredentialFromAuthSys(identity, signingKey)
+	if err != nil {
+		m.log.Errorf("Failed to sign AuthSys credential: %s", err)
+		return m.credRespWithStatus(drpc.DaosMiscError)
+	}
+
 	resp := &auth.GetCredResp{Cred: cred}
 	return drpc.Marshal(resp)
 }
 
+// getIdentity resolves the AuthSys identity for the client socket credentials,
+// using the credential cache if one is set.
+func (m *SecurityModule) getIdentity(info *security.DomainInfo) (*auth.Sys, error) {
+	resolve := func() (*auth.Sys, error) {
+		return auth.AuthSysFromCreds(m.ext, m.mapper, info)
+	}
+	if m.credCache == nil {
+		return resolve()
+	}
+
+	return m.credCache.Get(info, resolve)
+}
+
 func (m *SecurityModule) credRespWithStatus(status drpc.DaosStatus) ([]byte, error) {
 	resp := &auth.GetCredResp{Status: int32(status)}
 	return drpc.Marshal(resp)
@@ -28,6 +28,7 @@ import (
 	"net"
 	"os/user"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -213,3 +214,56 @@ func TestAgentSecurityModule_RequestCreds_BadUid(t *testing.T) {
 
 	expectCredResp(t, respBytes, int32(drpc.DaosMiscError), false)
 }
+
+// Count the user lookups made when generating the cred
+type countingExt struct {
+	external
+	userLookups int
+}
+
+func (e *countingExt) LookupUserID(uid uint32) (auth.User, error) {
+	e.userLookups++
+	return e.external.LookupUserID(uid)
+}
+
+func TestAgentSecurityModule_RequestCreds_Cached(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	// Set up a real unix socket so we can make a real connection
+	conn, cleanup := setupTestUnixConn(t)
+	defer cleanup()
+
+	cache, err := newCredentialCache(time.Minute, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ext := &countingExt{}
+	mod := NewSecurityModule(log, defaultTestTransportConfig()).WithCredentialCache(cache)
+	mod.ext = ext
+
+	var nonces []string
+	for i := 0; i < 2; i++ {
+		respBytes, err := callRequestCreds(mod, t, log, conn)
+		if err != nil {
+			t.Fatalf("Expected no error, got %+v", err)
+		}
+		expectCredResp(t, respBytes, 0, true)
+
+		resp := &auth.GetCredResp{}
+		if err := proto.Unmarshal(respBytes, resp); err != nil {
+			t.Fatal(err)
+		}
+		authSys, err := auth.AuthSysFromAuthToken(resp.Cred.GetToken())
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonces = append(nonces, string(authSys.GetNonce()))
+	}
+
+	common.AssertEqual(t, ext.userLookups, 1, "cached identity not used")
+	common.AssertEqual(t, cache.Stats(), credCacheStats{Entries: 1, Hits: 1, Misses: 1}, "")
+	if nonces[0] == nonces[1] {
+		t.Error("credentials issued from the cache share a nonce")
+	}
+}
@@ -0,0 +1,83 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"fmt"
+	"log/syslog"
+
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/pbin"
+)
+
+type (
+	// auditWriter is implemented by *syslog.Writer.
+	auditWriter interface {
+		Notice(string) error
+		Warning(string) error
+	}
+
+	// auditLog records each privileged action along with the uid of the
+	// user on whose behalf it was requested.
+	auditLog struct {
+		log logging.Logger
+		out auditWriter
+		uid int
+	}
+)
+
+// newAuditLog returns an *auditLog which writes to syslog. If syslog is
+// unavailable, the audit records are written to the log instead.
+func newAuditLog(log logging.Logger, binName string, uid int) *auditLog {
+	al := &auditLog{
+		log: log,
+		uid: uid,
+	}
+
+	out, err := syslog.New(syslog.LOG_AUTHPRIV|syslog.LOG_NOTICE, binName)
+	if err != nil {
+		log.Errorf("unable to open syslog for auditing: %s", err)
+		return al
+	}
+	al.out = out
+
+	return al
+}
+
+func (al *auditLog) message(req *pbin.Request) string {
+	return fmt.Sprintf("uid=%d method=%s payload=%s", al.uid, req.Method, req.Payload)
+}
+
+func (al *auditLog) allowed(req *pbin.Request) {
+	msg := al.message(req) + " allowed"
+	if al.out == nil || al.out.Notice(msg) != nil {
+		al.log.Info(msg)
+	}
+}
+
+func (al *auditLog) denied(req *pbin.Request, reason error) {
+	msg := fmt.Sprintf("%s denied: %s", al.message(req), reason)
+	if al.out == nil || al.out.Warning(msg) != nil {
+		al.log.Error(msg)
+	}
+}
@@ -23,6 +23,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 
@@ -77,7 +78,41 @@ func readRequest(log logging.Logger, rdr io.Reader) (*pbin.Request, error) {
 	return &req, nil
 }
 
-func handleRequest(log logging.Logger, scmProvider *scm.Provider, bdevProvider *bdev.Provider, req *pbin.Request, resDest io.Writer) (err error) {
+// requestHandler checks each request against the policy and records the
+// decision in the audit log before handling it.
+type requestHandler struct {
+	log          logging.Logger
+	policy       *policy
+	audit        *auditLog
+	newProviders func() (*scm.Provider, *bdev.Provider)
+	killGroup    func()
+}
+
+func (rh *requestHandler) handle(ctx context.Context, req *pbin.Request, resDest io.Writer) error {
+	if req == nil {
+		return errors.New("nil request")
+	}
+
+	if err := rh.policy.checkRequest(req); err != nil {
+		rh.audit.denied(req, err)
+		return sendFailure(err, &pbin.Response{}, resDest)
+	}
+	rh.audit.allowed(req)
+
+	scmProvider, bdevProvider := rh.newProviders()
+	return handleRequest(ctx, rh.log, scmProvider, bdevProvider, req, resDest)
+}
+
+// abort is called when a request's deadline passes or daos_server stops
+// waiting for the response. daos_server can't kill this process once it is
+// running as root, so the process group is killed here, along with
+// anything started to handle the request.
+func (rh *requestHandler) abort(req *pbin.Request, err error) {
+	rh.log.Errorf("%s request not completed: %s; exiting", req.Method, err)
+	rh.killGroup()
+}
+
+func handleRequest(ctx context.Context, log logging.Logger, scmProvider *scm.Provider, bdevProvider *bdev.Provider, req *pbin.Request, resDest io.Writer) (err error) {
 	if req == nil {
 		return errors.New("nil request")
 	}
@@ -93,9 +128,9 @@ func handleRequest(log logging.Logger, scmProvider *scm.Provider, bdevProvider *
 		var mRes *scm.MountResponse
 		switch req.Method {
 		case "ScmMount":
-			mRes, err = scmProvider.Mount(mReq)
+			mRes, err = scmProvider.Mount(ctx, mReq)
 		case "ScmUnmount":
-			mRes, err = scmProvider.Unmount(mReq)
+			mRes, err = scmProvider.Unmount(ctx, mReq)
 		}
 		if err != nil {
 			return sendFailure(err, &res, resDest)
@@ -111,9 +146,9 @@ func handleRequest(log logging.Logger, scmProvider *scm.Provider, bdevProvider *
 		var fRes *scm.FormatResponse
 		switch req.Method {
 		case "ScmFormat":
-			fRes, err = scmProvider.Format(fReq)
+			fRes, err = scmProvider.Format(ctx, fReq)
 		case "ScmCheckFormat":
-			fRes, err = scmProvider.CheckFormat(fReq)
+			fRes, err = scmProvider.CheckFormat(ctx, fReq)
 		}
 		if err != nil {
 			return sendFailure(err, &res, resDest)
@@ -126,7 +161,7 @@ func handleRequest(log logging.Logger, scmProvider *scm.Provider, bdevProvider *
 			return sendFailure(err, &res, resDest)
 		}
 
-		sRes, err := scmProvider.Scan(sReq)
+		sRes, err := scmProvider.Scan(ctx, sReq)
 		if err != nil {
 			return sendFailure(err, &res, resDest)
 		}
@@ -138,7 +173,7 @@ func handleRequest(log logging.Logger, scmProvider *scm.Provider, bdevProvider *
 			return sendFailure(err, &res, resDest)
 		}
 
-		pRes, err := scmProvider.Prepare(pReq)
+		pRes, err := scmProvider.Prepare(ctx, pReq)
 		if err != nil {
 			return sendFailure(err, &res, resDest)
 		}
@@ -150,7 +185,7 @@ func handleRequest(log logging.Logger, scmProvider *scm.Provider, bdevProvider *
 			return sendFailure(err, &res, resDest)
 		}
 
-		err = bdevProvider.Init(iReq)
+		err = bdevProvider.Init(ctx, iReq)
 		if err != nil {
 			return sendFailure(err, &res, resDest)
 		}
@@ -162,7 +197,7 @@ func handleRequest(log logging.Logger, scmProvider *scm.Provider, bdevProvider *
 			return sendFailure(err, &res, resDest)
 		}
 
-		sRes, err := bdevProvider.Scan(sReq)
+		sRes, err := bdevProvider.Scan(ctx, sReq)
 		if err != nil {
 			return sendFailure(err, &res, resDest)
 		}
@@ -174,7 +209,7 @@ func handleRequest(log logging.Logger, scmProvider *scm.Provider, bdevProvider *
 			return sendFailure(err, &res, resDest)
 		}
 
-		pRes, err := bdevProvider.Prepare(pReq)
+		pRes, err := bdevProvider.Prepare(ctx, pReq)
 		if err != nil {
 			return sendFailure(err, &res, resDest)
 		}
@@ -186,7 +221,7 @@ func handleRequest(log logging.Logger, scmProvider *scm.Provider, bdevProvider *
 			return sendFailure(err, &res, resDest)
 		}
 
-		fRes, err := bdevProvider.Format(fReq)
+		fRes, err := bdevProvider.Format(ctx, fReq)
 		if err != nil {
 			return sendFailure(err, &res, resDest)
 		}
@@ -24,6 +24,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io/ioutil"
 	"math/rand"
@@ -402,7 +403,7 @@ func TestHandler(t *testing.T) {
 			sp := scm.NewMockProvider(log, tc.smbc, tc.smsc)
 			bp := bdev.NewMockProvider(log, tc.bmbc)
 
-			gotErr := handleRequest(log, sp, bp, tc.req, &destBuf)
+			gotErr := handleRequest(context.Background(), log, sp, bp, tc.req, &destBuf)
 			common.CmpErr(t, tc.expErr, gotErr)
 
 			if destBuf.Len() > 0 {
@@ -27,14 +27,17 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"os/user"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/pkg/errors"
 
-	"github.com/daos-stack/daos/src/control/common"
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/pbin"
+	"github.com/daos-stack/daos/src/control/server"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
 	"github.com/daos-stack/daos/src/control/server/storage/scm"
 )
@@ -49,21 +52,32 @@ func exitWithError(log logging.Logger, err error) {
 	os.Exit(1)
 }
 
-func configureLogging(binName string) logging.Logger {
-	logLevel := logging.LogLevelError
-	combinedOut := ioutil.Discard
-	if logPath, set := os.LookupEnv(pbin.DaosAdminLogFileEnvVar); set {
-		lf, err := common.AppendFile(logPath)
-		if err == nil {
-			combinedOut = lf
-			logLevel = logging.LogLevelDebug
-		}
-	}
-
+func configureLogging(binName string) *logging.LeveledLogger {
 	// By default, we only want to log errors to stderr.
-	return logging.NewCombinedLogger(binName, combinedOut).
+	return logging.NewCombinedLogger(binName, ioutil.Discard).
 		WithErrorLogger(logging.NewCommandLineErrorLogger(os.Stderr)).
-		WithLogLevel(logLevel)
+		WithLogLevel(logging.LogLevelError)
+}
+
+// configureLogFile enables debug logging to the helper log file named in
+// the trusted daos_server config, if any. The environment is controlled by
+// the caller, so it isn't consulted for the log location.
+func configureLogFile(log *logging.LeveledLogger, binName string, cfg *server.Configuration) {
+	if cfg.HelperLogFile == "" {
+		return
+	}
+
+	lf, err := logging.NewRotatingFile(cfg.HelperLogFile, cfg.LogRotation)
+	if err != nil {
+		log.Errorf("unable to open %s: %s", cfg.HelperLogFile, err)
+		return
+	}
+
+	log.AddDebugLogger(logging.NewDebugLogger(lf))
+	log.AddInfoLogger(logging.NewInfoLogger(binName, lf))
+	log.AddWarnLogger(logging.NewWarnLogger(binName, lf))
+	log.AddErrorLogger(logging.NewErrorLogger(binName, lf))
+	log.SetLevel(logging.LogLevelDebug)
 }
 
 func checkParentName(log logging.Logger) {
@@ -78,6 +92,63 @@ func checkParentName(log logging.Logger) {
 	}
 }
 
+func persistentMode(log logging.Logger) bool {
+	val, set := os.LookupEnv(pbin.PersistentHelperEnvVar)
+	if !set {
+		return false
+	}
+
+	persistent, err := strconv.ParseBool(val)
+	if err != nil {
+		exitWithError(log, errors.Wrapf(err, "invalid %s value", pbin.PersistentHelperEnvVar))
+	}
+	return persistent
+}
+
+// privateStdout moves stdout to a new descriptor which is only used for
+// responses, and points the original at /dev/null so that anything the
+// storage libraries print can't corrupt the framed response stream.
+func privateStdout() (*os.File, error) {
+	fd, err := syscall.Dup(syscall.Stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer devNull.Close()
+
+	if err := syscall.Dup2(int(devNull.Fd()), syscall.Stdout); err != nil {
+		return nil, err
+	}
+
+	return os.NewFile(uintptr(fd), "daos_server"), nil
+}
+
+// killProcessGroup kills this process along with anything it has started.
+// daos_server starts it in its own process group, so that group is killed;
+// otherwise only this process is.
+func killProcessGroup() {
+	pid := os.Getpid()
+	if syscall.Getpgrp() == pid {
+		pid = 0
+	}
+	syscall.Kill(pid, syscall.SIGKILL)
+}
+
+// serveRequests handles requests until daos_server closes the channel.
+func serveRequests(binName string, rh *requestHandler) error {
+	out, err := privateStdout()
+	if err != nil {
+		return errors.Wrap(err, "failed to set up response channel")
+	}
+	conn := pbin.NewStdioConn(binName, "daos_server", os.Stdin, out)
+
+	return pbin.ServeRequests(conn, rh.handle, rh.abort)
+}
+
 func main() {
 	binName := filepath.Base(os.Args[0])
 	log := configureLogging(binName)
@@ -88,20 +159,56 @@ func main() {
 		exitWithError(log, errors.Errorf("%s not setuid root", binName))
 	}
 
+	// setuid(0) also changes the real uid, so note the caller's first.
+	callerUID := os.Getuid()
+
 	// hack for stuff that doesn't use geteuid() (e.g. ipmctl)
 	if err := setuid(0); err != nil {
 		exitWithError(log, errors.Wrap(err, "unable to setuid(0)"))
 	}
 
+	callerName := ""
+	if caller, err := user.LookupId(strconv.Itoa(callerUID)); err != nil {
+		log.Errorf("unable to look up caller uid %d: %s", callerUID, err)
+	} else {
+		callerName = caller.Username
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Errorf("%s; requests for configured storage will be rejected", err)
+		cfg = server.NewConfiguration()
+	}
+	configureLogFile(log, binName, cfg)
+	pol := newPolicy(cfg, callerName)
+
+	// The providers are created for each request so that nothing is
+	// cached between requests served by a persistent helper.
+	rh := &requestHandler{
+		log:    log,
+		policy: pol,
+		audit:  newAuditLog(log, binName, callerUID),
+		newProviders: func() (*scm.Provider, *bdev.Provider) {
+			return scm.DefaultProvider(log).WithForwardingDisabled(),
+				bdev.DefaultProvider(log).WithForwardingDisabled()
+		},
+		killGroup: killProcessGroup,
+	}
+
+	if persistentMode(log) {
+		if err := serveRequests(binName, rh); err != nil {
+			exitWithError(log, err)
+		}
+		return
+	}
+
 	conn := pbin.NewStdioConn(binName, "daos_server", os.Stdin, os.Stdout)
 	req, err := readRequest(log, conn)
 	if err != nil {
 		exitWithError(log, err)
 	}
 
-	scmProvider := scm.DefaultProvider(log).WithForwardingDisabled()
-	bdevProvider := bdev.DefaultProvider(log).WithForwardingDisabled()
-	if err := handleRequest(log, scmProvider, bdevProvider, req, conn); err != nil {
+	if err := pbin.ServeRequest(conn, req, pbin.InputClosed(os.Stdin), rh.handle, rh.abort); err != nil {
 		exitWithError(log, err)
 	}
 }
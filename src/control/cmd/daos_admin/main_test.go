@@ -0,0 +1,71 @@
+//
+// (C) Copyright 2020 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/daos-stack/daos/src/control/server"
+)
+
+func TestConfigureLogFile(t *testing.T) {
+	testDir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+
+	for name, tc := range map[string]struct {
+		logFile  string
+		expDebug bool
+	}{
+		"no helper log file": {},
+		"helper log file": {
+			logFile:  filepath.Join(testDir, "daos_admin.log"),
+			expDebug: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log := configureLogging("daos_admin")
+			cfg := server.NewConfiguration().WithHelperLogFile(tc.logFile)
+
+			configureLogFile(log, "daos_admin", cfg)
+			log.Debug("debug message")
+
+			if !tc.expDebug {
+				return
+			}
+			data, err := ioutil.ReadFile(tc.logFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), "debug message") {
+				t.Fatalf("expected debug message in %s, got %q", tc.logFile, data)
+			}
+		})
+	}
+}
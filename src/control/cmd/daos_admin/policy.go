@@ -0,0 +1,317 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/pbin"
+	"github.com/daos-stack/daos/src/control/server"
+	"github.com/daos-stack/daos/src/control/server/storage"
+	"github.com/daos-stack/daos/src/control/server/storage/bdev"
+	"github.com/daos-stack/daos/src/control/server/storage/scm"
+)
+
+type (
+	// scmTarget describes an SCM mountpoint permitted by the policy.
+	scmTarget struct {
+		class   storage.ScmClass
+		devices map[string]bool
+	}
+
+	// policy limits the storage that may be acted upon by requests
+	// to that which is described in the daos_server config.
+	policy struct {
+		scmTargets    map[string]*scmTarget
+		dcpmDevices   map[string]bool
+		bdevDevices   map[string]bool
+		pciAddrs      map[string]bool
+		serverUser    string
+		allowScmReset bool
+	}
+
+	policyCheck func(*policy, *pbin.Request) error
+)
+
+// methodChecks is the allow-list of methods which may be invoked, along
+// with the checks to be made on their payloads.
+var methodChecks = map[string]policyCheck{
+	"ScmMount":       (*policy).checkMount,
+	"ScmUnmount":     (*policy).checkMount,
+	"ScmFormat":      (*policy).checkScmFormat,
+	"ScmCheckFormat": (*policy).checkScmFormat,
+	"ScmScan":        allowAll,
+	"ScmPrepare":     (*policy).checkScmPrepare,
+	"BdevInit":       allowAll,
+	"BdevScan":       allowAll,
+	"BdevPrepare":    (*policy).checkBdevPrepare,
+	"BdevFormat":     (*policy).checkBdevFormat,
+}
+
+func allowAll(_ *policy, _ *pbin.Request) error {
+	return nil
+}
+
+// newPolicy builds a policy which permits the storage configured for the
+// I/O servers in cfg, as well as any NVMe devices in its bdev_include list.
+// NVMe devices may only be prepared for the configured user_name or, if it
+// is not set, for callerName, the user running daos_server.
+func newPolicy(cfg *server.Configuration, callerName string) *policy {
+	p := &policy{
+		scmTargets:    make(map[string]*scmTarget),
+		dcpmDevices:   make(map[string]bool),
+		bdevDevices:   make(map[string]bool),
+		pciAddrs:      make(map[string]bool),
+		serverUser:    cfg.UserName,
+		allowScmReset: cfg.AllowScmReset,
+	}
+	if p.serverUser == "" {
+		p.serverUser = callerName
+	}
+
+	for _, srv := range cfg.Servers {
+		scmCfg := srv.Storage.SCM
+		if scmCfg.MountPoint != "" {
+			tgt := &scmTarget{
+				class:   scmCfg.Class,
+				devices: make(map[string]bool),
+			}
+			for _, dev := range scmCfg.DeviceList {
+				tgt.devices[dev] = true
+				if scmCfg.Class == storage.ScmClassDCPM {
+					p.dcpmDevices[dev] = true
+				}
+			}
+			p.scmTargets[filepath.Clean(scmCfg.MountPoint)] = tgt
+		}
+
+		bdevCfg := srv.Storage.Bdev
+		for _, dev := range bdevCfg.DeviceList {
+			p.bdevDevices[dev] = true
+			if bdevCfg.Class == storage.BdevClassNvme {
+				p.pciAddrs[dev] = true
+			}
+		}
+	}
+
+	for _, addr := range cfg.BdevInclude {
+		p.pciAddrs[addr] = true
+	}
+
+	return p
+}
+
+// loadConfig loads the daos_server config installed alongside this binary,
+// which supplies both the policy and the helper's logging settings. The
+// caller controls which config daos_server actually runs with, so only a
+// file which is owned by root and can't be modified by other users is
+// trusted.
+func loadConfig() (*server.Configuration, error) {
+	cfg := server.NewConfiguration()
+	if err := cfg.SetPath(""); err != nil {
+		return nil, errors.Wrap(err, "failed to resolve daos_server config path")
+	}
+
+	if err := checkPolicyFile(cfg.Path); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Load(); err != nil {
+		return nil, errors.Wrapf(err, "failed to load %s", cfg.Path)
+	}
+
+	return cfg, nil
+}
+
+// checkPolicyFile ensures that the policy file is owned by root and is not
+// writable by any other user.
+func checkPolicyFile(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return errors.Wrap(err, "unable to check policy file")
+	}
+
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return errors.Errorf("unable to determine owner of %s", path)
+	}
+	if st.Uid != 0 {
+		return errors.Errorf("policy file %s is not owned by root", path)
+	}
+	if fi.Mode().Perm()&0022 != 0 {
+		return errors.Errorf("policy file %s is writable by non-root users", path)
+	}
+
+	return nil
+}
+
+// checkRequest returns an error if the request is not permitted by
+// the policy.
+func (p *policy) checkRequest(req *pbin.Request) error {
+	if req == nil {
+		return errors.New("nil request")
+	}
+
+	check, found := methodChecks[req.Method]
+	if !found {
+		return errors.Errorf("method %q is not permitted", req.Method)
+	}
+
+	if err := check(p, req); err != nil {
+		return errors.Wrapf(err, "%s request rejected by policy", req.Method)
+	}
+
+	return nil
+}
+
+func (p *policy) scmTarget(mountPoint string) (*scmTarget, error) {
+	tgt, found := p.scmTargets[filepath.Clean(mountPoint)]
+	if mountPoint == "" || !found {
+		return nil, errors.Errorf("mountpoint %q is not configured", mountPoint)
+	}
+
+	return tgt, nil
+}
+
+func (p *policy) checkMount(req *pbin.Request) error {
+	var mReq scm.MountRequest
+	if err := json.Unmarshal(req.Payload, &mReq); err != nil {
+		return err
+	}
+
+	tgt, err := p.scmTarget(mReq.Target)
+	if err != nil {
+		return err
+	}
+
+	// Only the target is needed to unmount.
+	if req.Method == "ScmUnmount" {
+		return nil
+	}
+
+	switch tgt.class {
+	case storage.ScmClassRAM:
+		if mReq.Source != "tmpfs" || mReq.FsType != "tmpfs" {
+			return errors.Errorf("mountpoint %q may only be used for a ramdisk", mReq.Target)
+		}
+	default:
+		if !tgt.devices[mReq.Source] {
+			return errors.Errorf("device %q is not configured for mountpoint %q",
+				mReq.Source, mReq.Target)
+		}
+	}
+
+	return nil
+}
+
+func (p *policy) checkScmFormat(req *pbin.Request) error {
+	var fReq scm.FormatRequest
+	if err := json.Unmarshal(req.Payload, &fReq); err != nil {
+		return err
+	}
+
+	tgt, err := p.scmTarget(fReq.Mountpoint)
+	if err != nil {
+		return err
+	}
+
+	if fReq.Ramdisk != nil && tgt.class != storage.ScmClassRAM {
+		return errors.Errorf("mountpoint %q is not configured for a ramdisk", fReq.Mountpoint)
+	}
+	if fReq.Dcpm != nil && !tgt.devices[fReq.Dcpm.Device] {
+		return errors.Errorf("device %q is not configured for mountpoint %q",
+			fReq.Dcpm.Device, fReq.Mountpoint)
+	}
+
+	return nil
+}
+
+// checkScmPrepare permits SCM modules to be prepared only when DCPM devices
+// are configured, as preparing them creates namespaces on every module.
+// Resetting the modules destroys those namespaces, so it must be enabled
+// explicitly.
+func (p *policy) checkScmPrepare(req *pbin.Request) error {
+	var pReq scm.PrepareRequest
+	if err := json.Unmarshal(req.Payload, &pReq); err != nil {
+		return err
+	}
+
+	if len(p.dcpmDevices) == 0 {
+		return errors.New("no DCPM devices are configured")
+	}
+	if pReq.Reset && !p.allowScmReset {
+		return errors.New("SCM reset is not enabled by allow_scm_reset")
+	}
+
+	return nil
+}
+
+// checkBdevPrepare permits only the configured NVMe devices to be prepared.
+// An empty whitelist would prepare every NVMe device in the host, so it is
+// rejected.
+func (p *policy) checkBdevPrepare(req *pbin.Request) error {
+	var pReq bdev.PrepareRequest
+	if err := json.Unmarshal(req.Payload, &pReq); err != nil {
+		return err
+	}
+
+	if p.serverUser == "" || pReq.TargetUser != p.serverUser {
+		return errors.Errorf("target user %q is not the daos_server user %q",
+			pReq.TargetUser, p.serverUser)
+	}
+
+	addrs := strings.FieldsFunc(pReq.PCIWhitelist, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	if len(addrs) == 0 {
+		return errors.New("PCI whitelist must list the configured NVMe devices")
+	}
+	for _, addr := range addrs {
+		if !p.pciAddrs[addr] {
+			return errors.Errorf("PCI address %q is not configured", addr)
+		}
+	}
+
+	return nil
+}
+
+func (p *policy) checkBdevFormat(req *pbin.Request) error {
+	var fReq bdev.FormatRequest
+	if err := json.Unmarshal(req.Payload, &fReq); err != nil {
+		return err
+	}
+
+	for _, dev := range fReq.DeviceList {
+		if !p.bdevDevices[dev] {
+			return errors.Errorf("device %q is not configured", dev)
+		}
+	}
+
+	return nil
+}
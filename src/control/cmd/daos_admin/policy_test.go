@@ -0,0 +1,484 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/pbin"
+	"github.com/daos-stack/daos/src/control/server"
+	"github.com/daos-stack/daos/src/control/server/ioserver"
+	"github.com/daos-stack/daos/src/control/server/storage/bdev"
+	"github.com/daos-stack/daos/src/control/server/storage/scm"
+)
+
+type testAuditWriter struct {
+	notices  []string
+	warnings []string
+}
+
+func (w *testAuditWriter) Notice(msg string) error {
+	w.notices = append(w.notices, msg)
+	return nil
+}
+
+func (w *testAuditWriter) Warning(msg string) error {
+	w.warnings = append(w.warnings, msg)
+	return nil
+}
+
+func testConfig() *server.Configuration {
+	return server.NewConfiguration().WithServers(
+		ioserver.NewConfig().
+			WithScmClass("dcpm").
+			WithScmMountPoint("/mnt/daos0").
+			WithScmDeviceList("/dev/pmem0").
+			WithBdevClass("nvme").
+			WithBdevDeviceList("0000:81:00.0"),
+		ioserver.NewConfig().
+			WithScmClass("ram").
+			WithScmMountPoint("/mnt/daos1").
+			WithBdevClass("file").
+			WithBdevDeviceList("/tmp/daos-bdev"),
+	).WithBdevInclude("0000:82:00.0")
+}
+
+func testPolicy() *policy {
+	return newPolicy(testConfig(), "daosuser")
+}
+
+func testRequest(t *testing.T, method string, payload interface{}) *pbin.Request {
+	t.Helper()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &pbin.Request{
+		Method:  method,
+		Payload: data,
+	}
+}
+
+func TestPolicy_CheckRequest(t *testing.T) {
+	for name, tc := range map[string]struct {
+		method  string
+		payload interface{}
+		expErr  error
+	}{
+		"unknown method": {
+			method: "RmRf",
+			expErr: errors.New("method \"RmRf\" is not permitted"),
+		},
+		"scan": {
+			method:  "ScmScan",
+			payload: scm.ScanRequest{},
+		},
+		"mount dcpm": {
+			method: "ScmMount",
+			payload: scm.MountRequest{
+				Source: "/dev/pmem0",
+				Target: "/mnt/daos0/",
+				FsType: "ext4",
+			},
+		},
+		"mount unconfigured device": {
+			method: "ScmMount",
+			payload: scm.MountRequest{
+				Source: "/dev/sda1",
+				Target: "/mnt/daos0",
+				FsType: "ext4",
+			},
+			expErr: errors.New("device \"/dev/sda1\" is not configured"),
+		},
+		"mount unconfigured target": {
+			method: "ScmMount",
+			payload: scm.MountRequest{
+				Source: "/dev/pmem0",
+				Target: "/etc",
+				FsType: "ext4",
+			},
+			expErr: errors.New("mountpoint \"/etc\" is not configured"),
+		},
+		"mount ramdisk": {
+			method: "ScmMount",
+			payload: scm.MountRequest{
+				Source: "tmpfs",
+				Target: "/mnt/daos1",
+				FsType: "tmpfs",
+			},
+		},
+		"mount device on ramdisk target": {
+			method: "ScmMount",
+			payload: scm.MountRequest{
+				Source: "/dev/pmem0",
+				Target: "/mnt/daos1",
+				FsType: "ext4",
+			},
+			expErr: errors.New("may only be used for a ramdisk"),
+		},
+		"unmount configured target": {
+			method: "ScmUnmount",
+			payload: scm.MountRequest{
+				Target: "/mnt/daos1",
+			},
+		},
+		"unmount unconfigured target": {
+			method: "ScmUnmount",
+			payload: scm.MountRequest{
+				Target: "/home",
+			},
+			expErr: errors.New("mountpoint \"/home\" is not configured"),
+		},
+		"format dcpm": {
+			method: "ScmFormat",
+			payload: scm.FormatRequest{
+				Mountpoint: "/mnt/daos0",
+				Dcpm:       &scm.DcpmParams{Device: "/dev/pmem0"},
+			},
+		},
+		"format unconfigured dcpm device": {
+			method: "ScmFormat",
+			payload: scm.FormatRequest{
+				Mountpoint: "/mnt/daos0",
+				Dcpm:       &scm.DcpmParams{Device: "/dev/sda"},
+			},
+			expErr: errors.New("device \"/dev/sda\" is not configured"),
+		},
+		"format ramdisk on dcpm target": {
+			method: "ScmFormat",
+			payload: scm.FormatRequest{
+				Mountpoint: "/mnt/daos0",
+				Ramdisk:    &scm.RamdiskParams{Size: 1},
+			},
+			expErr: errors.New("not configured for a ramdisk"),
+		},
+		"check format with empty mountpoint": {
+			method:  "ScmCheckFormat",
+			payload: scm.FormatRequest{},
+			expErr:  errors.New("mountpoint \"\" is not configured"),
+		},
+		"prepare all NVMe": {
+			method: "BdevPrepare",
+			payload: bdev.PrepareRequest{
+				TargetUser: "daosuser",
+			},
+			expErr: errors.New("PCI whitelist must list the configured NVMe devices"),
+		},
+		"prepare configured NVMe": {
+			method: "BdevPrepare",
+			payload: bdev.PrepareRequest{
+				PCIWhitelist: "0000:81:00.0,0000:82:00.0",
+				TargetUser:   "daosuser",
+			},
+		},
+		"prepare unconfigured NVMe": {
+			method: "BdevPrepare",
+			payload: bdev.PrepareRequest{
+				PCIWhitelist: "0000:81:00.0 0000:83:00.0",
+				TargetUser:   "daosuser",
+			},
+			expErr: errors.New("PCI address \"0000:83:00.0\" is not configured"),
+		},
+		"prepare NVMe for another user": {
+			method: "BdevPrepare",
+			payload: bdev.PrepareRequest{
+				PCIWhitelist: "0000:81:00.0",
+				TargetUser:   "root",
+			},
+			expErr: errors.New("target user \"root\" is not the daos_server user \"daosuser\""),
+		},
+		"prepare NVMe without target user": {
+			method: "BdevPrepare",
+			payload: bdev.PrepareRequest{
+				PCIWhitelist: "0000:81:00.0",
+			},
+			expErr: errors.New("target user \"\" is not the daos_server user"),
+		},
+		"prepare SCM": {
+			method:  "ScmPrepare",
+			payload: scm.PrepareRequest{},
+		},
+		"reset SCM": {
+			method: "ScmPrepare",
+			payload: scm.PrepareRequest{
+				Reset: true,
+			},
+			expErr: errors.New("SCM reset is not enabled by allow_scm_reset"),
+		},
+		"format configured bdevs": {
+			method: "BdevFormat",
+			payload: bdev.FormatRequest{
+				DeviceList: []string{"0000:81:00.0", "/tmp/daos-bdev"},
+			},
+		},
+		"format unconfigured bdev": {
+			method: "BdevFormat",
+			payload: bdev.FormatRequest{
+				DeviceList: []string{"0000:82:00.0"},
+			},
+			expErr: errors.New("device \"0000:82:00.0\" is not configured"),
+		},
+		"malformed payload": {
+			method:  "BdevFormat",
+			payload: "garbage",
+			expErr:  errors.New("cannot unmarshal"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotErr := testPolicy().checkRequest(testRequest(t, tc.method, tc.payload))
+			common.CmpErr(t, tc.expErr, gotErr)
+		})
+	}
+}
+
+func TestPolicy_Empty(t *testing.T) {
+	p := newPolicy(server.NewConfiguration(), "")
+
+	common.CmpErr(t, nil, p.checkRequest(testRequest(t, "BdevScan", bdev.ScanRequest{})))
+	common.CmpErr(t, errors.New("is not configured"),
+		p.checkRequest(testRequest(t, "ScmUnmount", scm.MountRequest{Target: "/"})))
+	common.CmpErr(t, errors.New("no DCPM devices are configured"),
+		p.checkRequest(testRequest(t, "ScmPrepare", scm.PrepareRequest{})))
+	common.CmpErr(t, errors.New("is not the daos_server user"),
+		p.checkRequest(testRequest(t, "BdevPrepare", bdev.PrepareRequest{})))
+}
+
+func TestPolicy_ServerUser(t *testing.T) {
+	for name, tc := range map[string]struct {
+		userName   string
+		callerName string
+		expUser    string
+	}{
+		"caller": {
+			callerName: "daos_caller",
+			expUser:    "daos_caller",
+		},
+		"configured user": {
+			userName:   "daosuser",
+			callerName: "daos_caller",
+			expUser:    "daosuser",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := newPolicy(testConfig().WithUserName(tc.userName), tc.callerName)
+			common.AssertEqual(t, tc.expUser, p.serverUser, "unexpected daos_server user")
+		})
+	}
+}
+
+func TestPolicy_ScmReset(t *testing.T) {
+	for name, tc := range map[string]struct {
+		allowReset bool
+		expErr     error
+	}{
+		"not allowed": {
+			expErr: errors.New("SCM reset is not enabled"),
+		},
+		"allowed": {
+			allowReset: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := newPolicy(testConfig().WithAllowScmReset(tc.allowReset), "daosuser")
+
+			gotErr := p.checkRequest(testRequest(t, "ScmPrepare", scm.PrepareRequest{Reset: true}))
+			common.CmpErr(t, tc.expErr, gotErr)
+		})
+	}
+}
+
+func TestCheckPolicyFile(t *testing.T) {
+	testDir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+
+	for name, tc := range map[string]struct {
+		mode   os.FileMode
+		noFile bool
+		expErr error
+	}{
+		"missing": {
+			noFile: true,
+			expErr: errors.New("no such file"),
+		},
+		"read-only": {
+			mode: 0644,
+		},
+		"world-writable": {
+			mode:   0646,
+			expErr: errors.New("writable by non-root users"),
+		},
+		"group-writable": {
+			mode:   0664,
+			expErr: errors.New("writable by non-root users"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(testDir, name)
+			if !tc.noFile {
+				if err := ioutil.WriteFile(path, nil, tc.mode); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.Chmod(path, tc.mode); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Files created by other users fail the ownership check first.
+			if os.Geteuid() != 0 && !tc.noFile {
+				tc.expErr = errors.New("is not owned by root")
+			}
+
+			common.CmpErr(t, tc.expErr, checkPolicyFile(path))
+		})
+	}
+}
+
+func TestRequestHandler(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req         *pbin.Request
+		expErr      error
+		expResErr   error
+		expNotices  int
+		expWarnings int
+	}{
+		"nil request": {
+			expErr: errors.New("nil request"),
+		},
+		"allowed": {
+			req: &pbin.Request{
+				Method:  "BdevScan",
+				Payload: []byte(`{}`),
+			},
+			expNotices: 1,
+		},
+		"denied": {
+			req: &pbin.Request{
+				Method:  "ScmUnmount",
+				Payload: []byte(`{"Target":"/"}`),
+			},
+			expResErr:   errors.New("ScmUnmount request rejected by policy"),
+			expWarnings: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(name)
+			defer common.ShowBufferOnFailure(t, buf)
+
+			aw := &testAuditWriter{}
+			rh := &requestHandler{
+				log:    log,
+				policy: testPolicy(),
+				audit: &auditLog{
+					log: log,
+					out: aw,
+					uid: 1234,
+				},
+				newProviders: func() (*scm.Provider, *bdev.Provider) {
+					return scm.NewMockProvider(log, nil, nil), bdev.NewMockProvider(log, nil)
+				},
+			}
+
+			var destBuf bytes.Buffer
+			gotErr := rh.handle(context.Background(), tc.req, &destBuf)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			gotRes := parseResponse(t, &destBuf)
+			var gotResErr error
+			if gotRes.Error != nil {
+				gotResErr = gotRes.Error
+			}
+			common.CmpErr(t, tc.expResErr, gotResErr)
+
+			common.AssertEqual(t, tc.expNotices, len(aw.notices), "unexpected number of allowed records")
+			common.AssertEqual(t, tc.expWarnings, len(aw.warnings), "unexpected number of denied records")
+			for _, msg := range append(aw.notices, aw.warnings...) {
+				common.AssertTrue(t, bytes.Contains([]byte(msg), []byte("uid=1234 method="+tc.req.Method)),
+					"audit record missing caller: "+msg)
+			}
+		})
+	}
+}
+
+func TestRequestHandler_Expired(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	// The providers don't return until the process group would have
+	// been killed, standing in for a request which never completes.
+	killed := make(chan struct{})
+	rh := &requestHandler{
+		log:    log,
+		policy: testPolicy(),
+		audit: &auditLog{
+			log: log,
+			out: &testAuditWriter{},
+		},
+		newProviders: func() (*scm.Provider, *bdev.Provider) {
+			<-killed
+			return scm.NewMockProvider(log, nil, nil), bdev.NewMockProvider(log, nil)
+		},
+		killGroup: func() {
+			close(killed)
+		},
+	}
+
+	req := &pbin.Request{
+		Method:   "BdevScan",
+		Payload:  []byte(`{}`),
+		Deadline: time.Now().Add(100 * time.Millisecond),
+	}
+
+	var destBuf bytes.Buffer
+	if err := pbin.ServeRequest(&destBuf, req, nil, rh.handle, rh.abort); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-killed:
+	default:
+		t.Fatal("expected process group to be killed when the deadline passed")
+	}
+
+	// daos_server is told why there was no response before the exit.
+	gotRes := parseResponse(t, &destBuf)
+	common.AssertTrue(t, gotRes.Expired, "expected the response to be marked as expired")
+}
@@ -0,0 +1,236 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/lib/hostlist"
+	"github.com/daos-stack/daos/src/control/security"
+)
+
+const (
+	caCommonName = "DAOS CA"
+	// minKeyBits is the smallest RSA key size still considered secure.
+	minKeyBits = 2048
+)
+
+type certCmd struct {
+	Generate certGenerateCmd `command:"generate" description:"Generate a CA and the certificates used by DAOS components"`
+}
+
+type certGenerateCmd struct {
+	logCmd
+	OutDir  string `short:"o" long:"out" required:"1" description:"Directory to write the generated certificates into"`
+	Hosts   string `short:"l" long:"hosts" description:"Hostlist of servers to generate host certificates for, e.g. node[1-64]"`
+	Days    int    `long:"days" default:"365" description:"Number of days the generated certificates are valid for"`
+	KeyBits int    `long:"key-bits" default:"4096" description:"Size of the generated RSA keys, at least 2048"`
+	CertDir string `long:"cert-dir" default:"/etc/daos/certs" description:"Directory the certificates will be installed in, used in generated config snippets"`
+}
+
+// certFiles are the paths of a certificate and its key relative to the
+// output directory.
+type certFiles struct {
+	cert, key string
+}
+
+var (
+	caFiles     = certFiles{"daosCA.crt", "private/daosCA.key"}
+	adminFiles  = certFiles{"admin.crt", "admin.key"}
+	agentFiles  = certFiles{"agent.crt", "agent.key"}
+	serverFiles = certFiles{"server.crt", "server.key"}
+	// the server identifies agents by the certificate in its client
+	// certificate directory named after the agent's auth origin
+	clientsDir      = "clients"
+	clientAgentCert = filepath.Join(clientsDir, "agent.crt")
+	hostsDir        = "hosts"
+	snippetsDir     = "config"
+)
+
+func hostFiles(host string) certFiles {
+	dir := filepath.Join(hostsDir, host)
+	return certFiles{filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")}
+}
+
+func (cmd *certGenerateCmd) mkdir(rel string) error {
+	return os.MkdirAll(filepath.Join(cmd.OutDir, rel), security.MaxDirPerm)
+}
+
+func (cmd *certGenerateCmd) write(kp *security.CertKeyPair, files certFiles) error {
+	if err := cmd.mkdir(filepath.Dir(files.key)); err != nil {
+		return err
+	}
+	if err := cmd.mkdir(filepath.Dir(files.cert)); err != nil {
+		return err
+	}
+
+	return kp.Write(filepath.Join(cmd.OutDir, files.cert),
+		filepath.Join(cmd.OutDir, files.key))
+}
+
+func (cmd *certGenerateCmd) hosts() ([]string, error) {
+	if cmd.Hosts == "" {
+		return nil, nil
+	}
+
+	hl, err := hostlist.Create(cmd.Hosts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid hostlist %q", cmd.Hosts)
+	}
+
+	return strings.Split(hl.DerangedString(), ","), nil
+}
+
+// Execute generates a CA along with admin, agent and server certificates
+// signed by it, plus a server certificate for each host in the hostlist.
+// Snippets of the transport configuration for each component are written
+// alongside the certificates.
+func (cmd *certGenerateCmd) Execute(args []string) error {
+	if cmd.Days <= 0 {
+		return errors.New("--days must be positive")
+	}
+	validity := time.Duration(cmd.Days) * 24 * time.Hour
+	if cmd.KeyBits < minKeyBits {
+		return errors.Errorf("--key-bits %d is too small, must be at least %d",
+			cmd.KeyBits, minKeyBits)
+	}
+
+	hosts, err := cmd.hosts()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(cmd.OutDir, security.MaxDirPerm); err != nil {
+		return err
+	}
+	if err := security.ValidateCertDirectory(cmd.OutDir); err != nil {
+		return err
+	}
+
+	cmd.log.Infof("Generating CA certificate in %s", cmd.OutDir)
+	ca, err := security.GenerateCA(caCommonName, cmd.KeyBits, validity)
+	if err != nil {
+		return errors.Wrap(err, "generate CA")
+	}
+	if err := cmd.write(ca, caFiles); err != nil {
+		return err
+	}
+
+	for _, c := range []struct {
+		role     string
+		dnsNames []string
+		files    certFiles
+	}{
+		{security.RoleAdmin, nil, adminFiles},
+		{security.RoleAgent, nil, agentFiles},
+		{security.RoleServer, []string{security.RoleServer}, serverFiles},
+	} {
+		cmd.log.Debugf("Generating %s certificate", c.role)
+		kp, err := ca.Issue(c.role, c.role, c.dnsNames, cmd.KeyBits, validity)
+		if err != nil {
+			return errors.Wrapf(err, "generate %s certificate", c.role)
+		}
+		if err := cmd.write(kp, c.files); err != nil {
+			return err
+		}
+		if c.role == security.RoleAgent {
+			if err := cmd.mkdir(clientsDir); err != nil {
+				return err
+			}
+			if err := kp.WriteCert(filepath.Join(cmd.OutDir, clientAgentCert)); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, host := range hosts {
+		cmd.log.Debugf("Generating server certificate for %s", host)
+		// servers are dialed by the expected server name, so the
+		// certificate must be valid for it as well as the hostname
+		kp, err := ca.Issue(host, security.RoleServer,
+			[]string{security.RoleServer, host}, cmd.KeyBits, validity)
+		if err != nil {
+			return errors.Wrapf(err, "generate server certificate for %s", host)
+		}
+		if err := cmd.write(kp, hostFiles(host)); err != nil {
+			return err
+		}
+	}
+	if len(hosts) > 0 {
+		cmd.log.Infof("Generated server certificates for %d hosts", len(hosts))
+	}
+
+	if err := cmd.writeSnippets(); err != nil {
+		return err
+	}
+
+	cmd.log.Infof("Certificates and config snippets written to %s", cmd.OutDir)
+
+	return nil
+}
+
+const serverSnippet = `# daos_server.yml
+# On each host install %[2]s/server.* from %[1]s/%[3]s/<host>/ if generated,
+# otherwise %[1]s/server.*.
+transport_config:
+  allow_insecure: false
+  client_cert_dir: %[2]s/clients
+  ca_cert: %[2]s/daosCA.crt
+  cert: %[2]s/server.crt
+  key: %[2]s/server.key
+`
+
+const clientSnippet = `# %[1]s
+transport_config:
+  allow_insecure: false
+  ca_cert: %[2]s/daosCA.crt
+  cert: %[2]s/%[3]s.crt
+  key: %[2]s/%[3]s.key
+  server_name: server
+`
+
+func (cmd *certGenerateCmd) writeSnippets() error {
+	if err := cmd.mkdir(snippetsDir); err != nil {
+		return err
+	}
+
+	for name, contents := range map[string]string{
+		"daos_server.yml": fmt.Sprintf(serverSnippet, cmd.OutDir, cmd.CertDir, hostsDir),
+		"daos_agent.yml":  fmt.Sprintf(clientSnippet, "daos_agent.yml", cmd.CertDir, security.RoleAgent),
+		"daos.yml":        fmt.Sprintf(clientSnippet, "daos.yml (dmg)", cmd.CertDir, security.RoleAdmin),
+	} {
+		path := filepath.Join(cmd.OutDir, snippetsDir, name)
+		if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+			return errors.Wrapf(err, "write %s", path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
+)
+
+func TestCertGenerate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		args     []string
+		expHosts []string
+		expErr   error
+	}{
+		"no hosts": {},
+		"host range": {
+			args:     []string{"--hosts", "node[1-3]"},
+			expHosts: []string{"node1", "node2", "node3"},
+		},
+		"bad hostlist": {
+			args:   []string{"--hosts", "node[3-1]"},
+			expErr: errors.New("invalid hostlist"),
+		},
+		"bad days": {
+			args:   []string{"--days", "0"},
+			expErr: errors.New("--days must be positive"),
+		},
+		"key too small": {
+			args:   []string{"--key-bits", "1024"},
+			expErr: errors.New("--key-bits 1024 is too small, must be at least 2048"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tmpDir, cleanup := common.CreateTestDir(t)
+			defer cleanup()
+			outDir := filepath.Join(tmpDir, "certs")
+
+			var logBuf bytes.Buffer
+			log := logging.NewCombinedLogger(t.Name(), &logBuf)
+
+			var opts mainOpts
+			args := append([]string{"cert", "generate", "--out", outDir,
+				"--key-bits", "2048"}, tc.args...)
+			err := parseOpts(args, &opts, log)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			if err := security.ValidateCertDirectory(outDir); err != nil {
+				t.Fatal(err)
+			}
+
+			ca, err := security.LoadCertificate(filepath.Join(outDir, caFiles.cert))
+			if err != nil {
+				t.Fatal(err)
+			}
+			roots := x509.NewCertPool()
+			roots.AddCert(ca)
+
+			verify := func(files certFiles, dnsName string, usage x509.ExtKeyUsage) {
+				t.Helper()
+
+				cert, err := security.LoadCertificate(filepath.Join(outDir, files.cert))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := security.LoadPrivateKey(filepath.Join(outDir, files.key)); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := cert.Verify(x509.VerifyOptions{
+					DNSName:   dnsName,
+					Roots:     roots,
+					KeyUsages: []x509.ExtKeyUsage{usage},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			verify(adminFiles, "", x509.ExtKeyUsageClientAuth)
+			verify(agentFiles, "", x509.ExtKeyUsageClientAuth)
+			verify(serverFiles, "server", x509.ExtKeyUsageServerAuth)
+			for _, host := range tc.expHosts {
+				verify(hostFiles(host), host, x509.ExtKeyUsageServerAuth)
+			}
+
+			if _, err := security.LoadCertificate(filepath.Join(outDir, clientAgentCert)); err != nil {
+				t.Fatal(err)
+			}
+
+			snippet, err := ioutil.ReadFile(filepath.Join(outDir, snippetsDir, "daos_agent.yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(snippet), "cert: /etc/daos/certs/agent.crt") {
+				t.Fatalf("unexpected agent config snippet:\n%s", snippet)
+			}
+		})
+	}
+}
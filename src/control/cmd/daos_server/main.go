@@ -54,6 +54,7 @@ type mainOpts struct {
 	Start   startCmd   `command:"start" description:"Start daos_server"`
 	Network networkCmd `command:"network" description:"Perform network device scan based on fabric provider"`
 	Version versionCmd `command:"version" description:"Print daos_server version"`
+	Cert    certCmd    `command:"cert" description:"Manage certificates used to secure the control plane"`
 }
 
 type versionCmd struct{}
@@ -24,11 +24,13 @@
 package main
 
 import (
+	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/pkg/errors"
 
-	"github.com/daos-stack/daos/src/control/common"
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/server"
 )
@@ -99,13 +101,18 @@ func (cmd *startCmd) configureLogging() error {
 	// Set log level mask for default logger from config,
 	// unless it was explicitly set to debug via CLI flag.
 	applyLogConfig := func() {
-		switch logging.LogLevel(cmd.config.ControlLogMask) {
+		mask := logging.LogMask(cmd.config.ControlLogMask)
+		switch mask.Level {
 		case logging.LogLevelDebug:
 			cmd.log.SetLevel(logging.LogLevelDebug)
 			cmd.log.Debugf("Switching control log level to DEBUG")
-		case logging.LogLevelError:
-			cmd.log.Debugf("Switching control log level to ERROR")
-			cmd.log.SetLevel(logging.LogLevelError)
+		case logging.LogLevelWarn, logging.LogLevelError:
+			cmd.log.Debugf("Switching control log level to %s", mask.Level)
+			cmd.log.SetLevel(mask.Level)
+		}
+		for subsystem, level := range mask.Subsystems {
+			cmd.log.Debugf("Switching %s log level to %s", subsystem, level)
+			cmd.log.SetSubsystemLevel(subsystem, level)
 		}
 
 		if cmd.config.ControlLogJSON {
@@ -126,11 +133,17 @@ func (cmd *startCmd) configureLogging() error {
 
 	// Set log file for default logger if specified in config.
 	if cmd.config.ControlLogFile != "" {
-		f, err := common.AppendFile(cmd.config.ControlLogFile)
+		f, err := logging.NewRotatingFile(cmd.config.ControlLogFile, cmd.config.LogRotation)
 		if err != nil {
 			return errors.WithMessage(err, "create log file")
 		}
 
+		// Reopen the log file on SIGHUP so that it can be rotated
+		// externally.
+		reopenSignals := make(chan os.Signal, 1)
+		signal.Notify(reopenSignals, syscall.SIGHUP)
+		go f.ReopenOn(context.Background(), reopenSignals)
+
 		cmd.log.Infof("%s logging to file %s",
 			os.Args[0], cmd.config.ControlLogFile)
 		// Create an additional set of loggers which append everything
@@ -138,6 +151,7 @@ func (cmd *startCmd) configureLogging() error {
 		cmd.log = cmd.log.
 			WithErrorLogger(logging.NewErrorLogger(hostname, f)).
 			WithInfoLogger(logging.NewInfoLogger(hostname, f)).
+			WithWarnLogger(logging.NewWarnLogger(hostname, f)).
 			WithDebugLogger(logging.NewDebugLogger(f))
 		applyLogConfig()
 
@@ -379,6 +379,27 @@ func TestStartLoggingConfiguration(t *testing.T) {
 			input:     "hello",
 			wantRe:    regexp.MustCompile(`hello`),
 		},
+		"Warn": {
+			configFn: func(cfg *server.Configuration) *server.Configuration {
+				return cfg.WithControlLogMask(server.ControlLogLevelWarn)
+			},
+			logFnName: "Info",
+			input:     "hello",
+			wantRe:    regexp.MustCompile(`^$`),
+		},
+		"Subsystem Debug Only": {
+			configFn: func(cfg *server.Configuration) *server.Configuration {
+				return cfg.WithControlLogMask(server.ControlLogLevel{
+					Level: logging.LogLevelInfo,
+					Subsystems: map[string]logging.LogLevel{
+						"drpc": logging.LogLevelDebug,
+					},
+				})
+			},
+			logFnName: "Debug",
+			input:     "hello",
+			wantRe:    regexp.MustCompile(`^$`),
+		},
 		"Error": {
 			configFn: func(cfg *server.Configuration) *server.Configuration {
 				return cfg.WithControlLogMask(server.ControlLogLevelError)
@@ -24,6 +24,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -63,6 +64,7 @@ func (cmd *storagePrepareCmd) Execute(args []string) error {
 		return err
 	}
 
+	ctx := context.Background()
 	cfg := server.NewConfiguration()
 	svc, err := server.DefaultStorageControlService(cmd.log, cfg)
 	if err != nil {
@@ -80,7 +82,7 @@ func (cmd *storagePrepareCmd) Execute(args []string) error {
 		cmd.log.Info(op + " locally-attached NVMe storage...")
 
 		// Prepare NVMe access through SPDK
-		if _, err := svc.NvmePrepare(bdev.PrepareRequest{
+		if _, err := svc.NvmePrepare(ctx, bdev.PrepareRequest{
 			HugePageCount: cmd.NrHugepages,
 			TargetUser:    cmd.TargetUser,
 			PCIWhitelist:  cmd.PCIWhiteList,
@@ -93,7 +95,7 @@ func (cmd *storagePrepareCmd) Execute(args []string) error {
 	if prepScm {
 		cmd.log.Info(op + " locally-attached SCM...")
 
-		state, err := svc.GetScmState()
+		state, err := svc.GetScmState(ctx)
 		if err != nil {
 			return concatErrors(scanErrors, err)
 		}
@@ -104,7 +106,7 @@ func (cmd *storagePrepareCmd) Execute(args []string) error {
 
 		// Prepare SCM modules to be presented as pmem device files.
 		// Pass evaluated state to avoid running GetScmState() twice.
-		resp, err := svc.ScmPrepare(scm.PrepareRequest{Reset: cmd.Reset})
+		resp, err := svc.ScmPrepare(ctx, scm.PrepareRequest{Reset: cmd.Reset})
 		if err != nil {
 			return concatErrors(scanErrors, err)
 		}
@@ -129,6 +131,7 @@ type storageScanCmd struct {
 }
 
 func (cmd *storageScanCmd) Execute(args []string) error {
+	ctx := context.Background()
 	svc, err := server.DefaultStorageControlService(cmd.log, server.NewConfiguration())
 	if err != nil {
 		return errors.WithMessage(err, "failed to init ControlService")
@@ -138,14 +141,14 @@ func (cmd *storageScanCmd) Execute(args []string) error {
 
 	scanErrors := make([]error, 0, 2)
 
-	res, err := svc.NvmeScan()
+	res, err := svc.NvmeScan(ctx)
 	if err != nil {
 		scanErrors = append(scanErrors, err)
 	} else {
 		cmd.log.Info(res.Controllers.String())
 	}
 
-	scmResp, err := svc.ScmScan()
+	scmResp, err := svc.ScmScan(ctx)
 	switch {
 	case err != nil:
 		scanErrors = append(scanErrors, err)
@@ -55,24 +55,51 @@ func isACLFileComment(line string) bool {
 
 // parseACL reads the content from io.Reader and puts the results into a
 // client.AccessControlList structure.
-// Assumes that ACE strings are provided one per line.
+// Assumes that ACE strings are provided one per line. Each entry is validated,
+// and errors are reported with the line number on which they were found.
 func parseACL(reader io.Reader) (*client.AccessControlList, error) {
 	aceList := make([]string, 0)
+	principalLines := make(map[string]int)
 	scanner := bufio.NewScanner(reader)
+	lineNum := 0
 	for scanner.Scan() {
 		if err := scanner.Err(); err != nil {
 			return nil, errors.WithMessage(err, "reading ACL file")
 		}
+		lineNum++
 
 		line := strings.TrimSpace(scanner.Text())
-		if line != "" && !isACLFileComment(line) {
-			aceList = append(aceList, line)
+		if line == "" || isACLFileComment(line) {
+			continue
 		}
+
+		ace, err := client.ParseACE(line)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "line %d", lineNum)
+		}
+
+		key := ace.PrincipalKey()
+		if firstLine, exists := principalLines[key]; exists {
+			return nil, errors.Errorf("line %d: duplicate entry for principal %s (first entry on line %d)",
+				lineNum, ace.Principal, firstLine)
+		}
+		principalLines[key] = lineNum
+
+		aceList = append(aceList, line)
 	}
 
 	return &client.AccessControlList{Entries: aceList}, nil
 }
 
+// checkACLHasOwner verifies that an ACL intended to replace a pool's ACL
+// contains an entry for the pool owner, so that the owner isn't locked out.
+func checkACLHasOwner(acl *client.AccessControlList) error {
+	if !acl.HasOwnerEntry() {
+		return errors.Errorf("ACL has no entry for %s", client.PrincipalOwner)
+	}
+	return nil
+}
+
 // formatACL converts the AccessControlList to a human-readable string.
 func formatACL(acl *client.AccessControlList) string {
 	var builder strings.Builder
@@ -97,3 +124,36 @@ func formatACL(acl *client.AccessControlList) string {
 
 	return builder.String()
 }
+
+// formatACLDiff converts the ACLDiff to a human-readable string.
+func formatACLDiff(diff *client.ACLDiff) string {
+	var builder strings.Builder
+
+	if diff.Empty() {
+		builder.WriteString("# No changes\n")
+		return builder.String()
+	}
+
+	if len(diff.Added) > 0 {
+		builder.WriteString("# Added:\n")
+		for _, ace := range diff.Added {
+			fmt.Fprintf(&builder, "+ %s\n", ace)
+		}
+	}
+
+	if len(diff.Changed) > 0 {
+		builder.WriteString("# Changed:\n")
+		for _, change := range diff.Changed {
+			fmt.Fprintf(&builder, "- %s\n+ %s\n", change.Old, change.New)
+		}
+	}
+
+	if len(diff.Removed) > 0 {
+		builder.WriteString("# Removed:\n")
+		for _, ace := range diff.Removed {
+			fmt.Fprintf(&builder, "- %s\n", ace)
+		}
+	}
+
+	return builder.String()
+}
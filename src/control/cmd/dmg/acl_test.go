@@ -97,7 +97,7 @@ func createTestFile(t *testing.T, filePath string, content string) {
 
 func TestReadACLFile_Success(t *testing.T) {
 	path := filepath.Join(os.TempDir(), "testACLFile.txt")
-	createTestFile(t, path, "A::OWNER@:rw\nA::user1@:rw\nA:g:group1@:r\n")
+	createTestFile(t, path, "A::OWNER@:rw\nA::user1@:rw\nA:G:group1@:r\n")
 	defer os.Remove(path)
 
 	expectedNumACEs := 3
@@ -182,11 +182,11 @@ func TestParseACL_WhitespaceExcluded(t *testing.T) {
 
 func TestParseACL_MultiValidACE(t *testing.T) {
 	expectedACEs := []string{
-		"A:g:GROUP@:r",
+		"A:G:GROUP@:r",
 		"A::OWNER@:rw",
-		"A:g:readers@:r",
-		"L:f:baduser@:rw",
-		"U:f:EVERYONE@:rw",
+		"A:G:readers@:r",
+		"L:F:baduser@:rw",
+		"U:F:EVERYONE@:rw",
 	}
 	expectedACL := &client.AccessControlList{
 		Entries: expectedACEs,
@@ -235,9 +235,9 @@ func TestParseACL_ErrorReadingFile(t *testing.T) {
 
 func TestParseACL_MultiValidACEWithComment(t *testing.T) {
 	expectedACEs := []string{
-		"A:g:readers@:r",
-		"L:f:baduser@:rw",
-		"U:f:EVERYONE@:rw",
+		"A:G:readers@:r",
+		"L:F:baduser@:rw",
+		"U:F:EVERYONE@:rw",
 	}
 	expectedACL := &client.AccessControlList{
 		Entries: expectedACEs,
@@ -268,6 +268,76 @@ func TestParseACL_MultiValidACEWithComment(t *testing.T) {
 	}
 }
 
+func TestParseACL_InvalidEntries(t *testing.T) {
+	for name, tc := range map[string]struct {
+		input  []string
+		expErr error
+	}{
+		"bad access type": {
+			input:  []string{"A::OWNER@:rw", "X::user@:rw"},
+			expErr: errors.New("line 2: invalid access type 'X'"),
+		},
+		"bad flag": {
+			input:  []string{"A::OWNER@:rw", "A:g:readers@:r"},
+			expErr: errors.New("line 2: invalid flag 'g'"),
+		},
+		"bad permission": {
+			input:  []string{"A::OWNER@:rwx"},
+			expErr: errors.New("line 1: invalid permission 'x'"),
+		},
+		"missing field": {
+			input:  []string{"# comment", "A::OWNER@"},
+			expErr: errors.New("line 2: ACE \"A::OWNER@\" must have 4 fields"),
+		},
+		"principal without domain separator": {
+			input:  []string{"A::OWNER@:rw", "", "A::user:rw"},
+			expErr: errors.New("line 3: ACE \"A::user:rw\": principal \"user\" must be in the format name@[domain]"),
+		},
+		"group principal without group flag": {
+			input:  []string{"A::GROUP@:rw"},
+			expErr: errors.New("line 1: ACE \"A::GROUP@:rw\": principal GROUP@ requires the 'G' flag"),
+		},
+		"alarm without flags": {
+			input:  []string{"L::user@:rw"},
+			expErr: errors.New("line 1: ACE \"L::user@:rw\": 'S' and 'F' flags are required for"),
+		},
+		"duplicate principal": {
+			input:  []string{"A::OWNER@:rw", "A::user@:r", "# dup", "A::user@:rw"},
+			expErr: errors.New("line 4: duplicate entry for principal user@ (first entry on line 2)"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := parseACL(&mockReader{
+				text: strings.Join(tc.input, "\n"),
+			})
+
+			common.CmpErr(t, tc.expErr, err)
+			if result != nil {
+				t.Fatalf("expected nil result, got %+v", result)
+			}
+		})
+	}
+}
+
+func TestParseACL_UserAndGroupSameName(t *testing.T) {
+	expectedACEs := []string{
+		"A::OWNER@:rw",
+		"A::admin@:rw",
+		"A:G:admin@:r",
+	}
+
+	result, err := parseACL(&mockReader{
+		text: strings.Join(expectedACEs, "\n"),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got '%s'", err.Error())
+	}
+
+	if diff := cmp.Diff(&client.AccessControlList{Entries: expectedACEs}, result); diff != "" {
+		t.Errorf("Unexpected ACL: %v\n", diff)
+	}
+}
+
 func TestFormatACL(t *testing.T) {
 	for name, tc := range map[string]struct {
 		acl    *client.AccessControlList
@@ -322,3 +392,34 @@ func TestFormatACL(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatACLDiff(t *testing.T) {
+	for name, tc := range map[string]struct {
+		diff   *client.ACLDiff
+		expStr string
+	}{
+		"nil": {
+			expStr: "# No changes\n",
+		},
+		"empty": {
+			diff:   &client.ACLDiff{},
+			expStr: "# No changes\n",
+		},
+		"all": {
+			diff: &client.ACLDiff{
+				Added: []string{"A::bob@:r"},
+				Changed: []client.ACLChange{
+					{Old: "A::OWNER@:r", New: "A::OWNER@:rw"},
+				},
+				Removed: []string{"A:G:readers@:r"},
+			},
+			expStr: "# Added:\n+ A::bob@:r\n" +
+				"# Changed:\n- A::OWNER@:r\n+ A::OWNER@:rw\n" +
+				"# Removed:\n- A:G:readers@:r\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			common.AssertEqual(t, formatACLDiff(tc.diff), tc.expStr, "string output didn't match")
+		})
+	}
+}
@@ -25,6 +25,7 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 	"testing"
@@ -185,7 +186,10 @@ func (tc *testConn) StorageSetFaulty(req *mgmtpb.DevStateReq) client.ResultState
 
 func (tc *testConn) SystemQuery() (system.Members, error) {
 	tc.appendInvocation("SystemQuery")
-	return make(system.Members, 0), nil
+	return system.Members{
+		system.NewMember(0, "", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 10001},
+			system.MemberStateStarted),
+	}, nil
 }
 
 func (tc *testConn) SystemStop(req client.SystemStopReq) (system.MemberResults, error) {
@@ -203,6 +207,21 @@ func (tc *testConn) ListPools(req client.ListPoolsReq) (*client.ListPoolsResp, e
 	return &client.ListPoolsResp{}, nil
 }
 
+func (tc *testConn) SystemEvents(req client.SystemEventsReq, eventFn func(*system.Event)) error {
+	tc.appendInvocation(fmt.Sprintf("SystemEvents-%+v", req))
+	return nil
+}
+
+func (tc *testConn) SystemExclude(ranks []uint32) (system.MemberResults, error) {
+	tc.appendInvocation(fmt.Sprintf("SystemExclude-%v", ranks))
+	return make(system.MemberResults, 0), nil
+}
+
+func (tc *testConn) SystemReintegrate(ranks []uint32) (system.MemberResults, error) {
+	tc.appendInvocation(fmt.Sprintf("SystemReintegrate-%v", ranks))
+	return make(system.MemberResults, 0), nil
+}
+
 func (tc *testConn) SystemStart() error {
 	tc.appendInvocation("SystemStart")
 	return nil
@@ -212,6 +231,16 @@ func (tc *testConn) SetTransportConfig(cfg *security.TransportConfig) {
 	tc.appendInvocation("SetTransportConfig")
 }
 
+func (tc *testConn) CertStatus() client.ResultMap {
+	tc.appendInvocation("CertStatus")
+	return nil
+}
+
+func (tc *testConn) SetLogLevel(req client.SetLogLevelReq) client.ResultMap {
+	tc.appendInvocation(fmt.Sprintf("SetLogLevel-%+v", req))
+	return nil
+}
+
 func (tc *testConn) NetworkListProviders() client.ResultMap {
 	tc.appendInvocation("NetworkListProviders")
 	return nil
@@ -53,6 +53,7 @@ type PoolCmd struct {
 	OverwriteACL PoolOverwriteACLCmd `command:"overwrite-acl" alias:"oa" description:"Overwrite a DAOS pool's Access Control List"`
 	UpdateACL    PoolUpdateACLCmd    `command:"update-acl" alias:"ua" description:"Update entries in a DAOS pool's Access Control List"`
 	DeleteACL    PoolDeleteACLCmd    `command:"delete-acl" alias:"da" description:"Delete an entry from a DAOS pool's Access Control List"`
+	ACL          PoolACLCmd          `command:"acl" description:"Inspect changes to a DAOS pool's Access Control List"`
 	SetProp      PoolSetPropCmd      `command:"set-prop" alias:"sp" description:"Set pool property"`
 }
 
@@ -254,6 +255,10 @@ func (d *PoolOverwriteACLCmd) Execute(args []string) error {
 		return err
 	}
 
+	if err := checkACLHasOwner(acl); err != nil {
+		return err
+	}
+
 	req := client.PoolOverwriteACLReq{
 		UUID: d.UUID,
 		ACL:  acl,
@@ -317,6 +322,50 @@ func (d *PoolUpdateACLCmd) Execute(args []string) error {
 	return nil
 }
 
+// PoolACLCmd is the struct representing the pool ACL subcommand group.
+type PoolACLCmd struct {
+	Diff PoolACLDiffCmd `command:"diff" description:"Show the changes an ACL file would make to a DAOS pool's Access Control List"`
+}
+
+// PoolACLDiffCmd represents the command to compare an ACL file against the
+// current Access Control List of a DAOS pool.
+type PoolACLDiffCmd struct {
+	logCmd
+	connectedCmd
+	UUID    string `long:"pool" required:"1" description:"UUID of DAOS pool"`
+	ACLFile string `short:"a" long:"acl-file" required:"1" description:"Path for proposed Access Control List file"`
+	Update  bool   `short:"u" long:"update" required:"0" description:"Compare as update-acl would apply the file, without removing entries"`
+}
+
+// Execute is run when the PoolACLDiffCmd subcommand is activated
+func (d *PoolACLDiffCmd) Execute(args []string) error {
+	acl, err := readACLFile(d.ACLFile)
+	if err != nil {
+		return err
+	}
+
+	if !d.Update {
+		if err := checkACLHasOwner(acl); err != nil {
+			return err
+		}
+	}
+
+	resp, err := d.conns.PoolGetACL(client.PoolGetACLReq{UUID: d.UUID})
+	if err != nil {
+		d.log.Infof("Pool-ACL-diff command failed: %s\n", err.Error())
+		return err
+	}
+
+	diff, err := client.DiffACL(resp.ACL, acl, !d.Update)
+	if err != nil {
+		return err
+	}
+
+	d.log.Info(formatACLDiff(diff))
+
+	return nil
+}
+
 // PoolDeleteACLCmd represents the command to delete an entry from the Access
 // Control List of a DAOS pool.
 type PoolDeleteACLCmd struct {
@@ -454,6 +503,9 @@ func poolCreate(log logging.Logger, conns client.Connect, scmSize string,
 		if err != nil {
 			return err
 		}
+		if err := checkACLHasOwner(acl); err != nil {
+			return err
+		}
 	}
 
 	if numSvcReps > maxNumSvcReps {
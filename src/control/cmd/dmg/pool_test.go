@@ -163,6 +163,12 @@ func TestPoolCommands(t *testing.T) {
 	}
 	createACLFile(t, testACLFile, testACL)
 
+	// An ACL file with no entry for the pool owner
+	testNoOwnerACLFile := filepath.Join(tmpDir, "no_owner_acl.txt")
+	createACLFile(t, testNoOwnerACLFile, &client.AccessControlList{
+		Entries: []string{"A:G:GROUP@:rw"},
+	})
+
 	// An existing file with contents for tests that need to verify overwrite
 	testExistingFile := filepath.Join(tmpDir, "existing.txt")
 	createACLFile(t, testExistingFile, testACL)
@@ -396,6 +402,12 @@ func TestPoolCommands(t *testing.T) {
 			}, " "),
 			nil,
 		},
+		{
+			"Overwrite pool ACL without owner entry",
+			fmt.Sprintf("pool overwrite-acl --pool 12345678-1234-1234-1234-1234567890ab --acl-file %s", testNoOwnerACLFile),
+			"ConnectClients",
+			dmgTestErr("ACL has no entry for OWNER@"),
+		},
 		{
 			"Update pool ACL with invalid ACL file",
 			"pool update-acl --pool 12345678-1234-1234-1234-1234567890ab --acl-file /not/a/real/file",
@@ -456,6 +468,40 @@ func TestPoolCommands(t *testing.T) {
 			}, " "),
 			nil,
 		},
+		{
+			"Diff pool ACL without ACL file",
+			"pool acl diff --pool 12345678-1234-1234-1234-1234567890ab",
+			"ConnectClients",
+			dmgTestErr("the required flag `-a, --acl-file' was not specified"),
+		},
+		{
+			"Diff pool ACL",
+			fmt.Sprintf("pool acl diff --pool 12345678-1234-1234-1234-1234567890ab --acl-file %s", testACLFile),
+			strings.Join([]string{
+				"ConnectClients",
+				fmt.Sprintf("PoolGetACL-%+v", client.PoolGetACLReq{
+					UUID: "12345678-1234-1234-1234-1234567890ab",
+				}),
+			}, " "),
+			nil,
+		},
+		{
+			"Diff pool ACL without owner entry",
+			fmt.Sprintf("pool acl diff --pool 12345678-1234-1234-1234-1234567890ab --acl-file %s", testNoOwnerACLFile),
+			"ConnectClients",
+			dmgTestErr("ACL has no entry for OWNER@"),
+		},
+		{
+			"Diff pool ACL as update without owner entry",
+			fmt.Sprintf("pool acl diff --pool 12345678-1234-1234-1234-1234567890ab --acl-file %s --update", testNoOwnerACLFile),
+			strings.Join([]string{
+				"ConnectClients",
+				fmt.Sprintf("PoolGetACL-%+v", client.PoolGetACLReq{
+					UUID: "12345678-1234-1234-1234-1234567890ab",
+				}),
+			}, " "),
+			nil,
+		},
 		{
 			"Nonexistent subcommand",
 			"pool quack",
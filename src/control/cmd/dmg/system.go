@@ -25,13 +25,17 @@ package main
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/client"
 	"github.com/daos-stack/daos/src/control/lib/hostlist"
 	"github.com/daos-stack/daos/src/control/lib/txtfmt"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
 // SystemCmd is the struct representing the top-level system subcommand.
@@ -41,6 +45,11 @@ type SystemCmd struct {
 	Stop        systemStopCmd      `command:"stop" alias:"s" description:"Perform controlled shutdown of DAOS system"`
 	Start       systemStartCmd     `command:"start" alias:"r" description:"Perform start of stopped DAOS system"`
 	ListPools   systemListPoolsCmd `command:"list-pools" alias:"p" description:"List all pools in the DAOS system"`
+	Events      systemEventsCmd    `command:"events" alias:"e" description:"Show DAOS system event log"`
+	Exclude     systemExcludeCmd   `command:"exclude" alias:"x" description:"Exclude ranks from DAOS system until reintegrated"`
+	Reintegrate systemReintCmd     `command:"reintegrate" alias:"i" description:"Reintegrate excluded ranks into DAOS system"`
+	CertStatus  systemCertCmd      `command:"cert-status" description:"Show certificates in use by DAOS servers"`
+	SetLogLevel systemLogLevelCmd  `command:"set-log-level" description:"Change log levels of DAOS servers without restarting"`
 }
 
 type leaderQueryCmd struct {
@@ -67,6 +76,38 @@ func (cmd *leaderQueryCmd) Execute(_ []string) error {
 type systemQueryCmd struct {
 	logCmd
 	connectedCmd
+	Rank    *uint32 `long:"rank" description:"Only show member with given rank"`
+	Verbose bool    `long:"verbose" short:"v" description:"Show all details of each member"`
+}
+
+// formatMemberDetails returns a string containing all details of a system
+// member in entity format.
+func formatMemberDetails(m *system.Member) string {
+	stateChange := "-"
+	if !m.StateChangeTime.IsZero() {
+		stateChange = m.StateChangeTime.Format(time.RFC3339)
+		if m.StateChangeReason != "" {
+			stateChange += " (" + m.StateChangeReason + ")"
+		}
+	}
+
+	uptime := "-"
+	if !m.StartTime.IsZero() {
+		uptime = time.Since(m.StartTime).Round(time.Second).String()
+	}
+
+	return txtfmt.FormatEntity(fmt.Sprintf("Rank %d", m.Rank), []txtfmt.TableRow{
+		{"UUID": m.UUID},
+		{"Control Address": m.Addr.String()},
+		{"Hostname": m.Hostname},
+		{"Instance Index": fmt.Sprintf("%d", m.InstanceIdx)},
+		{"Fabric Interface": m.FabricIface},
+		{"Fabric Provider": m.FabricProvider},
+		{"Targets": fmt.Sprintf("%d", m.TargetCount)},
+		{"State": m.State().String()},
+		{"Last State Change": stateChange},
+		{"Uptime": uptime},
+	})
 }
 
 // Execute is run when systemQueryCmd activates
@@ -77,11 +118,32 @@ func (cmd *systemQueryCmd) Execute(args []string) error {
 	}
 
 	cmd.log.Debug("System-Query command succeeded\n")
+
+	if cmd.Rank != nil {
+		var selected system.Members
+		for _, m := range members {
+			if m.Rank == *cmd.Rank {
+				selected = append(selected, m)
+			}
+		}
+		if len(selected) == 0 {
+			return errors.Errorf("rank %d is not a member of the system", *cmd.Rank)
+		}
+		members = selected
+	}
+
 	if len(members) == 0 {
 		cmd.log.Info("No members in system\n")
 		return nil
 	}
 
+	if cmd.Verbose {
+		for _, m := range members {
+			cmd.log.Info(formatMemberDetails(m))
+		}
+		return nil
+	}
+
 	rankTitle := "Rank"
 	uuidTitle := "UUID"
 	addrTitle := "Control Address"
@@ -131,6 +193,19 @@ func (cmd *systemStopCmd) Execute(args []string) error {
 	}
 	cmd.log.Debug("System-Stop command succeeded\n")
 
+	out, err := formatMemberResults(results)
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Info(out)
+
+	return nil
+}
+
+// formatMemberResults tabulates results of actions on system members with
+// ranks grouped by result.
+func formatMemberResults(results system.MemberResults) (string, error) {
 	groups := make(hostlist.HostGroups)
 
 	for _, r := range results {
@@ -139,14 +214,72 @@ func (cmd *systemStopCmd) Execute(args []string) error {
 			msg = r.Err.Error()
 		}
 		resStr := fmt.Sprintf("%s%s%s", r.Action, rowFieldSep, msg)
-		if err = groups.AddHost(resStr, fmt.Sprintf("rank%d", r.Rank)); err != nil {
-			return errors.Wrap(err, "adding rank result to group")
+		if err := groups.AddHost(resStr, fmt.Sprintf("rank%d", r.Rank)); err != nil {
+			return "", errors.Wrap(err, "adding rank result to group")
 		}
 	}
 
 	out, err := tabulateHostGroups(groups, "Ranks", "Operation", "Result")
 	if err != nil {
-		return errors.Wrap(err, "printing result table")
+		return "", errors.Wrap(err, "printing result table")
+	}
+
+	return out, nil
+}
+
+// systemExcludeCmd is the struct representing the command to exclude ranks
+// from the DAOS system.
+type systemExcludeCmd struct {
+	logCmd
+	connectedCmd
+	Ranks string `long:"ranks" required:"1" description:"Comma separated ranks or rank ranges to exclude (e.g. 0,3-5)"`
+}
+
+// Execute is run when systemExcludeCmd activates
+func (cmd *systemExcludeCmd) Execute(args []string) error {
+	ranks, err := parseRanks(cmd.Ranks)
+	if err != nil {
+		return errors.WithMessage(err, "parsing ranks")
+	}
+
+	results, err := cmd.conns.SystemExclude(ranks)
+	if err != nil {
+		return errors.Wrap(err, "System-Exclude command failed")
+	}
+
+	out, err := formatMemberResults(results)
+	if err != nil {
+		return err
+	}
+
+	cmd.log.Info(out)
+
+	return nil
+}
+
+// systemReintCmd is the struct representing the command to reintegrate
+// excluded ranks into the DAOS system.
+type systemReintCmd struct {
+	logCmd
+	connectedCmd
+	Ranks string `long:"ranks" required:"1" description:"Comma separated ranks or rank ranges to reintegrate (e.g. 0,3-5)"`
+}
+
+// Execute is run when systemReintCmd activates
+func (cmd *systemReintCmd) Execute(args []string) error {
+	ranks, err := parseRanks(cmd.Ranks)
+	if err != nil {
+		return errors.WithMessage(err, "parsing ranks")
+	}
+
+	results, err := cmd.conns.SystemReintegrate(ranks)
+	if err != nil {
+		return errors.Wrap(err, "System-Reintegrate command failed")
+	}
+
+	out, err := formatMemberResults(results)
+	if err != nil {
+		return err
 	}
 
 	cmd.log.Info(out)
@@ -230,3 +363,239 @@ func (cmd *systemListPoolsCmd) Execute(args []string) error {
 	cmd.log.Info(formatter.Format(table))
 	return nil
 }
+
+// systemEventsCmd is the struct representing the command to show the
+// system event log.
+type systemEventsCmd struct {
+	logCmd
+	connectedCmd
+	Since  string   `long:"since" description:"Only show events after RFC3339 time or duration ago (e.g. 2h30m)"`
+	Ranks  []uint32 `long:"rank" description:"Only show events for rank, may be repeated"`
+	Follow bool     `long:"follow" short:"f" description:"Keep showing events as they are recorded"`
+}
+
+// parseSince converts either a RFC3339 time or a duration before now into a
+// time.
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(since); err == nil {
+		return now.Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return time.Time{}, errors.Errorf(
+			"invalid --since value %q, expecting RFC3339 time or duration", since)
+	}
+
+	return t, nil
+}
+
+// Execute is run when systemEventsCmd activates
+func (cmd *systemEventsCmd) Execute(args []string) error {
+	req := client.SystemEventsReq{Ranks: cmd.Ranks, Follow: cmd.Follow}
+	if cmd.Since != "" {
+		since, err := parseSince(cmd.Since, time.Now())
+		if err != nil {
+			return err
+		}
+		req.Since = since
+	}
+
+	var count int
+	err := cmd.conns.SystemEvents(req, func(event *system.Event) {
+		count++
+		cmd.log.Infof("%s\n", event)
+	})
+	if err != nil {
+		return errors.Wrap(err, "System-Events command failed")
+	}
+
+	if count == 0 {
+		cmd.log.Info("No events recorded\n")
+	}
+
+	return nil
+}
+
+// certExpiryWarning is the period before expiry within which certificates are
+// flagged in cert-status output.
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// systemCertCmd is the struct representing the command to show the
+// certificates in use by each server.
+type systemCertCmd struct {
+	logCmd
+	connectedCmd
+}
+
+// certStatusSummary returns a short description of the validity of the
+// certificate and its CA.
+func certStatusSummary(status *client.CertStatus, now time.Time) string {
+	if status.Error != "" {
+		return status.Error
+	}
+
+	expiry := status.Cert.NotAfter
+	if status.CA != nil && status.CA.NotAfter.Before(expiry) {
+		expiry = status.CA.NotAfter
+	}
+
+	switch remaining := expiry.Sub(now); {
+	case remaining <= 0:
+		return "EXPIRED"
+	case remaining <= certExpiryWarning:
+		return fmt.Sprintf("expires in %d days", remaining/(24*time.Hour))
+	default:
+		return "OK"
+	}
+}
+
+// formatCertStatus returns a table of the certificates in use by each server.
+func formatCertStatus(results client.ResultMap, now time.Time) string {
+	hostTitle := "Host"
+	subjectTitle := "Subject"
+	expiryTitle := "Expires"
+	caExpiryTitle := "CA Expires"
+	statusTitle := "Status"
+
+	formatter := txtfmt.NewTableFormatter(hostTitle, subjectTitle, expiryTitle,
+		caExpiryTitle, statusTitle)
+	var table []txtfmt.TableRow
+
+	addrs := make([]string, 0, len(results))
+	for addr := range results {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	for _, addr := range addrs {
+		row := txtfmt.TableRow{hostTitle: addr}
+		res := results[addr]
+
+		status, ok := res.Value.(*client.CertStatus)
+		switch {
+		case res.Err != nil:
+			row[statusTitle] = res.Err.Error()
+		case !ok:
+			row[statusTitle] = fmt.Sprintf("unexpected result %v", res.Value)
+		case status.Insecure:
+			row[statusTitle] = "insecure"
+		case status.Cert == nil:
+			row[statusTitle] = status.Error
+		default:
+			row[subjectTitle] = status.Cert.Subject
+			row[expiryTitle] = status.Cert.NotAfter.Format(time.RFC3339)
+			if status.CA != nil {
+				row[caExpiryTitle] = status.CA.NotAfter.Format(time.RFC3339)
+			}
+			row[statusTitle] = certStatusSummary(status, now)
+		}
+
+		table = append(table, row)
+	}
+
+	return formatter.Format(table)
+}
+
+// Execute is run when systemCertCmd activates
+func (cmd *systemCertCmd) Execute(args []string) error {
+	cmd.log.Info(formatCertStatus(cmd.conns.CertStatus(), time.Now()))
+
+	return nil
+}
+
+// systemLogLevelCmd is the struct representing the command to change the
+// log levels of each server without restarting it.
+type systemLogLevelCmd struct {
+	logCmd
+	connectedCmd
+	Control string `long:"control" description:"Control plane log level, optionally followed by subsystem levels (e.g. INFO,drpc=DEBUG)"`
+	Engine  string `long:"engine" description:"I/O server log mask, as for D_LOG_MASK (e.g. DEBUG,MEM=ERR)"`
+	Ranks   string `long:"ranks" description:"Comma separated ranks or rank ranges to set the I/O server log mask for, all if unspecified (e.g. 0,3-5)"`
+}
+
+// formatSetLogLevel returns a table of the log levels that were in effect on
+// each server before they were changed, which can be used to revert them.
+func formatSetLogLevel(results client.ResultMap) string {
+	hostTitle := "Host"
+	targetTitle := "Target"
+	prevTitle := "Previous"
+	resultTitle := "Result"
+
+	formatter := txtfmt.NewTableFormatter(hostTitle, targetTitle, prevTitle,
+		resultTitle)
+	var table []txtfmt.TableRow
+
+	addrs := make([]string, 0, len(results))
+	for addr := range results {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+
+	for _, addr := range addrs {
+		res := results[addr]
+
+		resp, ok := res.Value.(*client.SetLogLevelResp)
+		switch {
+		case res.Err != nil:
+			table = append(table, txtfmt.TableRow{hostTitle: addr,
+				resultTitle: res.Err.Error()})
+			continue
+		case !ok:
+			table = append(table, txtfmt.TableRow{hostTitle: addr,
+				resultTitle: fmt.Sprintf("unexpected result %v", res.Value)})
+			continue
+		}
+
+		if resp.PrevControl != "" {
+			table = append(table, txtfmt.TableRow{
+				hostTitle:   addr,
+				targetTitle: "control",
+				prevTitle:   resp.PrevControl,
+				resultTitle: "OK",
+			})
+		}
+		for _, engine := range resp.Engines {
+			row := txtfmt.TableRow{
+				hostTitle:   addr,
+				targetTitle: fmt.Sprintf("rank %d", engine.Rank),
+				prevTitle:   engine.PrevMask,
+				resultTitle: "OK",
+			}
+			if engine.Error != "" {
+				row[resultTitle] = engine.Error
+			}
+			table = append(table, row)
+		}
+	}
+
+	return formatter.Format(table)
+}
+
+// Execute is run when systemLogLevelCmd activates
+func (cmd *systemLogLevelCmd) Execute(args []string) error {
+	if cmd.Control == "" && cmd.Engine == "" {
+		return errors.New("at least one of --control or --engine must be specified")
+	}
+	if cmd.Control != "" {
+		if _, err := logging.ParseLogMask(cmd.Control); err != nil {
+			return errors.WithMessage(err, "parsing --control")
+		}
+	}
+
+	req := client.SetLogLevelReq{Control: cmd.Control, Engine: cmd.Engine}
+	if cmd.Ranks != "" {
+		if cmd.Engine == "" {
+			return errors.New("--ranks requires --engine")
+		}
+		ranks, err := parseRanks(cmd.Ranks)
+		if err != nil {
+			return errors.WithMessage(err, "parsing ranks")
+		}
+		req.Ranks = ranks
+	}
+
+	cmd.log.Info(formatSetLogLevel(cmd.conns.SetLogLevel(req)))
+
+	return nil
+}
@@ -25,7 +25,17 @@ package main
 
 import (
 	"fmt"
+	"net"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/client"
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
 func TestSystemCommands(t *testing.T) {
@@ -36,6 +46,48 @@ func TestSystemCommands(t *testing.T) {
 			"ConnectClients SystemQuery",
 			nil,
 		},
+		{
+			"system query single rank",
+			"system query --rank 0",
+			"ConnectClients SystemQuery",
+			nil,
+		},
+		{
+			"system query single rank verbose",
+			"system query --rank 0 --verbose",
+			"ConnectClients SystemQuery",
+			nil,
+		},
+		{
+			"system query missing rank",
+			"system query --rank 5",
+			"ConnectClients SystemQuery",
+			errors.New("rank 5 is not a member of the system"),
+		},
+		{
+			"system exclude ranks",
+			"system exclude --ranks 0,3-5",
+			"ConnectClients SystemExclude-[0 3 4 5]",
+			nil,
+		},
+		{
+			"system exclude without ranks",
+			"system exclude",
+			"",
+			errMissingFlag,
+		},
+		{
+			"system exclude bad ranks",
+			"system exclude --ranks 5-3",
+			"ConnectClients",
+			errors.New("invalid rank range \"5-3\""),
+		},
+		{
+			"system reintegrate ranks",
+			"system reintegrate --ranks 2",
+			"ConnectClients SystemReintegrate-[2]",
+			nil,
+		},
 		{
 			"system stop with no arguments",
 			"system stop",
@@ -66,6 +118,60 @@ func TestSystemCommands(t *testing.T) {
 			"ConnectClients ListPools-{daos_server}",
 			nil,
 		},
+		{
+			"system events with no arguments",
+			"system events",
+			"ConnectClients SystemEvents-{Since:0001-01-01 00:00:00 +0000 UTC Ranks:[] Follow:false}",
+			nil,
+		},
+		{
+			"system events with filters and follow",
+			"system events --since 2019-11-20T10:00:00Z --rank 1 --rank 3 --follow",
+			"ConnectClients SystemEvents-{Since:2019-11-20 10:00:00 +0000 UTC Ranks:[1 3] Follow:true}",
+			nil,
+		},
+		{
+			"system events with bad since",
+			"system events --since yesterday",
+			"ConnectClients",
+			errors.New("invalid --since value"),
+		},
+		{
+			"system cert-status",
+			"system cert-status",
+			"ConnectClients CertStatus",
+			nil,
+		},
+		{
+			"system set-log-level control only",
+			"system set-log-level --control info,drpc=debug",
+			"ConnectClients SetLogLevel-{Control:info,drpc=debug Engine: Ranks:[]}",
+			nil,
+		},
+		{
+			"system set-log-level engine ranks",
+			"system set-log-level --engine DEBUG,MEM=ERR --ranks 0,2-3",
+			"ConnectClients SetLogLevel-{Control: Engine:DEBUG,MEM=ERR Ranks:[0 2 3]}",
+			nil,
+		},
+		{
+			"system set-log-level nothing to set",
+			"system set-log-level",
+			"ConnectClients",
+			errors.New("at least one of --control or --engine"),
+		},
+		{
+			"system set-log-level bad control level",
+			"system set-log-level --control loud",
+			"ConnectClients",
+			errors.New("parsing --control"),
+		},
+		{
+			"system set-log-level ranks without engine",
+			"system set-log-level --control info --ranks 1",
+			"ConnectClients",
+			errors.New("--ranks requires --engine"),
+		},
 		{
 			"Nonexistent subcommand",
 			"system quack",
@@ -74,3 +180,157 @@ func TestSystemCommands(t *testing.T) {
 		},
 	})
 }
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2019, 11, 20, 10, 0, 0, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		since  string
+		expErr error
+		expOut time.Time
+	}{
+		"duration": {
+			since:  "1h30m",
+			expOut: time.Date(2019, 11, 20, 8, 30, 0, 0, time.UTC),
+		},
+		"timestamp": {
+			since:  "2019-11-19T23:00:00Z",
+			expOut: time.Date(2019, 11, 19, 23, 0, 0, 0, time.UTC),
+		},
+		"garbage": {
+			since:  "last week",
+			expErr: errors.New("invalid --since value"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			out, err := parseSince(tc.since, now)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			if !out.Equal(tc.expOut) {
+				t.Fatalf("expected %s, got %s", tc.expOut, out)
+			}
+		})
+	}
+}
+
+func TestFormatMemberDetails(t *testing.T) {
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 10001}
+	member := system.NewMember(2, "uuid2", addr, system.MemberStateStopped)
+	member.Hostname = "node1"
+	member.InstanceIdx = 1
+	member.FabricIface = "ib1"
+	member.FabricProvider = "ofi+verbs;ofi_rxm"
+	member.TargetCount = 8
+	member.StateChangeTime = time.Date(2019, 11, 20, 10, 0, 0, 0, time.UTC)
+	member.StateChangeReason = "stop"
+
+	expOut := `Rank 2
+------
+UUID              : uuid2
+Control Address   : 10.0.0.1:10001
+Hostname          : node1
+Instance Index    : 1
+Fabric Interface  : ib1
+Fabric Provider   : ofi+verbs;ofi_rxm
+Targets           : 8
+State             : Stopped
+Last State Change : 2019-11-20T10:00:00Z (stop)
+Uptime            : -
+`
+
+	// entity rows are padded to a common width, ignore trailing space
+	lines := strings.Split(formatMemberDetails(member), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+
+	if diff := cmp.Diff(expOut, strings.Join(lines, "\n")); diff != "" {
+		t.Fatalf("unexpected output (-want, +got):\n%s\n", diff)
+	}
+}
+
+func TestFormatCertStatus(t *testing.T) {
+	now := time.Date(2019, 11, 20, 10, 0, 0, 0, time.UTC)
+	ca := &client.CertInfo{NotAfter: now.Add(10 * 365 * 24 * time.Hour)}
+	certStatus := func(notAfter time.Time) *client.CertStatus {
+		return &client.CertStatus{
+			Cert: &client.CertInfo{Subject: "CN=server,OU=server,O=DAOS", NotAfter: notAfter},
+			CA:   ca,
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		result    client.ClientResult
+		expStatus string
+	}{
+		"valid": {
+			result:    client.ClientResult{Value: certStatus(now.Add(365 * 24 * time.Hour))},
+			expStatus: "OK",
+		},
+		"expiring": {
+			result:    client.ClientResult{Value: certStatus(now.Add(72 * time.Hour))},
+			expStatus: "expires in 3 days",
+		},
+		"expired": {
+			result:    client.ClientResult{Value: certStatus(now.Add(-time.Hour))},
+			expStatus: "EXPIRED",
+		},
+		"verification failure": {
+			result: client.ClientResult{Value: &client.CertStatus{
+				Cert:  &client.CertInfo{NotAfter: now.Add(365 * 24 * time.Hour)},
+				Error: "verify certificate: unknown authority",
+			}},
+			expStatus: "verify certificate: unknown authority",
+		},
+		"insecure": {
+			result:    client.ClientResult{Value: &client.CertStatus{Insecure: true}},
+			expStatus: "insecure",
+		},
+		"connection error": {
+			result:    client.ClientResult{Err: errors.New("connection refused")},
+			expStatus: "connection refused",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			out := formatCertStatus(client.ResultMap{"node1:10001": tc.result}, now)
+
+			lines := strings.Split(strings.TrimSpace(out), "\n")
+			row := strings.TrimSpace(lines[len(lines)-1])
+			if !strings.HasPrefix(row, "node1:10001") || !strings.HasSuffix(row, tc.expStatus) {
+				t.Fatalf("expected row for node1:10001 with status %q, got:\n%s", tc.expStatus, out)
+			}
+		})
+	}
+}
+
+func TestFormatSetLogLevel(t *testing.T) {
+	results := client.ResultMap{
+		"node2:10001": client.ClientResult{Err: errors.New("connection refused")},
+		"node1:10001": client.ClientResult{Value: &client.SetLogLevelResp{
+			PrevControl: "INFO",
+			Engines: []*client.EngineLogMask{
+				{Rank: 0, PrevMask: "ERR"},
+				{Rank: 1, PrevMask: "WARN", Error: "SetLogMasks \"BOGUS\": -1003"},
+			},
+		}},
+	}
+
+	var rows [][]string
+	lines := strings.Split(strings.TrimSpace(formatSetLogLevel(results)), "\n")
+	for _, line := range lines[2:] {
+		rows = append(rows, strings.Fields(line))
+	}
+
+	expRows := [][]string{
+		{"node1:10001", "control", "INFO", "OK"},
+		{"node1:10001", "rank", "0", "ERR", "OK"},
+		{"node1:10001", "rank", "1", "WARN", "SetLogMasks", `"BOGUS":`, "-1003"},
+		{"node2:10001", "None", "None", "connection", "refused"},
+	}
+	if diff := cmp.Diff(expRows, rows); diff != "" {
+		t.Fatalf("unexpected output (-want, +got):\n%s\n", diff)
+	}
+}
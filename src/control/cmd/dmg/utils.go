@@ -176,3 +176,42 @@ func tabulateHostGroups(groups hostlist.HostGroups, titles ...string) (string, e
 
 	return formatter.Format(table), nil
 }
+
+// parseRanks takes a comma separated list of ranks and inclusive rank ranges
+// (e.g. "0,3-5") and returns the ordered set of individual ranks.
+func parseRanks(rankList string) ([]uint32, error) {
+	seen := make(map[uint32]struct{})
+	ranks := make([]uint32, 0)
+
+	for _, field := range strings.Split(rankList, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			return nil, errors.Errorf("invalid rank list %q", rankList)
+		}
+
+		bounds := strings.SplitN(field, "-", 2)
+		lo, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid rank %q", field)
+		}
+		hi := lo
+		if len(bounds) == 2 {
+			hi, err = strconv.ParseUint(bounds[1], 10, 32)
+			if err != nil || hi < lo {
+				return nil, errors.Errorf("invalid rank range %q", field)
+			}
+		}
+
+		for r := lo; r <= hi; r++ {
+			if _, exists := seen[uint32(r)]; exists {
+				continue
+			}
+			seen[uint32(r)] = struct{}{}
+			ranks = append(ranks, uint32(r))
+		}
+	}
+
+	sort.Slice(ranks, func(i, j int) bool { return ranks[i] < ranks[j] })
+
+	return ranks, nil
+}
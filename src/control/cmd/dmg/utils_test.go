@@ -294,3 +294,42 @@ func TestTabulateHostGroups(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRanks(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rankList  string
+		expRanks  []uint32
+		expErrMsg string
+	}{
+		"single rank": {
+			rankList: "3",
+			expRanks: []uint32{3},
+		},
+		"ranks and ranges": {
+			rankList: "7,0-2, 5,1",
+			expRanks: []uint32{0, 1, 2, 5, 7},
+		},
+		"empty": {
+			expErrMsg: "invalid rank list \"\"",
+		},
+		"negative rank": {
+			rankList:  "-1",
+			expErrMsg: "invalid rank \"-1\"",
+		},
+		"reversed range": {
+			rankList:  "4-2",
+			expErrMsg: "invalid rank range \"4-2\"",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ranks, err := parseRanks(tc.rankList)
+			ExpectError(t, err, tc.expErrMsg, name)
+			if tc.expErrMsg != "" {
+				return
+			}
+			if diff := cmp.Diff(tc.expRanks, ranks); diff != "" {
+				t.Fatalf("unexpected ranks (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	flags "github.com/jessevdk/go-flags"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/drpc"
+	"github.com/daos-stack/daos/src/control/lib/txtfmt"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+type cmdLogger interface {
+	setLog(*logging.LeveledLogger)
+}
+
+type logCmd struct {
+	log *logging.LeveledLogger
+}
+
+func (c *logCmd) setLog(log *logging.LeveledLogger) {
+	c.log = log
+}
+
+type cliOptions struct {
+	Debug bool    `short:"d" long:"debug" description:"enable debug output"`
+	Call  callCmd `command:"call" description:"Send a call to a dRPC server and print the decoded response"`
+	List  listCmd `command:"list" description:"List the known dRPC modules and methods"`
+}
+
+// listCmd is the struct representing the command to list the known methods.
+type listCmd struct {
+	logCmd
+}
+
+// Execute is run when listCmd activates
+func (cmd *listCmd) Execute(_ []string) error {
+	moduleTitle := "Module"
+	methodTitle := "Method"
+	reqTitle := "Request"
+	respTitle := "Response"
+
+	formatter := txtfmt.NewTableFormatter(moduleTitle, methodTitle, reqTitle, respTitle)
+	var table []txtfmt.TableRow
+
+	for _, mod := range knownModules {
+		for _, meth := range mod.methods {
+			row := txtfmt.TableRow{moduleTitle: mod.String()}
+			row[methodTitle] = fmt.Sprintf("%s (%d)", meth.name, meth.id)
+			row[reqTitle] = messageName(meth.request)
+			row[respTitle] = messageName(meth.response)
+
+			table = append(table, row)
+		}
+	}
+
+	cmd.log.Info(formatter.Format(table))
+
+	return nil
+}
+
+// callCmd is the struct representing the command to send a call to a dRPC
+// server.
+type callCmd struct {
+	logCmd
+	Socket  string        `short:"s" long:"socket" required:"1" description:"Path to the Unix domain socket of the dRPC server"`
+	Timeout time.Duration `short:"t" long:"timeout" default:"30s" description:"Time to wait for the response"`
+	Args    struct {
+		Module string `positional-arg-name:"module" required:"1" description:"Module name or ID"`
+		Method string `positional-arg-name:"method" required:"1" description:"Method name or ID"`
+		Body   string `positional-arg-name:"body" description:"Request as JSON, or - to read it from stdin"`
+	} `positional-args:"yes"`
+}
+
+// encodeRequest converts the JSON request for the method to the protobuf
+// body of a call. An empty request is sent for a method taking a body if
+// none is given.
+func encodeRequest(meth *methodInfo, reqJSON string) ([]byte, error) {
+	if meth.request == nil {
+		if reqJSON != "" {
+			return nil, errors.Errorf("method %s takes no request", meth.name)
+		}
+		return nil, nil
+	}
+	if reqJSON == "" {
+		reqJSON = "{}"
+	}
+
+	req := meth.request()
+	if err := jsonpb.UnmarshalString(reqJSON, req); err != nil {
+		return nil, errors.Wrapf(err, "decode %s", proto.MessageName(req))
+	}
+
+	return proto.Marshal(req)
+}
+
+// decodeResponse converts the protobuf body of the method's response to
+// JSON.
+func decodeResponse(meth *methodInfo, body []byte) (string, error) {
+	if meth.response == nil {
+		if len(body) != 0 {
+			return "", errors.Errorf("unexpected %d byte response to method %s",
+				len(body), meth.name)
+		}
+		return "{}", nil
+	}
+
+	resp := meth.response()
+	if err := proto.Unmarshal(body, resp); err != nil {
+		return "", errors.Wrapf(err, "unmarshal %s", proto.MessageName(resp))
+	}
+
+	marshaler := &jsonpb.Marshaler{
+		Indent:       "  ",
+		OrigName:     true,
+		EmitDefaults: true,
+	}
+	return marshaler.MarshalToString(resp)
+}
+
+// Execute is run when callCmd activates
+func (cmd *callCmd) Execute(_ []string) error {
+	mod, meth, err := lookupMethod(cmd.Args.Module, cmd.Args.Method)
+	if err != nil {
+		return err
+	}
+
+	reqJSON := cmd.Args.Body
+	if reqJSON == "-" {
+		buf, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return errors.Wrap(err, "read request from stdin")
+		}
+		reqJSON = string(buf)
+	}
+
+	call := &drpc.Call{Module: mod.id, Method: meth.id}
+	if call.Body, err = encodeRequest(meth, reqJSON); err != nil {
+		return err
+	}
+
+	client := drpc.NewClientConnection(cmd.Socket)
+	if err := client.Connect(); err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), cmd.Timeout)
+	defer cancel()
+
+	cmd.log.Debugf("calling %s method %s (%d) on %s", mod, meth.name, meth.id, cmd.Socket)
+	resp, err := client.SendMsg(ctx, call)
+	if err != nil {
+		return err
+	}
+	if resp.Status != drpc.Status_SUCCESS {
+		return errors.Errorf("%s call failed with status %s", meth.name, resp.Status)
+	}
+
+	out, err := decodeResponse(meth, resp.Body)
+	if err != nil {
+		return err
+	}
+	cmd.log.Info(out)
+
+	return nil
+}
+
+func exitWithError(log logging.Logger, err error) {
+	log.Errorf("%s: %v", path.Base(os.Args[0]), err)
+	os.Exit(1)
+}
+
+func parseOpts(args []string, opts *cliOptions, log *logging.LeveledLogger) error {
+	p := flags.NewParser(opts, flags.Default)
+	p.Options ^= flags.PrintErrors // Don't allow the library to print errors
+	p.CommandHandler = func(cmd flags.Commander, args []string) error {
+		if cmd == nil {
+			return nil
+		}
+
+		if opts.Debug {
+			log.WithLogLevel(logging.LogLevelDebug)
+			log.Debug("debug output enabled")
+		}
+
+		if logCmd, ok := cmd.(cmdLogger); ok {
+			logCmd.setLog(log)
+		}
+
+		return cmd.Execute(args)
+	}
+
+	_, err := p.ParseArgs(args)
+	return err
+}
+
+func main() {
+	var opts cliOptions
+	log := logging.NewCommandLineLogger()
+
+	if err := parseOpts(os.Args[1:], &opts, log); err != nil {
+		exitWithError(log, err)
+	}
+}
@@ -0,0 +1,243 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
+	"github.com/daos-stack/daos/src/control/drpc"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+func TestLookupMethod(t *testing.T) {
+	for name, tc := range map[string]struct {
+		module    string
+		method    string
+		expModule int32
+		expMethod int32
+		expErr    error
+	}{
+		"by name": {
+			module:    "mgmt",
+			method:    "PoolCreate",
+			expModule: drpc.ModuleMgmt,
+			expMethod: drpc.MethodPoolCreate,
+		},
+		"case insensitive": {
+			module:    "Security-Agent",
+			method:    "requestcredentials",
+			expModule: drpc.ModuleSecurityAgent,
+			expMethod: drpc.MethodRequestCredentials,
+		},
+		"by ID": {
+			module:    "2",
+			method:    "214",
+			expModule: drpc.ModuleMgmt,
+			expMethod: drpc.MethodListPools,
+		},
+		"unknown module": {
+			module: "bogus",
+			method: "PoolCreate",
+			expErr: errors.New("unknown module \"bogus\""),
+		},
+		"method of another module": {
+			module: "srv",
+			method: "PoolCreate",
+			expErr: errors.New("unknown method \"PoolCreate\" for module srv"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mod, meth, err := lookupMethod(tc.module, tc.method)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+			common.AssertEqual(t, mod.id, tc.expModule, "wrong module")
+			common.AssertEqual(t, meth.id, tc.expMethod, "wrong method")
+		})
+	}
+}
+
+func TestKnownModules_Unique(t *testing.T) {
+	modules := make(map[int32]bool)
+	for _, mod := range knownModules {
+		if modules[mod.id] {
+			t.Fatalf("module %s listed twice", mod)
+		}
+		modules[mod.id] = true
+
+		methods := make(map[int32]bool)
+		for _, meth := range mod.methods {
+			if methods[meth.id] {
+				t.Fatalf("method %d of module %s listed twice", meth.id, mod)
+			}
+			methods[meth.id] = true
+		}
+	}
+}
+
+// listPoolsModule answers ListPools calls with the pools named in the
+// request's system name, and fails other calls.
+type listPoolsModule struct {
+	calls []*mgmtpb.ListPoolsReq
+}
+
+func (m *listPoolsModule) HandleCall(_ *drpc.Session, method int32, body []byte) ([]byte, error) {
+	if method != drpc.MethodListPools {
+		return nil, drpc.UnknownMethodFailure()
+	}
+
+	req := &mgmtpb.ListPoolsReq{}
+	if err := proto.Unmarshal(body, req); err != nil {
+		return nil, drpc.UnmarshalingPayloadFailure()
+	}
+	m.calls = append(m.calls, req)
+
+	return drpc.Marshal(&mgmtpb.ListPoolsResp{
+		Pools: []*mgmtpb.ListPoolsResp_Pool{
+			{Uuid: req.Sys + "-pool", Svcreps: []uint32{0, 1}},
+		},
+	})
+}
+
+func (m *listPoolsModule) ID() int32 {
+	return drpc.ModuleMgmt
+}
+
+func TestDrpcCli_Call(t *testing.T) {
+	tmpDir, tmpCleanup := common.CreateTestDir(t)
+	defer tmpCleanup()
+	sock := filepath.Join(tmpDir, "test.sock")
+
+	srvLog, srvBuf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, srvBuf)
+
+	mod := &listPoolsModule{}
+	dss, _ := drpc.NewDomainSocketServer(context.Background(), srvLog, sock)
+	dss.RegisterRPCModule(mod)
+	if err := dss.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer dss.Shutdown()
+
+	for name, tc := range map[string]struct {
+		args     []string
+		expCalls []*mgmtpb.ListPoolsReq
+		expOut   string
+		expErr   error
+	}{
+		"list pools": {
+			args:     []string{"call", "-s", sock, "mgmt", "ListPools", `{"sys": "daos_server"}`},
+			expCalls: []*mgmtpb.ListPoolsReq{{Sys: "daos_server"}},
+			expOut: `{
+  "status": 0,
+  "pools": [
+    {
+      "uuid": "daos_server-pool",
+      "svcreps": [
+        0,
+        1
+      ]
+    }
+  ]
+}`,
+		},
+		"empty request": {
+			args:     []string{"call", "--socket", sock, "2", "214"},
+			expCalls: []*mgmtpb.ListPoolsReq{{}},
+			expOut:   `"uuid": "-pool"`,
+		},
+		"bad request": {
+			args:   []string{"call", "-s", sock, "mgmt", "ListPools", `{"bogus": 1}`},
+			expErr: errors.New("decode mgmt.ListPoolsReq"),
+		},
+		"request for method without one": {
+			args:   []string{"call", "-s", sock, "mgmt", "StartMS", `{}`},
+			expErr: errors.New("takes no request"),
+		},
+		"failed call": {
+			args:   []string{"call", "-s", sock, "mgmt", "PoolCreate"},
+			expErr: errors.New("PoolCreate call failed with status UNKNOWN_METHOD"),
+		},
+		"missing socket": {
+			args:   []string{"call", "mgmt", "ListPools"},
+			expErr: errors.New("required flag"),
+		},
+		"bad socket": {
+			args:   []string{"call", "-s", filepath.Join(tmpDir, "none.sock"), "mgmt", "ListPools"},
+			expErr: errors.New("dRPC connect"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+			mod.calls = nil
+
+			var opts cliOptions
+			err := parseOpts(tc.args, &opts, log)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expCalls, mod.calls, common.DefaultCmpOpts()...); diff != "" {
+				t.Fatalf("unexpected calls (-want, +got):\n%s\n", diff)
+			}
+			if !strings.Contains(buf.String(), tc.expOut) {
+				t.Fatalf("expected output to contain %q", tc.expOut)
+			}
+		})
+	}
+}
+
+func TestDrpcCli_List(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	var opts cliOptions
+	if err := parseOpts([]string{"list"}, &opts, log); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, mod := range knownModules {
+		for _, meth := range mod.methods {
+			if !strings.Contains(buf.String(), meth.name) {
+				t.Fatalf("method %s of module %s not listed", meth.name, mod)
+			}
+		}
+	}
+	for _, exp := range []string{"mgmt (2)", "mgmt.PoolCreateReq", "auth.GetCredResp"} {
+		if !strings.Contains(buf.String(), exp) {
+			t.Fatalf("expected output to contain %q", exp)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
+	srvpb "github.com/daos-stack/daos/src/control/common/proto/srv"
+	"github.com/daos-stack/daos/src/control/drpc"
+	"github.com/daos-stack/daos/src/control/security/auth"
+)
+
+// newMessage returns a new, empty protobuf message.
+type newMessage func() proto.Message
+
+// methodInfo describes a known dRPC method and the protobuf messages it
+// takes and returns. A nil request or response means that the call or
+// response has no body.
+type methodInfo struct {
+	name     string
+	id       int32
+	request  newMessage
+	response newMessage
+}
+
+// moduleInfo describes a known dRPC module and its methods.
+type moduleInfo struct {
+	name    string
+	id      int32
+	methods []*methodInfo
+}
+
+func (m *moduleInfo) String() string {
+	return fmt.Sprintf("%s (%d)", m.name, m.id)
+}
+
+// knownModules is the registry of the dRPC modules and methods served by
+// daos_io_server, daos_server and daos_agent.
+var knownModules = []*moduleInfo{
+	{
+		name: "mgmt",
+		id:   drpc.ModuleMgmt,
+		methods: []*methodInfo{
+			{"PrepShutdown", drpc.MethodPrepShutdown,
+				func() proto.Message { return &mgmtpb.PrepShutdownReq{} },
+				func() proto.Message { return &mgmtpb.DaosResp{} }},
+			{"KillRank", drpc.MethodKillRank,
+				func() proto.Message { return &mgmtpb.KillRankReq{} },
+				func() proto.Message { return &mgmtpb.DaosResp{} }},
+			{"SetRank", drpc.MethodSetRank,
+				func() proto.Message { return &mgmtpb.SetRankReq{} },
+				func() proto.Message { return &mgmtpb.DaosResp{} }},
+			{"CreateMS", drpc.MethodCreateMS,
+				func() proto.Message { return &mgmtpb.CreateMsReq{} },
+				func() proto.Message { return &mgmtpb.DaosResp{} }},
+			{"StartMS", drpc.MethodStartMS,
+				nil,
+				func() proto.Message { return &mgmtpb.DaosResp{} }},
+			{"Join", drpc.MethodJoin,
+				func() proto.Message { return &mgmtpb.JoinReq{} },
+				func() proto.Message { return &mgmtpb.JoinResp{} }},
+			{"GetAttachInfo", drpc.MethodGetAttachInfo,
+				func() proto.Message { return &mgmtpb.GetAttachInfoReq{} },
+				func() proto.Message { return &mgmtpb.GetAttachInfoResp{} }},
+			{"PoolCreate", drpc.MethodPoolCreate,
+				func() proto.Message { return &mgmtpb.PoolCreateReq{} },
+				func() proto.Message { return &mgmtpb.PoolCreateResp{} }},
+			{"PoolDestroy", drpc.MethodPoolDestroy,
+				func() proto.Message { return &mgmtpb.PoolDestroyReq{} },
+				func() proto.Message { return &mgmtpb.PoolDestroyResp{} }},
+			{"BioHealth", drpc.MethodBioHealth,
+				func() proto.Message { return &mgmtpb.BioHealthReq{} },
+				func() proto.Message { return &mgmtpb.BioHealthResp{} }},
+			{"SetUp", drpc.MethodSetUp,
+				nil,
+				func() proto.Message { return &mgmtpb.DaosResp{} }},
+			{"SmdDevs", drpc.MethodSmdDevs,
+				func() proto.Message { return &mgmtpb.SmdDevReq{} },
+				func() proto.Message { return &mgmtpb.SmdDevResp{} }},
+			{"SmdPools", drpc.MethodSmdPools,
+				func() proto.Message { return &mgmtpb.SmdPoolReq{} },
+				func() proto.Message { return &mgmtpb.SmdPoolResp{} }},
+			{"PoolGetACL", drpc.MethodPoolGetACL,
+				func() proto.Message { return &mgmtpb.GetACLReq{} },
+				func() proto.Message { return &mgmtpb.ACLResp{} }},
+			{"ListPools", drpc.MethodListPools,
+				func() proto.Message { return &mgmtpb.ListPoolsReq{} },
+				func() proto.Message { return &mgmtpb.ListPoolsResp{} }},
+			{"PoolOverwriteACL", drpc.MethodPoolOverwriteACL,
+				func() proto.Message { return &mgmtpb.ModifyACLReq{} },
+				func() proto.Message { return &mgmtpb.ACLResp{} }},
+			{"PoolUpdateACL", drpc.MethodPoolUpdateACL,
+				func() proto.Message { return &mgmtpb.ModifyACLReq{} },
+				func() proto.Message { return &mgmtpb.ACLResp{} }},
+			{"PoolDeleteACL", drpc.MethodPoolDeleteACL,
+				func() proto.Message { return &mgmtpb.DeleteACLReq{} },
+				func() proto.Message { return &mgmtpb.ACLResp{} }},
+			{"DevStateQuery", drpc.MethodDevStateQuery,
+				func() proto.Message { return &mgmtpb.DevStateReq{} },
+				func() proto.Message { return &mgmtpb.DevStateResp{} }},
+			{"SetFaultyState", drpc.MethodSetFaultyState,
+				func() proto.Message { return &mgmtpb.DevStateReq{} },
+				func() proto.Message { return &mgmtpb.DevStateResp{} }},
+			{"ListContainers", drpc.MethodListContainers,
+				func() proto.Message { return &mgmtpb.ListContReq{} },
+				func() proto.Message { return &mgmtpb.ListContResp{} }},
+			{"PoolQuery", drpc.MethodPoolQuery,
+				func() proto.Message { return &mgmtpb.PoolQueryReq{} },
+				func() proto.Message { return &mgmtpb.PoolQueryResp{} }},
+			{"PoolSetProp", drpc.MethodPoolSetProp,
+				func() proto.Message { return &mgmtpb.PoolSetPropReq{} },
+				func() proto.Message { return &mgmtpb.PoolSetPropResp{} }},
+			{"SetLogMasks", drpc.MethodSetLogMasks,
+				func() proto.Message { return &mgmtpb.SetLogMasksReq{} },
+				func() proto.Message { return &mgmtpb.DaosResp{} }},
+		},
+	},
+	{
+		name: "srv",
+		id:   drpc.ModuleSrv,
+		methods: []*methodInfo{
+			{"NotifyReady", drpc.MethodNotifyReady,
+				func() proto.Message { return &srvpb.NotifyReadyReq{} },
+				nil},
+			{"BIOError", drpc.MethodBIOError,
+				func() proto.Message { return &srvpb.BioErrorReq{} },
+				nil},
+		},
+	},
+	{
+		name: "security",
+		id:   drpc.ModuleSecurity,
+		methods: []*methodInfo{
+			{"ValidateCredentials", drpc.MethodValidateCredentials,
+				func() proto.Message { return &auth.ValidateCredReq{} },
+				func() proto.Message { return &auth.ValidateCredResp{} }},
+		},
+	},
+	{
+		name: "security-agent",
+		id:   drpc.ModuleSecurityAgent,
+		methods: []*methodInfo{
+			{"RequestCredentials", drpc.MethodRequestCredentials,
+				nil,
+				func() proto.Message { return &auth.GetCredResp{} }},
+		},
+	},
+}
+
+// matches returns true if the name or decimal ID matches the string,
+// ignoring case.
+func matches(str, name string, id int32) bool {
+	if strings.EqualFold(str, name) {
+		return true
+	}
+	num, err := strconv.ParseInt(str, 10, 32)
+	return err == nil && int32(num) == id
+}
+
+// lookupMethod finds a known method by the module and method names or IDs.
+func lookupMethod(module, method string) (*moduleInfo, *methodInfo, error) {
+	for _, mod := range knownModules {
+		if !matches(module, mod.name, mod.id) {
+			continue
+		}
+		for _, meth := range mod.methods {
+			if matches(method, meth.name, meth.id) {
+				return mod, meth, nil
+			}
+		}
+		return nil, nil, errors.Errorf("unknown method %q for module %s", method, mod)
+	}
+
+	return nil, nil, errors.Errorf("unknown module %q", module)
+}
+
+// messageName returns the protobuf type of the message made by newMsg, or
+// "none" if there is no message.
+func messageName(newMsg newMessage) string {
+	if newMsg == nil {
+		return "none"
+	}
+	return proto.MessageName(newMsg())
+}
@@ -120,7 +120,7 @@ func runDrpcClient(log logging.Logger) error {
 		return errors.Wrap(err, "marshalling the Call body")
 	}
 
-	resp, err := client.SendMsg(message)
+	resp, err := client.SendMsg(context.Background(), message)
 	if err != nil {
 		return errors.Wrap(err, "sending message")
 	}
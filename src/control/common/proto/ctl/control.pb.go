@@ -27,26 +27,31 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 func init() { proto.RegisterFile("control.proto", fileDescriptor_0c5120591600887d) }
 
 var fileDescriptor_0c5120591600887d = []byte{
-	// 292 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0xcd, 0x4a, 0xc3, 0x40,
-	0x10, 0xc7, 0x5b, 0x14, 0x85, 0xd5, 0x78, 0x98, 0xc6, 0x2a, 0x39, 0xf6, 0x01, 0x42, 0xd1, 0x83,
-	0xe0, 0xd1, 0x4a, 0x0f, 0xa2, 0x52, 0x9b, 0x07, 0x90, 0x75, 0x1d, 0x4a, 0x30, 0xc9, 0xa6, 0xb3,
-	0xd3, 0x4a, 0x9e, 0xc3, 0x17, 0x96, 0xfd, 0xa8, 0x26, 0x4d, 0x8f, 0xf3, 0xdb, 0xff, 0x07, 0x93,
-	0x89, 0x88, 0x94, 0xae, 0x98, 0x74, 0x91, 0xd6, 0xa4, 0x59, 0xc3, 0x91, 0xe2, 0x22, 0x89, 0x0c,
-	0x6b, 0x92, 0x2b, 0xf4, 0x2c, 0x81, 0x0a, 0xf9, 0x5b, 0xd3, 0xd7, 0xbb, 0x51, 0xb2, 0x0a, 0xec,
-	0xdc, 0x34, 0x86, 0xb1, 0xf4, 0xd3, 0xcd, 0xcf, 0xb1, 0x38, 0x7d, 0x59, 0x95, 0x3c, 0xe3, 0x02,
-	0x66, 0xe2, 0x22, 0xf3, 0xf6, 0x05, 0x61, 0x2d, 0x09, 0x61, 0x9c, 0x2a, 0x2e, 0xd2, 0x2e, 0x5c,
-	0xe2, 0x3a, 0xb9, 0x3a, 0xc8, 0x4d, 0x3d, 0x19, 0xc0, 0xbd, 0x38, 0x0b, 0x3c, 0x53, 0xb2, 0x82,
-	0x51, 0x5b, 0x69, 0x89, 0xb5, 0xc7, 0x7d, 0xe8, 0xbc, 0x0f, 0x22, 0x0a, 0x70, 0xae, 0xa9, 0x94,
-	0x0c, 0x97, 0x6d, 0xa1, 0x67, 0xd6, 0x3f, 0x3e, 0x84, 0x6d, 0xc2, 0x74, 0xe8, 0xfa, 0xdd, 0x82,
-	0x6f, 0x1b, 0xa4, 0x66, 0xd7, 0xff, 0x4f, 0x5a, 0xfd, 0x6d, 0xe8, 0xfa, 0xef, 0x84, 0xf0, 0x30,
-	0x63, 0x5d, 0x03, 0xb4, 0x54, 0x16, 0x58, 0xe7, 0xa8, 0xc7, 0xfe, 0x96, 0x0e, 0x4c, 0x12, 0x43,
-	0x57, 0x25, 0x89, 0xf7, 0x4b, 0x03, 0x74, 0xde, 0x27, 0x11, 0xbf, 0xfa, 0x2b, 0x3d, 0xe7, 0x86,
-	0x17, 0xa4, 0xb7, 0xf9, 0x27, 0x92, 0x81, 0x6b, 0xa7, 0xdf, 0xcd, 0xf6, 0x6d, 0x89, 0xeb, 0x0d,
-	0x1a, 0x0e, 0xeb, 0x77, 0x5f, 0xea, 0xa2, 0x99, 0x0c, 0x60, 0x2e, 0x20, 0x64, 0xd9, 0xaf, 0xfa,
-	0x88, 0xdb, 0x5c, 0xa1, 0x09, 0x57, 0xf4, 0x53, 0x38, 0x81, 0xcb, 0x89, 0x7b, 0xdc, 0xa5, 0x4c,
-	0x87, 0x1f, 0x27, 0xee, 0xe7, 0xb8, 0xfd, 0x0d, 0x00, 0x00, 0xff, 0xff, 0xd1, 0xd3, 0xc5, 0x2b,
-	0x63, 0x02, 0x00, 0x00,
+	// 382 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0xd3, 0xdd, 0x4e, 0xe2, 0x40,
+	0x14, 0x07, 0x70, 0xc8, 0x26, 0x6c, 0x32, 0x4b, 0x37, 0xbb, 0x07, 0x96, 0xd5, 0x5e, 0xf2, 0x00,
+	0x84, 0xe8, 0x85, 0x89, 0x57, 0x46, 0x94, 0x0b, 0x53, 0x0d, 0xd2, 0x07, 0x30, 0x63, 0x39, 0x69,
+	0x1a, 0x4b, 0xa7, 0xcc, 0x9c, 0x56, 0x79, 0x4f, 0x1f, 0xc8, 0xcc, 0x47, 0xe9, 0xf0, 0xe1, 0x65,
+	0x7f, 0x73, 0x3e, 0x32, 0xff, 0x4c, 0x59, 0x90, 0x88, 0x82, 0xa4, 0xc8, 0x27, 0xa5, 0x14, 0x24,
+	0xe0, 0x47, 0x42, 0x79, 0x18, 0x28, 0x12, 0x92, 0xa7, 0x68, 0x2d, 0x84, 0x02, 0xe9, 0x5d, 0xc8,
+	0xb7, 0x17, 0x95, 0xf0, 0xc2, 0x59, 0x5f, 0x6d, 0x15, 0xe1, 0xda, 0x7e, 0x5d, 0x7c, 0xf6, 0xd8,
+	0xcf, 0xc7, 0x74, 0x4d, 0x33, 0xca, 0x61, 0xc6, 0x7e, 0xc7, 0xb6, 0x7d, 0x21, 0xb1, 0xe4, 0x12,
+	0x61, 0x34, 0x49, 0x28, 0x9f, 0xec, 0xe3, 0x12, 0x37, 0xe1, 0xff, 0x93, 0xae, 0xca, 0x71, 0x07,
+	0xae, 0xd9, 0x2f, 0xe7, 0x71, 0xc2, 0x0b, 0x18, 0xf8, 0x95, 0x5a, 0x74, 0xfb, 0xf0, 0x18, 0x4d,
+	0xef, 0x2d, 0x0b, 0x1c, 0xce, 0x85, 0x5c, 0x73, 0x82, 0x7f, 0x7e, 0xa1, 0x35, 0xdd, 0x3f, 0x3a,
+	0xc5, 0x7a, 0xc2, 0xb4, 0x6b, 0xf6, 0x9b, 0x0b, 0x3e, 0x57, 0x28, 0xb7, 0xcd, 0xfe, 0x56, 0xbc,
+	0xfd, 0x3e, 0x9a, 0xfd, 0x57, 0x8c, 0x59, 0x8c, 0x49, 0x94, 0x00, 0x5e, 0x95, 0x06, 0xdd, 0x39,
+	0x38, 0xb2, 0xdd, 0xa5, 0x9d, 0x71, 0x49, 0xb0, 0x5f, 0xc5, 0x25, 0x1d, 0x2e, 0x75, 0xe8, 0x7a,
+	0xfb, 0x16, 0xef, 0x6b, 0x2c, 0x48, 0x81, 0x5f, 0x67, 0x49, 0x77, 0xff, 0x39, 0x54, 0x73, 0xd9,
+	0x1b, 0x16, 0x38, 0xfa, 0x48, 0xf2, 0x6a, 0x85, 0x4d, 0x60, 0xbe, 0x79, 0x81, 0xed, 0xb3, 0xd9,
+	0x1e, 0xb1, 0xbf, 0x96, 0x97, 0x98, 0x15, 0x84, 0xa9, 0xe4, 0x84, 0x70, 0xee, 0x95, 0x7b, 0xae,
+	0x27, 0x85, 0xdf, 0x1d, 0x35, 0x01, 0xce, 0x50, 0x52, 0x4c, 0x9c, 0x2a, 0xe5, 0x02, 0x6c, 0xa1,
+	0x0d, 0xd0, 0xb7, 0x5d, 0x80, 0x48, 0x91, 0x48, 0x23, 0xac, 0x31, 0x6f, 0x02, 0x6c, 0xc5, 0x0b,
+	0xd0, 0x47, 0xd3, 0xfb, 0xc0, 0x86, 0x4f, 0xf6, 0x99, 0x47, 0x99, 0xa2, 0x85, 0x14, 0x75, 0xb6,
+	0x42, 0xa9, 0xe0, 0xcc, 0xd4, 0x37, 0xdf, 0xfa, 0x6c, 0x89, 0x9b, 0x0a, 0x15, 0x85, 0xa3, 0x13,
+	0x27, 0x65, 0xbe, 0x1d, 0x77, 0x60, 0xce, 0xc0, 0xcd, 0xd2, 0xcf, 0xf2, 0x0e, 0xeb, 0x2c, 0x41,
+	0xe5, 0x7e, 0x03, 0xfb, 0xe5, 0xde, 0xb0, 0x99, 0x33, 0x3c, 0x72, 0x33, 0x65, 0xda, 0x7d, 0xed,
+	0x99, 0xbf, 0xeb, 0xf2, 0x6b, 0x00, 0xdf, 0x5c, 0x26, 0xa8, 0xa4, 0x03, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -73,6 +78,16 @@ type MgmtCtlClient interface {
 	SystemStop(ctx context.Context, in *SystemStopReq, opts ...grpc.CallOption) (*SystemStopResp, error)
 	// Start DAOS system (restart data-plane instances)
 	SystemStart(ctx context.Context, in *SystemStartReq, opts ...grpc.CallOption) (*SystemStartResp, error)
+	// Retrieve DAOS system event log, optionally following new events
+	SystemEvents(ctx context.Context, in *SystemEventsReq, opts ...grpc.CallOption) (MgmtCtl_SystemEventsClient, error)
+	// Exclude ranks from DAOS system until reintegrated
+	SystemExclude(ctx context.Context, in *SystemExcludeReq, opts ...grpc.CallOption) (*SystemExcludeResp, error)
+	// Reintegrate previously excluded ranks into DAOS system
+	SystemReintegrate(ctx context.Context, in *SystemReintegrateReq, opts ...grpc.CallOption) (*SystemReintegrateResp, error)
+	// Retrieve details of the certificates in use by the server
+	CertStatus(ctx context.Context, in *CertStatusReq, opts ...grpc.CallOption) (*CertStatusResp, error)
+	// Change control and data plane log levels without restarting
+	SetLogLevel(ctx context.Context, in *SetLogLevelReq, opts ...grpc.CallOption) (*SetLogLevelResp, error)
 	// Retrieve a list of supported fabric providers
 	NetworkListProviders(ctx context.Context, in *ProviderListRequest, opts ...grpc.CallOption) (*ProviderListReply, error)
 	// Perform a fabric scan to determine the available provider, device, NUMA node combinations
@@ -164,6 +179,74 @@ func (c *mgmtCtlClient) SystemStart(ctx context.Context, in *SystemStartReq, opt
 	return out, nil
 }
 
+func (c *mgmtCtlClient) SystemEvents(ctx context.Context, in *SystemEventsReq, opts ...grpc.CallOption) (MgmtCtl_SystemEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_MgmtCtl_serviceDesc.Streams[1], "/ctl.MgmtCtl/SystemEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &mgmtCtlSystemEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MgmtCtl_SystemEventsClient interface {
+	Recv() (*SystemEvent, error)
+	grpc.ClientStream
+}
+
+type mgmtCtlSystemEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *mgmtCtlSystemEventsClient) Recv() (*SystemEvent, error) {
+	m := new(SystemEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *mgmtCtlClient) SystemExclude(ctx context.Context, in *SystemExcludeReq, opts ...grpc.CallOption) (*SystemExcludeResp, error) {
+	out := new(SystemExcludeResp)
+	err := c.cc.Invoke(ctx, "/ctl.MgmtCtl/SystemExclude", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mgmtCtlClient) SystemReintegrate(ctx context.Context, in *SystemReintegrateReq, opts ...grpc.CallOption) (*SystemReintegrateResp, error) {
+	out := new(SystemReintegrateResp)
+	err := c.cc.Invoke(ctx, "/ctl.MgmtCtl/SystemReintegrate", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mgmtCtlClient) CertStatus(ctx context.Context, in *CertStatusReq, opts ...grpc.CallOption) (*CertStatusResp, error) {
+	out := new(CertStatusResp)
+	err := c.cc.Invoke(ctx, "/ctl.MgmtCtl/CertStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mgmtCtlClient) SetLogLevel(ctx context.Context, in *SetLogLevelReq, opts ...grpc.CallOption) (*SetLogLevelResp, error) {
+	out := new(SetLogLevelResp)
+	err := c.cc.Invoke(ctx, "/ctl.MgmtCtl/SetLogLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mgmtCtlClient) NetworkListProviders(ctx context.Context, in *ProviderListRequest, opts ...grpc.CallOption) (*ProviderListReply, error) {
 	out := new(ProviderListReply)
 	err := c.cc.Invoke(ctx, "/ctl.MgmtCtl/NetworkListProviders", in, out, opts...)
@@ -174,7 +257,7 @@ func (c *mgmtCtlClient) NetworkListProviders(ctx context.Context, in *ProviderLi
 }
 
 func (c *mgmtCtlClient) NetworkScanDevices(ctx context.Context, in *DeviceScanRequest, opts ...grpc.CallOption) (MgmtCtl_NetworkScanDevicesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &_MgmtCtl_serviceDesc.Streams[1], "/ctl.MgmtCtl/NetworkScanDevices", opts...)
+	stream, err := c.cc.NewStream(ctx, &_MgmtCtl_serviceDesc.Streams[2], "/ctl.MgmtCtl/NetworkScanDevices", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -219,6 +302,16 @@ type MgmtCtlServer interface {
 	SystemStop(context.Context, *SystemStopReq) (*SystemStopResp, error)
 	// Start DAOS system (restart data-plane instances)
 	SystemStart(context.Context, *SystemStartReq) (*SystemStartResp, error)
+	// Retrieve DAOS system event log, optionally following new events
+	SystemEvents(*SystemEventsReq, MgmtCtl_SystemEventsServer) error
+	// Exclude ranks from DAOS system until reintegrated
+	SystemExclude(context.Context, *SystemExcludeReq) (*SystemExcludeResp, error)
+	// Reintegrate previously excluded ranks into DAOS system
+	SystemReintegrate(context.Context, *SystemReintegrateReq) (*SystemReintegrateResp, error)
+	// Retrieve details of the certificates in use by the server
+	CertStatus(context.Context, *CertStatusReq) (*CertStatusResp, error)
+	// Change control and data plane log levels without restarting
+	SetLogLevel(context.Context, *SetLogLevelReq) (*SetLogLevelResp, error)
 	// Retrieve a list of supported fabric providers
 	NetworkListProviders(context.Context, *ProviderListRequest) (*ProviderListReply, error)
 	// Perform a fabric scan to determine the available provider, device, NUMA node combinations
@@ -247,6 +340,21 @@ func (*UnimplementedMgmtCtlServer) SystemStop(ctx context.Context, req *SystemSt
 func (*UnimplementedMgmtCtlServer) SystemStart(ctx context.Context, req *SystemStartReq) (*SystemStartResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SystemStart not implemented")
 }
+func (*UnimplementedMgmtCtlServer) SystemEvents(req *SystemEventsReq, srv MgmtCtl_SystemEventsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SystemEvents not implemented")
+}
+func (*UnimplementedMgmtCtlServer) SystemExclude(ctx context.Context, req *SystemExcludeReq) (*SystemExcludeResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SystemExclude not implemented")
+}
+func (*UnimplementedMgmtCtlServer) SystemReintegrate(ctx context.Context, req *SystemReintegrateReq) (*SystemReintegrateResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SystemReintegrate not implemented")
+}
+func (*UnimplementedMgmtCtlServer) CertStatus(ctx context.Context, req *CertStatusReq) (*CertStatusResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CertStatus not implemented")
+}
+func (*UnimplementedMgmtCtlServer) SetLogLevel(ctx context.Context, req *SetLogLevelReq) (*SetLogLevelResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetLogLevel not implemented")
+}
 func (*UnimplementedMgmtCtlServer) NetworkListProviders(ctx context.Context, req *ProviderListRequest) (*ProviderListReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NetworkListProviders not implemented")
 }
@@ -369,6 +477,99 @@ func _MgmtCtl_SystemStart_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MgmtCtl_SystemEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SystemEventsReq)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MgmtCtlServer).SystemEvents(m, &mgmtCtlSystemEventsServer{stream})
+}
+
+type MgmtCtl_SystemEventsServer interface {
+	Send(*SystemEvent) error
+	grpc.ServerStream
+}
+
+type mgmtCtlSystemEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *mgmtCtlSystemEventsServer) Send(m *SystemEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MgmtCtl_SystemExclude_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SystemExcludeReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MgmtCtlServer).SystemExclude(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ctl.MgmtCtl/SystemExclude",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MgmtCtlServer).SystemExclude(ctx, req.(*SystemExcludeReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MgmtCtl_SystemReintegrate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SystemReintegrateReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MgmtCtlServer).SystemReintegrate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ctl.MgmtCtl/SystemReintegrate",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MgmtCtlServer).SystemReintegrate(ctx, req.(*SystemReintegrateReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MgmtCtl_CertStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CertStatusReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MgmtCtlServer).CertStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ctl.MgmtCtl/CertStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MgmtCtlServer).CertStatus(ctx, req.(*CertStatusReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MgmtCtl_SetLogLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLogLevelReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MgmtCtlServer).SetLogLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ctl.MgmtCtl/SetLogLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MgmtCtlServer).SetLogLevel(ctx, req.(*SetLogLevelReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MgmtCtl_NetworkListProviders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ProviderListRequest)
 	if err := dec(in); err != nil {
@@ -432,6 +633,22 @@ var _MgmtCtl_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SystemStart",
 			Handler:    _MgmtCtl_SystemStart_Handler,
 		},
+		{
+			MethodName: "SystemExclude",
+			Handler:    _MgmtCtl_SystemExclude_Handler,
+		},
+		{
+			MethodName: "SystemReintegrate",
+			Handler:    _MgmtCtl_SystemReintegrate_Handler,
+		},
+		{
+			MethodName: "CertStatus",
+			Handler:    _MgmtCtl_CertStatus_Handler,
+		},
+		{
+			MethodName: "SetLogLevel",
+			Handler:    _MgmtCtl_SetLogLevel_Handler,
+		},
 		{
 			MethodName: "NetworkListProviders",
 			Handler:    _MgmtCtl_NetworkListProviders_Handler,
@@ -443,6 +660,11 @@ var _MgmtCtl_serviceDesc = grpc.ServiceDesc{
 			Handler:       _MgmtCtl_StorageFormat_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "SystemEvents",
+			Handler:       _MgmtCtl_SystemEvents_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "NetworkScanDevices",
 			Handler:       _MgmtCtl_NetworkScanDevices_Handler,
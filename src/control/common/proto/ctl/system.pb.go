@@ -27,6 +27,14 @@ type SystemMember struct {
 	Uuid                 string   `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
 	Rank                 uint32   `protobuf:"varint,3,opt,name=rank,proto3" json:"rank,omitempty"`
 	State                uint32   `protobuf:"varint,4,opt,name=state,proto3" json:"state,omitempty"`
+	Hostname             string   `protobuf:"bytes,5,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Idx                  uint32   `protobuf:"varint,6,opt,name=idx,proto3" json:"idx,omitempty"`
+	Fabriciface          string   `protobuf:"bytes,7,opt,name=fabriciface,proto3" json:"fabriciface,omitempty"`
+	Fabricprovider       string   `protobuf:"bytes,8,opt,name=fabricprovider,proto3" json:"fabricprovider,omitempty"`
+	Ntargets             uint32   `protobuf:"varint,9,opt,name=ntargets,proto3" json:"ntargets,omitempty"`
+	Statechanged         string   `protobuf:"bytes,10,opt,name=statechanged,proto3" json:"statechanged,omitempty"`
+	Statereason          string   `protobuf:"bytes,11,opt,name=statereason,proto3" json:"statereason,omitempty"`
+	Uptime               uint64   `protobuf:"varint,12,opt,name=uptime,proto3" json:"uptime,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -85,6 +93,62 @@ func (m *SystemMember) GetState() uint32 {
 	return 0
 }
 
+func (m *SystemMember) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *SystemMember) GetIdx() uint32 {
+	if m != nil {
+		return m.Idx
+	}
+	return 0
+}
+
+func (m *SystemMember) GetFabriciface() string {
+	if m != nil {
+		return m.Fabriciface
+	}
+	return ""
+}
+
+func (m *SystemMember) GetFabricprovider() string {
+	if m != nil {
+		return m.Fabricprovider
+	}
+	return ""
+}
+
+func (m *SystemMember) GetNtargets() uint32 {
+	if m != nil {
+		return m.Ntargets
+	}
+	return 0
+}
+
+func (m *SystemMember) GetStatechanged() string {
+	if m != nil {
+		return m.Statechanged
+	}
+	return ""
+}
+
+func (m *SystemMember) GetStatereason() string {
+	if m != nil {
+		return m.Statereason
+	}
+	return ""
+}
+
+func (m *SystemMember) GetUptime() uint64 {
+	if m != nil {
+		return m.Uptime
+	}
+	return 0
+}
+
 // SystemStopReq supplies system shutdown parameters.
 type SystemStopReq struct {
 	Prep                 bool     `protobuf:"varint,1,opt,name=prep,proto3" json:"prep,omitempty"`
@@ -374,6 +438,656 @@ func (m *SystemQueryResp) GetMembers() []*SystemMember {
 	return nil
 }
 
+// SystemExcludeReq supplies the ranks to be excluded from the system.
+type SystemExcludeReq struct {
+	Ranks                []uint32 `protobuf:"varint,1,rep,packed,name=ranks,proto3" json:"ranks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SystemExcludeReq) Reset()         { *m = SystemExcludeReq{} }
+func (m *SystemExcludeReq) String() string { return proto.CompactTextString(m) }
+func (*SystemExcludeReq) ProtoMessage()    {}
+func (*SystemExcludeReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{7}
+}
+
+func (m *SystemExcludeReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SystemExcludeReq.Unmarshal(m, b)
+}
+func (m *SystemExcludeReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SystemExcludeReq.Marshal(b, m, deterministic)
+}
+func (m *SystemExcludeReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SystemExcludeReq.Merge(m, src)
+}
+func (m *SystemExcludeReq) XXX_Size() int {
+	return xxx_messageInfo_SystemExcludeReq.Size(m)
+}
+func (m *SystemExcludeReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SystemExcludeReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SystemExcludeReq proto.InternalMessageInfo
+
+func (m *SystemExcludeReq) GetRanks() []uint32 {
+	if m != nil {
+		return m.Ranks
+	}
+	return nil
+}
+
+// SystemExcludeResp returns results of attempts to exclude system members.
+type SystemExcludeResp struct {
+	Results              []*SystemStopResp_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *SystemExcludeResp) Reset()         { *m = SystemExcludeResp{} }
+func (m *SystemExcludeResp) String() string { return proto.CompactTextString(m) }
+func (*SystemExcludeResp) ProtoMessage()    {}
+func (*SystemExcludeResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{8}
+}
+
+func (m *SystemExcludeResp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SystemExcludeResp.Unmarshal(m, b)
+}
+func (m *SystemExcludeResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SystemExcludeResp.Marshal(b, m, deterministic)
+}
+func (m *SystemExcludeResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SystemExcludeResp.Merge(m, src)
+}
+func (m *SystemExcludeResp) XXX_Size() int {
+	return xxx_messageInfo_SystemExcludeResp.Size(m)
+}
+func (m *SystemExcludeResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_SystemExcludeResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SystemExcludeResp proto.InternalMessageInfo
+
+func (m *SystemExcludeResp) GetResults() []*SystemStopResp_Result {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// SystemReintegrateReq supplies the excluded ranks to be reintegrated.
+type SystemReintegrateReq struct {
+	Ranks                []uint32 `protobuf:"varint,1,rep,packed,name=ranks,proto3" json:"ranks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SystemReintegrateReq) Reset()         { *m = SystemReintegrateReq{} }
+func (m *SystemReintegrateReq) String() string { return proto.CompactTextString(m) }
+func (*SystemReintegrateReq) ProtoMessage()    {}
+func (*SystemReintegrateReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{9}
+}
+
+func (m *SystemReintegrateReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SystemReintegrateReq.Unmarshal(m, b)
+}
+func (m *SystemReintegrateReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SystemReintegrateReq.Marshal(b, m, deterministic)
+}
+func (m *SystemReintegrateReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SystemReintegrateReq.Merge(m, src)
+}
+func (m *SystemReintegrateReq) XXX_Size() int {
+	return xxx_messageInfo_SystemReintegrateReq.Size(m)
+}
+func (m *SystemReintegrateReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SystemReintegrateReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SystemReintegrateReq proto.InternalMessageInfo
+
+func (m *SystemReintegrateReq) GetRanks() []uint32 {
+	if m != nil {
+		return m.Ranks
+	}
+	return nil
+}
+
+// SystemReintegrateResp returns results of attempts to reintegrate excluded
+// system members.
+type SystemReintegrateResp struct {
+	Results              []*SystemStopResp_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *SystemReintegrateResp) Reset()         { *m = SystemReintegrateResp{} }
+func (m *SystemReintegrateResp) String() string { return proto.CompactTextString(m) }
+func (*SystemReintegrateResp) ProtoMessage()    {}
+func (*SystemReintegrateResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{10}
+}
+
+func (m *SystemReintegrateResp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SystemReintegrateResp.Unmarshal(m, b)
+}
+func (m *SystemReintegrateResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SystemReintegrateResp.Marshal(b, m, deterministic)
+}
+func (m *SystemReintegrateResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SystemReintegrateResp.Merge(m, src)
+}
+func (m *SystemReintegrateResp) XXX_Size() int {
+	return xxx_messageInfo_SystemReintegrateResp.Size(m)
+}
+func (m *SystemReintegrateResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_SystemReintegrateResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SystemReintegrateResp proto.InternalMessageInfo
+
+func (m *SystemReintegrateResp) GetResults() []*SystemStopResp_Result {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// SystemEventsReq supplies system event log query parameters.
+type SystemEventsReq struct {
+	Since                string   `protobuf:"bytes,1,opt,name=since,proto3" json:"since,omitempty"`
+	Ranks                []uint32 `protobuf:"varint,2,rep,packed,name=ranks,proto3" json:"ranks,omitempty"`
+	Follow               bool     `protobuf:"varint,3,opt,name=follow,proto3" json:"follow,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SystemEventsReq) Reset()         { *m = SystemEventsReq{} }
+func (m *SystemEventsReq) String() string { return proto.CompactTextString(m) }
+func (*SystemEventsReq) ProtoMessage()    {}
+func (*SystemEventsReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{11}
+}
+
+func (m *SystemEventsReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SystemEventsReq.Unmarshal(m, b)
+}
+func (m *SystemEventsReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SystemEventsReq.Marshal(b, m, deterministic)
+}
+func (m *SystemEventsReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SystemEventsReq.Merge(m, src)
+}
+func (m *SystemEventsReq) XXX_Size() int {
+	return xxx_messageInfo_SystemEventsReq.Size(m)
+}
+func (m *SystemEventsReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SystemEventsReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SystemEventsReq proto.InternalMessageInfo
+
+func (m *SystemEventsReq) GetSince() string {
+	if m != nil {
+		return m.Since
+	}
+	return ""
+}
+
+func (m *SystemEventsReq) GetRanks() []uint32 {
+	if m != nil {
+		return m.Ranks
+	}
+	return nil
+}
+
+func (m *SystemEventsReq) GetFollow() bool {
+	if m != nil {
+		return m.Follow
+	}
+	return false
+}
+
+// SystemEvent describes an entry in the system event log.
+type SystemEvent struct {
+	Sequence             uint64   `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Time                 string   `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	Type                 uint32   `protobuf:"varint,3,opt,name=type,proto3" json:"type,omitempty"`
+	Rank                 uint32   `protobuf:"varint,4,opt,name=rank,proto3" json:"rank,omitempty"`
+	Oldstate             uint32   `protobuf:"varint,5,opt,name=oldstate,proto3" json:"oldstate,omitempty"`
+	Newstate             uint32   `protobuf:"varint,6,opt,name=newstate,proto3" json:"newstate,omitempty"`
+	Reason               string   `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	Identity             string   `protobuf:"bytes,8,opt,name=identity,proto3" json:"identity,omitempty"`
+	Origin               string   `protobuf:"bytes,9,opt,name=origin,proto3" json:"origin,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SystemEvent) Reset()         { *m = SystemEvent{} }
+func (m *SystemEvent) String() string { return proto.CompactTextString(m) }
+func (*SystemEvent) ProtoMessage()    {}
+func (*SystemEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{12}
+}
+
+func (m *SystemEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SystemEvent.Unmarshal(m, b)
+}
+func (m *SystemEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SystemEvent.Marshal(b, m, deterministic)
+}
+func (m *SystemEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SystemEvent.Merge(m, src)
+}
+func (m *SystemEvent) XXX_Size() int {
+	return xxx_messageInfo_SystemEvent.Size(m)
+}
+func (m *SystemEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_SystemEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SystemEvent proto.InternalMessageInfo
+
+func (m *SystemEvent) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *SystemEvent) GetTime() string {
+	if m != nil {
+		return m.Time
+	}
+	return ""
+}
+
+func (m *SystemEvent) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *SystemEvent) GetRank() uint32 {
+	if m != nil {
+		return m.Rank
+	}
+	return 0
+}
+
+func (m *SystemEvent) GetOldstate() uint32 {
+	if m != nil {
+		return m.Oldstate
+	}
+	return 0
+}
+
+func (m *SystemEvent) GetNewstate() uint32 {
+	if m != nil {
+		return m.Newstate
+	}
+	return 0
+}
+
+func (m *SystemEvent) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *SystemEvent) GetIdentity() string {
+	if m != nil {
+		return m.Identity
+	}
+	return ""
+}
+
+func (m *SystemEvent) GetOrigin() string {
+	if m != nil {
+		return m.Origin
+	}
+	return ""
+}
+
+// CertStatusReq requests details of the certificates in use by a server.
+type CertStatusReq struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CertStatusReq) Reset()         { *m = CertStatusReq{} }
+func (m *CertStatusReq) String() string { return proto.CompactTextString(m) }
+func (*CertStatusReq) ProtoMessage()    {}
+func (*CertStatusReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{13}
+}
+
+func (m *CertStatusReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CertStatusReq.Unmarshal(m, b)
+}
+func (m *CertStatusReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CertStatusReq.Marshal(b, m, deterministic)
+}
+func (m *CertStatusReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CertStatusReq.Merge(m, src)
+}
+func (m *CertStatusReq) XXX_Size() int {
+	return xxx_messageInfo_CertStatusReq.Size(m)
+}
+func (m *CertStatusReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_CertStatusReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CertStatusReq proto.InternalMessageInfo
+
+// CertInfo describes a certificate.
+type CertInfo struct {
+	Subject              string   `protobuf:"bytes,1,opt,name=subject,proto3" json:"subject,omitempty"`
+	Issuer               string   `protobuf:"bytes,2,opt,name=issuer,proto3" json:"issuer,omitempty"`
+	Notbefore            string   `protobuf:"bytes,3,opt,name=notbefore,proto3" json:"notbefore,omitempty"`
+	Notafter             string   `protobuf:"bytes,4,opt,name=notafter,proto3" json:"notafter,omitempty"`
+	Dnsnames             []string `protobuf:"bytes,5,rep,name=dnsnames,proto3" json:"dnsnames,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CertInfo) Reset()         { *m = CertInfo{} }
+func (m *CertInfo) String() string { return proto.CompactTextString(m) }
+func (*CertInfo) ProtoMessage()    {}
+func (*CertInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{14}
+}
+
+func (m *CertInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CertInfo.Unmarshal(m, b)
+}
+func (m *CertInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CertInfo.Marshal(b, m, deterministic)
+}
+func (m *CertInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CertInfo.Merge(m, src)
+}
+func (m *CertInfo) XXX_Size() int {
+	return xxx_messageInfo_CertInfo.Size(m)
+}
+func (m *CertInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_CertInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CertInfo proto.InternalMessageInfo
+
+func (m *CertInfo) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *CertInfo) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *CertInfo) GetNotbefore() string {
+	if m != nil {
+		return m.Notbefore
+	}
+	return ""
+}
+
+func (m *CertInfo) GetNotafter() string {
+	if m != nil {
+		return m.Notafter
+	}
+	return ""
+}
+
+func (m *CertInfo) GetDnsnames() []string {
+	if m != nil {
+		return m.Dnsnames
+	}
+	return nil
+}
+
+// CertStatusResp returns details of the certificates in use by a server.
+type CertStatusResp struct {
+	Insecure             bool      `protobuf:"varint,1,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	Cert                 *CertInfo `protobuf:"bytes,2,opt,name=cert,proto3" json:"cert,omitempty"`
+	Ca                   *CertInfo `protobuf:"bytes,3,opt,name=ca,proto3" json:"ca,omitempty"`
+	Error                string    `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CertStatusResp) Reset()         { *m = CertStatusResp{} }
+func (m *CertStatusResp) String() string { return proto.CompactTextString(m) }
+func (*CertStatusResp) ProtoMessage()    {}
+func (*CertStatusResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{15}
+}
+
+func (m *CertStatusResp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CertStatusResp.Unmarshal(m, b)
+}
+func (m *CertStatusResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CertStatusResp.Marshal(b, m, deterministic)
+}
+func (m *CertStatusResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CertStatusResp.Merge(m, src)
+}
+func (m *CertStatusResp) XXX_Size() int {
+	return xxx_messageInfo_CertStatusResp.Size(m)
+}
+func (m *CertStatusResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_CertStatusResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CertStatusResp proto.InternalMessageInfo
+
+func (m *CertStatusResp) GetInsecure() bool {
+	if m != nil {
+		return m.Insecure
+	}
+	return false
+}
+
+func (m *CertStatusResp) GetCert() *CertInfo {
+	if m != nil {
+		return m.Cert
+	}
+	return nil
+}
+
+func (m *CertStatusResp) GetCa() *CertInfo {
+	if m != nil {
+		return m.Ca
+	}
+	return nil
+}
+
+func (m *CertStatusResp) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// SetLogLevelReq supplies new log levels for a server, empty values leave
+// the corresponding level unchanged.
+type SetLogLevelReq struct {
+	Control              string   `protobuf:"bytes,1,opt,name=control,proto3" json:"control,omitempty"`
+	Engine               string   `protobuf:"bytes,2,opt,name=engine,proto3" json:"engine,omitempty"`
+	Ranks                []uint32 `protobuf:"varint,3,rep,packed,name=ranks,proto3" json:"ranks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetLogLevelReq) Reset()         { *m = SetLogLevelReq{} }
+func (m *SetLogLevelReq) String() string { return proto.CompactTextString(m) }
+func (*SetLogLevelReq) ProtoMessage()    {}
+func (*SetLogLevelReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{16}
+}
+
+func (m *SetLogLevelReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetLogLevelReq.Unmarshal(m, b)
+}
+func (m *SetLogLevelReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetLogLevelReq.Marshal(b, m, deterministic)
+}
+func (m *SetLogLevelReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetLogLevelReq.Merge(m, src)
+}
+func (m *SetLogLevelReq) XXX_Size() int {
+	return xxx_messageInfo_SetLogLevelReq.Size(m)
+}
+func (m *SetLogLevelReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetLogLevelReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetLogLevelReq proto.InternalMessageInfo
+
+func (m *SetLogLevelReq) GetControl() string {
+	if m != nil {
+		return m.Control
+	}
+	return ""
+}
+
+func (m *SetLogLevelReq) GetEngine() string {
+	if m != nil {
+		return m.Engine
+	}
+	return ""
+}
+
+func (m *SetLogLevelReq) GetRanks() []uint32 {
+	if m != nil {
+		return m.Ranks
+	}
+	return nil
+}
+
+// SetLogLevelResp returns the levels in effect before the change so that
+// it can be reverted.
+type SetLogLevelResp struct {
+	Prevcontrol          string                    `protobuf:"bytes,1,opt,name=prevcontrol,proto3" json:"prevcontrol,omitempty"`
+	Engines              []*SetLogLevelResp_Engine `protobuf:"bytes,2,rep,name=engines,proto3" json:"engines,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *SetLogLevelResp) Reset()         { *m = SetLogLevelResp{} }
+func (m *SetLogLevelResp) String() string { return proto.CompactTextString(m) }
+func (*SetLogLevelResp) ProtoMessage()    {}
+func (*SetLogLevelResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{17}
+}
+
+func (m *SetLogLevelResp) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetLogLevelResp.Unmarshal(m, b)
+}
+func (m *SetLogLevelResp) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetLogLevelResp.Marshal(b, m, deterministic)
+}
+func (m *SetLogLevelResp) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetLogLevelResp.Merge(m, src)
+}
+func (m *SetLogLevelResp) XXX_Size() int {
+	return xxx_messageInfo_SetLogLevelResp.Size(m)
+}
+func (m *SetLogLevelResp) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetLogLevelResp.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetLogLevelResp proto.InternalMessageInfo
+
+func (m *SetLogLevelResp) GetPrevcontrol() string {
+	if m != nil {
+		return m.Prevcontrol
+	}
+	return ""
+}
+
+func (m *SetLogLevelResp) GetEngines() []*SetLogLevelResp_Engine {
+	if m != nil {
+		return m.Engines
+	}
+	return nil
+}
+
+type SetLogLevelResp_Engine struct {
+	Rank                 uint32   `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Prevmask             string   `protobuf:"bytes,2,opt,name=prevmask,proto3" json:"prevmask,omitempty"`
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetLogLevelResp_Engine) Reset()         { *m = SetLogLevelResp_Engine{} }
+func (m *SetLogLevelResp_Engine) String() string { return proto.CompactTextString(m) }
+func (*SetLogLevelResp_Engine) ProtoMessage()    {}
+func (*SetLogLevelResp_Engine) Descriptor() ([]byte, []int) {
+	return fileDescriptor_86a7260ebdc12f47, []int{17, 0}
+}
+
+func (m *SetLogLevelResp_Engine) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetLogLevelResp_Engine.Unmarshal(m, b)
+}
+func (m *SetLogLevelResp_Engine) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetLogLevelResp_Engine.Marshal(b, m, deterministic)
+}
+func (m *SetLogLevelResp_Engine) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetLogLevelResp_Engine.Merge(m, src)
+}
+func (m *SetLogLevelResp_Engine) XXX_Size() int {
+	return xxx_messageInfo_SetLogLevelResp_Engine.Size(m)
+}
+func (m *SetLogLevelResp_Engine) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetLogLevelResp_Engine.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetLogLevelResp_Engine proto.InternalMessageInfo
+
+func (m *SetLogLevelResp_Engine) GetRank() uint32 {
+	if m != nil {
+		return m.Rank
+	}
+	return 0
+}
+
+func (m *SetLogLevelResp_Engine) GetPrevmask() string {
+	if m != nil {
+		return m.Prevmask
+	}
+	return ""
+}
+
+func (m *SetLogLevelResp_Engine) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*SystemMember)(nil), "ctl.SystemMember")
 	proto.RegisterType((*SystemStopReq)(nil), "ctl.SystemStopReq")
@@ -383,28 +1097,73 @@ func init() {
 	proto.RegisterType((*SystemStartResp)(nil), "ctl.SystemStartResp")
 	proto.RegisterType((*SystemQueryReq)(nil), "ctl.SystemQueryReq")
 	proto.RegisterType((*SystemQueryResp)(nil), "ctl.SystemQueryResp")
+	proto.RegisterType((*SystemExcludeReq)(nil), "ctl.SystemExcludeReq")
+	proto.RegisterType((*SystemExcludeResp)(nil), "ctl.SystemExcludeResp")
+	proto.RegisterType((*SystemReintegrateReq)(nil), "ctl.SystemReintegrateReq")
+	proto.RegisterType((*SystemReintegrateResp)(nil), "ctl.SystemReintegrateResp")
+	proto.RegisterType((*SystemEventsReq)(nil), "ctl.SystemEventsReq")
+	proto.RegisterType((*SystemEvent)(nil), "ctl.SystemEvent")
+	proto.RegisterType((*CertStatusReq)(nil), "ctl.CertStatusReq")
+	proto.RegisterType((*CertInfo)(nil), "ctl.CertInfo")
+	proto.RegisterType((*CertStatusResp)(nil), "ctl.CertStatusResp")
+	proto.RegisterType((*SetLogLevelReq)(nil), "ctl.SetLogLevelReq")
+	proto.RegisterType((*SetLogLevelResp)(nil), "ctl.SetLogLevelResp")
+	proto.RegisterType((*SetLogLevelResp_Engine)(nil), "ctl.SetLogLevelResp.Engine")
 }
 
 func init() { proto.RegisterFile("system.proto", fileDescriptor_86a7260ebdc12f47) }
 
 var fileDescriptor_86a7260ebdc12f47 = []byte{
-	// 285 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0xc1, 0x6a, 0xbb, 0x40,
-	0x10, 0xc6, 0xd9, 0xbf, 0xf9, 0x47, 0x33, 0x4d, 0xda, 0x64, 0x29, 0x65, 0xc9, 0x49, 0x3c, 0x09,
-	0x05, 0x0f, 0x6d, 0xa1, 0xb7, 0xbe, 0x41, 0x0f, 0xdd, 0xbc, 0x40, 0x8c, 0x0e, 0x45, 0xa2, 0x71,
-	0x33, 0xbb, 0x1e, 0xf2, 0x46, 0x7d, 0xcc, 0xb2, 0xa3, 0x36, 0xe6, 0xf6, 0xcd, 0x8f, 0x6f, 0x98,
-	0xf9, 0xf8, 0x60, 0x69, 0x2f, 0xd6, 0x61, 0x93, 0x19, 0x6a, 0x5d, 0x2b, 0x83, 0xc2, 0xd5, 0xc9,
-	0x1e, 0x96, 0x3b, 0x86, 0x9f, 0xd8, 0x1c, 0x90, 0xa4, 0x84, 0x59, 0x5e, 0x96, 0xa4, 0x44, 0x2c,
-	0xd2, 0x85, 0x66, 0xed, 0x59, 0xd7, 0x55, 0xa5, 0xfa, 0xd7, 0x33, 0xaf, 0x3d, 0xa3, 0xfc, 0x74,
-	0x54, 0x41, 0x2c, 0xd2, 0x95, 0x66, 0x2d, 0x1f, 0xe1, 0xbf, 0x75, 0xb9, 0x43, 0x35, 0x63, 0xd8,
-	0x0f, 0xc9, 0x3b, 0xac, 0xfa, 0x0b, 0x3b, 0xd7, 0x1a, 0x8d, 0x67, 0xbf, 0x6a, 0x08, 0x0d, 0x9f,
-	0x88, 0x34, 0x6b, 0xcf, 0x8e, 0x55, 0x5d, 0xf3, 0x89, 0x48, 0xb3, 0x4e, 0x7e, 0x04, 0xdc, 0x4f,
-	0x37, 0xad, 0x91, 0x6f, 0x10, 0x12, 0xda, 0xae, 0x76, 0x56, 0x89, 0x38, 0x48, 0xef, 0x5e, 0xb6,
-	0x59, 0xe1, 0xea, 0xec, 0xd6, 0x95, 0x69, 0xb6, 0xe8, 0xd1, 0xba, 0xdd, 0xc3, 0xbc, 0x47, 0x7f,
-	0x5f, 0x8b, 0xc9, 0xd7, 0x4f, 0x30, 0xcf, 0x0b, 0x57, 0xb5, 0xa7, 0x21, 0xdf, 0x30, 0x49, 0x05,
-	0x21, 0x12, 0xb5, 0x84, 0x25, 0x87, 0x8c, 0xf4, 0x38, 0xca, 0x35, 0x04, 0x8d, 0xfd, 0xe6, 0x94,
-	0x0b, 0xed, 0x65, 0xb2, 0xbe, 0x7e, 0x9a, 0x93, 0xd3, 0x78, 0x4e, 0x36, 0xf0, 0x70, 0x43, 0xac,
-	0xb9, 0x9a, 0xbe, 0x3a, 0xa4, 0x8b, 0x37, 0x7d, 0x8c, 0xa6, 0x81, 0x58, 0x23, 0x9f, 0x21, 0x6c,
-	0xb8, 0x89, 0x31, 0xe1, 0x66, 0x92, 0xb0, 0xef, 0x48, 0x8f, 0x8e, 0xc3, 0x9c, 0x8b, 0x7c, 0xfd,
-	0x0d, 0x00, 0x00, 0xff, 0xff, 0x0c, 0xa5, 0x0a, 0x1f, 0xd8, 0x01, 0x00, 0x00,
+	// 815 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xcd, 0x8e, 0x23, 0x35,
+	0x10, 0x56, 0xe7, 0xb7, 0x53, 0x99, 0xcc, 0x8f, 0x35, 0xac, 0xac, 0x00, 0x52, 0xe8, 0x03, 0x8a,
+	0x04, 0xca, 0x61, 0x00, 0x71, 0xe3, 0x82, 0xe6, 0xb0, 0xd2, 0x2e, 0x12, 0x1e, 0x21, 0x71, 0xc4,
+	0xe9, 0xae, 0x64, 0x9b, 0xe9, 0xd8, 0x3d, 0xb6, 0x7b, 0x76, 0xe7, 0xce, 0x13, 0xf0, 0x14, 0x3c,
+	0x03, 0x8f, 0xc1, 0x5b, 0xf0, 0x16, 0xa8, 0x6c, 0x77, 0xa7, 0xb3, 0x1a, 0x71, 0x98, 0x5b, 0x7d,
+	0x9f, 0xab, 0xcb, 0xe5, 0xaf, 0x7e, 0x1a, 0xce, 0xec, 0x93, 0x75, 0x78, 0xd8, 0xd4, 0x46, 0x3b,
+	0xcd, 0x86, 0xb9, 0xab, 0xb2, 0x7f, 0x06, 0x70, 0x76, 0xe7, 0xd9, 0xb7, 0x78, 0xd8, 0xa2, 0x61,
+	0x0c, 0x46, 0xb2, 0x28, 0x0c, 0x4f, 0x56, 0xc9, 0x7a, 0x26, 0xbc, 0x4d, 0x5c, 0xd3, 0x94, 0x05,
+	0x1f, 0x04, 0x8e, 0x6c, 0xe2, 0x8c, 0x54, 0xf7, 0x7c, 0xb8, 0x4a, 0xd6, 0x0b, 0xe1, 0x6d, 0x76,
+	0x0d, 0x63, 0xeb, 0xa4, 0x43, 0x3e, 0xf2, 0x64, 0x00, 0x6c, 0x09, 0xe9, 0x3b, 0x6d, 0x9d, 0x92,
+	0x07, 0xe4, 0x63, 0x1f, 0xa1, 0xc3, 0xec, 0x12, 0x86, 0x65, 0xf1, 0x81, 0x4f, 0xbc, 0x3f, 0x99,
+	0x6c, 0x05, 0xf3, 0x9d, 0xdc, 0x9a, 0x32, 0x2f, 0x77, 0x32, 0x47, 0x3e, 0xf5, 0x1f, 0xf4, 0x29,
+	0xf6, 0x25, 0x9c, 0x07, 0x58, 0x1b, 0xfd, 0x58, 0x16, 0x68, 0x78, 0xea, 0x9d, 0x3e, 0x62, 0xe9,
+	0x5e, 0xe5, 0xa4, 0xd9, 0xa3, 0xb3, 0x7c, 0xe6, 0x2f, 0xe8, 0x30, 0xcb, 0xe0, 0xcc, 0x27, 0x97,
+	0xbf, 0x93, 0x6a, 0x8f, 0x05, 0x07, 0x1f, 0xe1, 0x84, 0xa3, 0x4c, 0x3c, 0x36, 0x28, 0xad, 0x56,
+	0x7c, 0x1e, 0x32, 0xe9, 0x51, 0xec, 0x15, 0x4c, 0x9a, 0xda, 0x95, 0x07, 0xe4, 0x67, 0xab, 0x64,
+	0x3d, 0x12, 0x11, 0x65, 0xdf, 0xc3, 0x22, 0x68, 0x7a, 0xe7, 0x74, 0x2d, 0xf0, 0x81, 0xc4, 0xaa,
+	0x0d, 0xd6, 0x5e, 0xd4, 0x54, 0x78, 0x9b, 0xb8, 0xfb, 0xb2, 0xaa, 0xbc, 0xa8, 0xa9, 0xf0, 0x76,
+	0xf6, 0x57, 0x02, 0xe7, 0xfd, 0x2f, 0x6d, 0xcd, 0xbe, 0x85, 0xa9, 0x41, 0xdb, 0x54, 0xce, 0xf2,
+	0x64, 0x35, 0x5c, 0xcf, 0x6f, 0x96, 0x9b, 0xdc, 0x55, 0x9b, 0x53, 0xaf, 0x8d, 0xf0, 0x2e, 0xa2,
+	0x75, 0x5d, 0xfe, 0x06, 0x93, 0x40, 0x75, 0x75, 0x4a, 0x7a, 0x75, 0x7a, 0x05, 0x13, 0x99, 0xbb,
+	0x52, 0xab, 0x58, 0xd1, 0x88, 0x18, 0x87, 0x29, 0x1a, 0xa3, 0x0d, 0x16, 0xbe, 0xac, 0xa9, 0x68,
+	0x21, 0xd5, 0xe9, 0x60, 0xf7, 0xbe, 0xae, 0x33, 0x41, 0x66, 0x76, 0x79, 0xcc, 0x54, 0x1a, 0x27,
+	0xf0, 0x21, 0xbb, 0x82, 0x8b, 0x13, 0xc6, 0xd6, 0x47, 0xa7, 0x9f, 0x1b, 0x34, 0x4f, 0xe4, 0xf4,
+	0x03, 0x5c, 0x9c, 0x30, 0xb6, 0x66, 0x5f, 0xc1, 0xf4, 0xe0, 0x7b, 0xaf, 0x7d, 0xe1, 0x55, 0xef,
+	0x85, 0xa1, 0x2b, 0x45, 0xeb, 0x91, 0xad, 0xe1, 0x32, 0x1c, 0xdc, 0x7e, 0xc8, 0xab, 0xa6, 0x40,
+	0x52, 0xf7, 0x1a, 0xc6, 0xf4, 0xac, 0xf0, 0xf9, 0x42, 0x04, 0x90, 0xbd, 0x86, 0xab, 0x8f, 0x3c,
+	0x5f, 0xaa, 0x66, 0xf6, 0x35, 0x5c, 0x07, 0x0f, 0x81, 0xa5, 0x72, 0xb8, 0x37, 0xd2, 0xfd, 0xcf,
+	0xc5, 0x6f, 0xe1, 0x93, 0x67, 0xbc, 0x5f, 0x7c, 0xf9, 0x2f, 0xad, 0x62, 0xb7, 0x8f, 0xa8, 0x9c,
+	0x8d, 0xf7, 0xda, 0x52, 0xe5, 0x18, 0x87, 0x34, 0x80, 0x63, 0x36, 0x83, 0x5e, 0x36, 0x54, 0xeb,
+	0x9d, 0xae, 0x2a, 0xfd, 0x3e, 0x96, 0x34, 0xa2, 0xec, 0xdf, 0x04, 0xe6, 0xbd, 0xb8, 0x34, 0x2d,
+	0x16, 0x1f, 0x1a, 0x6c, 0xc3, 0x8e, 0x44, 0x87, 0xa9, 0x87, 0x7c, 0x97, 0xc7, 0xf9, 0x27, 0xdb,
+	0x73, 0x4f, 0x35, 0xb6, 0xf3, 0x4f, 0x76, 0xd7, 0x6b, 0xa3, 0x5e, 0xaf, 0x2d, 0x21, 0xd5, 0x55,
+	0x11, 0xd6, 0xc2, 0x38, 0x4c, 0x61, 0x8b, 0xe9, 0x4c, 0xe1, 0xfb, 0x70, 0x16, 0x56, 0x40, 0x87,
+	0x29, 0xef, 0x38, 0x78, 0x61, 0x05, 0x44, 0x44, 0xdf, 0x94, 0x05, 0x2a, 0x57, 0xba, 0xa7, 0x38,
+	0xf7, 0x1d, 0xa6, 0x6f, 0xb4, 0x29, 0xf7, 0xa5, 0xf2, 0xf3, 0x3e, 0x13, 0x11, 0x65, 0x17, 0xb0,
+	0xf8, 0x11, 0x8d, 0xbb, 0x73, 0xd2, 0x35, 0x24, 0x60, 0xf6, 0x67, 0x02, 0x29, 0x31, 0xaf, 0xd5,
+	0x4e, 0x53, 0xd7, 0xdb, 0x66, 0xfb, 0x3b, 0xe6, 0x2e, 0xea, 0xd9, 0x42, 0x8a, 0x57, 0x5a, 0xdb,
+	0xa0, 0x69, 0xe7, 0x24, 0x20, 0xf6, 0x19, 0xcc, 0x94, 0x76, 0x5b, 0xdc, 0x69, 0x13, 0x04, 0x98,
+	0x89, 0x23, 0xe1, 0x5f, 0xa5, 0x9d, 0xdc, 0x39, 0x34, 0x71, 0x60, 0x3a, 0x4c, 0x67, 0x85, 0xb2,
+	0xb4, 0xfa, 0x2c, 0x1f, 0xaf, 0x86, 0x74, 0xd6, 0xe2, 0xec, 0x8f, 0x04, 0xce, 0xfb, 0x69, 0xda,
+	0xda, 0x3f, 0x56, 0x59, 0xcc, 0x1b, 0x83, 0x71, 0x77, 0x74, 0x98, 0x7d, 0x01, 0xa3, 0x1c, 0x8d,
+	0xf3, 0xa9, 0xcd, 0x6f, 0x16, 0xbe, 0x95, 0xda, 0x37, 0x09, 0x7f, 0xc4, 0x3e, 0x87, 0x41, 0x2e,
+	0xf9, 0xf0, 0x39, 0x87, 0x41, 0x2e, 0xa9, 0x61, 0xfc, 0x7c, 0xc7, 0x2c, 0x03, 0xc8, 0x7e, 0x85,
+	0xf3, 0x3b, 0x74, 0x6f, 0xf4, 0xfe, 0x0d, 0x3e, 0x62, 0x45, 0xed, 0xc6, 0x61, 0x9a, 0x6b, 0xe5,
+	0x8c, 0xae, 0x5a, 0x81, 0x22, 0x24, 0x81, 0x50, 0xed, 0x4b, 0xd5, 0xb6, 0x46, 0x44, 0xc7, 0x56,
+	0x1c, 0xf6, 0x07, 0xe3, 0xef, 0x04, 0x2e, 0x4e, 0x42, 0xdb, 0x9a, 0x96, 0x6c, 0x6d, 0xf0, 0xf1,
+	0x34, 0x7e, 0x9f, 0x62, 0xdf, 0xc1, 0x34, 0x44, 0x0d, 0x8d, 0x3d, 0xbf, 0xf9, 0x34, 0x4c, 0xcd,
+	0x69, 0xa0, 0xcd, 0xad, 0xf7, 0x11, 0xad, 0xef, 0xf2, 0x27, 0x98, 0x04, 0xea, 0xd9, 0x0d, 0xb8,
+	0x84, 0x94, 0xee, 0x38, 0x48, 0x7b, 0x1f, 0x53, 0xef, 0xf0, 0x51, 0x96, 0x61, 0x4f, 0x96, 0xed,
+	0xc4, 0xff, 0x34, 0xbf, 0xf9, 0x6f, 0x00, 0xbb, 0xbf, 0x46, 0x51, 0x44, 0x07, 0x00, 0x00,
 }
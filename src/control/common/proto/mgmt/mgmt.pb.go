@@ -27,38 +27,39 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 func init() { proto.RegisterFile("mgmt.proto", fileDescriptor_24cf82780fd24e73) }
 
 var fileDescriptor_24cf82780fd24e73 = []byte{
-	// 486 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x94, 0x4d, 0x6f, 0xd3, 0x4c,
-	0x10, 0x80, 0xdf, 0x43, 0xf5, 0x42, 0xa6, 0x4d, 0x48, 0x37, 0x05, 0xa4, 0x1c, 0xb9, 0x70, 0x0b,
-	0x52, 0xf9, 0x8c, 0xe0, 0x42, 0x63, 0xf1, 0x99, 0x8a, 0x50, 0x8b, 0x33, 0x5a, 0xe2, 0x69, 0x62,
-	0x61, 0x7b, 0xdd, 0xdd, 0x89, 0xab, 0xfe, 0x2a, 0xfe, 0x22, 0x9a, 0xfd, 0xb0, 0x37, 0x69, 0x38,
-	0xf4, 0xb6, 0xf3, 0x64, 0x9e, 0x9d, 0x9d, 0x9d, 0xac, 0x01, 0xca, 0x55, 0x49, 0x93, 0x5a, 0x2b,
-	0x52, 0xe2, 0x80, 0xd7, 0x63, 0xa8, 0x95, 0x2a, 0x1c, 0x19, 0xf7, 0x8c, 0x6e, 0xfc, 0x72, 0x64,
-	0x48, 0x69, 0xb9, 0xc2, 0x9f, 0x57, 0x1b, 0xd4, 0x37, 0xe1, 0x77, 0xb9, 0xf4, 0xa9, 0xa7, 0x7f,
-	0x7a, 0x70, 0xef, 0x7c, 0x55, 0x52, 0xda, 0x2c, 0xc5, 0x53, 0x38, 0xf8, 0xa2, 0xf2, 0x4a, 0xf4,
-	0x27, 0x76, 0x77, 0x5e, 0x5f, 0xe0, 0xd5, 0x78, 0x10, 0x87, 0xa6, 0x7e, 0xf2, 0x9f, 0x78, 0x07,
-	0x87, 0x73, 0x94, 0x19, 0xea, 0xef, 0xbc, 0xa9, 0x38, 0x71, 0x09, 0x11, 0x62, 0xed, 0xe1, 0x1e,
-	0x6a, 0xed, 0x29, 0xc0, 0x42, 0xa9, 0x62, 0xa6, 0x51, 0x12, 0x8a, 0x91, 0x4b, 0xeb, 0x08, 0xbb,
-	0x27, 0xb7, 0x61, 0x28, 0xcc, 0x2c, 0x41, 0x43, 0x5a, 0xb5, 0x85, 0x23, 0x14, 0x15, 0xde, 0xa2,
-	0xd6, 0x7e, 0x05, 0x3d, 0x86, 0xee, 0xd0, 0xa2, 0xcb, 0x6a, 0x8f, 0x3c, 0xba, 0xc5, 0xe2, 0xaa,
-	0x29, 0xd2, 0x42, 0xab, 0x3a, 0xae, 0xea, 0xd1, 0x4e, 0xd5, 0x96, 0x5a, 0x7b, 0xe2, 0xda, 0xfd,
-	0x88, 0xf4, 0x7e, 0x36, 0x17, 0x0f, 0x5c, 0x9a, 0x8b, 0xd8, 0xf3, 0x97, 0x6d, 0x23, 0x9b, 0xff,
-	0x1a, 0x86, 0x9c, 0xff, 0xad, 0x41, 0x7d, 0xad, 0x73, 0x42, 0xb6, 0xfc, 0x61, 0xcf, 0x55, 0x96,
-	0x5f, 0xde, 0xfc, 0x4b, 0x7c, 0x01, 0x7d, 0x16, 0x7f, 0xd4, 0x99, 0xbc, 0xbb, 0x95, 0x60, 0x81,
-	0x5b, 0x56, 0x0b, 0xf6, 0x5a, 0x67, 0xd0, 0xe7, 0x16, 0x88, 0xe4, 0x72, 0xfd, 0xb9, 0xba, 0x54,
-	0xe2, 0x51, 0xd7, 0x57, 0x0b, 0xd9, 0x7c, 0xbc, 0x97, 0xdb, 0x3d, 0xde, 0xc2, 0xe0, 0x2c, 0x57,
-	0x9f, 0x50, 0x16, 0xb4, 0xde, 0x9a, 0x49, 0x4b, 0xa3, 0x99, 0x44, 0xcc, 0xca, 0xa7, 0x70, 0x98,
-	0x96, 0xd9, 0x3c, 0x37, 0x94, 0x60, 0x63, 0xc2, 0xb5, 0xa6, 0x65, 0x96, 0x60, 0xc3, 0xda, 0x70,
-	0x1b, 0x58, 0xe7, 0x25, 0x1c, 0x79, 0x87, 0x3b, 0x36, 0xa2, 0xcb, 0xe1, 0x98, 0xad, 0xe3, 0x1d,
-	0xe2, 0x07, 0x72, 0xb4, 0xd0, 0x58, 0xa7, 0xeb, 0x0d, 0x65, 0xea, 0xba, 0x12, 0x61, 0xd2, 0x11,
-	0x8b, 0x9e, 0x49, 0x22, 0x95, 0xf1, 0xe2, 0x33, 0xb8, 0xff, 0x35, 0x2f, 0x8a, 0x0b, 0x59, 0xfd,
-	0x16, 0x7e, 0xe7, 0x10, 0xef, 0x17, 0xa6, 0x00, 0x29, 0x49, 0x4d, 0x9c, 0x61, 0xc2, 0xcb, 0xe8,
-	0x48, 0xf4, 0x32, 0x62, 0x18, 0xfe, 0xdb, 0x5d, 0x63, 0xfe, 0x1e, 0x5b, 0x10, 0xdd, 0x63, 0xc4,
-	0xac, 0xf7, 0x06, 0xfa, 0x09, 0x36, 0x29, 0x49, 0x42, 0x37, 0x83, 0xe3, 0x30, 0x7e, 0x07, 0x59,
-	0x15, 0xbb, 0xc8, 0x8f, 0x6f, 0x98, 0xba, 0x6f, 0x4b, 0x8a, 0xf4, 0x41, 0x6e, 0x0a, 0xba, 0x83,
-	0x3c, 0x85, 0x01, 0x9f, 0x64, 0xa6, 0x2a, 0x92, 0x79, 0x85, 0xda, 0x04, 0x35, 0xd0, 0x48, 0xed,
-	0x10, 0xab, 0xbf, 0xfe, 0xb7, 0x1f, 0xae, 0xe7, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x44, 0xa0,
-	0xe2, 0x84, 0x03, 0x05, 0x00, 0x00,
+	// 506 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x94, 0xcd, 0x72, 0xd3, 0x30,
+	0x10, 0x80, 0x39, 0x74, 0x80, 0x6e, 0x9b, 0x90, 0x2a, 0x05, 0x66, 0x72, 0xe4, 0xc2, 0x2d, 0xcc,
+	0x14, 0x0a, 0x64, 0xe0, 0x42, 0x63, 0xfe, 0xd3, 0x21, 0xc4, 0xc3, 0x99, 0x11, 0xf1, 0x36, 0xf1,
+	0xe0, 0x58, 0xae, 0xb4, 0x71, 0xa7, 0x4f, 0xc2, 0xeb, 0x32, 0xab, 0x1f, 0x5b, 0x49, 0xd3, 0x43,
+	0x6f, 0xda, 0xcf, 0xfb, 0x69, 0x25, 0xad, 0x64, 0x80, 0xd5, 0x62, 0x45, 0xc3, 0x4a, 0x2b, 0x52,
+	0x62, 0x8f, 0xc7, 0x03, 0xa8, 0x94, 0x2a, 0x1c, 0x19, 0xec, 0x1b, 0x5d, 0xfb, 0x61, 0xdf, 0x90,
+	0xd2, 0x72, 0x81, 0xbf, 0x2f, 0xd7, 0xa8, 0xaf, 0xc3, 0x77, 0x39, 0xf7, 0xa9, 0x27, 0xff, 0x00,
+	0x1e, 0x9c, 0x2f, 0x56, 0x94, 0xd6, 0x73, 0xf1, 0x1c, 0xf6, 0xbe, 0xa9, 0xbc, 0x14, 0x9d, 0xa1,
+	0x9d, 0x9d, 0xc7, 0x33, 0xbc, 0x1c, 0x74, 0xe3, 0xd0, 0x54, 0xcf, 0xee, 0x89, 0xf7, 0x70, 0x30,
+	0x41, 0x99, 0xa1, 0xfe, 0xc9, 0x93, 0x8a, 0x63, 0x97, 0x10, 0x21, 0xd6, 0x1e, 0xef, 0xa0, 0xd6,
+	0x1e, 0x01, 0x4c, 0x95, 0x2a, 0xc6, 0x1a, 0x25, 0xa1, 0xe8, 0xbb, 0xb4, 0x96, 0xb0, 0x7b, 0x7c,
+	0x13, 0x86, 0xc2, 0xcc, 0x12, 0x34, 0xa4, 0x55, 0x53, 0x38, 0x42, 0x51, 0xe1, 0x0d, 0x6a, 0xed,
+	0xd7, 0xb0, 0xcf, 0xd0, 0x2d, 0x5a, 0xb4, 0x59, 0xcd, 0x92, 0xfb, 0x37, 0x58, 0x5c, 0x35, 0x45,
+	0x9a, 0x6a, 0x55, 0xc5, 0x55, 0x3d, 0xda, 0xaa, 0xda, 0x50, 0x6b, 0x0f, 0xdd, 0x76, 0x3f, 0x23,
+	0x7d, 0x18, 0x4f, 0xc4, 0x23, 0x97, 0xe6, 0x22, 0xf6, 0xfc, 0x61, 0xdb, 0xc8, 0xe6, 0xbf, 0x81,
+	0x1e, 0xe7, 0xff, 0xa8, 0x51, 0x5f, 0xe9, 0x9c, 0x90, 0x2d, 0xbf, 0xd8, 0x73, 0x95, 0xe5, 0x17,
+	0xd7, 0xb7, 0x89, 0xaf, 0xa0, 0xc3, 0xe2, 0xaf, 0x2a, 0x93, 0x77, 0xb7, 0x12, 0x2c, 0x70, 0xc3,
+	0x6a, 0xc0, 0x4e, 0xeb, 0x0c, 0x3a, 0xbc, 0x05, 0x22, 0x39, 0x5f, 0x7e, 0x2d, 0x2f, 0x94, 0x78,
+	0xd2, 0xee, 0xab, 0x81, 0x6c, 0x3e, 0xdd, 0xc9, 0xed, 0x1c, 0xef, 0xa0, 0x7b, 0x96, 0xab, 0x2f,
+	0x28, 0x0b, 0x5a, 0x6e, 0xf4, 0xa4, 0xa1, 0x51, 0x4f, 0x22, 0x66, 0xe5, 0x13, 0x38, 0x48, 0x57,
+	0xd9, 0x24, 0x37, 0x94, 0x60, 0x6d, 0xc2, 0xb1, 0xa6, 0xab, 0x2c, 0xc1, 0x9a, 0xb5, 0xde, 0x26,
+	0xb0, 0xce, 0x29, 0x1c, 0x7a, 0x87, 0x77, 0x6c, 0x44, 0x9b, 0xc3, 0x31, 0x5b, 0x47, 0x5b, 0xc4,
+	0x37, 0xe4, 0x70, 0xaa, 0xb1, 0x4a, 0x97, 0x6b, 0xca, 0xd4, 0x55, 0x29, 0x42, 0xa7, 0x23, 0x16,
+	0x3d, 0x93, 0x44, 0x2a, 0xe3, 0xc5, 0x17, 0xf0, 0xf0, 0x7b, 0x5e, 0x14, 0x33, 0x59, 0xfe, 0x15,
+	0x7e, 0xe6, 0x10, 0xdf, 0x2a, 0x4c, 0xf3, 0x72, 0x11, 0x0b, 0x21, 0xde, 0x2d, 0x9c, 0xc2, 0xc1,
+	0x0c, 0x2b, 0xa5, 0xe9, 0x63, 0x8d, 0x25, 0x85, 0x9b, 0x19, 0xa1, 0xdd, 0xda, 0x08, 0x20, 0x25,
+	0xa9, 0x89, 0x27, 0x36, 0xe1, 0x05, 0xb6, 0x24, 0x7a, 0x81, 0x31, 0x0c, 0x6f, 0xa8, 0x3d, 0x40,
+	0xdf, 0xaf, 0x06, 0x44, 0xfd, 0x8a, 0x98, 0xf5, 0xde, 0x42, 0x27, 0xc1, 0x3a, 0x25, 0x49, 0xe8,
+	0x7a, 0x7d, 0x14, 0xae, 0x99, 0x83, 0xac, 0x8a, 0x6d, 0xe4, 0xaf, 0x49, 0x2f, 0x75, 0xff, 0xb0,
+	0x14, 0xe9, 0x93, 0x5c, 0x17, 0x74, 0x07, 0x79, 0x04, 0x5d, 0x5e, 0xc9, 0x58, 0x95, 0x24, 0xf3,
+	0x12, 0xb5, 0x09, 0x6a, 0xa0, 0x91, 0xda, 0x22, 0x56, 0xff, 0xdc, 0xb7, 0x3f, 0xc8, 0x97, 0xff,
+	0x07, 0x00, 0x38, 0xb7, 0x8f, 0x63, 0x6b, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -106,6 +107,10 @@ type MgmtSvcClient interface {
 	PrepShutdown(ctx context.Context, in *PrepShutdownReq, opts ...grpc.CallOption) (*DaosResp, error)
 	// Kill DAOS IO server identified by rank.
 	KillRank(ctx context.Context, in *KillRankReq, opts ...grpc.CallOption) (*DaosResp, error)
+	// Check liveness of DAOS IO server identified by rank.
+	PingRank(ctx context.Context, in *PingRankReq, opts ...grpc.CallOption) (*DaosResp, error)
+	// Record event detected on a DAOS IO server in the system event log.
+	ReportEvent(ctx context.Context, in *ReportEventReq, opts ...grpc.CallOption) (*DaosResp, error)
 	// Start DAOS IO servers identified by rank.
 	StartRanks(ctx context.Context, in *StartRanksReq, opts ...grpc.CallOption) (*StartRanksResp, error)
 	// List all pools in a DAOS system: basic info: UUIDs, service ranks.
@@ -270,6 +275,24 @@ func (c *mgmtSvcClient) KillRank(ctx context.Context, in *KillRankReq, opts ...g
 	return out, nil
 }
 
+func (c *mgmtSvcClient) PingRank(ctx context.Context, in *PingRankReq, opts ...grpc.CallOption) (*DaosResp, error) {
+	out := new(DaosResp)
+	err := c.cc.Invoke(ctx, "/mgmt.MgmtSvc/PingRank", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mgmtSvcClient) ReportEvent(ctx context.Context, in *ReportEventReq, opts ...grpc.CallOption) (*DaosResp, error) {
+	out := new(DaosResp)
+	err := c.cc.Invoke(ctx, "/mgmt.MgmtSvc/ReportEvent", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *mgmtSvcClient) StartRanks(ctx context.Context, in *StartRanksReq, opts ...grpc.CallOption) (*StartRanksResp, error) {
 	out := new(StartRanksResp)
 	err := c.cc.Invoke(ctx, "/mgmt.MgmtSvc/StartRanks", in, out, opts...)
@@ -350,6 +373,10 @@ type MgmtSvcServer interface {
 	PrepShutdown(context.Context, *PrepShutdownReq) (*DaosResp, error)
 	// Kill DAOS IO server identified by rank.
 	KillRank(context.Context, *KillRankReq) (*DaosResp, error)
+	// Check liveness of DAOS IO server identified by rank.
+	PingRank(context.Context, *PingRankReq) (*DaosResp, error)
+	// Record event detected on a DAOS IO server in the system event log.
+	ReportEvent(context.Context, *ReportEventReq) (*DaosResp, error)
 	// Start DAOS IO servers identified by rank.
 	StartRanks(context.Context, *StartRanksReq) (*StartRanksResp, error)
 	// List all pools in a DAOS system: basic info: UUIDs, service ranks.
@@ -414,6 +441,12 @@ func (*UnimplementedMgmtSvcServer) PrepShutdown(ctx context.Context, req *PrepSh
 func (*UnimplementedMgmtSvcServer) KillRank(ctx context.Context, req *KillRankReq) (*DaosResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method KillRank not implemented")
 }
+func (*UnimplementedMgmtSvcServer) PingRank(ctx context.Context, req *PingRankReq) (*DaosResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PingRank not implemented")
+}
+func (*UnimplementedMgmtSvcServer) ReportEvent(ctx context.Context, req *ReportEventReq) (*DaosResp, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportEvent not implemented")
+}
 func (*UnimplementedMgmtSvcServer) StartRanks(ctx context.Context, req *StartRanksReq) (*StartRanksResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method StartRanks not implemented")
 }
@@ -722,6 +755,42 @@ func _MgmtSvc_KillRank_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MgmtSvc_PingRank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRankReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MgmtSvcServer).PingRank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mgmt.MgmtSvc/PingRank",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MgmtSvcServer).PingRank(ctx, req.(*PingRankReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MgmtSvc_ReportEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportEventReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MgmtSvcServer).ReportEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/mgmt.MgmtSvc/ReportEvent",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MgmtSvcServer).ReportEvent(ctx, req.(*ReportEventReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MgmtSvc_StartRanks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(StartRanksReq)
 	if err := dec(in); err != nil {
@@ -880,6 +949,14 @@ var _MgmtSvc_serviceDesc = grpc.ServiceDesc{
 			MethodName: "KillRank",
 			Handler:    _MgmtSvc_KillRank_Handler,
 		},
+		{
+			MethodName: "PingRank",
+			Handler:    _MgmtSvc_PingRank_Handler,
+		},
+		{
+			MethodName: "ReportEvent",
+			Handler:    _MgmtSvc_ReportEvent_Handler,
+		},
 		{
 			MethodName: "StartRanks",
 			Handler:    _MgmtSvc_StartRanks_Handler,
@@ -91,6 +91,11 @@ type JoinReq struct {
 	Uri                  string   `protobuf:"bytes,3,opt,name=uri,proto3" json:"uri,omitempty"`
 	Nctxs                uint32   `protobuf:"varint,4,opt,name=nctxs,proto3" json:"nctxs,omitempty"`
 	Addr                 string   `protobuf:"bytes,5,opt,name=addr,proto3" json:"addr,omitempty"`
+	Hostname             string   `protobuf:"bytes,6,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Idx                  uint32   `protobuf:"varint,7,opt,name=idx,proto3" json:"idx,omitempty"`
+	Fabriciface          string   `protobuf:"bytes,8,opt,name=fabriciface,proto3" json:"fabriciface,omitempty"`
+	Fabricprovider       string   `protobuf:"bytes,9,opt,name=fabricprovider,proto3" json:"fabricprovider,omitempty"`
+	Ntgts                uint32   `protobuf:"varint,10,opt,name=ntgts,proto3" json:"ntgts,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -156,6 +161,41 @@ func (m *JoinReq) GetAddr() string {
 	return ""
 }
 
+func (m *JoinReq) GetHostname() string {
+	if m != nil {
+		return m.Hostname
+	}
+	return ""
+}
+
+func (m *JoinReq) GetIdx() uint32 {
+	if m != nil {
+		return m.Idx
+	}
+	return 0
+}
+
+func (m *JoinReq) GetFabriciface() string {
+	if m != nil {
+		return m.Fabriciface
+	}
+	return ""
+}
+
+func (m *JoinReq) GetFabricprovider() string {
+	if m != nil {
+		return m.Fabricprovider
+	}
+	return ""
+}
+
+func (m *JoinReq) GetNtgts() uint32 {
+	if m != nil {
+		return m.Ntgts
+	}
+	return 0
+}
+
 type JoinResp struct {
 	Status               int32          `protobuf:"varint,1,opt,name=status,proto3" json:"status,omitempty"`
 	Rank                 uint32         `protobuf:"varint,2,opt,name=rank,proto3" json:"rank,omitempty"`
@@ -516,6 +556,108 @@ func (m *KillRankReq) GetRank() uint32 {
 	return 0
 }
 
+type PingRankReq struct {
+	Rank                 uint32   `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PingRankReq) Reset()         { *m = PingRankReq{} }
+func (m *PingRankReq) String() string { return proto.CompactTextString(m) }
+func (*PingRankReq) ProtoMessage()    {}
+func (*PingRankReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2bbe8325d22c1a26, []int{9}
+}
+
+func (m *PingRankReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PingRankReq.Unmarshal(m, b)
+}
+func (m *PingRankReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PingRankReq.Marshal(b, m, deterministic)
+}
+func (m *PingRankReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PingRankReq.Merge(m, src)
+}
+func (m *PingRankReq) XXX_Size() int {
+	return xxx_messageInfo_PingRankReq.Size(m)
+}
+func (m *PingRankReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_PingRankReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PingRankReq proto.InternalMessageInfo
+
+func (m *PingRankReq) GetRank() uint32 {
+	if m != nil {
+		return m.Rank
+	}
+	return 0
+}
+
+type ReportEventReq struct {
+	Rank                 uint32   `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Type                 uint32   `protobuf:"varint,2,opt,name=type,proto3" json:"type,omitempty"`
+	Reason               string   `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Identity             string   `protobuf:"bytes,4,opt,name=identity,proto3" json:"identity,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReportEventReq) Reset()         { *m = ReportEventReq{} }
+func (m *ReportEventReq) String() string { return proto.CompactTextString(m) }
+func (*ReportEventReq) ProtoMessage()    {}
+func (*ReportEventReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2bbe8325d22c1a26, []int{10}
+}
+
+func (m *ReportEventReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportEventReq.Unmarshal(m, b)
+}
+func (m *ReportEventReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportEventReq.Marshal(b, m, deterministic)
+}
+func (m *ReportEventReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportEventReq.Merge(m, src)
+}
+func (m *ReportEventReq) XXX_Size() int {
+	return xxx_messageInfo_ReportEventReq.Size(m)
+}
+func (m *ReportEventReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportEventReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportEventReq proto.InternalMessageInfo
+
+func (m *ReportEventReq) GetRank() uint32 {
+	if m != nil {
+		return m.Rank
+	}
+	return 0
+}
+
+func (m *ReportEventReq) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *ReportEventReq) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *ReportEventReq) GetIdentity() string {
+	if m != nil {
+		return m.Identity
+	}
+	return ""
+}
+
 type StartRanksReq struct {
 	Ranks                []uint32 `protobuf:"varint,1,rep,packed,name=ranks,proto3" json:"ranks,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -527,7 +669,7 @@ func (m *StartRanksReq) Reset()         { *m = StartRanksReq{} }
 func (m *StartRanksReq) String() string { return proto.CompactTextString(m) }
 func (*StartRanksReq) ProtoMessage()    {}
 func (*StartRanksReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2bbe8325d22c1a26, []int{9}
+	return fileDescriptor_2bbe8325d22c1a26, []int{11}
 }
 
 func (m *StartRanksReq) XXX_Unmarshal(b []byte) error {
@@ -566,7 +708,7 @@ func (m *StartRanksResp) Reset()         { *m = StartRanksResp{} }
 func (m *StartRanksResp) String() string { return proto.CompactTextString(m) }
 func (*StartRanksResp) ProtoMessage()    {}
 func (*StartRanksResp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2bbe8325d22c1a26, []int{10}
+	return fileDescriptor_2bbe8325d22c1a26, []int{12}
 }
 
 func (m *StartRanksResp) XXX_Unmarshal(b []byte) error {
@@ -605,7 +747,7 @@ func (m *SetRankReq) Reset()         { *m = SetRankReq{} }
 func (m *SetRankReq) String() string { return proto.CompactTextString(m) }
 func (*SetRankReq) ProtoMessage()    {}
 func (*SetRankReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2bbe8325d22c1a26, []int{11}
+	return fileDescriptor_2bbe8325d22c1a26, []int{13}
 }
 
 func (m *SetRankReq) XXX_Unmarshal(b []byte) error {
@@ -646,7 +788,7 @@ func (m *CreateMsReq) Reset()         { *m = CreateMsReq{} }
 func (m *CreateMsReq) String() string { return proto.CompactTextString(m) }
 func (*CreateMsReq) ProtoMessage()    {}
 func (*CreateMsReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_2bbe8325d22c1a26, []int{12}
+	return fileDescriptor_2bbe8325d22c1a26, []int{14}
 }
 
 func (m *CreateMsReq) XXX_Unmarshal(b []byte) error {
@@ -688,6 +830,45 @@ func (m *CreateMsReq) GetAddr() string {
 	return ""
 }
 
+type SetLogMasksReq struct {
+	Masks                string   `protobuf:"bytes,1,opt,name=masks,proto3" json:"masks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetLogMasksReq) Reset()         { *m = SetLogMasksReq{} }
+func (m *SetLogMasksReq) String() string { return proto.CompactTextString(m) }
+func (*SetLogMasksReq) ProtoMessage()    {}
+func (*SetLogMasksReq) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2bbe8325d22c1a26, []int{15}
+}
+
+func (m *SetLogMasksReq) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetLogMasksReq.Unmarshal(m, b)
+}
+func (m *SetLogMasksReq) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetLogMasksReq.Marshal(b, m, deterministic)
+}
+func (m *SetLogMasksReq) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetLogMasksReq.Merge(m, src)
+}
+func (m *SetLogMasksReq) XXX_Size() int {
+	return xxx_messageInfo_SetLogMasksReq.Size(m)
+}
+func (m *SetLogMasksReq) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetLogMasksReq.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetLogMasksReq proto.InternalMessageInfo
+
+func (m *SetLogMasksReq) GetMasks() string {
+	if m != nil {
+		return m.Masks
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("mgmt.JoinResp_State", JoinResp_State_name, JoinResp_State_value)
 	proto.RegisterType((*DaosResp)(nil), "mgmt.DaosResp")
@@ -700,43 +881,54 @@ func init() {
 	proto.RegisterType((*GetAttachInfoResp_Psr)(nil), "mgmt.GetAttachInfoResp.Psr")
 	proto.RegisterType((*PrepShutdownReq)(nil), "mgmt.PrepShutdownReq")
 	proto.RegisterType((*KillRankReq)(nil), "mgmt.KillRankReq")
+	proto.RegisterType((*PingRankReq)(nil), "mgmt.PingRankReq")
+	proto.RegisterType((*ReportEventReq)(nil), "mgmt.ReportEventReq")
 	proto.RegisterType((*StartRanksReq)(nil), "mgmt.StartRanksReq")
 	proto.RegisterType((*StartRanksResp)(nil), "mgmt.StartRanksResp")
 	proto.RegisterType((*SetRankReq)(nil), "mgmt.SetRankReq")
 	proto.RegisterType((*CreateMsReq)(nil), "mgmt.CreateMsReq")
+	proto.RegisterType((*SetLogMasksReq)(nil), "mgmt.SetLogMasksReq")
 }
 
 func init() { proto.RegisterFile("srv.proto", fileDescriptor_2bbe8325d22c1a26) }
 
 var fileDescriptor_2bbe8325d22c1a26 = []byte{
-	// 458 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x53, 0x6d, 0x6f, 0xd3, 0x30,
-	0x10, 0x26, 0x71, 0xd3, 0xb5, 0x57, 0x75, 0x0b, 0x56, 0x85, 0xa2, 0xc1, 0x87, 0xca, 0xda, 0xa4,
-	0x0a, 0xa4, 0x20, 0x8d, 0x0f, 0x7c, 0x46, 0x20, 0xa1, 0xf1, 0x5a, 0x1c, 0xf8, 0x01, 0x5e, 0xe2,
-	0xb1, 0x68, 0x6d, 0x1c, 0xce, 0x0e, 0x50, 0x89, 0x3f, 0xc0, 0xbf, 0x46, 0xe7, 0xa4, 0xdd, 0xda,
-	0x95, 0x7d, 0xbb, 0xc7, 0xf7, 0x9c, 0xef, 0xb9, 0x37, 0x18, 0x5a, 0xfc, 0x99, 0xd6, 0x68, 0x9c,
-	0xe1, 0xbd, 0xe5, 0xf7, 0xa5, 0x13, 0x02, 0x06, 0x6f, 0x94, 0xb1, 0x52, 0xdb, 0x9a, 0x3f, 0x82,
-	0xbe, 0x75, 0xca, 0x35, 0x36, 0x09, 0xa6, 0xc1, 0x2c, 0x92, 0x1d, 0x12, 0x4b, 0x38, 0x78, 0x67,
-	0xca, 0x4a, 0xea, 0x1f, 0x9c, 0x43, 0xaf, 0x69, 0xca, 0xc2, 0x13, 0x86, 0xd2, 0xdb, 0xf4, 0x86,
-	0xaa, 0xba, 0x4e, 0xc2, 0x69, 0x30, 0x1b, 0x4b, 0x6f, 0xf3, 0x18, 0x58, 0x83, 0x65, 0xc2, 0x3c,
-	0x8d, 0x4c, 0x3e, 0x81, 0xa8, 0xca, 0xdd, 0x6f, 0x9b, 0xf4, 0x3c, 0xad, 0x05, 0x14, 0xab, 0x8a,
-	0x02, 0x93, 0xa8, 0xfd, 0x8f, 0x6c, 0xf1, 0x07, 0x06, 0x6d, 0xba, 0xff, 0x4b, 0xda, 0x9b, 0xf3,
-	0x29, 0x44, 0xe4, 0xd5, 0x3e, 0xeb, 0xe1, 0xd9, 0x24, 0xa5, 0x02, 0xd3, 0xf5, 0x57, 0x69, 0x46,
-	0x3e, 0xd9, 0x52, 0x44, 0x02, 0x91, 0xc7, 0xbc, 0x0f, 0xe1, 0xf9, 0xa7, 0xf8, 0x01, 0x3f, 0x00,
-	0xf6, 0xf9, 0xdb, 0xd7, 0x38, 0x10, 0x33, 0x38, 0xfc, 0xa0, 0x55, 0xa1, 0xf1, 0x4b, 0xa3, 0x71,
-	0x45, 0x35, 0x93, 0x86, 0x95, 0x75, 0x7a, 0xd9, 0x55, 0xdd, 0x21, 0x91, 0xc1, 0xd1, 0x16, 0xd3,
-	0xd6, 0xfc, 0x04, 0xc6, 0x79, 0x83, 0xa8, 0x2b, 0xd7, 0x7a, 0xba, 0x88, 0xed, 0x47, 0x7e, 0x0c,
-	0x03, 0xd4, 0xf5, 0xa2, 0xcc, 0x95, 0x4d, 0xc2, 0x29, 0x9b, 0x0d, 0xe5, 0x06, 0x8b, 0x13, 0x88,
-	0xdf, 0x6a, 0xf7, 0xca, 0x39, 0x95, 0x5f, 0x9d, 0x57, 0x97, 0x86, 0x04, 0xc4, 0xc0, 0xec, 0xca,
-	0x76, 0x7f, 0x91, 0x29, 0xfe, 0x06, 0xf0, 0x70, 0x87, 0x76, 0x4f, 0xb3, 0x9e, 0x43, 0xaf, 0xb6,
-	0xd8, 0xe6, 0x1a, 0x9d, 0x3d, 0x6e, 0xfb, 0x72, 0x27, 0x3c, 0x9d, 0x5b, 0x94, 0x9e, 0x78, 0xfc,
-	0x0c, 0xd8, 0xdc, 0xe2, 0xa6, 0xc9, 0xc1, 0xdd, 0xc1, 0x86, 0x9b, 0xc1, 0x8a, 0x53, 0x38, 0x9a,
-	0xa3, 0xae, 0xb3, 0xab, 0xc6, 0x15, 0xe6, 0xd7, 0x7a, 0x4b, 0x76, 0x03, 0xc5, 0x4b, 0x18, 0xbd,
-	0x2f, 0x17, 0x0b, 0xa9, 0xaa, 0x6b, 0xa2, 0x4c, 0x20, 0xba, 0x34, 0x98, 0x6b, 0xcf, 0x19, 0xc8,
-	0x16, 0xec, 0x1b, 0xab, 0x38, 0x85, 0x71, 0xe6, 0x14, 0x3a, 0x8a, 0xb4, 0x5d, 0x28, 0x39, 0xa8,
-	0x4a, 0x46, 0x9b, 0xe4, 0x01, 0xcd, 0xed, 0x36, 0x6d, 0xa7, 0x1d, 0xec, 0xd6, 0x3a, 0x4f, 0x01,
-	0x32, 0xed, 0xd6, 0x42, 0xf6, 0x69, 0xcd, 0x60, 0xf4, 0x1a, 0xb5, 0x72, 0xfa, 0xa3, 0x4f, 0xf8,
-	0x04, 0x86, 0x17, 0xc6, 0x38, 0xeb, 0x50, 0xd5, 0x9d, 0xde, 0x9b, 0x87, 0xcd, 0x49, 0x84, 0xdb,
-	0x27, 0xe1, 0xd7, 0x9a, 0xdd, 0xac, 0xf5, 0x45, 0xdf, 0x9f, 0xdd, 0x8b, 0x7f, 0x01, 0x00, 0x00,
-	0xff, 0xff, 0xaa, 0xca, 0x8c, 0xc4, 0x83, 0x03, 0x00, 0x00,
+	// 592 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0xc1, 0x6e, 0xd3, 0x4c,
+	0x10, 0xfe, 0x1d, 0x27, 0x69, 0x32, 0x51, 0xd3, 0xfc, 0x56, 0x85, 0x56, 0x85, 0x43, 0x58, 0xb5,
+	0x55, 0x05, 0x52, 0x90, 0xca, 0x81, 0x33, 0x02, 0x84, 0x0a, 0x2d, 0x04, 0x1b, 0x1e, 0x60, 0x6b,
+	0x4f, 0xd2, 0x55, 0x13, 0xaf, 0xd9, 0x1d, 0x97, 0x46, 0xe2, 0x05, 0x78, 0x66, 0x2e, 0x68, 0xd6,
+	0x4e, 0xda, 0xa6, 0x69, 0x6f, 0xf3, 0xcd, 0x7e, 0x33, 0x3b, 0xfb, 0xcd, 0xcc, 0x42, 0xd7, 0xd9,
+	0xab, 0x51, 0x61, 0x0d, 0x99, 0xa8, 0x39, 0x9f, 0xce, 0x49, 0x4a, 0xe8, 0xbc, 0x57, 0xc6, 0xc5,
+	0xe8, 0x8a, 0xe8, 0x09, 0xb4, 0x1d, 0x29, 0x2a, 0x9d, 0x08, 0x86, 0xc1, 0x51, 0x2b, 0xae, 0x91,
+	0xfc, 0x1b, 0xc0, 0xd6, 0x27, 0xa3, 0xf3, 0x18, 0x7f, 0x46, 0x11, 0x34, 0xcb, 0x52, 0x67, 0x9e,
+	0xd1, 0x8d, 0xbd, 0xcd, 0x3e, 0xab, 0xf2, 0x4b, 0xd1, 0x18, 0x06, 0x47, 0xdb, 0xb1, 0xb7, 0xa3,
+	0x01, 0x84, 0xa5, 0xd5, 0x22, 0xf4, 0x34, 0x36, 0xa3, 0x5d, 0x68, 0xe5, 0x29, 0x5d, 0x3b, 0xd1,
+	0xf4, 0xb4, 0x0a, 0x70, 0xac, 0xca, 0x32, 0x2b, 0x5a, 0x55, 0x3e, 0xb6, 0xa3, 0x3d, 0xe8, 0x5c,
+	0x18, 0x47, 0xb9, 0x9a, 0xa3, 0x68, 0x7b, 0xff, 0x0a, 0x73, 0x5e, 0x9d, 0x5d, 0x8b, 0x2d, 0x9f,
+	0x83, 0xcd, 0x68, 0x08, 0xbd, 0x89, 0x3a, 0xb7, 0x3a, 0xd5, 0x13, 0x95, 0xa2, 0xe8, 0xf8, 0x80,
+	0xdb, 0xae, 0xe8, 0x10, 0xfa, 0x15, 0x2c, 0xac, 0xb9, 0xd2, 0x19, 0x5a, 0xd1, 0xf5, 0xa4, 0x35,
+	0xaf, 0xaf, 0x90, 0xa6, 0xe4, 0x04, 0xd4, 0x15, 0x32, 0x90, 0xbf, 0xa1, 0x53, 0x3d, 0xfe, 0x61,
+	0x85, 0x36, 0x2a, 0xf0, 0x02, 0x5a, 0x7c, 0x8a, 0x5e, 0x83, 0xfe, 0xf1, 0xee, 0x88, 0xf5, 0x1e,
+	0x2d, 0x53, 0x8d, 0x12, 0x3e, 0x8b, 0x2b, 0x8a, 0x14, 0xd0, 0xf2, 0x38, 0x6a, 0x43, 0xe3, 0xe4,
+	0xcb, 0xe0, 0xbf, 0x68, 0x0b, 0xc2, 0xaf, 0x3f, 0xbe, 0x0f, 0x02, 0x79, 0x04, 0xfd, 0x53, 0x54,
+	0x19, 0xda, 0x6f, 0x25, 0xda, 0x05, 0x77, 0x80, 0x6b, 0x58, 0x38, 0xc2, 0x79, 0xdd, 0x83, 0x1a,
+	0xc9, 0x04, 0x76, 0xee, 0x30, 0x5d, 0x11, 0xed, 0xc3, 0x76, 0x5a, 0x5a, 0x8b, 0x39, 0x55, 0x27,
+	0x75, 0xc4, 0x5d, 0x27, 0xcb, 0x6d, 0xb1, 0x98, 0xe9, 0x54, 0x39, 0xd1, 0x18, 0x86, 0x2c, 0xf7,
+	0x12, 0xcb, 0x7d, 0x18, 0x7c, 0x44, 0x7a, 0x4b, 0xa4, 0xd2, 0x8b, 0x93, 0x7c, 0x62, 0xb8, 0x80,
+	0x01, 0x84, 0x6e, 0xe1, 0xea, 0x5c, 0x6c, 0xca, 0x3f, 0x01, 0xfc, 0xbf, 0x46, 0x7b, 0x44, 0xac,
+	0x57, 0xd0, 0x2c, 0x9c, 0xad, 0xee, 0xea, 0x1d, 0x3f, 0xad, 0x74, 0xb9, 0x17, 0x3e, 0x1a, 0x3b,
+	0x1b, 0x7b, 0xe2, 0xde, 0x4b, 0x08, 0xc7, 0xce, 0xae, 0x44, 0x0e, 0xee, 0x8f, 0x59, 0x63, 0x35,
+	0x66, 0xf2, 0x00, 0x76, 0xc6, 0x16, 0x8b, 0xe4, 0xa2, 0xa4, 0xcc, 0xfc, 0x5a, 0xce, 0xec, 0x7a,
+	0xa0, 0x7c, 0x03, 0xbd, 0xcf, 0x7a, 0x36, 0x8b, 0x55, 0x7e, 0xc9, 0x94, 0x5d, 0x68, 0x4d, 0x8c,
+	0x4d, 0xd1, 0x73, 0x3a, 0x71, 0x05, 0x36, 0xb5, 0x55, 0x3e, 0x87, 0xde, 0x58, 0xe7, 0xd3, 0x65,
+	0xe0, 0xa6, 0xdc, 0x33, 0xe8, 0xc7, 0x58, 0x18, 0x4b, 0x1f, 0xae, 0x30, 0xa7, 0x07, 0x58, 0xec,
+	0xa3, 0x45, 0x81, 0xcb, 0xe4, 0x6c, 0xb3, 0x64, 0x16, 0x95, 0x33, 0x79, 0xbd, 0x38, 0x35, 0xe2,
+	0x16, 0xe9, 0x0c, 0x73, 0xd2, 0xb4, 0xf0, 0xeb, 0xd3, 0x8d, 0x57, 0x58, 0x1e, 0xc0, 0x76, 0x42,
+	0xca, 0x12, 0x57, 0xe4, 0xea, 0xb7, 0xf0, 0x05, 0x2c, 0x7b, 0xc8, 0x63, 0xec, 0x01, 0x0f, 0xd2,
+	0x6d, 0xda, 0x5a, 0x7f, 0xc2, 0x5b, 0xeb, 0x3e, 0x04, 0x48, 0x90, 0x1e, 0x7b, 0x60, 0x02, 0xbd,
+	0x77, 0x16, 0x15, 0xe1, 0x99, 0xbf, 0xf0, 0x19, 0x74, 0xcf, 0x8d, 0x21, 0x47, 0x56, 0x15, 0xb5,
+	0x80, 0x37, 0x8e, 0xd5, 0x8f, 0xd1, 0xb8, 0xfb, 0x63, 0xf8, 0xad, 0x0f, 0x6f, 0xb6, 0x5e, 0x1e,
+	0x42, 0x3f, 0x41, 0x3a, 0x35, 0xd3, 0x33, 0xe5, 0x56, 0x0f, 0x99, 0xb3, 0x5d, 0x8f, 0x5a, 0x05,
+	0xce, 0xdb, 0xfe, 0xfb, 0x7a, 0xfd, 0x6f, 0x00, 0x66, 0x31, 0x7e, 0x62, 0xcb, 0x04, 0x00, 0x00,
 }
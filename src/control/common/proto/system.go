@@ -25,6 +25,7 @@ package proto
 
 import (
 	"net"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -42,12 +43,26 @@ func MembersToPB(members system.Members) (pbMembers []*ctlpb.SystemMember, err e
 			return nil, errors.Errorf("nil member at index %d", i)
 		}
 
-		pbMembers = append(pbMembers, &ctlpb.SystemMember{
-			Addr:  m.Addr.String(),
-			Uuid:  m.UUID,
-			Rank:  m.Rank,
-			State: uint32(m.State()),
-		})
+		pbMember := &ctlpb.SystemMember{
+			Addr:           m.Addr.String(),
+			Uuid:           m.UUID,
+			Rank:           m.Rank,
+			State:          uint32(m.State()),
+			Hostname:       m.Hostname,
+			Idx:            m.InstanceIdx,
+			Fabriciface:    m.FabricIface,
+			Fabricprovider: m.FabricProvider,
+			Ntargets:       m.TargetCount,
+			Statereason:    m.StateChangeReason,
+		}
+		if !m.StateChangeTime.IsZero() {
+			pbMember.Statechanged = m.StateChangeTime.Format(time.RFC3339)
+		}
+		if m.State() == system.MemberStateStarted && !m.StartTime.IsZero() {
+			pbMember.Uptime = uint64(time.Since(m.StartTime).Seconds())
+		}
+
+		pbMembers = append(pbMembers, pbMember)
 	}
 
 	return
@@ -66,8 +81,26 @@ func MembersFromPB(log logging.Logger, pbMembers []*ctlpb.SystemMember) (members
 			return
 		}
 
-		members = append(members, system.NewMember(m.Rank, m.Uuid, addr,
-			system.MemberState(m.State)))
+		member := system.NewMember(m.Rank, m.Uuid, addr,
+			system.MemberState(m.State))
+		member.Hostname = m.Hostname
+		member.InstanceIdx = m.Idx
+		member.FabricIface = m.Fabriciface
+		member.FabricProvider = m.Fabricprovider
+		member.TargetCount = m.Ntargets
+		member.StateChangeReason = m.Statereason
+		if m.Statechanged != "" {
+			member.StateChangeTime, err = time.Parse(time.RFC3339, m.Statechanged)
+			if err != nil {
+				return nil, errors.Wrapf(err,
+					"parse state change time of rank %d", m.Rank)
+			}
+		}
+		if m.Uptime > 0 {
+			member.StartTime = time.Now().Add(-time.Duration(m.Uptime) * time.Second)
+		}
+
+		members = append(members, member)
 	}
 
 	return
@@ -105,3 +138,42 @@ func MemberResultsFromPB(log logging.Logger, pbResults []*ctlpb.SystemStopResp_R
 
 	return results
 }
+
+// EventToPB converts system.Event to equivalent protobuf format.
+func EventToPB(event *system.Event) *ctlpb.SystemEvent {
+	return &ctlpb.SystemEvent{
+		Sequence: event.Sequence,
+		Time:     event.Time.Format(time.RFC3339Nano),
+		Type:     uint32(event.Type),
+		Rank:     event.Rank,
+		Oldstate: uint32(event.OldState),
+		Newstate: uint32(event.NewState),
+		Reason:   event.Reason,
+		Identity: event.Identity,
+		Origin:   event.Origin,
+	}
+}
+
+// EventFromPB converts system event from protobuf format to system.Event.
+func EventFromPB(pbEvent *ctlpb.SystemEvent) (*system.Event, error) {
+	ts, err := time.Parse(time.RFC3339Nano, pbEvent.Time)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse time of event %d", pbEvent.Sequence)
+	}
+	if !system.EventType(pbEvent.Type).IsValid() {
+		return nil, errors.Errorf("unknown type %d of event %d",
+			pbEvent.Type, pbEvent.Sequence)
+	}
+
+	return &system.Event{
+		Sequence: pbEvent.Sequence,
+		Time:     ts,
+		Type:     system.EventType(pbEvent.Type),
+		Rank:     pbEvent.Rank,
+		OldState: system.MemberState(pbEvent.Oldstate),
+		NewState: system.MemberState(pbEvent.Newstate),
+		Reason:   pbEvent.Reason,
+		Identity: pbEvent.Identity,
+		Origin:   pbEvent.Origin,
+	}, nil
+}
@@ -38,9 +38,9 @@ const MsgStoragePrepareWarn = "Memory allocation goals for SCM will be changed a
 	"and subsequent reboot maybe required.\n"
 
 type StoragePrepareNvmeCmd struct {
-	PCIWhiteList string `short:"w" long:"pci-whitelist" description:"Whitespace separated list of PCI devices (by address) to be unbound from Kernel driver and used with SPDK (default is all PCI devices)."`
+	PCIWhiteList string `short:"w" long:"pci-whitelist" description:"Whitespace separated list of PCI devices (by address) to be unbound from Kernel driver and used with SPDK (default is all PCI devices; when not run as root, the devices must be configured and listed here)."`
 	NrHugepages  int    `short:"p" long:"hugepages" description:"Number of hugepages to allocate (in MB) for use by SPDK (default 1024)"`
-	TargetUser   string `short:"u" long:"target-user" description:"User that will own hugepage mountpoint directory and vfio groups."`
+	TargetUser   string `short:"u" long:"target-user" description:"User that will own hugepage mountpoint directory and vfio groups (when not run as root, this must be the daos_server user)."`
 }
 
 type StoragePrepareScmCmd struct{}
@@ -0,0 +1,139 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package drpc
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CaptureRecord describes a single dRPC call and the response to it, as
+// written to a capture file.
+type CaptureRecord struct {
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration_ns"`
+	Module   int32         `json:"module"`
+	Method   int32         `json:"method"`
+	Sequence int64         `json:"sequence"`
+	Body     []byte        `json:"body,omitempty"`
+	Status   Status        `json:"status"`
+	Response []byte        `json:"response,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// newCaptureRecord returns a record of the call made at the given time and
+// its outcome.
+func newCaptureRecord(call *Call, resp *Response, started time.Time, err error) *CaptureRecord {
+	rec := &CaptureRecord{
+		Time:     started,
+		Duration: time.Since(started),
+		Module:   call.GetModule(),
+		Method:   call.GetMethod(),
+		Sequence: call.GetSequence(),
+		Body:     call.GetBody(),
+		Status:   resp.GetStatus(),
+		Response: resp.GetBody(),
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+
+	return rec
+}
+
+// Recorder writes records of dRPC calls as JSON lines, one record per line,
+// to a capture file. A Recorder may be shared by several clients and
+// servers; the first write error stops further records and is returned by
+// every later call to Record.
+type Recorder struct {
+	sync.Mutex
+	output io.Writer
+	err    error
+}
+
+// NewRecorder returns a *Recorder writing records to the supplied io.Writer.
+func NewRecorder(output io.Writer) *Recorder {
+	return &Recorder{output: output}
+}
+
+// Record writes a record of the call made at the given time, along with
+// the response or the error that ended it.
+func (r *Recorder) Record(call *Call, resp *Response, started time.Time, err error) error {
+	buf, mErr := json.Marshal(newCaptureRecord(call, resp, started, err))
+	if mErr != nil {
+		return errors.Wrap(mErr, "marshal capture record")
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+	if _, wErr := r.output.Write(append(buf, '\n')); wErr != nil {
+		r.err = errors.Wrap(wErr, "write capture record")
+	}
+
+	return r.err
+}
+
+// ReadCapture reads the records written by a Recorder.
+func ReadCapture(input io.Reader) ([]*CaptureRecord, error) {
+	var records []*CaptureRecord
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(nil, 2*DefaultMaxMessageSize)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		rec := &CaptureRecord{}
+		if err := json.Unmarshal(scanner.Bytes(), rec); err != nil {
+			return nil, errors.Wrapf(err, "capture record on line %d", line)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read capture")
+	}
+
+	return records, nil
+}
+
+// LoadCaptureFile reads the records from the capture file at path.
+func LoadCaptureFile(path string) ([]*CaptureRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "open capture file")
+	}
+	defer f.Close()
+
+	return ReadCapture(f)
+}
@@ -0,0 +1,210 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package drpc
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	started := time.Now()
+
+	calls := []struct {
+		call *Call
+		resp *Response
+		err  error
+	}{
+		{
+			call: &Call{Module: 1, Method: 2, Sequence: 3, Body: []byte("call")},
+			resp: &Response{Sequence: 3, Status: Status_SUCCESS, Body: []byte("resp")},
+		},
+		{
+			call: &Call{Module: 1, Method: 4, Sequence: 4},
+			resp: &Response{Sequence: 4, Status: Status_UNKNOWN_METHOD},
+		},
+		{
+			call: &Call{Module: 2, Method: 1, Sequence: 5},
+			err:  FaultTimeout,
+		},
+	}
+	for _, c := range calls {
+		if err := rec.Record(c.call, c.resp, started, c.err); err != nil {
+			t.Fatal(err)
+		}
+	}
+	common.AssertEqual(t, strings.Count(buf.String(), "\n"), len(calls),
+		"expected one line per record")
+
+	records, err := ReadCapture(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expRecords := []*CaptureRecord{
+		{
+			Module: 1, Method: 2, Sequence: 3, Body: []byte("call"),
+			Status: Status_SUCCESS, Response: []byte("resp"),
+		},
+		{
+			Module: 1, Method: 4, Sequence: 4,
+			Status: Status_UNKNOWN_METHOD,
+		},
+		{
+			Module: 2, Method: 1, Sequence: 5,
+			Error: FaultTimeout.Error(),
+		},
+	}
+	opts := cmpopts.IgnoreFields(CaptureRecord{}, "Time", "Duration")
+	if diff := cmp.Diff(expRecords, records, opts); diff != "" {
+		t.Fatalf("unexpected records (-want, +got):\n%s\n", diff)
+	}
+	for _, r := range records {
+		common.AssertTrue(t, r.Time.Equal(started), "record has wrong time")
+	}
+}
+
+func TestReadCapture_BadRecord(t *testing.T) {
+	_, err := ReadCapture(strings.NewReader("{\"module\": 1}\n\nnot json\n"))
+	common.CmpErr(t, errors.New("capture record on line 3"), err)
+}
+
+type failWriter struct {
+	writes int
+}
+
+func (w *failWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return 0, errors.New("disk full")
+}
+
+func TestRecorder_WriteError(t *testing.T) {
+	w := &failWriter{}
+	rec := NewRecorder(w)
+
+	for i := 0; i < 2; i++ {
+		err := rec.Record(&Call{}, &Response{}, time.Now(), nil)
+		common.CmpErr(t, errors.New("disk full"), err)
+	}
+	common.AssertEqual(t, w.writes, 1, "expected no writes after a failure")
+}
+
+func TestServer_Integration_CaptureReplay(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	tmpDir, tmpCleanup := common.CreateTestDir(t)
+	defer tmpCleanup()
+
+	var srvCapture, clientCapture bytes.Buffer
+	mod := &echoModule{}
+	calls := []*Call{
+		{Module: mod.ID(), Method: 1, Body: []byte("first")},
+		{Module: mod.ID(), Method: 2, Body: []byte("second")},
+		{Module: mod.ID() + 1, Method: 1},
+	}
+
+	sendCalls := func(path string, rec *Recorder) []*Response {
+		client := NewClientConnection(path).WithRecorder(rec)
+		if err := client.Connect(); err != nil {
+			t.Fatalf("failed to connect client: %v", err)
+		}
+		defer client.Close()
+
+		var resps []*Response
+		for _, call := range calls {
+			resp, err := client.SendMsg(context.Background(), &Call{
+				Module: call.Module,
+				Method: call.Method,
+				Body:   call.Body,
+			})
+			if err != nil {
+				t.Fatalf("failed to send message: %v", err)
+			}
+			resps = append(resps, resp)
+		}
+		return resps
+	}
+
+	// Record the calls on both ends of a live session.
+	path := filepath.Join(tmpDir, "live.sock")
+	dss, _ := NewDomainSocketServer(context.Background(), log, path)
+	dss.WithRecorder(NewRecorder(&srvCapture))
+	dss.RegisterRPCModule(mod)
+	if err := dss.Start(); err != nil {
+		t.Fatalf("Couldn't start dRPC server: %v", err)
+	}
+	liveResps := sendCalls(path, NewRecorder(&clientCapture))
+	dss.Shutdown()
+
+	srvRecords, err := ReadCapture(&srvCapture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientRecords, err := ReadCapture(&clientCapture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := cmpopts.IgnoreFields(CaptureRecord{}, "Time", "Duration")
+	if diff := cmp.Diff(clientRecords, srvRecords, opts); diff != "" {
+		t.Fatalf("client and server captures differ (-client, +server):\n%s\n", diff)
+	}
+	common.AssertEqual(t, len(srvRecords), len(calls), "expected a record per call")
+	common.AssertEqual(t, srvRecords[2].Status, Status_UNKNOWN_MODULE, "bad recorded status")
+
+	// Replay the server's capture without the real module.
+	path = filepath.Join(tmpDir, "replay.sock")
+	replay, mods, err := NewReplayServer(context.Background(), log, path, srvRecords)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := replay.Start(); err != nil {
+		t.Fatalf("Couldn't start replay server: %v", err)
+	}
+	defer replay.Shutdown()
+
+	replayResps := sendCalls(path, nil)
+	if diff := cmp.Diff(liveResps, replayResps); diff != "" {
+		t.Fatalf("replayed responses differ (-live, +replay):\n%s\n", diff)
+	}
+	for _, m := range mods {
+		common.AssertEqual(t, m.Remaining(), 0, "expected all responses to be replayed")
+	}
+
+	// Once the recorded responses run out, calls fail.
+	resps := sendCalls(path, nil)
+	common.AssertEqual(t, resps[0].Status, Status_FAILURE, "expected failure after capture exhausted")
+}
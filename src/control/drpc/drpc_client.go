@@ -24,21 +24,24 @@
 package drpc
 
 import (
+	"context"
 	"net"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 )
 
 // DomainSocketClient is the interface to a dRPC client communicating over a
-// Unix Domain Socket
+// Unix Domain Socket. SendMsg may be called concurrently; the Locker is only
+// needed by callers wanting exclusive use of the connection.
 type DomainSocketClient interface {
 	sync.Locker
 	IsConnected() bool
 	Connect() error
 	Close() error
-	SendMsg(call *Call) (*Response, error)
+	SendMsg(ctx context.Context, call *Call) (*Response, error)
 }
 
 // domainSocketDialer is an interface that connects to a Unix Domain Socket
@@ -46,23 +49,56 @@ type domainSocketDialer interface {
 	dial(socketPath string) (net.Conn, error)
 }
 
-// ClientConnection represents a client connection to a dRPC server
+// ClientConnection represents a client connection to a dRPC server. Several
+// calls may be in flight on the connection at once; responses are matched
+// to calls by sequence number.
 type ClientConnection struct {
 	sync.Mutex
 	socketPath string             // Filesystem location of dRPC socket
 	dialer     domainSocketDialer // Interface to connect to the socket
 	conn       net.Conn           // Connection to socket
 	sequence   int64              // Increment each time we send
+
+	callMutex  sync.Mutex     // protects the fields above and below
+	writeMutex sync.Mutex     // serializes writes of calls
+	session    *clientSession // calls in flight on conn
+	readCond   *sync.Cond     // signaled when a read completes
+	writer     messageWriter  // splits large calls into frames
+	maxMsgSize int            // largest response accepted
+	recorder   *Recorder      // records calls if set
+}
+
+// clientSession holds the calls in flight on one connection to the server.
+// It is replaced when the client reconnects, so calls still waiting on the
+// old connection don't see responses or errors from the new one.
+type clientSession struct {
+	conn    net.Conn
+	reader  *messageReader         // reassembles framed responses
+	pending map[int64]*pendingCall // calls waiting for a response
+	reading bool                   // a caller is reading a response
+}
+
+// pendingCall holds the result of a call once its response has been read.
+type pendingCall struct {
+	done bool
+	resp *Response
+	err  error
 }
 
 // IsConnected indicates whether the client connection is currently active
 func (c *ClientConnection) IsConnected() bool {
+	c.callMutex.Lock()
+	defer c.callMutex.Unlock()
+
 	return c.conn != nil
 }
 
 // Connect opens a connection to the internal Unix Domain Socket path
 func (c *ClientConnection) Connect() error {
-	if c.IsConnected() {
+	c.callMutex.Lock()
+	defer c.callMutex.Unlock()
+
+	if c.conn != nil {
 		// Nothing to do
 		return nil
 	}
@@ -72,14 +108,38 @@ func (c *ClientConnection) Connect() error {
 		return errors.Wrap(err, "dRPC connect")
 	}
 
+	// The sequence number carries on from the previous connection, as
+	// calls made on it may still be waiting for their responses.
 	c.conn = conn
-	c.sequence = 0 // reset message sequence number on connect
 	return nil
 }
 
-// Close shuts down the connection to the Unix Domain Socket
+// WithMaxMessageSize sets the size of the largest response accepted from
+// the server.
+func (c *ClientConnection) WithMaxMessageSize(size int) *ClientConnection {
+	c.callMutex.Lock()
+	defer c.callMutex.Unlock()
+
+	c.maxMsgSize = size
+	return c
+}
+
+// WithRecorder records every call sent on the connection and its response.
+func (c *ClientConnection) WithRecorder(rec *Recorder) *ClientConnection {
+	c.callMutex.Lock()
+	defer c.callMutex.Unlock()
+
+	c.recorder = rec
+	return c
+}
+
+// Close shuts down the connection to the Unix Domain Socket. Calls still
+// waiting for a response fail.
 func (c *ClientConnection) Close() error {
-	if !c.IsConnected() {
+	c.callMutex.Lock()
+	defer c.callMutex.Unlock()
+
+	if c.conn == nil {
 		// Nothing to do
 		return nil
 	}
@@ -93,32 +153,62 @@ func (c *ClientConnection) Close() error {
 	return nil
 }
 
-func (c *ClientConnection) sendCall(msg *Call) error {
+// startCall assigns the next sequence number to the call and registers it
+// to wait for a response on the current connection.
+func (c *ClientConnection) startCall(msg *Call) (*clientSession, *pendingCall, error) {
+	c.callMutex.Lock()
+	defer c.callMutex.Unlock()
+
+	if c.conn == nil {
+		return nil, nil, errors.Errorf("dRPC not connected")
+	}
+	if c.readCond == nil {
+		c.readCond = sync.NewCond(&c.callMutex)
+	}
+	if c.session == nil || c.session.conn != c.conn {
+		if c.maxMsgSize == 0 {
+			c.maxMsgSize = DefaultMaxMessageSize
+		}
+		c.session = &clientSession{
+			conn:    c.conn,
+			reader:  newMessageReader(c.maxMsgSize),
+			pending: make(map[int64]*pendingCall),
+		}
+	}
+
 	// increment sequence every call, always nonzero
 	c.sequence++
 	msg.Sequence = c.sequence
+	call := &pendingCall{}
+	c.session.pending[msg.Sequence] = call
 
-	callBytes, err := proto.Marshal(msg)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal dRPC request")
+	return c.session, call, nil
+}
+
+func (c *ClientConnection) sendCall(ctx context.Context, conn net.Conn, callBytes []byte) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetWriteDeadline(deadline); err != nil {
+		return errors.Wrap(err, "dRPC set write deadline")
 	}
 
-	if _, err := c.conn.Write(callBytes); err != nil {
+	if err := c.writer.writeMessage(conn, callBytes); err != nil {
 		return errors.Wrap(err, "dRPC send")
 	}
 
 	return nil
 }
 
-func (c *ClientConnection) recvResponse() (*Response, error) {
-	respBytes := make([]byte, MaxMsgSize)
-	numBytes, err := c.conn.Read(respBytes)
+func (c *ClientConnection) recvResponse(conn net.Conn, reader *messageReader) (*Response, error) {
+	respBytes, err := reader.readMessage(conn)
 	if err != nil {
 		return nil, errors.Wrap(err, "dRPC recv")
 	}
 
 	resp := &Response{}
-	err = proto.Unmarshal(respBytes[:numBytes], resp)
+	err = proto.Unmarshal(respBytes, resp)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal dRPC response")
 	}
@@ -126,23 +216,171 @@ func (c *ClientConnection) recvResponse() (*Response, error) {
 	return resp, nil
 }
 
-// SendMsg sends a message to the connected dRPC server, and returns the
-// response to the caller.
-func (c *ClientConnection) SendMsg(msg *Call) (*Response, error) {
-	if !c.IsConnected() {
-		return nil, errors.Errorf("dRPC not connected")
+// deliver hands a response read from the connection to the call waiting for
+// it. A response to a call which has given up waiting, or which has no
+// sequence number, is discarded. A read error fails every waiting call, as
+// the connection can no longer be trusted. Must be called with callMutex
+// held.
+func (s *clientSession) deliver(resp *Response, err error) {
+	if err != nil {
+		for _, call := range s.pending {
+			if !call.done {
+				call.done, call.err = true, err
+			}
+		}
+		return
+	}
+
+	if call, found := s.pending[resp.Sequence]; found && !call.done {
+		call.done, call.resp = true, resp
+	}
+}
+
+// awaitResponse waits for the response to the call with the given sequence
+// number. Whichever waiting caller gets to the connection first reads the
+// next response and hands it to the call it belongs to.
+//
+// If the context ends first, the call stops waiting and its response is
+// discarded when it arrives. The connection is kept for the other calls in
+// flight on it; an interrupted read leaves it usable, as responses are read
+// a whole packet at a time.
+func (c *ClientConnection) awaitResponse(ctx context.Context, s *clientSession, sequence int64, call *pendingCall) (*Response, error) {
+	c.callMutex.Lock()
+	defer c.callMutex.Unlock()
+
+	// Wake this caller when the context ends, interrupting its read if it
+	// is the one reading from the connection.
+	var selfReading bool
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stop:
+			return
+		}
+
+		c.callMutex.Lock()
+		defer c.callMutex.Unlock()
+		if selfReading {
+			s.conn.SetReadDeadline(time.Now())
+		}
+		c.readCond.Broadcast()
+	}()
+	defer close(stop)
+	defer delete(s.pending, sequence)
+
+	for !call.done {
+		if ctx.Err() != nil {
+			return nil, callError(ctx)
+		}
+		if s.reading {
+			c.readCond.Wait()
+			continue
+		}
+
+		s.reading, selfReading = true, true
+		deadline, _ := ctx.Deadline()
+		err := s.conn.SetReadDeadline(deadline)
+		c.callMutex.Unlock()
+		var resp *Response
+		if err == nil {
+			resp, err = c.recvResponse(s.conn, s.reader)
+		}
+		c.callMutex.Lock()
+		s.reading, selfReading = false, false
+
+		// Another waiting caller takes over reading.
+		c.readCond.Broadcast()
+		if isTimeout(err) {
+			return nil, callError(ctx)
+		}
+		s.deliver(resp, err)
+	}
+
+	return call.resp, call.err
+}
+
+// abandon closes the connection after a call was partially written to it,
+// unless it has already been replaced. Calls still waiting on it fail, and
+// the next call reconnects. Must be called with callMutex held.
+func (c *ClientConnection) abandon(conn net.Conn) {
+	if c.conn != conn {
+		return
+	}
+
+	conn.Close()
+	c.conn = nil
+}
+
+// callError returns the error for a call abandoned because its context
+// ended.
+func callError(ctx context.Context) error {
+	if ctx.Err() == context.Canceled {
+		return errors.Wrap(ctx.Err(), "dRPC call")
 	}
 
+	return FaultTimeout
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := errors.Cause(err).(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// SendMsg sends a message to the connected dRPC server, and returns the
+// response to the caller. It is safe to call from several goroutines at
+// once.
+//
+// The context's deadline bounds both sending the call and waiting for the
+// response. A call that times out returns FaultTimeout; other calls in
+// flight on the connection carry on.
+func (c *ClientConnection) SendMsg(ctx context.Context, msg *Call) (*Response, error) {
 	if msg == nil {
 		return nil, errors.Errorf("invalid dRPC call")
 	}
+	if ctx.Err() != nil {
+		return nil, callError(ctx)
+	}
 
-	err := c.sendCall(msg)
+	started := time.Now()
+	s, call, err := c.startCall(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.exchange(ctx, s, msg, call)
+	if c.recorder != nil {
+		// Failing to record the call doesn't fail it.
+		_ = c.recorder.Record(msg, resp, started, err)
+	}
+
+	return resp, err
+}
+
+// exchange sends the call and waits for the response to it.
+func (c *ClientConnection) exchange(ctx context.Context, s *clientSession, msg *Call, call *pendingCall) (*Response, error) {
+	callBytes, err := proto.Marshal(msg)
+	if err == nil {
+		err = c.sendCall(ctx, s.conn, callBytes)
+	} else {
+		err = errors.Wrap(err, "failed to marshal dRPC request")
+	}
 	if err != nil {
+		c.callMutex.Lock()
+		defer c.callMutex.Unlock()
+		delete(s.pending, msg.Sequence)
+		if isTimeout(err) {
+			// A call split into frames may have been partially
+			// written, which the server can't recover from.
+			if len(callBytes) > MaxMsgSize {
+				c.abandon(s.conn)
+			}
+			return nil, callError(ctx)
+		}
 		return nil, errors.WithStack(err)
 	}
 
-	return c.recvResponse()
+	return c.awaitResponse(ctx, s, msg.Sequence, call)
 }
 
 // NewClientConnection creates a new dRPC client
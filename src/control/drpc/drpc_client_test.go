@@ -24,9 +24,11 @@
 package drpc
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -104,8 +106,8 @@ func TestClient_Connect_Success(t *testing.T) {
 		"Expected conn returned from the mock dialer")
 	common.AssertEqual(t, dialer.InputSockPath, testSockPath,
 		"Should be using passed-in socket path")
-	common.AssertEqual(t, client.sequence, int64(0),
-		"Expected sequence number to have been reset")
+	common.AssertEqual(t, client.sequence, int64(10),
+		"Expected sequence number to carry on from the previous connection")
 }
 
 func TestClient_Connect_Error(t *testing.T) {
@@ -174,7 +176,7 @@ func TestClient_SendMsg_NilInput(t *testing.T) {
 	conn := newMockConn()
 	client := newTestClientConnection(newMockDialer(), conn)
 
-	response, err := client.SendMsg(nil)
+	response, err := client.SendMsg(context.Background(), nil)
 
 	common.AssertTrue(t, response == nil, "Expected no response")
 	common.ExpectError(t, err, "invalid dRPC call", "Expect error on nil input")
@@ -221,7 +223,7 @@ func TestClient_SendMsg_Success(t *testing.T) {
 	conn.SetReadOutputBytesToResponse(t, expectedResp)
 	expectedRespBytes := conn.ReadOutputBytes
 
-	response, err := client.SendMsg(call)
+	response, err := client.SendMsg(context.Background(), call)
 
 	common.AssertTrue(t, err == nil, "Expected no error")
 	common.AssertTrue(t, response != nil, "Expected a real response")
@@ -242,13 +244,29 @@ func TestClient_SendMsg_Success(t *testing.T) {
 func TestClient_SendMsg_NotConnected(t *testing.T) {
 	client := newTestClientConnection(newMockDialer(), nil)
 
-	response, err := client.SendMsg(newTestCall())
+	response, err := client.SendMsg(context.Background(), newTestCall())
 
 	common.AssertTrue(t, response == nil, "Expected no response")
 	common.ExpectError(t, err, "dRPC not connected",
 		"Expected error for unconnected client")
 }
 
+func TestClient_SendMsg_ContextExpired(t *testing.T) {
+	conn := newMockConn()
+	client := newTestClientConnection(newMockDialer(), conn)
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+	<-ctx.Done()
+
+	response, err := client.SendMsg(ctx, newTestCall())
+
+	common.AssertTrue(t, response == nil, "Expected no response")
+	common.AssertTrue(t, FaultTimeout.Equals(err), "Expected timeout fault")
+	common.AssertEqual(t, conn.WriteCallCount, 0, "Expected no write")
+	common.AssertTrue(t, client.IsConnected(), "Expected connection to be kept")
+}
+
 func TestClient_SendMsg_WriteError(t *testing.T) {
 	conn := newMockConn()
 	client := newTestClientConnection(newMockDialer(), conn)
@@ -256,7 +274,7 @@ func TestClient_SendMsg_WriteError(t *testing.T) {
 	call := newTestCall()
 	conn.WriteOutputError = errors.New("mock write failure")
 
-	response, err := client.SendMsg(call)
+	response, err := client.SendMsg(context.Background(), call)
 
 	common.AssertTrue(t, response == nil, "Expected no response")
 	common.CmpErr(t, conn.WriteOutputError, err)
@@ -272,7 +290,7 @@ func TestClient_SendMsg_ReadError(t *testing.T) {
 	conn.ReadOutputNumBytes = 0
 	conn.ReadOutputError = errors.New("mock read failure")
 
-	response, err := client.SendMsg(call)
+	response, err := client.SendMsg(context.Background(), call)
 
 	common.AssertTrue(t, response == nil, "Expected no response")
 	common.CmpErr(t, conn.ReadOutputError, err)
@@ -292,9 +310,31 @@ func TestClient_SendMsg_UnmarshalResponseFailure(t *testing.T) {
 	}
 	conn.ReadOutputNumBytes = len(conn.ReadOutputBytes)
 
-	response, err := client.SendMsg(call)
+	response, err := client.SendMsg(context.Background(), call)
 
 	expectedErr := "failed to unmarshal dRPC response: unexpected EOF"
 	common.AssertTrue(t, response == nil, "Expected no response")
 	common.ExpectError(t, err, expectedErr, "Expected protobuf error")
 }
+
+func TestClient_SendMsg_UnmatchedSequence(t *testing.T) {
+	conn := newMockConn()
+	client := newTestClientConnection(newMockDialer(), conn)
+
+	call := newTestCall()
+	conn.SetWriteOutputBytesForCall(t, call)
+
+	// A response to some other call, e.g. a late one to a call which has
+	// given up waiting, is discarded rather than given to this one.
+	conn.SetReadOutputBytesToResponse(t, &Response{Sequence: -1, Status: Status_FAILED_UNMARSHAL_CALL})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	response, err := client.SendMsg(ctx, call)
+
+	common.AssertTrue(t, response == nil, "Expected no response")
+	common.AssertTrue(t, FaultTimeout.Equals(err), "Expected timeout fault")
+	common.AssertEqual(t, len(client.session.pending), 0, "Expected no pending calls")
+	common.AssertTrue(t, client.IsConnected(), "Expected connection to be kept")
+}
@@ -32,17 +32,27 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
 )
 
-// MaxMsgSize is the maximum drpc message size that may be sent.
+// MaxMsgSize is the maximum drpc packet size that may be sent.
 // Using a packetsocket over the unix domain socket means that we receive
-// a whole message at a time without knowing its size. So for this reason
-// we need to restrict the maximum message size so we can preallocate a
-// buffer to put all of the information in. Corresponding C definition is
-// found in include/daos/drpc.h
+// a whole packet at a time without knowing its size. So for this reason
+// we need to restrict the maximum packet size so we can preallocate a
+// buffer to put all of the information in. Larger messages are split into
+// frames of at most this size. Corresponding C definition is found in
+// include/daos/drpc.h
 //
 const MaxMsgSize = 16384
 
+// DefaultSocketMode is the mode of the socket file unless set with
+// WithSocketMode. It allows any local user to connect.
+const DefaultSocketMode os.FileMode = 0777
+
+// MaxSessionCalls is the maximum number of calls handled concurrently for a
+// single session. Further calls wait to be read until one completes.
+const MaxSessionCalls = 16
+
 // DomainSocketServer is the object that listens for incoming dRPC connections,
 // maintains the connections for sessions, and manages the message processing.
 type DomainSocketServer struct {
@@ -54,6 +64,10 @@ type DomainSocketServer struct {
 	service       *ModuleService
 	sessions      map[net.Conn]*Session
 	sessionsMutex sync.Mutex
+	sockMode      os.FileMode
+	sockUID       int
+	sockGID       int
+	maxMsgSize    int
 }
 
 // closeSession cleans up the session and removes it from the list of active
@@ -67,13 +81,26 @@ func (d *DomainSocketServer) closeSession(s *Session) {
 }
 
 // listenSession runs the listening loop for a Session. It listens for incoming
-// dRPC calls and processes them.
+// dRPC calls and processes them concurrently, so that a slow call doesn't
+// hold up the others sent on the same session.
 func (d *DomainSocketServer) listenSession(s *Session) {
+	inFlight := make(chan struct{}, MaxSessionCalls)
 	for {
-		if err := s.ProcessIncomingMessage(); err != nil {
+		msg, err := s.readMessage()
+		if err != nil || len(msg) == 0 {
+			// An empty read means the peer has shut down the
+			// connection.
 			d.closeSession(s)
 			break
 		}
+
+		inFlight <- struct{}{}
+		go func() {
+			defer func() { <-inFlight }()
+			if err := s.processMessage(msg); err != nil {
+				d.log.Debugf("%s: failed to process dRPC call: %v", d.sockFile, err)
+			}
+		}()
 	}
 }
 
@@ -95,6 +122,7 @@ func (d *DomainSocketServer) Listen() {
 
 		d.log.Debug("Creating session for connection")
 		c := NewSession(conn, d.service)
+		c.reader = newMessageReader(d.maxMsgSize)
 		d.sessionsMutex.Lock()
 		d.sessions[conn] = c
 		d.sessionsMutex.Unlock()
@@ -116,11 +144,14 @@ func (d *DomainSocketServer) Start() error {
 	}
 	d.listener = lis
 
-	// TODO: Should we set more granular permissions? The only writer should
-	// be the IO server and we should know which user is running it.
-	if err := os.Chmod(d.sockFile, 0777); err != nil {
+	if err := os.Chmod(d.sockFile, d.sockMode); err != nil {
 		return errors.Wrapf(err, "Unable to set permissions on %s", d.sockFile)
 	}
+	if d.sockUID != -1 || d.sockGID != -1 {
+		if err := os.Chown(d.sockFile, d.sockUID, d.sockGID); err != nil {
+			return errors.Wrapf(err, "Unable to set ownership of %s", d.sockFile)
+		}
+	}
 
 	go d.Listen()
 	return nil
@@ -132,6 +163,33 @@ func (d *DomainSocketServer) Shutdown() {
 	d.cancelCtx()
 }
 
+// WithSocketMode sets the permissions of the socket file created by Start.
+func (d *DomainSocketServer) WithSocketMode(mode os.FileMode) *DomainSocketServer {
+	d.sockMode = mode
+	return d
+}
+
+// WithSocketOwner sets the owner and group of the socket file created by
+// Start. An ID of -1 leaves the owner or group unchanged.
+func (d *DomainSocketServer) WithSocketOwner(uid, gid int) *DomainSocketServer {
+	d.sockUID = uid
+	d.sockGID = gid
+	return d
+}
+
+// WithMaxMessageSize sets the size of the largest call accepted from a
+// client.
+func (d *DomainSocketServer) WithMaxMessageSize(size int) *DomainSocketServer {
+	d.maxMsgSize = size
+	return d
+}
+
+// WithRecorder records every call handled by the server and its response.
+func (d *DomainSocketServer) WithRecorder(rec *Recorder) *DomainSocketServer {
+	d.service.WithRecorder(rec)
+	return d
+}
+
 // RegisterRPCModule takes a Module and associates it with the given
 // DomainSocketServer so it can be used to process incoming dRPC calls.
 func (d *DomainSocketServer) RegisterRPCModule(mod Module) {
@@ -148,33 +206,58 @@ func NewDomainSocketServer(ctx context.Context, log logging.Logger, sock string)
 	sessions := make(map[net.Conn]*Session)
 	dssCtx, cancelCtx := context.WithCancel(ctx)
 	return &DomainSocketServer{
-		log:       log,
-		sockFile:  sock,
-		ctx:       dssCtx,
-		cancelCtx: cancelCtx,
-		service:   service,
-		sessions:  sessions}, nil
+		log:        log,
+		sockFile:   sock,
+		ctx:        dssCtx,
+		cancelCtx:  cancelCtx,
+		service:    service,
+		sessions:   sessions,
+		sockMode:   DefaultSocketMode,
+		sockUID:    -1,
+		sockGID:    -1,
+		maxMsgSize: DefaultMaxMessageSize}, nil
 }
 
 // Session represents an individual client connection to the Domain Socket Server.
 type Session struct {
-	Conn net.Conn
-	mod  *ModuleService
+	Conn       net.Conn
+	mod        *ModuleService
+	reader     *messageReader
+	writer     messageWriter
+	writeMutex sync.Mutex
+	peerOnce   sync.Once
+	peer       *security.DomainInfo
+	peerErr    error
 }
 
 // ProcessIncomingMessage listens for an incoming message on the session,
 // calls its handler, and sends the response.
 func (s *Session) ProcessIncomingMessage() error {
-	buffer := make([]byte, MaxMsgSize)
+	msg, err := s.readMessage()
+	if err != nil {
+		return err
+	}
+
+	return s.processMessage(msg)
+}
 
-	bytesRead, err := s.Conn.Read(buffer)
+// readMessage reads the next incoming message on the session, reassembling
+// it if it was split into frames.
+func (s *Session) readMessage() ([]byte, error) {
+	msg, err := s.reader.readMessage(s.Conn)
 	if err != nil {
 		// This indicates that we have reached a bad state
 		// for the connection and we need to terminate the handler.
-		return err
+		return nil, err
 	}
 
-	response, err := s.mod.ProcessMessage(s, buffer[:bytesRead])
+	return msg, nil
+}
+
+// processMessage calls the handler for the message and sends the response.
+// It may be called for several messages on the session at once.
+func (s *Session) processMessage(msg []byte) error {
+	response, err := s.mod.ProcessMessage(s, msg)
 	if err != nil {
 		// The only way we hit here is if we fail to marshal the module's
 		// response. Should not actually be possible. ProcessMessage
@@ -182,7 +265,10 @@ func (s *Session) ProcessIncomingMessage() error {
 		return err
 	}
 
-	_, err = s.Conn.Write(response)
+	s.writeMutex.Lock()
+	defer s.writeMutex.Unlock()
+
+	err = s.writer.writeMessage(s.Conn, response)
 	if err != nil {
 		// This should only happen if we're shutting down while
 		// trying to send our response.
@@ -192,6 +278,21 @@ func (s *Session) ProcessIncomingMessage() error {
 	return nil
 }
 
+// PeerInfo returns the credentials of the process at the other end of the
+// session's unix socket, looking them up on first use.
+func (s *Session) PeerInfo(log logging.Logger) (*security.DomainInfo, error) {
+	s.peerOnce.Do(func() {
+		uConn, ok := s.Conn.(*net.UnixConn)
+		if !ok {
+			s.peerErr = errors.New("connection is not a unix socket")
+			return
+		}
+		s.peer, s.peerErr = security.DomainInfoFromUnixConn(log, uConn)
+	})
+
+	return s.peer, s.peerErr
+}
+
 // Close closes the session
 func (s *Session) Close() {
 	_ = s.Conn.Close()
@@ -200,7 +301,8 @@ func (s *Session) Close() {
 // NewSession creates a new dRPC Session object
 func NewSession(conn net.Conn, svc *ModuleService) *Session {
 	return &Session{
-		Conn: conn,
-		mod:  svc,
+		Conn:   conn,
+		mod:    svc,
+		reader: newMessageReader(DefaultMaxMessageSize),
 	}
 }
@@ -23,10 +23,14 @@
 package drpc
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/go-cmp/cmp"
@@ -232,6 +236,9 @@ func TestServer_Listen_AcceptConnection(t *testing.T) {
 
 	lis := newMockListener()
 	lis.setNumConnsToAccept(3)
+	// keep the sessions open until the test is done with them
+	lis.connReadBlock = make(chan struct{})
+	defer close(lis.connReadBlock)
 	dss, _ := NewDomainSocketServer(context.Background(), log, "dontcare.sock")
 	dss.listener = lis
 
@@ -239,7 +246,10 @@ func TestServer_Listen_AcceptConnection(t *testing.T) {
 
 	common.AssertEqual(t, lis.acceptCallCount, lis.acceptNumConns+1,
 		"should have returned after listener errored")
-	common.AssertEqual(t, len(dss.sessions), lis.acceptNumConns,
+	dss.sessionsMutex.Lock()
+	numSessions := len(dss.sessions)
+	dss.sessionsMutex.Unlock()
+	common.AssertEqual(t, numSessions, lis.acceptNumConns,
 		"server should have made connections into sessions")
 }
 
@@ -313,7 +323,7 @@ func TestServer_Integration(t *testing.T) {
 	call := &Call{
 		Module: mod.ID(),
 	}
-	resp, err := client.SendMsg(call)
+	resp, err := client.SendMsg(context.Background(), call)
 	if err != nil {
 		t.Fatalf("failed to send message: %v", err)
 	}
@@ -327,3 +337,269 @@ func TestServer_Integration(t *testing.T) {
 		t.Fatalf("(-want, +got)\n%s", diff)
 	}
 }
+
+func TestServer_Integration_PeerRestricted(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	tmpDir, tmpCleanup := common.CreateTestDir(t)
+	defer tmpCleanup()
+	path := filepath.Join(tmpDir, "test.sock")
+
+	dss, _ := NewDomainSocketServer(context.Background(), log, path)
+	dss.WithSocketMode(0700).WithSocketOwner(os.Getuid(), os.Getgid())
+
+	allowed := &mockRestrictedModule{
+		mockModule: mockModule{
+			IDValue:            1,
+			HandleCallResponse: []byte("successful!"),
+		},
+		PeerAllowList: PeerAllowList{UIDs: []uint32{uint32(os.Getuid())}},
+	}
+	dss.RegisterRPCModule(allowed)
+	denied := &mockRestrictedModule{
+		mockModule: mockModule{
+			IDValue:            2,
+			HandleCallResponse: []byte("successful!"),
+		},
+		PeerAllowList: PeerAllowList{
+			UIDs: []uint32{uint32(os.Getuid()) + 1},
+			GIDs: []uint32{uint32(os.Getgid()) + 1},
+		},
+	}
+	dss.RegisterRPCModule(denied)
+
+	if err := dss.Start(); err != nil {
+		t.Fatalf("Couldn't start dRPC server: %v", err)
+	}
+	defer dss.Shutdown()
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, fi.Mode().Perm(), os.FileMode(0700), "bad socket mode")
+
+	client := NewClientConnection(path)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	defer client.Close()
+
+	for _, tc := range []struct {
+		module    int32
+		expStatus Status
+	}{
+		{module: allowed.ID(), expStatus: Status_SUCCESS},
+		{module: denied.ID(), expStatus: Status_FAILURE},
+	} {
+		resp, err := client.SendMsg(context.Background(), &Call{Module: tc.module})
+		if err != nil {
+			t.Fatalf("failed to send message: %v", err)
+		}
+		common.AssertEqual(t, resp.GetStatus(), tc.expStatus,
+			fmt.Sprintf("bad status for module %d", tc.module))
+	}
+}
+
+// blockingModule is a Module whose calls to method 1 block until released
+type blockingModule struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (m *blockingModule) HandleCall(session *Session, method int32, input []byte) ([]byte, error) {
+	if method == 1 {
+		m.started <- struct{}{}
+		<-m.release
+	}
+	return []byte(fmt.Sprintf("method %d", method)), nil
+}
+
+func (m *blockingModule) ID() int32 {
+	return 1234
+}
+
+func TestServer_Integration_Pipelined(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	tmpDir, tmpCleanup := common.CreateTestDir(t)
+	defer tmpCleanup()
+	path := filepath.Join(tmpDir, "test.sock")
+
+	dss, _ := NewDomainSocketServer(context.Background(), log, path)
+	mod := &blockingModule{
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	dss.RegisterRPCModule(mod)
+
+	if err := dss.Start(); err != nil {
+		t.Fatalf("Couldn't start dRPC server: %v", err)
+	}
+	defer dss.Shutdown()
+
+	client := NewClientConnection(path)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	defer client.Close()
+
+	type result struct {
+		resp *Response
+		err  error
+	}
+	slowDone := make(chan result)
+	go func() {
+		resp, err := client.SendMsg(context.Background(), &Call{Module: mod.ID(), Method: 1})
+		slowDone <- result{resp, err}
+	}()
+	<-mod.started
+
+	// The slow call is still in flight on the same connection.
+	resp, err := client.SendMsg(context.Background(), &Call{Module: mod.ID(), Method: 2})
+	if err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+	common.AssertEqual(t, string(resp.GetBody()), "method 2", "fast call got wrong response")
+
+	close(mod.release)
+	slow := <-slowDone
+	if slow.err != nil {
+		t.Fatalf("failed to send message: %v", slow.err)
+	}
+	common.AssertEqual(t, string(slow.resp.GetBody()), "method 1", "slow call got wrong response")
+	common.AssertEqual(t, slow.resp.GetSequence(), int64(1), "slow call got wrong sequence")
+}
+
+// echoModule is a Module that responds with the body of the call
+type echoModule struct{}
+
+func (m *echoModule) HandleCall(session *Session, method int32, input []byte) ([]byte, error) {
+	return input, nil
+}
+
+func (m *echoModule) ID() int32 {
+	return 4321
+}
+
+func TestServer_Integration_LargeMessages(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	tmpDir, tmpCleanup := common.CreateTestDir(t)
+	defer tmpCleanup()
+	path := filepath.Join(tmpDir, "test.sock")
+
+	const maxSize = 8 * MaxMsgSize
+	dss, _ := NewDomainSocketServer(context.Background(), log, path)
+	dss.WithMaxMessageSize(maxSize)
+	mod := &echoModule{}
+	dss.RegisterRPCModule(mod)
+
+	if err := dss.Start(); err != nil {
+		t.Fatalf("Couldn't start dRPC server: %v", err)
+	}
+	defer dss.Shutdown()
+
+	client := NewClientConnection(path)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	defer client.Close()
+
+	body := testMessage(5*MaxMsgSize + 123)
+	resp, err := client.SendMsg(context.Background(), &Call{Module: mod.ID(), Body: body})
+	if err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+	common.AssertEqual(t, resp.GetStatus(), Status_SUCCESS, "bad status")
+	common.AssertTrue(t, bytes.Equal(resp.GetBody(), body), "response body differs from call body")
+
+	// The server drops the session when a call is over its limit.
+	_, err = client.SendMsg(context.Background(), &Call{Module: mod.ID(), Body: testMessage(maxSize)})
+	common.CmpErr(t, errors.New("dRPC recv"), err)
+}
+
+func TestServer_Integration_Timeout(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	tmpDir, tmpCleanup := common.CreateTestDir(t)
+	defer tmpCleanup()
+	path := filepath.Join(tmpDir, "test.sock")
+
+	dss, _ := NewDomainSocketServer(context.Background(), log, path)
+	mod := &blockingModule{
+		started: make(chan struct{}, 3),
+		release: make(chan struct{}),
+	}
+	dss.RegisterRPCModule(mod)
+
+	if err := dss.Start(); err != nil {
+		t.Fatalf("Couldn't start dRPC server: %v", err)
+	}
+	defer dss.Shutdown()
+	var releaseOnce sync.Once
+	release := func() { releaseOnce.Do(func() { close(mod.release) }) }
+	defer release()
+
+	client := NewClientConnection(path)
+	if err := client.Connect(); err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	defer client.Close()
+
+	type result struct {
+		resp *Response
+		err  error
+	}
+	slowDone := make(chan result)
+	go func() {
+		resp, err := client.SendMsg(context.Background(), &Call{Module: mod.ID(), Method: 1})
+		slowDone <- result{resp, err}
+	}()
+	<-mod.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := client.SendMsg(ctx, &Call{Module: mod.ID(), Method: 1})
+	if !FaultTimeout.Equals(err) {
+		t.Fatalf("expected timeout fault, got %v", err)
+	}
+	common.AssertTrue(t, client.IsConnected(), "connection should be kept after a timeout")
+
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		<-mod.started
+		<-mod.started
+		cancel()
+	}()
+	_, err = client.SendMsg(ctx, &Call{Module: mod.ID(), Method: 1})
+	common.CmpErr(t, context.Canceled, err)
+	common.AssertFalse(t, FaultTimeout.Equals(err), "cancellation is not a timeout")
+
+	// The calls given up on don't affect the others on the connection,
+	// and their late responses are discarded.
+	resp, err := client.SendMsg(context.Background(), &Call{Module: mod.ID(), Method: 2})
+	if err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+	common.AssertEqual(t, string(resp.GetBody()), "method 2", "call got wrong response")
+
+	release()
+	slow := <-slowDone
+	if slow.err != nil {
+		t.Fatalf("slow call failed: %v", slow.err)
+	}
+	common.AssertEqual(t, string(slow.resp.GetBody()), "method 1", "slow call got wrong response")
+
+	call := &Call{Module: mod.ID(), Method: 2}
+	resp, err = client.SendMsg(context.Background(), call)
+	if err != nil {
+		t.Fatalf("failed to send message: %v", err)
+	}
+	common.AssertEqual(t, string(resp.GetBody()), "method 2", "call got wrong response")
+	common.AssertEqual(t, resp.GetSequence(), call.GetSequence(), "call got another call's response")
+}
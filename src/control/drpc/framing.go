@@ -0,0 +1,230 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package drpc
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// Messages larger than MaxMsgSize are split into frames, each sent as one
+// packet made up of a frame header followed by part of the message. The
+// first byte of a frame is not a valid protobuf field tag (its wire type is
+// 7), so a receiver can tell frames from messages sent whole. The C
+// implementation in src/common/drpc.c uses the same format, and the two must
+// be changed together.
+//
+// Frame header layout (little endian):
+//	0: frameMagic
+//	1: frameVersion
+//	2: reserved (2 bytes)
+//	4: message ID, unique among the sender's messages in flight (4 bytes)
+//	8: total message length (4 bytes)
+//	12: offset of this frame's data in the message (4 bytes)
+const (
+	frameMagic      byte = 0xDF
+	frameVersion    byte = 1
+	frameHeaderSize      = 16
+	frameDataSize        = MaxMsgSize - frameHeaderSize
+
+	// DefaultMaxMessageSize is the largest message, split into frames or
+	// not, accepted by a receiver unless set otherwise.
+	DefaultMaxMessageSize = 64 * 1024 * 1024
+
+	// maxPartialMessages is the number of framed messages a receiver
+	// reassembles at once on a connection.
+	maxPartialMessages = 16
+)
+
+type frameHeader struct {
+	id     uint32
+	total  uint32
+	offset uint32
+}
+
+func (h *frameHeader) marshal(buf []byte) {
+	buf[0] = frameMagic
+	buf[1] = frameVersion
+	buf[2], buf[3] = 0, 0
+	binary.LittleEndian.PutUint32(buf[4:], h.id)
+	binary.LittleEndian.PutUint32(buf[8:], h.total)
+	binary.LittleEndian.PutUint32(buf[12:], h.offset)
+}
+
+func unmarshalFrameHeader(packet []byte) (*frameHeader, error) {
+	if len(packet) < frameHeaderSize {
+		return nil, errors.Errorf("dRPC frame too short (%d bytes)", len(packet))
+	}
+	if packet[1] != frameVersion {
+		return nil, errors.Errorf("unsupported dRPC frame version %d", packet[1])
+	}
+
+	return &frameHeader{
+		id:     binary.LittleEndian.Uint32(packet[4:]),
+		total:  binary.LittleEndian.Uint32(packet[8:]),
+		offset: binary.LittleEndian.Uint32(packet[12:]),
+	}, nil
+}
+
+// isFrame reports whether the packet is a frame rather than a whole message.
+func isFrame(packet []byte) bool {
+	return len(packet) > 0 && packet[0] == frameMagic
+}
+
+// messageWriter writes messages to a packet connection, splitting any that
+// don't fit in a single packet into frames.
+type messageWriter struct {
+	lastID uint32
+}
+
+// writeMessage writes the message to the connection. Concurrent calls must
+// be serialized by the caller.
+func (w *messageWriter) writeMessage(conn net.Conn, msg []byte) error {
+	if len(msg) <= MaxMsgSize {
+		_, err := conn.Write(msg)
+		return err
+	}
+
+	w.lastID++
+	hdr := frameHeader{
+		id:    w.lastID,
+		total: uint32(len(msg)),
+	}
+	packet := make([]byte, MaxMsgSize)
+	for offset := 0; offset < len(msg); offset += frameDataSize {
+		hdr.offset = uint32(offset)
+		hdr.marshal(packet)
+		n := copy(packet[frameHeaderSize:], msg[offset:])
+		if _, err := conn.Write(packet[:frameHeaderSize+n]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// partialMessage is a framed message still being received. Its buffer grows
+// as frames arrive rather than being allocated up front from the size
+// claimed by the sender.
+type partialMessage struct {
+	total int
+	data  []byte
+}
+
+// messageReader reads messages from a packet connection, reassembling any
+// that were split into frames. Frames of up to maxPartialMessages messages
+// may be interleaved, and the data buffered for them together may not exceed
+// the size limit. Only one goroutine may read at a time.
+type messageReader struct {
+	maxSize  int
+	partial  map[uint32]*partialMessage
+	buffered int
+}
+
+func newMessageReader(maxSize int) *messageReader {
+	return &messageReader{
+		maxSize: maxSize,
+		partial: make(map[uint32]*partialMessage),
+	}
+}
+
+// readMessage reads packets from the connection until a message is complete.
+func (r *messageReader) readMessage(conn net.Conn) ([]byte, error) {
+	for {
+		packet := make([]byte, MaxMsgSize)
+		n, err := conn.Read(packet)
+		if err != nil {
+			return nil, err
+		}
+
+		msg, err := r.addPacket(packet[:n])
+		if err != nil || msg != nil {
+			return msg, err
+		}
+	}
+}
+
+// addPacket adds a packet read from the connection, returning the message
+// if it is now complete.
+func (r *messageReader) addPacket(packet []byte) ([]byte, error) {
+	if !isFrame(packet) {
+		if len(packet) > r.maxSize {
+			return nil, errors.Errorf("dRPC message size %d exceeds limit %d",
+				len(packet), r.maxSize)
+		}
+		return packet, nil
+	}
+
+	hdr, err := unmarshalFrameHeader(packet)
+	if err != nil {
+		return nil, err
+	}
+	data := packet[frameHeaderSize:]
+
+	msg, found := r.partial[hdr.id]
+	if !found {
+		if hdr.total > uint32(r.maxSize) {
+			return nil, errors.Errorf("dRPC message size %d exceeds limit %d",
+				hdr.total, r.maxSize)
+		}
+		if len(r.partial) >= maxPartialMessages {
+			return nil, errors.Errorf("too many incomplete dRPC messages (limit %d)",
+				maxPartialMessages)
+		}
+		msg = &partialMessage{total: int(hdr.total)}
+		r.partial[hdr.id] = msg
+	}
+
+	// Packets on the connection arrive in the order they were sent, so
+	// each frame must continue where the last one left off.
+	if int(hdr.total) != msg.total || int(hdr.offset) != len(msg.data) ||
+		len(msg.data)+len(data) > msg.total || len(data) == 0 {
+		r.discard(hdr.id)
+		return nil, errors.Errorf("dRPC frame for message %d out of sequence (offset %d, %d bytes, %d of %d received)",
+			hdr.id, hdr.offset, len(data), len(msg.data), msg.total)
+	}
+	if r.buffered+len(data) > r.maxSize {
+		r.discard(hdr.id)
+		return nil, errors.Errorf("incomplete dRPC messages exceed limit %d",
+			r.maxSize)
+	}
+	msg.data = append(msg.data, data...)
+	r.buffered += len(data)
+
+	if len(msg.data) < msg.total {
+		return nil, nil
+	}
+	r.discard(hdr.id)
+
+	return msg.data, nil
+}
+
+// discard stops reassembling the message with the given ID.
+func (r *messageReader) discard(id uint32) {
+	if msg, found := r.partial[id]; found {
+		r.buffered -= len(msg.data)
+		delete(r.partial, id)
+	}
+}
@@ -0,0 +1,253 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package drpc
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+// packetConn is a net.Conn that keeps the packets written to it and returns
+// them in order when read.
+type packetConn struct {
+	net.Conn
+	packets [][]byte
+}
+
+func (c *packetConn) Write(b []byte) (int, error) {
+	c.packets = append(c.packets, append([]byte{}, b...))
+	return len(b), nil
+}
+
+func (c *packetConn) Read(b []byte) (int, error) {
+	if len(c.packets) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(b, c.packets[0])
+	c.packets = c.packets[1:]
+	return n, nil
+}
+
+func testMessage(size int) []byte {
+	msg := make([]byte, size)
+	for i := range msg {
+		msg[i] = byte(i % 251)
+	}
+	return msg
+}
+
+func testFrame(id, total, offset uint32, data []byte) []byte {
+	packet := make([]byte, frameHeaderSize+len(data))
+	(&frameHeader{id: id, total: total, offset: offset}).marshal(packet)
+	copy(packet[frameHeaderSize:], data)
+	return packet
+}
+
+func TestFraming_RoundTrip(t *testing.T) {
+	for name, tc := range map[string]struct {
+		size       int
+		expPackets int
+	}{
+		"small": {
+			size:       100,
+			expPackets: 1,
+		},
+		"one full packet": {
+			size:       MaxMsgSize,
+			expPackets: 1,
+		},
+		"just over one packet": {
+			size:       MaxMsgSize + 1,
+			expPackets: 2,
+		},
+		"several frames": {
+			size:       3*frameDataSize + 10,
+			expPackets: 4,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conn := &packetConn{}
+			msg := testMessage(tc.size)
+
+			var w messageWriter
+			if err := w.writeMessage(conn, msg); err != nil {
+				t.Fatal(err)
+			}
+			common.AssertEqual(t, len(conn.packets), tc.expPackets, "wrong number of packets")
+			for _, packet := range conn.packets {
+				common.AssertTrue(t, len(packet) <= MaxMsgSize, "packet too large")
+			}
+
+			got, err := newMessageReader(DefaultMaxMessageSize).readMessage(conn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, msg) {
+				t.Fatal("reassembled message differs from original")
+			}
+		})
+	}
+}
+
+func TestFraming_AddPacket(t *testing.T) {
+	msgA := testMessage(30)
+	msgB := testMessage(20)
+
+	for name, tc := range map[string]struct {
+		maxSize    int
+		packets    [][]byte
+		expMsgs    [][]byte
+		expErr     error
+		expPartial int // incomplete messages left
+	}{
+		"unframed message": {
+			packets: [][]byte{msgA},
+			expMsgs: [][]byte{msgA},
+		},
+		"partial message": {
+			packets: [][]byte{
+				testFrame(1, 30, 0, msgA[:10]),
+				testFrame(1, 30, 10, msgA[10:20]),
+			},
+			expMsgs:    [][]byte{nil, nil},
+			expPartial: 1,
+		},
+		"complete message": {
+			packets: [][]byte{
+				testFrame(1, 30, 0, msgA[:10]),
+				testFrame(1, 30, 10, msgA[10:]),
+			},
+			expMsgs: [][]byte{nil, msgA},
+		},
+		"interleaved messages": {
+			packets: [][]byte{
+				testFrame(1, 30, 0, msgA[:10]),
+				testFrame(2, 20, 0, msgB[:15]),
+				msgB,
+				testFrame(1, 30, 10, msgA[10:]),
+				testFrame(2, 20, 15, msgB[15:]),
+			},
+			expMsgs: [][]byte{nil, nil, msgB, msgA, msgB},
+		},
+		"truncated header": {
+			packets: [][]byte{testFrame(1, 30, 0, nil)[:8]},
+			expErr:  errors.New("dRPC frame too short (8 bytes)"),
+		},
+		"bad version": {
+			packets: [][]byte{append([]byte{frameMagic, 2}, make([]byte, 20)...)},
+			expErr:  errors.New("unsupported dRPC frame version 2"),
+		},
+		"missing frame": {
+			packets: [][]byte{
+				testFrame(1, 30, 0, msgA[:10]),
+				testFrame(1, 30, 20, msgA[20:]),
+			},
+			expMsgs: [][]byte{nil},
+			expErr:  errors.New("dRPC frame for message 1 out of sequence (offset 20, 10 bytes, 10 of 30 received)"),
+		},
+		"frame past end of message": {
+			packets: [][]byte{
+				testFrame(1, 30, 0, msgA[:10]),
+				testFrame(1, 30, 10, testMessage(25)),
+			},
+			expMsgs: [][]byte{nil},
+			expErr:  errors.New("dRPC frame for message 1 out of sequence (offset 10, 25 bytes, 10 of 30 received)"),
+		},
+		"total changed": {
+			packets: [][]byte{
+				testFrame(1, 30, 0, msgA[:10]),
+				testFrame(1, 40, 10, msgA[10:]),
+			},
+			expMsgs: [][]byte{nil},
+			expErr:  errors.New("out of sequence"),
+		},
+		"framed message too large": {
+			maxSize: 25,
+			packets: [][]byte{testFrame(1, 30, 0, msgA[:10])},
+			expErr:  errors.New("dRPC message size 30 exceeds limit 25"),
+		},
+		"incomplete messages too large": {
+			maxSize: 25,
+			packets: [][]byte{
+				testFrame(1, 20, 0, msgB[:10]),
+				testFrame(2, 20, 0, msgB[:10]),
+				testFrame(3, 20, 0, msgB[:10]),
+			},
+			expMsgs:    [][]byte{nil, nil},
+			expErr:     errors.New("incomplete dRPC messages exceed limit 25"),
+			expPartial: 2,
+		},
+		"too many incomplete messages": {
+			packets: func() [][]byte {
+				var packets [][]byte
+				for id := uint32(1); id <= maxPartialMessages+1; id++ {
+					packets = append(packets, testFrame(id, 20, 0, msgB[:10]))
+				}
+				return packets
+			}(),
+			expMsgs:    make([][]byte, maxPartialMessages),
+			expErr:     errors.New("too many incomplete dRPC messages (limit 16)"),
+			expPartial: maxPartialMessages,
+		},
+		"unframed message too large": {
+			maxSize: 25,
+			packets: [][]byte{msgA},
+			expErr:  errors.New("dRPC message size 30 exceeds limit 25"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if tc.maxSize == 0 {
+				tc.maxSize = DefaultMaxMessageSize
+			}
+			r := newMessageReader(tc.maxSize)
+
+			var gotMsgs [][]byte
+			var err error
+			for _, packet := range tc.packets {
+				var msg []byte
+				if msg, err = r.addPacket(packet); err != nil {
+					break
+				}
+				gotMsgs = append(gotMsgs, msg)
+			}
+
+			common.CmpErr(t, tc.expErr, err)
+			if diff := cmp.Diff(tc.expMsgs, gotMsgs); diff != "" {
+				t.Fatalf("unexpected messages (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, len(r.partial), tc.expPartial, "wrong number of incomplete messages")
+			var buffered int
+			for _, msg := range r.partial {
+				buffered += len(msg.data)
+			}
+			common.AssertEqual(t, r.buffered, buffered, "buffered size out of step")
+		})
+	}
+}
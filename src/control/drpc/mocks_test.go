@@ -46,6 +46,12 @@ func (m *mockModule) ID() int32 {
 	return m.IDValue
 }
 
+// mockRestrictedModule is a mock of the PeerRestrictedModule interface
+type mockRestrictedModule struct {
+	mockModule
+	PeerAllowList
+}
+
 func newTestModule(ID int32) *mockModule {
 	return &mockModule{
 		IDValue: ID,
@@ -65,9 +71,13 @@ type mockConn struct {
 	WriteInputBytes     []byte
 	CloseCallCount      int // Number of times called
 	CloseOutputError    error
+	ReadBlock           chan struct{} // Read blocks until closed, if set
 }
 
 func (m *mockConn) Read(b []byte) (n int, err error) {
+	if m.ReadBlock != nil {
+		<-m.ReadBlock
+	}
 	m.ReadCallCount++
 	m.ReadInputBytes = b
 	copy(b, m.ReadOutputBytes)
@@ -137,6 +147,7 @@ type mockListener struct {
 	acceptCallCount int
 	closeErr        error
 	closeCallCount  int
+	connReadBlock   chan struct{} // passed to accepted connections
 }
 
 func (l *mockListener) Accept() (net.Conn, error) {
@@ -144,7 +155,9 @@ func (l *mockListener) Accept() (net.Conn, error) {
 	if l.acceptCallCount > l.acceptNumConns {
 		return nil, l.acceptErr
 	}
-	return newMockConn(), nil
+	conn := newMockConn()
+	conn.ReadBlock = l.connReadBlock
+	return conn, nil
 }
 
 func (l *mockListener) Close() error {
@@ -23,6 +23,8 @@
 package drpc
 
 import (
+	"time"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 
@@ -39,8 +41,9 @@ type Module interface {
 // ModuleService is the collection of Modules used by
 // DomainSocketServer to be used to process messages.
 type ModuleService struct {
-	log     logging.Logger
-	modules map[int32]Module
+	log      logging.Logger
+	modules  map[int32]Module
+	recorder *Recorder
 }
 
 // NewModuleService creates an initialized ModuleService instance
@@ -52,6 +55,13 @@ func NewModuleService(log logging.Logger) *ModuleService {
 	}
 }
 
+// WithRecorder records every call processed by the service and its
+// response.
+func (r *ModuleService) WithRecorder(rec *Recorder) *ModuleService {
+	r.recorder = rec
+	return r
+}
+
 // RegisterModule will take in a type that implements the Module interface
 // and ensure that no other module is already registered with that module
 // identifier.
@@ -104,26 +114,72 @@ func marshalResponse(sequence int64, status Status, body []byte) ([]byte, error)
 	return responseBytes, nil
 }
 
+// checkPeer returns a Failure if the module only accepts calls from some
+// peers and the session's peer is not one of them.
+func (r *ModuleService) checkPeer(session *Session, module Module) error {
+	restricted, ok := module.(PeerRestrictedModule)
+	if !ok {
+		return nil
+	}
+	allowed := restricted.AllowedPeers()
+	if allowed.empty() {
+		return nil
+	}
+
+	info, err := session.PeerInfo(r.log)
+	if err != nil {
+		r.log.Errorf("unable to get peer credentials: %s", err)
+		return UnauthorizedPeerFailure(module.ID(), nil)
+	}
+	if !allowed.Allows(info) {
+		return UnauthorizedPeerFailure(module.ID(), info)
+	}
+
+	return nil
+}
+
 // ProcessMessage is the main entry point into the ModuleService. It accepts a
 // marshaled drpc.Call instance, processes it, calls the handler in the
 // appropriate Module, and marshals the result into the body of a drpc.Response.
 func (r *ModuleService) ProcessMessage(session *Session, msgBytes []byte) ([]byte, error) {
+	started := time.Now()
 	msg := &Call{}
 
 	err := proto.Unmarshal(msgBytes, msg)
 	if err != nil {
 		return marshalResponse(-1, Status_FAILED_UNMARSHAL_CALL, nil)
 	}
+
+	status, respBody := r.handleCall(session, msg)
+	if r.recorder != nil {
+		resp := &Response{Sequence: msg.GetSequence(), Status: status}
+		if status == Status_SUCCESS {
+			resp.Body = respBody
+		}
+		if err := r.recorder.Record(msg, resp, started, nil); err != nil {
+			r.log.Errorf("failed to record dRPC call: %s", err)
+		}
+	}
+
+	return marshalResponse(msg.GetSequence(), status, respBody)
+}
+
+// handleCall passes the call to the module it is addressed to, returning
+// the status and body of the response.
+func (r *ModuleService) handleCall(session *Session, msg *Call) (Status, []byte) {
 	module, ok := r.GetModule(msg.GetModule())
 	if !ok {
-		err = errors.Errorf("Attempted to call unregistered module")
-		return marshalResponse(msg.GetSequence(), Status_UNKNOWN_MODULE, nil)
+		return Status_UNKNOWN_MODULE, nil
+	}
+	if err := r.checkPeer(session, module); err != nil {
+		r.log.Errorf("rejected call to %d:%d: %s", module.ID(), msg.GetMethod(), err)
+		return ErrorToStatus(err), nil
 	}
 	respBody, err := module.HandleCall(session, msg.GetMethod(), msg.GetBody())
 	if err != nil {
 		r.log.Errorf("HandleCall for %d:%d failed: %s\n", module.ID(), msg.GetMethod(), err)
-		return marshalResponse(msg.GetSequence(), ErrorToStatus(err), nil)
+		return ErrorToStatus(err), nil
 	}
 
-	return marshalResponse(msg.GetSequence(), Status_SUCCESS, respBody)
+	return Status_SUCCESS, respBody
 }
@@ -172,6 +172,7 @@ func TestService_ProcessMessage(t *testing.T) {
 
 	for name, tc := range map[string]struct {
 		callBytes      []byte
+		allowedPeers   *PeerAllowList
 		handleCallErr  error
 		handleCallResp []byte
 		expectedResp   *Response
@@ -199,6 +200,18 @@ func TestService_ProcessMessage(t *testing.T) {
 			handleCallResp: []byte("succeeded"),
 			expectedResp:   getResponse(testSequenceNum, Status_SUCCESS, []byte("succeeded")),
 		},
+		"empty peer allow list": {
+			callBytes:      getCallBytes(t, testSequenceNum, defaultTestModID),
+			allowedPeers:   &PeerAllowList{},
+			handleCallResp: []byte("succeeded"),
+			expectedResp:   getResponse(testSequenceNum, Status_SUCCESS, []byte("succeeded")),
+		},
+		"peer credentials unavailable": {
+			callBytes:      getCallBytes(t, testSequenceNum, defaultTestModID),
+			allowedPeers:   &PeerAllowList{UIDs: []uint32{0}},
+			handleCallResp: []byte("succeeded"),
+			expectedResp:   getResponse(testSequenceNum, Status_FAILURE, nil),
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			log, buf := logging.NewTestLogger(t.Name())
@@ -209,9 +222,16 @@ func TestService_ProcessMessage(t *testing.T) {
 			mockMod.HandleCallResponse = tc.handleCallResp
 
 			service := NewModuleService(log)
-			service.RegisterModule(mockMod)
+			if tc.allowedPeers != nil {
+				service.RegisterModule(&mockRestrictedModule{
+					mockModule:    *mockMod,
+					PeerAllowList: *tc.allowedPeers,
+				})
+			} else {
+				service.RegisterModule(mockMod)
+			}
 
-			respBytes, err := service.ProcessMessage(&Session{}, tc.callBytes)
+			respBytes, err := service.ProcessMessage(&Session{Conn: &mockConn{}}, tc.callBytes)
 
 			if err != nil {
 				t.Fatalf("expected nil error, got: %v", err)
@@ -96,6 +96,9 @@ const (
 	MethodPoolQuery = C.DRPC_METHOD_MGMT_POOL_QUERY
 	// MethodPoolSetProp defines a method for setting a pool property
 	MethodPoolSetProp = C.DRPC_METHOD_MGMT_POOL_SET_PROP
+	// MethodSetLogMasks defines a method for changing the log masks
+	// of a running I/O server
+	MethodSetLogMasks = C.DRPC_METHOD_MGMT_SET_LOG_MASKS
 )
 
 const (
@@ -0,0 +1,88 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package drpc
+
+import (
+	"fmt"
+
+	"github.com/daos-stack/daos/src/control/security"
+)
+
+// PeerAllowList restricts the local peers that may call a Module to those
+// running with one of the listed uids or gids. An empty list allows any peer.
+// Embedding a PeerAllowList in a Module makes it a PeerRestrictedModule.
+type PeerAllowList struct {
+	UIDs []uint32
+	GIDs []uint32
+}
+
+// AllowedPeers returns the allow list itself.
+func (l *PeerAllowList) AllowedPeers() *PeerAllowList {
+	return l
+}
+
+func (l *PeerAllowList) empty() bool {
+	return l == nil || (len(l.UIDs) == 0 && len(l.GIDs) == 0)
+}
+
+// Allows reports whether the peer with the given credentials may make calls.
+func (l *PeerAllowList) Allows(info *security.DomainInfo) bool {
+	if l.empty() {
+		return true
+	}
+	if info == nil {
+		return false
+	}
+
+	for _, uid := range l.UIDs {
+		if uid == info.Uid() {
+			return true
+		}
+	}
+	for _, gid := range l.GIDs {
+		if gid == info.Gid() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PeerRestrictedModule is a Module that only accepts calls from some local
+// peers.
+type PeerRestrictedModule interface {
+	Module
+	AllowedPeers() *PeerAllowList
+}
+
+// UnauthorizedPeerFailure creates a Failure for a call from a peer that is
+// not allowed to use the module.
+func UnauthorizedPeerFailure(module int32, info *security.DomainInfo) Failure {
+	peer := "unknown peer"
+	if info != nil {
+		peer = fmt.Sprintf("peer with uid %d gid %d", info.Uid(), info.Gid())
+	}
+
+	return NewFailureWithMessage(fmt.Sprintf("%s is not allowed to call module %d",
+		peer, module))
+}
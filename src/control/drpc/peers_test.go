@@ -0,0 +1,81 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package drpc
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/security"
+)
+
+func TestPeerAllowList_Allows(t *testing.T) {
+	peer := security.InitDomainInfo(&syscall.Ucred{Uid: 1000, Gid: 100}, "")
+
+	for name, tc := range map[string]struct {
+		list   *PeerAllowList
+		info   *security.DomainInfo
+		expRes bool
+	}{
+		"nil list": {
+			info:   peer,
+			expRes: true,
+		},
+		"empty list": {
+			list:   &PeerAllowList{},
+			info:   peer,
+			expRes: true,
+		},
+		"uid allowed": {
+			list:   &PeerAllowList{UIDs: []uint32{0, 1000}},
+			info:   peer,
+			expRes: true,
+		},
+		"gid allowed": {
+			list:   &PeerAllowList{UIDs: []uint32{0}, GIDs: []uint32{100}},
+			info:   peer,
+			expRes: true,
+		},
+		"not allowed": {
+			list: &PeerAllowList{UIDs: []uint32{0}, GIDs: []uint32{0}},
+			info: peer,
+		},
+		"unknown peer": {
+			list: &PeerAllowList{UIDs: []uint32{1000}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			common.AssertEqual(t, tc.list.Allows(tc.info), tc.expRes, "")
+		})
+	}
+}
+
+func TestUnauthorizedPeerFailure(t *testing.T) {
+	peer := security.InitDomainInfo(&syscall.Ucred{Uid: 1000, Gid: 100}, "")
+
+	common.CmpErr(t, NewFailureWithMessage("peer with uid 1000 gid 100 is not allowed to call module 2"),
+		UnauthorizedPeerFailure(2, peer))
+	common.CmpErr(t, NewFailureWithMessage("unknown peer is not allowed to call module 2"),
+		UnauthorizedPeerFailure(2, nil))
+}
@@ -0,0 +1,130 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package drpc
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+// ReplayModule is a Module that answers calls with the responses recorded
+// in a capture rather than handling them. Calls to each method are answered
+// with the responses recorded for that method, in the order they were
+// recorded; the bodies of the calls are not checked.
+type ReplayModule struct {
+	sync.Mutex
+	id        int32
+	responses map[int32][]*CaptureRecord
+}
+
+// NewReplayModules returns a ReplayModule for each module called in the
+// records. Calls that failed without a response, e.g. because they timed
+// out, are skipped.
+func NewReplayModules(records []*CaptureRecord) []*ReplayModule {
+	byID := make(map[int32]*ReplayModule)
+	for _, rec := range records {
+		if rec.Error != "" {
+			continue
+		}
+
+		mod, found := byID[rec.Module]
+		if !found {
+			mod = &ReplayModule{
+				id:        rec.Module,
+				responses: make(map[int32][]*CaptureRecord),
+			}
+			byID[rec.Module] = mod
+		}
+		mod.responses[rec.Method] = append(mod.responses[rec.Method], rec)
+	}
+
+	mods := make([]*ReplayModule, 0, len(byID))
+	for _, mod := range byID {
+		mods = append(mods, mod)
+	}
+	sort.Slice(mods, func(i, j int) bool { return mods[i].id < mods[j].id })
+
+	return mods
+}
+
+// ID returns the ID of the replayed module.
+func (m *ReplayModule) ID() int32 {
+	return m.id
+}
+
+// HandleCall returns the next recorded response for the method. A recorded
+// failure status is returned as a Failure so that the response carries the
+// same status.
+func (m *ReplayModule) HandleCall(session *Session, method int32, input []byte) ([]byte, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	recs := m.responses[method]
+	if len(recs) == 0 {
+		return nil, errors.Errorf("no recorded response left for module %d method %d",
+			m.id, method)
+	}
+	rec := recs[0]
+	m.responses[method] = recs[1:]
+
+	if rec.Status != Status_SUCCESS {
+		return nil, NewFailure(rec.Status)
+	}
+
+	return rec.Response, nil
+}
+
+// Remaining returns the number of recorded responses not yet replayed.
+func (m *ReplayModule) Remaining() int {
+	m.Lock()
+	defer m.Unlock()
+
+	var count int
+	for _, recs := range m.responses {
+		count += len(recs)
+	}
+
+	return count
+}
+
+// NewReplayServer returns a DomainSocketServer listening on sock that
+// answers calls with the responses in the records, for use in place of a
+// running I/O server. The server must be started by the caller.
+func NewReplayServer(ctx context.Context, log logging.Logger, sock string, records []*CaptureRecord) (*DomainSocketServer, []*ReplayModule, error) {
+	dss, err := NewDomainSocketServer(ctx, log, sock)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mods := NewReplayModules(records)
+	for _, mod := range mods {
+		dss.RegisterRPCModule(mod)
+	}
+
+	return dss, mods, nil
+}
@@ -55,7 +55,14 @@ const (
 	SystemMemberExists
 	SystemMemberMissing
 	SystemMemberChanged
+	SystemMemberExcluded
+	SystemMemberNotExcluded
 
 	// security fault codes
 	SecurityUnknown Code = iota + 900
+	SecurityMethodUnauthorized
+
+	// dRPC fault codes
+	DrpcUnknown Code = iota + 500
+	DrpcTimeout
 )
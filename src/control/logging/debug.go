@@ -23,10 +23,8 @@
 package logging
 
 import (
-	"fmt"
 	"io"
 	"log"
-	"os"
 )
 
 const debugLogFlags = log.Lmicroseconds | log.Lshortfile
@@ -49,14 +47,17 @@ type DefaultDebugLogger struct {
 
 // Debugf emits a formatted debug message.
 func (l *DefaultDebugLogger) Debugf(format string, args ...interface{}) {
-	depth := logOutputDepth
+	l.emit(debugDepth(args), nil, format, args...)
+}
+
+func (l *DefaultDebugLogger) debugfWithFields(fields []field, format string, args ...interface{}) {
+	l.emit(debugDepth(args), fields, format, args...)
+}
+
+func debugDepth(args []interface{}) int {
 	if len(args) == 0 {
 		// Adjust for the extra call to Debug()
-		depth += 1
-	}
-
-	out := fmt.Sprintf(format, args...)
-	if err := l.log.Output(depth, out); err != nil {
-		fmt.Fprintf(os.Stderr, "logger Debugf() failed: %s\n", err)
+		return logOutputDepth + 1
 	}
+	return logOutputDepth
 }
@@ -40,15 +40,20 @@ const (
 // to include timestamps and filenames.
 func NewCommandLineLogger() *LeveledLogger {
 	return &LeveledLogger{
-		level: DefaultLogLevel,
-		debugLoggers: []DebugLogger{
-			NewDebugLogger(os.Stdout),
-		},
-		infoLoggers: []InfoLogger{
-			NewCommandLineInfoLogger(os.Stdout),
-		},
-		errorLoggers: []ErrorLogger{
-			NewCommandLineErrorLogger(os.Stderr),
+		loggerCore: &loggerCore{
+			level: DefaultLogLevel,
+			debugLoggers: []DebugLogger{
+				NewDebugLogger(os.Stdout),
+			},
+			infoLoggers: []InfoLogger{
+				NewCommandLineInfoLogger(os.Stdout),
+			},
+			warnLoggers: []WarnLogger{
+				NewCommandLineWarnLogger(os.Stderr),
+			},
+			errorLoggers: []ErrorLogger{
+				NewCommandLineErrorLogger(os.Stderr),
+			},
 		},
 	}
 }
@@ -64,15 +69,20 @@ func NewStdoutLogger(prefix string) *LeveledLogger {
 // to send all output to the supplied io.Writer.
 func NewCombinedLogger(prefix string, output io.Writer) *LeveledLogger {
 	return &LeveledLogger{
-		level: DefaultLogLevel,
-		debugLoggers: []DebugLogger{
-			NewDebugLogger(output),
-		},
-		infoLoggers: []InfoLogger{
-			NewInfoLogger(prefix, output),
-		},
-		errorLoggers: []ErrorLogger{
-			NewErrorLogger(prefix, output),
+		loggerCore: &loggerCore{
+			level: DefaultLogLevel,
+			debugLoggers: []DebugLogger{
+				NewDebugLogger(output),
+			},
+			infoLoggers: []InfoLogger{
+				NewInfoLogger(prefix, output),
+			},
+			warnLoggers: []WarnLogger{
+				NewWarnLogger(prefix, output),
+			},
+			errorLoggers: []ErrorLogger{
+				NewErrorLogger(prefix, output),
+			},
 		},
 	}
 }
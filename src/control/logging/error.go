@@ -23,10 +23,8 @@
 package logging
 
 import (
-	"fmt"
 	"io"
 	"log"
-	"os"
 )
 
 const errorLogFlags = log.LstdFlags
@@ -66,8 +64,9 @@ type DefaultErrorLogger struct {
 
 // Errorf emits a formatted error message.
 func (l *DefaultErrorLogger) Errorf(format string, args ...interface{}) {
-	out := fmt.Sprintf(format, args...)
-	if err := l.log.Output(logOutputDepth, out); err != nil {
-		fmt.Fprintf(os.Stderr, "logger Errorf() failed: %s\n", err)
-	}
+	l.emit(logOutputDepth, nil, format, args...)
+}
+
+func (l *DefaultErrorLogger) errorfWithFields(fields []field, format string, args ...interface{}) {
+	l.emit(logOutputDepth, fields, format, args...)
 }
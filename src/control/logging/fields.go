@@ -0,0 +1,123 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package logging
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// subsystemKey is the field identifying the subsystem a message
+	// was logged by.
+	subsystemKey = "subsystem"
+	// missingValue is used for a key supplied without a value.
+	missingValue = "MISSING"
+)
+
+type (
+	// field is a key/value pair attached to log messages.
+	field struct {
+		key   string
+		value interface{}
+	}
+
+	// fieldsOutputter is implemented by Outputters which can emit
+	// fields in a structured form.
+	fieldsOutputter interface {
+		outputFields(callDepth int, fields []field, msg string) error
+	}
+
+	fieldsDebug interface {
+		debugfWithFields(fields []field, format string, args ...interface{})
+	}
+	fieldsInfo interface {
+		infofWithFields(fields []field, format string, args ...interface{})
+	}
+	fieldsWarn interface {
+		warnfWithFields(fields []field, format string, args ...interface{})
+	}
+	fieldsError interface {
+		errorfWithFields(fields []field, format string, args ...interface{})
+	}
+)
+
+// newFields appends the alternating keys and values to the existing
+// fields, replacing any existing values for the same keys.
+func newFields(existing []field, keyvals ...interface{}) []field {
+	fields := make([]field, len(existing), len(existing)+(len(keyvals)+1)/2)
+	copy(fields, existing)
+
+	for i := 0; i < len(keyvals); i += 2 {
+		f := field{key: fmt.Sprint(keyvals[i]), value: missingValue}
+		if i+1 < len(keyvals) {
+			f.value = keyvals[i+1]
+		}
+
+		replaced := false
+		for j := range fields {
+			if fields[j].key == f.key {
+				fields[j] = f
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			fields = append(fields, f)
+		}
+	}
+
+	return fields
+}
+
+// formatFields returns the fields as key=value pairs to be appended to
+// a text message.
+func formatFields(fields []field) string {
+	var sb strings.Builder
+	for _, f := range fields {
+		val := fmt.Sprint(f.value)
+		if val == "" || strings.ContainsAny(val, " =\"") {
+			val = strconv.Quote(val)
+		}
+		fmt.Fprintf(&sb, " %s=%s", f.key, val)
+	}
+	return sb.String()
+}
+
+// jsonFieldValue returns the value of the field in a form which will be
+// encoded usefully as JSON.
+func jsonFieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Marshaler, encoding.TextMarshaler:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
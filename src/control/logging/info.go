@@ -23,10 +23,8 @@
 package logging
 
 import (
-	"fmt"
 	"io"
 	"log"
-	"os"
 )
 
 const infoLogFlags = log.LstdFlags
@@ -68,8 +66,9 @@ type DefaultInfoLogger struct {
 
 // Infof emits a formatted informational message.
 func (l *DefaultInfoLogger) Infof(format string, args ...interface{}) {
-	out := fmt.Sprintf(format, args...)
-	if err := l.log.Output(logOutputDepth, out); err != nil {
-		fmt.Fprintf(os.Stderr, "logger Infof() failed: %s\n", err)
-	}
+	l.emit(logOutputDepth, nil, format, args...)
+}
+
+func (l *DefaultInfoLogger) infofWithFields(fields []field, format string, args ...interface{}) {
+	l.emit(logOutputDepth, fields, format, args...)
 }
@@ -23,7 +23,9 @@
 package logging
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"runtime"
@@ -69,6 +71,13 @@ func formatJSONTime(t time.Time, flags int) string {
 // Output emulates log.Logger's Output(), but formats
 // the message as a JSON-structured log entry.
 func (f *JSONFormatter) Output(callDepth int, msg string) error {
+	return f.outputFields(callDepth+1, nil, msg)
+}
+
+// outputFields formats the message as a JSON-structured log entry
+// with each of the fields as an additional key. Fields which would
+// replace one of the standard keys are prefixed with "field_".
+func (f *JSONFormatter) outputFields(callDepth int, fields []field, msg string) error {
 	now := time.Now()
 	var file string
 	var line int
@@ -93,6 +102,12 @@ func (f *JSONFormatter) Output(callDepth int, msg string) error {
 		return err
 	}
 
+	if len(fields) > 0 {
+		if buf, err = appendJSONFields(buf, fields); err != nil {
+			return err
+		}
+	}
+
 	if _, err := f.output.Write(buf); err != nil {
 		return err
 	}
@@ -100,6 +115,39 @@ func (f *JSONFormatter) Output(callDepth int, msg string) error {
 	return err
 }
 
+// appendJSONFields adds the fields as keys to the encoded JSON object.
+func appendJSONFields(obj []byte, fields []field) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Write(obj[:len(obj)-1])
+
+	for _, f := range fields {
+		key := f.key
+		switch key {
+		case "level", "time", "extra", "source", "message":
+			key = "field_" + key
+		}
+		encKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		encVal, err := json.Marshal(jsonFieldValue(f.value))
+		if err != nil {
+			// Fall back to the text representation.
+			if encVal, err = json.Marshal(fmt.Sprint(f.value)); err != nil {
+				return nil, err
+			}
+		}
+
+		buf.WriteByte(',')
+		buf.Write(encKey)
+		buf.WriteByte(':')
+		buf.Write(encVal)
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
 // NewJSONFormatter returns a *JSONFormatter configured to
 // emit JSON-formatted output.
 func NewJSONFormatter(output io.Writer, level, extraData string, flags int) *JSONFormatter {
@@ -118,6 +166,9 @@ type (
 	jsonInfo interface {
 		WithJSONOutput() InfoLogger
 	}
+	jsonWarn interface {
+		WithJSONOutput() WarnLogger
+	}
 	jsonError interface {
 		WithJSONOutput() ErrorLogger
 	}
@@ -131,6 +182,7 @@ func (ll *LeveledLogger) WithJSONOutput() *LeveledLogger {
 
 	var debugLoggers []DebugLogger
 	var infoLoggers []InfoLogger
+	var warnLoggers []WarnLogger
 	var errorLoggers []ErrorLogger
 
 	for _, l := range ll.debugLoggers {
@@ -151,6 +203,15 @@ func (ll *LeveledLogger) WithJSONOutput() *LeveledLogger {
 	}
 	ll.infoLoggers = infoLoggers
 
+	for _, l := range ll.warnLoggers {
+		if jsonLogger, ok := l.(jsonWarn); ok {
+			if wl, ok := jsonLogger.WithJSONOutput().(WarnLogger); ok {
+				warnLoggers = append(warnLoggers, wl)
+			}
+		}
+	}
+	ll.warnLoggers = warnLoggers
+
 	for _, l := range ll.errorLoggers {
 		if jsonLogger, ok := l.(jsonError); ok {
 			if el, ok := jsonLogger.WithJSONOutput().(ErrorLogger); ok {
@@ -175,6 +236,18 @@ func (l *DefaultErrorLogger) WithJSONOutput() ErrorLogger {
 	}
 }
 
+// WithJSONOutput switches the logger's output to use structured
+// JSON formatting.
+func (l *DefaultWarnLogger) WithJSONOutput() WarnLogger {
+	return &DefaultWarnLogger{
+		baseLogger{
+			dest:   l.dest,
+			prefix: l.prefix,
+			log:    NewJSONFormatter(l.dest, "WARN", l.prefix, warnLogFlags),
+		},
+	}
+}
+
 // WithJSONOutput switches the logger's output to use structured
 // JSON formatting.
 func (l *DefaultInfoLogger) WithJSONOutput() InfoLogger {
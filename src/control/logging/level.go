@@ -24,6 +24,7 @@ package logging
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync/atomic"
 )
@@ -33,6 +34,8 @@ const (
 	LogLevelDisabled LogLevel = iota
 	// LogLevelError emits messages at ERROR or higher
 	LogLevelError
+	// LogLevelWarn emits messages at WARN or higher
+	LogLevelWarn
 	// LogLevelInfo emits messages at INFO or higher
 	LogLevelInfo
 	// LogLevelDebug emits messages at DEBUG or higher
@@ -40,6 +43,7 @@ const (
 
 	strDisabled = "DISABLED"
 	strError    = "ERROR"
+	strWarn     = "WARN"
 	strInfo     = "INFO"
 	strDebug    = "DEBUG"
 )
@@ -66,6 +70,8 @@ func (ll *LogLevel) SetString(in string) error {
 		level = LogLevelDisabled
 	case strings.EqualFold(in, strError):
 		level = LogLevelError
+	case strings.EqualFold(in, strWarn):
+		level = LogLevelWarn
 	case strings.EqualFold(in, strInfo):
 		level = LogLevelInfo
 	case strings.EqualFold(in, strDebug):
@@ -84,6 +90,8 @@ func (ll LogLevel) String() string {
 		return strDisabled
 	case LogLevelError:
 		return strError
+	case LogLevelWarn:
+		return strWarn
 	case LogLevelInfo:
 		return strInfo
 	case LogLevelDebug:
@@ -92,3 +100,63 @@ func (ll LogLevel) String() string {
 		return "UNKNOWN"
 	}
 }
+
+// LogMask holds a default log level along with levels for individual
+// subsystems which override it.
+type LogMask struct {
+	Level      LogLevel
+	Subsystems map[string]LogLevel
+}
+
+// ParseLogMask parses a comma-separated log mask consisting of a default
+// level and subsystem=level pairs, e.g. "info,drpc=debug,storage=warn".
+// If the default level is omitted, DefaultLogLevel is used.
+func ParseLogMask(in string) (LogMask, error) {
+	mask := LogMask{Level: DefaultLogLevel}
+	var haveDefault bool
+
+	for _, item := range strings.Split(in, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		var level LogLevel
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) == 1 {
+			if haveDefault {
+				return LogMask{}, fmt.Errorf("log mask %q has more than one default level", in)
+			}
+			if err := level.SetString(kv[0]); err != nil {
+				return LogMask{}, err
+			}
+			mask.Level = level
+			haveDefault = true
+			continue
+		}
+
+		subsystem := strings.TrimSpace(kv[0])
+		if subsystem == "" {
+			return LogMask{}, fmt.Errorf("log mask %q has a level without a subsystem", in)
+		}
+		if err := level.SetString(strings.TrimSpace(kv[1])); err != nil {
+			return LogMask{}, err
+		}
+		if mask.Subsystems == nil {
+			mask.Subsystems = make(map[string]LogLevel)
+		}
+		mask.Subsystems[subsystem] = level
+	}
+
+	return mask, nil
+}
+
+func (lm LogMask) String() string {
+	items := make([]string, 0, len(lm.Subsystems))
+	for subsystem, level := range lm.Subsystems {
+		items = append(items, subsystem+"="+level.String())
+	}
+	sort.Strings(items)
+
+	return strings.Join(append([]string{lm.Level.String()}, items...), ",")
+}
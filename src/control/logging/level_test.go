@@ -23,8 +23,11 @@
 package logging_test
 
 import (
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/daos-stack/daos/src/control/logging"
 )
 
@@ -36,6 +39,7 @@ func TestLogLevelToString(t *testing.T) {
 		"Zero Value": {expected: "DISABLED"},
 		"Disabled":   {expected: "DISABLED", level: logging.LogLevelDisabled},
 		"Error":      {expected: "ERROR", level: logging.LogLevelError},
+		"Warn":       {expected: "WARN", level: logging.LogLevelWarn},
 		"Info":       {expected: "INFO", level: logging.LogLevelInfo},
 		"Debug":      {expected: "DEBUG", level: logging.LogLevelDebug},
 		"Unknown":    {expected: "UNKNOWN", level: logging.LogLevel(42)},
@@ -60,6 +64,8 @@ func TestLogLevelFromString(t *testing.T) {
 		"disabled":  {expected: logging.LogLevelDisabled},
 		"Error":     {expected: logging.LogLevelError},
 		"error":     {expected: logging.LogLevelError},
+		"Warn":      {expected: logging.LogLevelWarn},
+		"warn":      {expected: logging.LogLevelWarn},
 		"Info":      {expected: logging.LogLevelInfo},
 		"info":      {expected: logging.LogLevelInfo},
 		"Debug":     {expected: logging.LogLevelDebug},
@@ -80,3 +86,75 @@ func TestLogLevelFromString(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLogMask(t *testing.T) {
+	for name, tc := range map[string]struct {
+		in        string
+		expMask   logging.LogMask
+		expString string
+		expErr    string
+	}{
+		"empty": {
+			expMask:   logging.LogMask{Level: logging.DefaultLogLevel},
+			expString: "INFO",
+		},
+		"level only": {
+			in:        "debug",
+			expMask:   logging.LogMask{Level: logging.LogLevelDebug},
+			expString: "DEBUG",
+		},
+		"level and subsystems": {
+			in: "info, drpc=debug,storage=WARN",
+			expMask: logging.LogMask{
+				Level: logging.LogLevelInfo,
+				Subsystems: map[string]logging.LogLevel{
+					"drpc":    logging.LogLevelDebug,
+					"storage": logging.LogLevelWarn,
+				},
+			},
+			expString: "INFO,drpc=DEBUG,storage=WARN",
+		},
+		"subsystems only": {
+			in: "storage=error",
+			expMask: logging.LogMask{
+				Level: logging.DefaultLogLevel,
+				Subsystems: map[string]logging.LogLevel{
+					"storage": logging.LogLevelError,
+				},
+			},
+			expString: "INFO,storage=ERROR",
+		},
+		"two default levels": {
+			in:     "info,debug",
+			expErr: "more than one default level",
+		},
+		"bad level": {
+			in:     "drpc=loud",
+			expErr: "not a valid log level",
+		},
+		"missing subsystem": {
+			in:     "=debug",
+			expErr: "without a subsystem",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mask, err := logging.ParseLogMask(tc.in)
+			if tc.expErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expMask, mask); diff != "" {
+				t.Fatalf("unexpected mask (-want, +got):\n%s\n", diff)
+			}
+			if mask.String() != tc.expString {
+				t.Fatalf("expected %q, got %q", tc.expString, mask.String())
+			}
+		})
+	}
+}
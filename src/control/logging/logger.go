@@ -24,7 +24,9 @@ package logging
 
 import (
 	"bytes"
+	"fmt"
 	"io"
+	"os"
 	"sync"
 )
 
@@ -35,6 +37,8 @@ type (
 		Debug(msg string)
 		InfoLogger
 		Info(msg string)
+		WarnLogger
+		Warn(msg string)
 		ErrorLogger
 		Error(msg string)
 	}
@@ -51,6 +55,12 @@ type (
 		Infof(format string, args ...interface{})
 	}
 
+	// WarnLogger defines an interface to be implemented
+	// by Warn loggers.
+	WarnLogger interface {
+		Warnf(format string, args ...interface{})
+	}
+
 	// ErrorLogger defines an interface to be implemented
 	// by Error loggers.
 	ErrorLogger interface {
@@ -66,13 +76,26 @@ type (
 	// LeveledLogger provides a logging implementation which
 	// can emit log messages to multiple destinations with
 	// different output formats.
+	//
+	// Loggers derived from a LeveledLogger with WithFields or
+	// WithSubsystem share its destinations and levels, so that
+	// changes made to any of them apply to all.
 	LeveledLogger struct {
+		*loggerCore
+
+		subsystem string
+		fields    []field
+	}
+
+	loggerCore struct {
 		sync.RWMutex
 
-		level        LogLevel
-		debugLoggers []DebugLogger
-		infoLoggers  []InfoLogger
-		errorLoggers []ErrorLogger
+		level           LogLevel
+		subsystemLevels map[string]LogLevel
+		debugLoggers    []DebugLogger
+		infoLoggers     []InfoLogger
+		warnLoggers     []WarnLogger
+		errorLoggers    []ErrorLogger
 	}
 
 	baseLogger struct {
@@ -82,17 +105,110 @@ type (
 	}
 )
 
-// SetLevel sets the logger's LogLevel, at or above
+// emit formats the message and sends it to the Outputter along with
+// any fields. The callDepth is that which would be supplied to the
+// Outputter by the caller.
+func (bl *baseLogger) emit(callDepth int, fields []field, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+
+	var err error
+	if fo, ok := bl.log.(fieldsOutputter); ok && len(fields) > 0 {
+		err = fo.outputFields(callDepth+1, fields, msg)
+	} else {
+		err = bl.log.Output(callDepth+1, msg+formatFields(fields))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logger output failed: %s\n", err)
+	}
+}
+
+// WithFields returns a logger which attaches the supplied key/value
+// pairs to each message, e.g. WithFields("rank", 3, "pool", uuid).
+// Keys are expected to be strings; a key without a value is given the
+// value "MISSING".
+func (ll *LeveledLogger) WithFields(keyvals ...interface{}) *LeveledLogger {
+	return &LeveledLogger{
+		loggerCore: ll.loggerCore,
+		subsystem:  ll.subsystem,
+		fields:     newFields(ll.fields, keyvals...),
+	}
+}
+
+// WithSubsystem returns a logger for messages from the named subsystem.
+// Its level may be set independently of the default level with
+// SetSubsystemLevel or SetLogMask, and its messages carry a "subsystem"
+// field.
+func (ll *LeveledLogger) WithSubsystem(subsystem string) *LeveledLogger {
+	return &LeveledLogger{
+		loggerCore: ll.loggerCore,
+		subsystem:  subsystem,
+		fields:     newFields(ll.fields, subsystemKey, subsystem),
+	}
+}
+
+// SetLevel sets the logger's default LogLevel, at or above
 // which messages will be emitted.
 func (ll *LeveledLogger) SetLevel(newLevel LogLevel) {
 	ll.level.Set(newLevel)
 }
 
-// Level returns the logger's current LogLevel.
+// Level returns the logger's current LogLevel, which is
+// that of its subsystem if one has been set.
 func (ll *LeveledLogger) Level() LogLevel {
+	if ll.subsystem != "" {
+		ll.RLock()
+		level, found := ll.subsystemLevels[ll.subsystem]
+		ll.RUnlock()
+		if found {
+			return level
+		}
+	}
 	return ll.level.Get()
 }
 
+// SetSubsystemLevel sets the LogLevel for messages from the
+// named subsystem, overriding the default level.
+func (ll *LeveledLogger) SetSubsystemLevel(subsystem string, newLevel LogLevel) {
+	ll.Lock()
+	defer ll.Unlock()
+
+	if ll.subsystemLevels == nil {
+		ll.subsystemLevels = make(map[string]LogLevel)
+	}
+	ll.subsystemLevels[subsystem] = newLevel
+}
+
+// SetLogMask sets the default LogLevel and replaces any
+// subsystem levels with those in the mask.
+func (ll *LeveledLogger) SetLogMask(mask LogMask) {
+	subsystemLevels := make(map[string]LogLevel, len(mask.Subsystems))
+	for subsystem, level := range mask.Subsystems {
+		subsystemLevels[subsystem] = level
+	}
+
+	ll.Lock()
+	defer ll.Unlock()
+
+	ll.level.Set(mask.Level)
+	ll.subsystemLevels = subsystemLevels
+}
+
+// LogMask returns the default LogLevel and any subsystem levels.
+func (ll *LeveledLogger) LogMask() LogMask {
+	ll.RLock()
+	defer ll.RUnlock()
+
+	mask := LogMask{Level: ll.level.Get()}
+	if len(ll.subsystemLevels) > 0 {
+		mask.Subsystems = make(map[string]LogLevel, len(ll.subsystemLevels))
+		for subsystem, level := range ll.subsystemLevels {
+			mask.Subsystems[subsystem] = level
+		}
+	}
+
+	return mask
+}
+
 // WithLogLevel allows the logger's LogLevel to be set
 // as part of a chained method call.
 func (ll *LeveledLogger) WithLogLevel(level LogLevel) *LeveledLogger {
@@ -114,6 +230,13 @@ func (ll *LeveledLogger) WithInfoLogger(newLogger InfoLogger) *LeveledLogger {
 	return ll
 }
 
+// WithWarnLogger adds the specified Warn logger to
+// the logger as part of a chained method call.
+func (ll *LeveledLogger) WithWarnLogger(newLogger WarnLogger) *LeveledLogger {
+	ll.AddWarnLogger(newLogger)
+	return ll
+}
+
 // WithErrorLogger adds the specified Error logger to
 // the logger as part of a chained method call.
 func (ll *LeveledLogger) WithErrorLogger(newLogger ErrorLogger) *LeveledLogger {
@@ -146,6 +269,10 @@ func (ll *LeveledLogger) Debugf(format string, args ...interface{}) {
 	ll.RUnlock()
 
 	for _, l := range loggers {
+		if fl, ok := l.(fieldsDebug); ok && len(ll.fields) > 0 {
+			fl.debugfWithFields(ll.fields, format, args...)
+			continue
+		}
 		l.Debugf(format, args...)
 	}
 }
@@ -175,10 +302,47 @@ func (ll *LeveledLogger) Infof(format string, args ...interface{}) {
 	ll.RUnlock()
 
 	for _, l := range loggers {
+		if fl, ok := l.(fieldsInfo); ok && len(ll.fields) > 0 {
+			fl.infofWithFields(ll.fields, format, args...)
+			continue
+		}
 		l.Infof(format, args...)
 	}
 }
 
+// AddWarnLogger adds the specified Warn logger to the logger.
+func (ll *LeveledLogger) AddWarnLogger(newLogger WarnLogger) {
+	ll.Lock()
+	defer ll.Unlock()
+	ll.warnLoggers = append(ll.warnLoggers, newLogger)
+}
+
+// Warn emits an unformatted message at Warn level, if
+// the logger is configured to do so.
+func (ll *LeveledLogger) Warn(msg string) {
+	ll.Warnf(msg)
+}
+
+// Warnf emits a formatted message at Warn level, if
+// the logger is configured to do so.
+func (ll *LeveledLogger) Warnf(format string, args ...interface{}) {
+	if ll.Level() < LogLevelWarn {
+		return
+	}
+
+	ll.RLock()
+	loggers := ll.warnLoggers
+	ll.RUnlock()
+
+	for _, l := range loggers {
+		if fl, ok := l.(fieldsWarn); ok && len(ll.fields) > 0 {
+			fl.warnfWithFields(ll.fields, format, args...)
+			continue
+		}
+		l.Warnf(format, args...)
+	}
+}
+
 // AddErrorLogger adds the specified Error logger to the logger.
 func (ll *LeveledLogger) AddErrorLogger(newLogger ErrorLogger) {
 	ll.Lock()
@@ -204,6 +368,10 @@ func (ll *LeveledLogger) Errorf(format string, args ...interface{}) {
 	ll.RUnlock()
 
 	for _, l := range loggers {
+		if fl, ok := l.(fieldsError); ok && len(ll.fields) > 0 {
+			fl.errorfWithFields(ll.fields, format, args...)
+			continue
+		}
 		l.Errorf(format, args...)
 	}
 }
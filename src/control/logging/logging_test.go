@@ -24,9 +24,13 @@ package logging_test
 
 import (
 	"bytes"
+	"errors"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/daos-stack/daos/src/control/logging"
 )
 
@@ -214,6 +218,11 @@ func TestLogLevels(t *testing.T) {
 		"Info-Debug":     {setLevel: logging.LogLevelInfo, fn: logger.Debug, fnInput: "test", expected: regexp.MustCompile(`^$`)},
 		"Info-Info":      {setLevel: logging.LogLevelInfo, fn: logger.Info, fnInput: "test", expected: regexp.MustCompile(`test`)},
 		"Info-Error":     {setLevel: logging.LogLevelInfo, fn: logger.Error, fnInput: "test", expected: regexp.MustCompile(`test`)},
+		"Warn-Debug":     {setLevel: logging.LogLevelWarn, fn: logger.Debug, fnInput: "test", expected: regexp.MustCompile(`^$`)},
+		"Warn-Info":      {setLevel: logging.LogLevelWarn, fn: logger.Info, fnInput: "test", expected: regexp.MustCompile(`^$`)},
+		"Warn-Warn":      {setLevel: logging.LogLevelWarn, fn: logger.Warn, fnInput: "test", expected: regexp.MustCompile(`WARN .*test`)},
+		"Warn-Error":     {setLevel: logging.LogLevelWarn, fn: logger.Error, fnInput: "test", expected: regexp.MustCompile(`test`)},
+		"Error-Warn":     {setLevel: logging.LogLevelError, fn: logger.Warn, fnInput: "test", expected: regexp.MustCompile(`^$`)},
 		"Error-Debug":    {setLevel: logging.LogLevelError, fn: logger.Debug, fnInput: "test", expected: regexp.MustCompile(`^$`)},
 		"Error-Info":     {setLevel: logging.LogLevelError, fn: logger.Info, fnInput: "test", expected: regexp.MustCompile(`^$`)},
 		"Error-Error":    {setLevel: logging.LogLevelError, fn: logger.Error, fnInput: "test", expected: regexp.MustCompile(`test`)},
@@ -231,3 +240,135 @@ func TestLogLevels(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldsOutput(t *testing.T) {
+	for name, tc := range map[string]struct {
+		json     bool
+		keyvals  []interface{}
+		expected *regexp.Regexp
+	}{
+		"text": {
+			keyvals:  []interface{}{"rank", 3, "pool", "a b"},
+			expected: regexp.MustCompile(`INFO .*hello rank=3 pool="a b"\n$`),
+		},
+		"text missing value": {
+			keyvals:  []interface{}{"rank"},
+			expected: regexp.MustCompile(`hello rank=MISSING\n$`),
+		},
+		"json": {
+			json:     true,
+			keyvals:  []interface{}{"rank", 3, "pool", "a b", "err", errors.New("oops")},
+			expected: regexp.MustCompile(`"message":"hello","rank":3,"pool":"a b","err":"oops"}\n$`),
+		},
+		"json reserved key": {
+			json:     true,
+			keyvals:  []interface{}{"message", "other"},
+			expected: regexp.MustCompile(`"message":"hello","field_message":"other"}\n$`),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := logging.NewCombinedLogger("testPrefix", &buf)
+			if tc.json {
+				logger = logger.WithJSONOutput()
+			}
+
+			logger.WithFields(tc.keyvals...).Info("hello")
+			if !tc.expected.MatchString(buf.String()) {
+				t.Fatalf("expected %q to match %s", buf.String(), tc.expected)
+			}
+
+			buf.Reset()
+			logger.Info("hello")
+			if strings.Contains(buf.String(), "rank") {
+				t.Fatalf("expected fields not to be added to parent logger: %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestSubsystemLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewCombinedLogger("testPrefix", &buf)
+	drpcLog := logger.WithSubsystem("drpc")
+	storageLog := logger.WithSubsystem("storage").WithFields("rank", 1)
+
+	mask, err := logging.ParseLogMask("info,drpc=debug,storage=warn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.SetLogMask(mask)
+
+	for name, tc := range map[string]struct {
+		fn       func(string)
+		expected *regexp.Regexp
+	}{
+		"default debug": {fn: logger.Debug, expected: regexp.MustCompile(`^$`)},
+		"default info":  {fn: logger.Info, expected: regexp.MustCompile(`INFO .*test\n$`)},
+		"drpc debug":    {fn: drpcLog.Debug, expected: regexp.MustCompile(`DEBUG .*test subsystem=drpc\n$`)},
+		"storage info":  {fn: storageLog.Info, expected: regexp.MustCompile(`^$`)},
+		"storage warn":  {fn: storageLog.Warn, expected: regexp.MustCompile(`WARN .*test subsystem=storage rank=1\n$`)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			buf.Reset()
+			tc.fn("test")
+			if !tc.expected.MatchString(buf.String()) {
+				t.Fatalf("expected %q to match %s", buf.String(), tc.expected)
+			}
+		})
+	}
+
+	if diff := cmp.Diff(mask, logger.LogMask()); diff != "" {
+		t.Fatalf("unexpected mask (-want, +got):\n%s\n", diff)
+	}
+
+	// Changing the default level doesn't affect subsystems with
+	// their own level.
+	logger.SetLevel(logging.LogLevelError)
+	buf.Reset()
+	drpcLog.Debug("test")
+	if buf.String() == "" {
+		t.Fatal("expected drpc debug message after default level change")
+	}
+}
+
+func TestDebugSource(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.NewCombinedLogger("testPrefix", &buf).
+		WithLogLevel(logging.LogLevelDebug)
+
+	logger.Debug("plain")
+	logger.Debugf("formatted %d", 1)
+	logger.WithFields("k", "v").Debug("with fields")
+	logger.WithJSONOutput().WithFields("k", "v").Debugf("json %d", 2)
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.Contains(line, "logging_test.go:") {
+			t.Fatalf("expected source to be this file: %q", line)
+		}
+	}
+}
+
+func TestAuditOutput(t *testing.T) {
+	var buf bytes.Buffer
+	auditLog := logging.NewAuditLogger(&buf)
+
+	type record struct {
+		Method string `json:"method"`
+		Status string `json:"status"`
+	}
+	for _, rec := range []record{{"a", "ok"}, {"b", "error"}} {
+		if err := auditLog.Audit(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := `{"method":"a","status":"ok"}` + "\n" + `{"method":"b","status":"error"}` + "\n"
+	if buf.String() != expected {
+		t.Fatalf("expected %q, got %q", expected, buf.String())
+	}
+
+	if err := auditLog.Audit(func() {}); err == nil {
+		t.Fatal("expected error auditing unencodable record")
+	}
+}
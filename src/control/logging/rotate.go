@@ -0,0 +1,375 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package logging
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// backupTimeFormat is used to name rotated log files so that they
+	// sort in the order in which they were rotated.
+	backupTimeFormat = "20060102T150405.000000000"
+	compressSuffix   = ".gz"
+	bytesPerMB       = 1 << 20
+)
+
+// LogRotation controls the rotation of a log file. The zero value
+// disables rotation, so the file grows without bound.
+type LogRotation struct {
+	// MaxSizeMB is the size in MiB at which the file is rotated.
+	MaxSizeMB int `yaml:"max_size_mb,omitempty" json:"max_size_mb,omitempty"`
+	// MaxAge is how long rotated files are kept; zero keeps them
+	// regardless of age.
+	MaxAge time.Duration `yaml:"max_age,omitempty" json:"max_age,omitempty"`
+	// MaxBackups is the number of rotated files kept; zero keeps
+	// them all.
+	MaxBackups int `yaml:"max_backups,omitempty" json:"max_backups,omitempty"`
+	// Compress causes rotated files to be compressed with gzip.
+	Compress bool `yaml:"compress,omitempty" json:"compress,omitempty"`
+}
+
+// Validate returns an error if the rotation settings are invalid.
+func (lr LogRotation) Validate() error {
+	switch {
+	case lr.MaxSizeMB < 0:
+		return errors.New("max_size_mb must not be negative")
+	case lr.MaxAge < 0:
+		return errors.New("max_age must not be negative")
+	case lr.MaxBackups < 0:
+		return errors.New("max_backups must not be negative")
+	}
+	return nil
+}
+
+// RotatingFile is an io.WriteCloser which appends to a log file, moving
+// it aside and starting a new one when it reaches the maximum size.
+// Rotated files are named after the log file with the time of rotation
+// appended, and are compressed and removed in the background according
+// to the LogRotation settings.
+type RotatingFile struct {
+	sync.Mutex
+	path     string
+	cfg      LogRotation
+	file     *os.File
+	size     int64
+	closed   bool
+	cleaning sync.Mutex
+	cleanups sync.WaitGroup
+}
+
+// NewRotatingFile opens the log file at path for appending, creating it
+// if necessary.
+func NewRotatingFile(path string, cfg LogRotation) (*RotatingFile, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid log rotation")
+	}
+
+	rf := &RotatingFile{
+		path: path,
+		cfg:  cfg,
+	}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func (rf *RotatingFile) open() error {
+	f, err := os.OpenFile(rf.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0664)
+	if err != nil {
+		return err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	rf.file = f
+	rf.size = fi.Size()
+	return nil
+}
+
+func (rf *RotatingFile) closeFile() error {
+	if rf.file == nil {
+		return nil
+	}
+
+	err := rf.file.Close()
+	rf.file = nil
+	return err
+}
+
+func (rf *RotatingFile) maxSize() int64 {
+	return int64(rf.cfg.MaxSizeMB) * bytesPerMB
+}
+
+// Write implements io.Writer. The file is rotated first if the write
+// would take it beyond its maximum size.
+func (rf *RotatingFile) Write(data []byte) (int, error) {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.closed {
+		return 0, os.ErrClosed
+	}
+
+	// A failed reopen is retried on the next write.
+	if rf.file == nil {
+		if err := rf.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if rf.maxSize() > 0 && rf.size > 0 && rf.size+int64(len(data)) > rf.maxSize() {
+		if err := rf.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.file.Write(data)
+	rf.size += int64(n)
+	return n, err
+}
+
+// Rotate moves the current log file aside and starts a new one.
+func (rf *RotatingFile) Rotate() error {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.closed {
+		return os.ErrClosed
+	}
+
+	return rf.rotate()
+}
+
+func (rf *RotatingFile) rotate() error {
+	if err := rf.closeFile(); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(rf.path); err == nil {
+		if err := os.Rename(rf.path, rf.backupPath(time.Now())); err != nil {
+			return errors.Wrap(err, "failed to rotate log file")
+		}
+	}
+
+	if err := rf.open(); err != nil {
+		return err
+	}
+
+	rf.cleanups.Add(1)
+	go func() {
+		defer rf.cleanups.Done()
+		rf.cleanup()
+	}()
+
+	return nil
+}
+
+// backupPath returns an unused name for a file rotated at the given time.
+func (rf *RotatingFile) backupPath(ts time.Time) string {
+	for {
+		backup := rf.path + "." + ts.Format(backupTimeFormat)
+		if _, err := os.Stat(backup); os.IsNotExist(err) {
+			if _, err := os.Stat(backup + compressSuffix); os.IsNotExist(err) {
+				return backup
+			}
+		}
+		ts = ts.Add(time.Nanosecond)
+	}
+}
+
+// Reopen closes and reopens the log file, so that writes go to a new
+// file if the old one has been moved aside by an external tool such as
+// logrotate.
+func (rf *RotatingFile) Reopen() error {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.closed {
+		return os.ErrClosed
+	}
+
+	if err := rf.closeFile(); err != nil {
+		return err
+	}
+	return rf.open()
+}
+
+// ReopenOn reopens the log file each time a signal is received on the
+// channel, until the context is canceled.
+func (rf *RotatingFile) ReopenOn(ctx context.Context, signals <-chan os.Signal) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			if err := rf.Reopen(); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to reopen log file %s: %s\n", rf.path, err)
+			}
+		}
+	}
+}
+
+// Close waits for any compression or removal of rotated files to finish
+// and closes the log file.
+func (rf *RotatingFile) Close() error {
+	rf.cleanups.Wait()
+
+	rf.Lock()
+	defer rf.Unlock()
+
+	rf.closed = true
+	return rf.closeFile()
+}
+
+type backupFile struct {
+	path    string
+	rotated time.Time
+}
+
+// backups returns the rotated log files, newest first.
+func (rf *RotatingFile) backups() ([]backupFile, error) {
+	dir, base := filepath.Split(rf.path)
+	if dir == "" {
+		dir = "."
+	}
+	prefix := base + "."
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []backupFile
+	for _, fi := range entries {
+		name := fi.Name()
+		if fi.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		ts := strings.TrimSuffix(strings.TrimPrefix(name, prefix), compressSuffix)
+		rotated, err := time.ParseInLocation(backupTimeFormat, ts, time.Local)
+		if err != nil {
+			// not one of ours
+			continue
+		}
+		backups = append(backups, backupFile{
+			path:    filepath.Join(dir, name),
+			rotated: rotated,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].rotated.After(backups[j].rotated)
+	})
+
+	return backups, nil
+}
+
+// cleanup removes rotated files which are beyond the configured limits
+// and compresses the remainder if required.
+func (rf *RotatingFile) cleanup() {
+	rf.cleaning.Lock()
+	defer rf.cleaning.Unlock()
+
+	backups, err := rf.backups()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to list rotated log files: %s\n", err)
+		return
+	}
+
+	cutoff := time.Now().Add(-rf.cfg.MaxAge)
+	for i, backup := range backups {
+		if (rf.cfg.MaxBackups > 0 && i >= rf.cfg.MaxBackups) ||
+			(rf.cfg.MaxAge > 0 && backup.rotated.Before(cutoff)) {
+
+			if err := os.Remove(backup.path); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "failed to remove rotated log file: %s\n", err)
+			}
+			continue
+		}
+
+		if rf.cfg.Compress && !strings.HasSuffix(backup.path, compressSuffix) {
+			if err := compressFile(backup.path); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to compress rotated log file: %s\n", err)
+			}
+		}
+	}
+}
+
+// compressFile replaces the file with a gzip-compressed copy.
+func compressFile(path string) (err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	gzPath := path + compressSuffix
+	out, err := os.OpenFile(gzPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fi.Mode())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(gzPath)
+		}
+	}()
+
+	gz := gzip.NewWriter(out)
+	if _, err = io.Copy(gz, in); err != nil {
+		return err
+	}
+	if err = gz.Close(); err != nil {
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
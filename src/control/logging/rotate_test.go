@@ -0,0 +1,325 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package logging_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+const (
+	mb      = 1 << 20
+	testMsg = "hello world"
+)
+
+func rotateTestDir(t *testing.T) (string, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "rotate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+// rotatedFiles returns the names of the files in dir rotated from the log
+// file, oldest first.
+func rotatedFiles(t *testing.T, dir, logName string) []string {
+	t.Helper()
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, fi := range entries {
+		if strings.HasPrefix(fi.Name(), logName+".") {
+			names = append(names, fi.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func writeChunk(t *testing.T, rf *logging.RotatingFile, fill byte, size int) {
+	t.Helper()
+
+	if _, err := rf.Write([]byte(strings.Repeat(string(fill), size))); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRotatingFile_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg    logging.LogRotation
+		expErr string
+	}{
+		"zero value": {},
+		"negative size": {
+			cfg:    logging.LogRotation{MaxSizeMB: -1},
+			expErr: "max_size_mb",
+		},
+		"negative age": {
+			cfg:    logging.LogRotation{MaxAge: -time.Hour},
+			expErr: "max_age",
+		},
+		"negative backups": {
+			cfg:    logging.LogRotation{MaxBackups: -1},
+			expErr: "max_backups",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir, cleanup := rotateTestDir(t)
+			defer cleanup()
+
+			rf, err := logging.NewRotatingFile(filepath.Join(dir, "test.log"), tc.cfg)
+			if tc.expErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				rf.Close()
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestRotatingFile_MaxSize(t *testing.T) {
+	dir, cleanup := rotateTestDir(t)
+	defer cleanup()
+	logPath := filepath.Join(dir, "test.log")
+
+	rf, err := logging.NewRotatingFile(logPath, logging.LogRotation{MaxSizeMB: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writeChunk(t, rf, 'a', mb/2)
+	writeChunk(t, rf, 'b', mb/2)
+	if got := rotatedFiles(t, dir, "test.log"); len(got) != 0 {
+		t.Fatalf("expected no rotation at the maximum size, got %v", got)
+	}
+
+	writeChunk(t, rf, 'c', 1)
+	// A single write larger than the maximum is not split, but goes
+	// to a file of its own.
+	writeChunk(t, rf, 'd', 2*mb)
+	writeChunk(t, rf, 'e', 1)
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := rotatedFiles(t, dir, "test.log")
+	if len(rotated) != 3 {
+		t.Fatalf("expected 3 rotated files, got %v", rotated)
+	}
+
+	for name, expFirst := range map[string]byte{
+		rotated[0]: 'a',
+		rotated[1]: 'c',
+		rotated[2]: 'd',
+		"test.log": 'e',
+	} {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data[0] != expFirst {
+			t.Fatalf("%s: expected data starting with %q, got %q", name, expFirst, data[0])
+		}
+	}
+
+	if _, err := rf.Write([]byte("x")); err != os.ErrClosed {
+		t.Fatalf("expected %v after Close, got %v", os.ErrClosed, err)
+	}
+}
+
+func TestRotatingFile_MaxBackups(t *testing.T) {
+	dir, cleanup := rotateTestDir(t)
+	defer cleanup()
+	logPath := filepath.Join(dir, "test.log")
+
+	rf, err := logging.NewRotatingFile(logPath, logging.LogRotation{MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fill := range []byte("abcd") {
+		writeChunk(t, rf, fill, 10)
+		if err := rf.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := rotatedFiles(t, dir, "test.log")
+	if len(rotated) != 2 {
+		t.Fatalf("expected 2 rotated files, got %v", rotated)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, rotated[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data[0] != 'c' {
+		t.Fatalf("expected oldest rotated files to be removed, got %q", data)
+	}
+}
+
+func TestRotatingFile_MaxAge(t *testing.T) {
+	dir, cleanup := rotateTestDir(t)
+	defer cleanup()
+	logPath := filepath.Join(dir, "test.log")
+
+	old := logPath + "." + time.Now().Add(-2*time.Hour).Format("20060102T150405.000000000")
+	if err := ioutil.WriteFile(old, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	unrelated := logPath + ".orig"
+	if err := ioutil.WriteFile(unrelated, []byte("keep"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rf, err := logging.NewRotatingFile(logPath, logging.LogRotation{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeChunk(t, rf, 'a', 10)
+	if err := rf.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatal("expected expired rotated file to be removed")
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Fatalf("expected unrelated file to be kept: %s", err)
+	}
+	if rotated := rotatedFiles(t, dir, "test.log"); len(rotated) != 2 {
+		t.Fatalf("expected new rotated file to be kept, got %v", rotated)
+	}
+}
+
+func TestRotatingFile_Compress(t *testing.T) {
+	dir, cleanup := rotateTestDir(t)
+	defer cleanup()
+	logPath := filepath.Join(dir, "test.log")
+
+	rf, err := logging.NewRotatingFile(logPath, logging.LogRotation{Compress: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rf.Write([]byte(testMsg)); err != nil {
+		t.Fatal(err)
+	}
+	if err := rf.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := rotatedFiles(t, dir, "test.log")
+	if len(rotated) != 1 || !strings.HasSuffix(rotated[0], ".gz") {
+		t.Fatalf("expected a single compressed file, got %v", rotated)
+	}
+
+	f, err := os.Open(filepath.Join(dir, rotated[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != testMsg {
+		t.Fatalf("expected %q, got %q", testMsg, data)
+	}
+}
+
+func TestRotatingFile_ReopenOn(t *testing.T) {
+	dir, cleanup := rotateTestDir(t)
+	defer cleanup()
+	logPath := filepath.Join(dir, "test.log")
+
+	rf, err := logging.NewRotatingFile(logPath, logging.LogRotation{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rf.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal)
+	go rf.ReopenOn(ctx, signals)
+
+	writeChunk(t, rf, 'a', 10)
+
+	// Simulate logrotate moving the file aside.
+	moved := logPath + ".1"
+	if err := os.Rename(logPath, moved); err != nil {
+		t.Fatal(err)
+	}
+	signals <- os.Interrupt
+	// The unbuffered channel ensures the previous signal has been
+	// received; this one can only be received after it was handled.
+	signals <- os.Interrupt
+
+	writeChunk(t, rf, 'b', 10)
+
+	for path, expData := range map[string]string{
+		moved:   strings.Repeat("a", 10),
+		logPath: strings.Repeat("b", 10),
+	} {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != expData {
+			t.Fatalf("%s: expected %q, got %q", path, expData, data)
+		}
+	}
+}
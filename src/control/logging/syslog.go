@@ -46,6 +46,9 @@ type (
 	syslogInfo interface {
 		WithSyslogOutput() InfoLogger
 	}
+	syslogWarn interface {
+		WithSyslogOutput() WarnLogger
+	}
 	syslogError interface {
 		WithSyslogOutput() ErrorLogger
 	}
@@ -59,6 +62,7 @@ func (ll *LeveledLogger) WithSyslogOutput() *LeveledLogger {
 
 	var debugLoggers []DebugLogger
 	var infoLoggers []InfoLogger
+	var warnLoggers []WarnLogger
 	var errorLoggers []ErrorLogger
 
 	for _, l := range ll.debugLoggers {
@@ -79,6 +83,15 @@ func (ll *LeveledLogger) WithSyslogOutput() *LeveledLogger {
 	}
 	ll.infoLoggers = infoLoggers
 
+	for _, l := range ll.warnLoggers {
+		if syslogger, ok := l.(syslogWarn); ok {
+			if wl, ok := syslogger.WithSyslogOutput().(WarnLogger); ok {
+				warnLoggers = append(warnLoggers, wl)
+			}
+		}
+	}
+	ll.warnLoggers = warnLoggers
+
 	for _, l := range ll.errorLoggers {
 		if syslogger, ok := l.(syslogError); ok {
 			if el, ok := syslogger.WithSyslogOutput().(ErrorLogger); ok {
@@ -103,6 +116,18 @@ func (l *DefaultErrorLogger) WithSyslogOutput() ErrorLogger {
 	}
 }
 
+// WithSyslogOutput switches the logger's output to emit messages
+// via the system logging service.
+func (l *DefaultWarnLogger) WithSyslogOutput() WarnLogger {
+	// Disable timestamps -- they're supplied by syslog
+	flags := warnLogFlags ^ log.LstdFlags
+	return &DefaultWarnLogger{
+		baseLogger{
+			log: MustCreateSyslogger(syslog.LOG_WARNING, flags),
+		},
+	}
+}
+
 // WithSyslogOutput switches the logger's output to emit messages
 // via the system logging service.
 func (l *DefaultInfoLogger) WithSyslogOutput() InfoLogger {
@@ -0,0 +1,72 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package logging
+
+import (
+	"io"
+	"log"
+)
+
+const warnLogFlags = log.LstdFlags
+
+// NewCommandLineWarnLogger returns an WarnLogger configured
+// for outputting unadorned warning messages (i.e. no timestamps,
+// source info, etc); typically used for CLI utility logging.
+func NewCommandLineWarnLogger(output io.Writer) *DefaultWarnLogger {
+	return &DefaultWarnLogger{
+		baseLogger{
+			dest: output,
+			log:  log.New(output, "WARNING: ", emptyLogFlags),
+		},
+	}
+}
+
+// NewWarnLogger returns an WarnLogger configured for outputting
+// warning messages with standard formatting (e.g. to stderr, logfile, etc.)
+func NewWarnLogger(prefix string, output io.Writer) *DefaultWarnLogger {
+	loggerPrefix := "WARN "
+	if prefix != "" {
+		loggerPrefix = prefix + " " + loggerPrefix
+	}
+	return &DefaultWarnLogger{
+		baseLogger{
+			dest:   output,
+			prefix: prefix,
+			log:    log.New(output, loggerPrefix, warnLogFlags),
+		},
+	}
+}
+
+// DefaultWarnLogger implements the WarnLogger interface.
+type DefaultWarnLogger struct {
+	baseLogger
+}
+
+// Warnf emits a formatted warning message.
+func (l *DefaultWarnLogger) Warnf(format string, args ...interface{}) {
+	l.emit(logOutputDepth, nil, format, args...)
+}
+
+func (l *DefaultWarnLogger) warnfWithFields(fields []field, format string, args ...interface{}) {
+	l.emit(logOutputDepth, fields, format, args...)
+}
@@ -26,9 +26,12 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"io/ioutil"
 	"os"
 	"os/exec"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -44,6 +47,10 @@ type (
 	Request struct {
 		Method  string
 		Payload json.RawMessage
+		// Deadline is the time by which the request must be handled.
+		// The privileged binary enforces it itself, as it can't be
+		// killed by its caller once it is running as root.
+		Deadline time.Time
 	}
 
 	// RequestFailure represents a failed request. The error message
@@ -57,6 +64,9 @@ type (
 	Response struct {
 		Error   *RequestFailure
 		Payload json.RawMessage
+		// Expired is set by the privileged binary when the request's
+		// deadline passed before it could be handled.
+		Expired bool
 	}
 
 	cmdLogger struct {
@@ -117,15 +127,97 @@ func decodeResponse(resBuf []byte) (*Response, error) {
 	return nil, errors.Wrapf(err, "pbin failed to decode response data(%q)", resBuf)
 }
 
-func ExecReq(parent context.Context, log logging.Logger, binPath string, req *Request) (res *Response, err error) {
+// exitTimeout is the time allowed for the privileged binary to exit after
+// its input has been closed.
+const exitTimeout = 5 * time.Second
+
+// requestErr returns the error to report for a request which failed in
+// the given context, if the failure was due to the context. The privileged
+// binary enforces the request's deadline itself, so it may give up on the
+// request before the context's own timer has fired.
+func requestErr(ctx context.Context, err error) error {
+	if err == context.DeadlineExceeded || err == context.Canceled {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+// RequestContext returns the context in which the privileged binary should
+// handle the request. It is canceled when the request's deadline passes,
+// or when done is closed because the caller has stopped waiting for the
+// response.
+func RequestContext(req *Request, done <-chan struct{}) (context.Context, context.CancelFunc) {
+	parent, cancelParent := context.WithCancel(context.Background())
+	ctx, cancel := parent, cancelParent
+	if !req.Deadline.IsZero() {
+		ctx, cancel = context.WithDeadline(parent, req.Deadline)
+	}
+
+	go func() {
+		select {
+		case <-done:
+			cancelParent()
+		case <-ctx.Done():
+		}
+	}()
+
+	return ctx, func() {
+		cancel()
+		cancelParent()
+	}
+}
+
+// InputClosed returns a channel which is closed when the caller closes the
+// privileged binary's input. It is used once the request has been read, as
+// the caller closes the input if it stops waiting for the response.
+func InputClosed(in io.Reader) <-chan struct{} {
+	closed := make(chan struct{})
+	go func() {
+		io.Copy(ioutil.Discard, in)
+		close(closed)
+	}()
+
+	return closed
+}
+
+// withDeadline returns a copy of the request carrying the context's
+// deadline, if it has one.
+func withDeadline(ctx context.Context, req *Request) *Request {
+	dlReq := *req
+	if deadline, ok := ctx.Deadline(); ok {
+		dlReq.Deadline = deadline
+	}
+	return &dlReq
+}
+
+// killProcessGroup kills the privileged binary along with anything that
+// it has started. The binary is started in its own process group for this
+// purpose. This fails if the binary is setuid root and the caller is not
+// root, in which case the binary kills its own process group when it sees
+// that its input has been closed.
+func killProcessGroup(log logging.Logger, proc *os.Process) {
+	if err := syscall.Kill(-proc.Pid, syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+		log.Debugf("unable to kill process group %d: %s", proc.Pid, err)
+	}
+}
+
+// ExecReq runs the privileged binary to handle a single request. If the
+// context is canceled before the response has been received, the binary's
+// input is closed and its process group is killed, and the context's error
+// is returned once it has exited.
+func ExecReq(ctx context.Context, log logging.Logger, binPath string, req *Request) (res *Response, err error) {
 	if req == nil {
 		return nil, errors.New("nil request")
 	}
 
-	ctx, killChild := context.WithCancel(parent)
-	defer killChild()
-
-	child := exec.CommandContext(ctx, binPath)
+	child := exec.Command(binPath)
+	child.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 	child.Stderr = &cmdLogger{
 		logFn:  log.Error,
 		prefix: binPath,
@@ -150,9 +242,11 @@ func ExecReq(parent context.Context, log logging.Logger, binPath string, req *Re
 	// make sure we reap any children on the way out
 	defer func() {
 		// If there was an error, kill the child so that it can't
-		// hang around waiting for input.
+		// hang around waiting for input, and wait for it to exit.
 		if err != nil {
-			killChild()
+			killProcessGroup(log, child.Process)
+			conn.Close()
+			awaitExit(log, binPath, child)
 			return
 		}
 
@@ -160,11 +254,33 @@ func ExecReq(parent context.Context, log logging.Logger, binPath string, req *Re
 		err = child.Wait()
 	}()
 
-	sendData, err := json.Marshal(req)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// The context is also canceled once the request has
+			// completed, so check that it hasn't.
+			select {
+			case <-done:
+				return
+			default:
+			}
+			// Unblock any pending read or write.
+			killProcessGroup(log, child.Process)
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	sendData, err := json.Marshal(withDeadline(ctx, req))
 	if err != nil {
 		return nil, err
 	}
 	if _, err := conn.Write(sendData); err != nil {
+		if ctxErr := requestErr(ctx, err); ctxErr != nil {
+			return nil, ctxErr
+		}
 		return nil, errors.Wrap(err, "pbin write failed")
 	}
 
@@ -174,6 +290,9 @@ func ExecReq(parent context.Context, log logging.Logger, binPath string, req *Re
 		recvData := make([]byte, MaxMessageSize)
 		recvLen, err := conn.Read(recvData)
 		if err != nil {
+			if ctxErr := requestErr(ctx, err); ctxErr != nil {
+				return nil, ctxErr
+			}
 			return nil, errors.Wrap(err, "pbin read failed")
 		}
 
@@ -186,6 +305,9 @@ func ExecReq(parent context.Context, log logging.Logger, binPath string, req *Re
 			}
 			return nil, err
 		}
+		if res.Expired {
+			return nil, context.DeadlineExceeded
+		}
 		if res.Error != nil {
 			return nil, res.Error
 		}
@@ -193,3 +315,20 @@ func ExecReq(parent context.Context, log logging.Logger, binPath string, req *Re
 		return res, nil
 	}
 }
+
+// awaitExit waits for the privileged binary to exit after its input has
+// been closed. If it does not exit in time, it is reaped in the background.
+func awaitExit(log logging.Logger, binPath string, child *exec.Cmd) {
+	exited := make(chan struct{})
+	go func() {
+		child.Wait()
+		close(exited)
+	}()
+
+	select {
+	case <-exited:
+	case <-time.After(exitTimeout):
+		log.Errorf("%s (pid %d) did not exit after its input was closed",
+			binPath, child.Process.Pid)
+	}
+}
@@ -26,8 +26,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -51,6 +53,17 @@ func reqRes() {
 		childErrExit(err)
 	}
 
+	if req.Method == "hang" {
+		pbin.ServeRequest(conn, &req, pbin.InputClosed(os.Stdin), func(ctx context.Context, _ *pbin.Request, _ io.Writer) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, func(*pbin.Request, error) {
+			// Exit as daos_admin does when a request is not completed.
+			os.Exit(1)
+		})
+		return
+	}
+
 	res := pbin.Response{
 		Payload: req.Payload,
 	}
@@ -113,3 +126,21 @@ func TestPbinExec(t *testing.T) {
 		})
 	}
 }
+
+func TestPbinExec_ContextCanceled(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	os.Setenv(childModeEnvVar, childModeReqRes)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := pbin.ExecReq(ctx, log, os.Args[0], &pbin.Request{Method: "hang"})
+	common.CmpErr(t, context.DeadlineExceeded, err)
+
+	if time.Since(start) > 10*time.Second {
+		t.Fatal("expected hung binary to be killed when the context expired")
+	}
+}
@@ -25,8 +25,12 @@ package pbin
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -39,8 +43,11 @@ type (
 	Forwarder struct {
 		Disabled bool
 
-		log      logging.Logger
-		pbinName string
+		log          logging.Logger
+		pbinName     string
+		noPersistent bool
+		timeouts     map[string]time.Duration
+		retireAfter  map[string]bool
 	}
 
 	// ForwardableRequest is intended to be embedded into
@@ -65,8 +72,20 @@ func (r ForwardableRequest) IsForwarded() bool {
 // NewForwarder returns a configured *Forwarder.
 func NewForwarder(log logging.Logger, pbinName string) *Forwarder {
 	fwd := &Forwarder{
-		log:      log,
-		pbinName: pbinName,
+		log:         log,
+		pbinName:    pbinName,
+		timeouts:    make(map[string]time.Duration),
+		retireAfter: make(map[string]bool),
+	}
+
+	if val, set := os.LookupEnv(DaosAdminTimeoutsEnvVar); set {
+		timeouts, err := ParseTimeouts(val)
+		if err != nil {
+			log.Errorf("%s was set to an invalid value (%q): %s; using default timeouts",
+				DaosAdminTimeoutsEnvVar, val, err)
+		} else {
+			fwd.timeouts = timeouts
+		}
 	}
 
 	if val, set := os.LookupEnv(DisableReqFwdEnvVar); set {
@@ -79,13 +98,117 @@ func NewForwarder(log logging.Logger, pbinName string) *Forwarder {
 		fwd.Disabled = disabled
 	}
 
+	if val, set := os.LookupEnv(DisablePersistentHelperEnvVar); set {
+		disabled, err := strconv.ParseBool(val)
+		if err != nil {
+			log.Errorf("%s was set to non-boolean value (%q); not disabling",
+				DisablePersistentHelperEnvVar, val)
+			return fwd
+		}
+		fwd.noPersistent = disabled
+	}
+
 	return fwd
 }
 
+// WithDefaultTimeouts sets the time allowed for requests to each of the
+// given methods, unless it has been overridden in the environment.
+func (f *Forwarder) WithDefaultTimeouts(timeouts map[string]time.Duration) *Forwarder {
+	for method, timeout := range timeouts {
+		if _, set := f.timeouts[method]; !set {
+			f.timeouts[method] = timeout
+		}
+	}
+
+	return f
+}
+
+// WithRetireAfter sets the methods which leave state in the privileged
+// binary (e.g. claimed devices) that must not outlive the request, so the
+// persistent helper is retired after each request to one of them.
+func (f *Forwarder) WithRetireAfter(methods ...string) *Forwarder {
+	for _, method := range methods {
+		f.retireAfter[method] = true
+	}
+
+	return f
+}
+
+// Timeout returns the time allowed for a request to the given method.
+func (f *Forwarder) Timeout(method string) time.Duration {
+	if timeout, set := f.timeouts[method]; set {
+		return timeout
+	}
+	return DefaultRequestTimeout
+}
+
+// ParseTimeouts parses a comma-separated list of method=duration pairs.
+func ParseTimeouts(val string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+
+	for _, pair := range strings.Split(val, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("%q is not a method=duration pair", pair)
+		}
+
+		timeout, err := time.ParseDuration(kv[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timeout for %s", kv[0])
+		}
+		if timeout <= 0 {
+			return nil, errors.Errorf("timeout for %s must be positive", kv[0])
+		}
+		timeouts[kv[0]] = timeout
+	}
+
+	return timeouts, nil
+}
+
+// FormatTimeouts formats the timeouts as a list which can be parsed
+// by ParseTimeouts.
+func FormatTimeouts(timeouts map[string]time.Duration) string {
+	pairs := make([]string, 0, len(timeouts))
+	for method, timeout := range timeouts {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", method, timeout))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// sendReq sends the request to the persistent helper for the privileged
+// binary, falling back to running the binary for this request if the
+// helper is unavailable.
+func (f *Forwarder) sendReq(ctx context.Context, pbinPath string, req *Request) (*Response, error) {
+	if f.noPersistent {
+		return ExecReq(ctx, f.log, pbinPath, req)
+	}
+
+	helper := sharedHelper(f.log, pbinPath)
+	if f.retireAfter[req.Method] {
+		defer helper.Retire()
+	}
+
+	res, err := helper.SendReq(ctx, req)
+	if IsHelperUnavailable(err) {
+		return ExecReq(ctx, f.log, pbinPath, req)
+	}
+
+	return res, err
+}
+
 // SendReq is responsible for marshaling the forwarded request into a message
 // that is sent to the privileged binary, then unmarshaling the response for
-// the caller.
-func (f *Forwarder) SendReq(method string, fwdReq interface{}, fwdRes interface{}) error {
+// the caller. If the request does not complete within the time allowed for
+// the method, or the context is canceled, the privileged binary is killed
+// and a *RequestFailure naming the method is returned.
+func (f *Forwarder) SendReq(parent context.Context, method string, fwdReq interface{}, fwdRes interface{}) error {
 	if fwdReq == nil {
 		return errors.New("nil request")
 	}
@@ -108,12 +231,34 @@ func (f *Forwarder) SendReq(method string, fwdReq interface{}, fwdRes interface{
 		Payload: payload,
 	}
 
-	ctx := context.TODO()
-	res, err := ExecReq(ctx, f.log, pbinPath, req)
+	timeout := f.Timeout(method)
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	started := time.Now()
+	res, err := f.sendReq(ctx, pbinPath, req)
 	if err != nil {
 		if IsFailedRequest(err) {
 			return err
 		}
+
+		switch requestErr(ctx, err) {
+		case context.DeadlineExceeded:
+			// The caller's deadline may have been the shorter one.
+			waited := timeout
+			if requestErr(parent, nil) != nil {
+				waited = time.Since(started).Round(time.Millisecond)
+			}
+			return &RequestFailure{
+				Message: fmt.Sprintf("%s request to %s timed out after %s",
+					method, f.pbinName, waited),
+			}
+		case context.Canceled:
+			return &RequestFailure{
+				Message: fmt.Sprintf("%s request to %s was canceled", method, f.pbinName),
+			}
+		}
+
 		return errors.Wrap(err, "privileged binary execution failed")
 	}
 
@@ -0,0 +1,214 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package pbin_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/pbin"
+)
+
+func TestForwarder_ParseTimeouts(t *testing.T) {
+	for name, tc := range map[string]struct {
+		val    string
+		expOut map[string]time.Duration
+		expErr error
+	}{
+		"empty": {
+			expOut: map[string]time.Duration{},
+		},
+		"single": {
+			val: "ScmFormat=20m",
+			expOut: map[string]time.Duration{
+				"ScmFormat": 20 * time.Minute,
+			},
+		},
+		"multiple with spaces": {
+			val: "ScmFormat=20m, BdevFormat=1h,",
+			expOut: map[string]time.Duration{
+				"ScmFormat":  20 * time.Minute,
+				"BdevFormat": time.Hour,
+			},
+		},
+		"missing duration": {
+			val:    "ScmFormat",
+			expErr: errors.New("not a method=duration pair"),
+		},
+		"missing method": {
+			val:    "=1m",
+			expErr: errors.New("not a method=duration pair"),
+		},
+		"bad duration": {
+			val:    "ScmFormat=soon",
+			expErr: errors.New("invalid timeout for ScmFormat"),
+		},
+		"zero duration": {
+			val:    "ScmFormat=0s",
+			expErr: errors.New("must be positive"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gotOut, gotErr := pbin.ParseTimeouts(tc.val)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expOut, gotOut); diff != "" {
+				t.Fatalf("unexpected timeouts (-want, +got):\n%s\n", diff)
+			}
+
+			roundTrip, err := pbin.ParseTimeouts(pbin.FormatTimeouts(gotOut))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(gotOut, roundTrip); diff != "" {
+				t.Fatalf("timeouts changed by round trip (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestForwarder_Timeout(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	os.Setenv(pbin.DaosAdminTimeoutsEnvVar, "ScmFormat=20m")
+	defer os.Unsetenv(pbin.DaosAdminTimeoutsEnvVar)
+
+	fwd := pbin.NewForwarder(log, os.Args[0]).WithDefaultTimeouts(
+		map[string]time.Duration{
+			"ScmFormat": 10 * time.Minute,
+			"ScmScan":   2 * time.Minute,
+		})
+
+	common.AssertEqual(t, fwd.Timeout("ScmFormat"), 20*time.Minute,
+		"expected environment to override default timeout")
+	common.AssertEqual(t, fwd.Timeout("ScmScan"), 2*time.Minute,
+		"expected default timeout")
+	common.AssertEqual(t, fwd.Timeout("BdevScan"), pbin.DefaultRequestTimeout,
+		"expected package default timeout")
+}
+
+func TestForwarder_RetireAfter(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+	defer pbin.StopHelpers()
+
+	os.Setenv(childModeEnvVar, childModeServe)
+
+	fwd := pbin.NewForwarder(log, os.Args[0]).WithRetireAfter("claim")
+
+	sendReq := func(method string) int {
+		t.Helper()
+
+		var pid int
+		if err := fwd.SendReq(context.Background(), method, struct{}{}, &pid); err != nil {
+			t.Fatal(err)
+		}
+		return pid
+	}
+
+	firstPid := sendReq("pid")
+	common.AssertEqual(t, firstPid, sendReq("pid"),
+		"helper should not be retired after an unlisted method")
+	common.AssertEqual(t, firstPid, sendReq("claim"),
+		"listed method should be sent to the running helper")
+
+	// The helper must have exited before the request returned.
+	if err := syscall.Kill(firstPid, 0); err != syscall.ESRCH {
+		t.Fatalf("expected retired helper (pid %d) to have exited, got %v", firstPid, err)
+	}
+	if sendReq("pid") == firstPid {
+		t.Fatal("expected helper to be retired after a listed method")
+	}
+}
+
+func TestForwarder_SendReqTimeout(t *testing.T) {
+	for name, tc := range map[string]struct {
+		persistent bool
+	}{
+		"exec": {},
+		"persistent helper": {
+			persistent: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+			defer pbin.StopHelpers()
+
+			if tc.persistent {
+				os.Setenv(childModeEnvVar, childModeServe)
+			} else {
+				os.Setenv(childModeEnvVar, childModeReqRes)
+				os.Setenv(pbin.DisablePersistentHelperEnvVar, "true")
+				defer os.Unsetenv(pbin.DisablePersistentHelperEnvVar)
+			}
+
+			fwd := pbin.NewForwarder(log, os.Args[0]).WithDefaultTimeouts(
+				map[string]time.Duration{"hang": 100 * time.Millisecond})
+
+			var res struct{}
+			err := fwd.SendReq(context.Background(), "hang", struct{}{}, &res)
+			if !pbin.IsFailedRequest(err) {
+				t.Fatalf("expected a *RequestFailure, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "hang request") ||
+				!strings.Contains(err.Error(), "timed out after 100ms") {
+
+				t.Fatalf("unexpected error message: %s", err)
+			}
+		})
+	}
+}
+
+func TestForwarder_SendReqCanceled(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+	defer pbin.StopHelpers()
+
+	os.Setenv(childModeEnvVar, childModeServe)
+
+	fwd := pbin.NewForwarder(log, os.Args[0])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	var res struct{}
+	err := fwd.SendReq(ctx, "hang", struct{}{}, &res)
+	common.CmpErr(t, errors.New("hang request to "+os.Args[0]+" was canceled"), err)
+}
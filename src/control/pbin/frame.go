@@ -0,0 +1,78 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package pbin
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// MaxFrameSize is the largest message that may be exchanged with a
+// persistent helper process.
+const MaxFrameSize = 16 << 20
+
+const frameHeaderSize = 4
+
+// WriteMessage writes data to w as a single length-prefixed frame.
+func WriteMessage(w io.Writer, data []byte) error {
+	if len(data) > MaxFrameSize {
+		return errors.Errorf("message size %d exceeds maximum of %d", len(data), MaxFrameSize)
+	}
+
+	frame := make([]byte, frameHeaderSize+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[frameHeaderSize:], data)
+
+	_, err := w.Write(frame)
+	return err
+}
+
+// ReadMessage reads a single length-prefixed frame from r and returns
+// its contents. io.EOF is returned if r is closed before a new frame
+// has started.
+func ReadMessage(r io.Reader) ([]byte, error) {
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, errors.Wrap(err, "truncated frame header")
+		}
+		return nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header)
+	if size > MaxFrameSize {
+		return nil, errors.Errorf("frame size %d exceeds maximum of %d", size, MaxFrameSize)
+	}
+
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, errors.Wrap(err, "truncated frame")
+	}
+
+	return data, nil
+}
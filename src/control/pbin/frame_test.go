@@ -0,0 +1,113 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package pbin_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/pbin"
+)
+
+func TestFraming_RoundTrip(t *testing.T) {
+	msgs := [][]byte{
+		[]byte(`{"Method":"one"}`),
+		{},
+		bytes.Repeat([]byte("x"), pbin.MaxMessageSize*4),
+	}
+
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		if err := pbin.WriteMessage(&buf, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, want := range msgs {
+		got, err := pbin.ReadMessage(&buf)
+		if err != nil {
+			t.Fatalf("message %d: %s", i, err)
+		}
+		if !bytes.Equal(want, got) {
+			t.Fatalf("message %d: got %d bytes, want %d", i, len(got), len(want))
+		}
+	}
+
+	_, err := pbin.ReadMessage(&buf)
+	if err != io.EOF {
+		t.Fatalf("expected io.EOF after last message, got %v", err)
+	}
+}
+
+func TestFraming_ReadErrors(t *testing.T) {
+	header := func(size uint32) []byte {
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, size)
+		return b
+	}
+
+	for name, tc := range map[string]struct {
+		data   []byte
+		expErr error
+	}{
+		"empty": {
+			expErr: io.EOF,
+		},
+		"truncated header": {
+			data:   []byte{0, 0},
+			expErr: errors.New("truncated frame header"),
+		},
+		"truncated body": {
+			data:   append(header(10), []byte("short")...),
+			expErr: errors.New("truncated frame"),
+		},
+		"missing body": {
+			data:   header(10),
+			expErr: errors.New("truncated frame"),
+		},
+		"oversized frame": {
+			data:   header(pbin.MaxFrameSize + 1),
+			expErr: errors.New("exceeds maximum"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := pbin.ReadMessage(bytes.NewReader(tc.data))
+			common.CmpErr(t, tc.expErr, err)
+		})
+	}
+}
+
+func TestFraming_WriteTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+	err := pbin.WriteMessage(&buf, make([]byte, pbin.MaxFrameSize+1))
+	common.CmpErr(t, errors.New("exceeds maximum"), err)
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected nothing to be written, got %d bytes", buf.Len())
+	}
+}
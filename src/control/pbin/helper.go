@@ -0,0 +1,527 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package pbin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+const (
+	// helperPingMethod is answered by ServeRequests itself, and is used
+	// to check that a newly-started helper is serving framed requests.
+	helperPingMethod = "HelperPing"
+)
+
+type (
+	// Helper manages a long-lived instance of a privileged binary which
+	// serves framed requests over its stdin/stdout. The process is started
+	// on the first request, and is restarted if it exits.
+	Helper struct {
+		sync.Mutex
+		log         logging.Logger
+		binPath     string
+		proc        *helperProc
+		unavailable error
+	}
+
+	helperProc struct {
+		log     logging.Logger
+		binPath string
+		cmd     *exec.Cmd
+		conn    *StdioConn
+		exited  chan struct{}
+	}
+
+	helperUnavailable struct {
+		err error
+	}
+)
+
+var sharedHelpers = struct {
+	sync.Mutex
+	helpers map[string]*Helper
+}{
+	helpers: make(map[string]*Helper),
+}
+
+func (hu *helperUnavailable) Error() string {
+	return "persistent helper unavailable: " + hu.err.Error()
+}
+
+// IsHelperUnavailable indicates whether or not the error was returned
+// because a persistent helper could not be started. In this case the
+// request was not sent, and it is safe to retry it with ExecReq.
+func IsHelperUnavailable(err error) bool {
+	_, ok := errors.Cause(err).(*helperUnavailable)
+	return ok
+}
+
+// NewHelper returns a *Helper for the privileged binary at binPath.
+func NewHelper(log logging.Logger, binPath string) *Helper {
+	return &Helper{
+		log:     log,
+		binPath: binPath,
+	}
+}
+
+// sharedHelper returns the *Helper for binPath which is shared by all
+// Forwarders in this process, creating it if necessary.
+func sharedHelper(log logging.Logger, binPath string) *Helper {
+	sharedHelpers.Lock()
+	defer sharedHelpers.Unlock()
+
+	h, found := sharedHelpers.helpers[binPath]
+	if !found {
+		h = NewHelper(log, binPath)
+		sharedHelpers.helpers[binPath] = h
+	}
+
+	return h
+}
+
+// StopHelpers stops any persistent helper processes which were started
+// to serve forwarded requests.
+func StopHelpers() {
+	sharedHelpers.Lock()
+	defer sharedHelpers.Unlock()
+
+	for _, h := range sharedHelpers.helpers {
+		h.Stop()
+	}
+}
+
+// RetireHelpers retires any persistent helper processes which were
+// started to serve forwarded requests, so that new ones are started for
+// subsequent requests.
+func RetireHelpers() {
+	sharedHelpers.Lock()
+	defer sharedHelpers.Unlock()
+
+	for _, h := range sharedHelpers.helpers {
+		h.Retire()
+	}
+}
+
+// SendReq sends the request to the helper process, starting it first if
+// it is not running, and returns its response. Requests are sent one at a
+// time. If the helper cannot be started, an error which satisfies
+// IsHelperUnavailable is returned and the helper is not started again.
+func (h *Helper) SendReq(ctx context.Context, req *Request) (*Response, error) {
+	if req == nil {
+		return nil, errors.New("nil request")
+	}
+
+	sendData, err := json.Marshal(withDeadline(ctx, req))
+	if err != nil {
+		return nil, err
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	if h.unavailable != nil {
+		return nil, h.unavailable
+	}
+
+	for attempt := 0; ; attempt++ {
+		if h.proc == nil || h.proc.hasExited() {
+			proc, err := startHelper(ctx, h.log, h.binPath)
+			if err != nil {
+				// Try again next time if the request was
+				// canceled while the helper was starting.
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				h.unavailable = &helperUnavailable{err: err}
+				h.log.Errorf("%s: %s; running once per request", h.binPath, h.unavailable)
+				return nil, h.unavailable
+			}
+			h.proc = proc
+		}
+
+		err := WriteMessage(h.proc.conn, sendData)
+		if err == nil {
+			break
+		}
+
+		// The helper exited before it could have read any of the
+		// request, so it is safe to send it to a new one.
+		h.proc.kill()
+		h.proc = nil
+		if attempt > 0 {
+			return nil, errors.Wrap(err, "pbin write failed")
+		}
+	}
+
+	res, err := h.proc.recv(ctx)
+	if err == nil && res.Expired {
+		// The helper exits once it has given up on a request.
+		err = context.DeadlineExceeded
+	}
+	if err != nil {
+		h.proc.kill()
+		h.proc = nil
+		return nil, err
+	}
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	return res, nil
+}
+
+// Stop closes the helper's input and waits for it to exit. A subsequent
+// request will start a new helper process.
+func (h *Helper) Stop() {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.proc == nil {
+		return
+	}
+	h.proc.stop(h.log)
+	h.proc = nil
+}
+
+// Retire stops the helper when it holds state which must not outlive a
+// request, waiting for it to exit so that the state has been released
+// before Retire returns.
+func (h *Helper) Retire() {
+	h.Stop()
+}
+
+func startHelper(ctx context.Context, log logging.Logger, binPath string) (*helperProc, error) {
+	toChildRd, toChild, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	fromChild, fromChildWr, err := os.Pipe()
+	if err != nil {
+		toChildRd.Close()
+		toChild.Close()
+		return nil, err
+	}
+
+	child := exec.Command(binPath)
+	child.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	child.Stdin = toChildRd
+	child.Stdout = fromChildWr
+	child.Stderr = &cmdLogger{
+		logFn:  log.Error,
+		prefix: binPath,
+	}
+
+	// ensure that /usr/sbin is in $PATH
+	os.Setenv("PATH", os.Getenv("PATH")+":/usr/sbin")
+	child.Env = append(os.Environ(), PersistentHelperEnvVar+"=true")
+
+	err = child.Start()
+	// The child has its own copies of these now.
+	toChildRd.Close()
+	fromChildWr.Close()
+	if err != nil {
+		toChild.Close()
+		fromChild.Close()
+		return nil, err
+	}
+
+	proc := &helperProc{
+		log:     log,
+		binPath: binPath,
+		cmd:     child,
+		conn:    NewStdioConn("server", binPath, fromChild, toChild),
+		exited:  make(chan struct{}),
+	}
+	go func() {
+		err := child.Wait()
+		log.Debugf("%s (pid %d) exited: %v", binPath, child.Process.Pid, err)
+		close(proc.exited)
+	}()
+
+	if err := proc.ping(ctx); err != nil {
+		proc.kill()
+		return nil, err
+	}
+	log.Debugf("started %s (pid %d) as a persistent helper", binPath, child.Process.Pid)
+
+	return proc, nil
+}
+
+func (p *helperProc) hasExited() bool {
+	select {
+	case <-p.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *helperProc) ping(ctx context.Context) error {
+	sendData, err := json.Marshal(&Request{Method: helperPingMethod})
+	if err != nil {
+		return err
+	}
+	if err := WriteMessage(p.conn, sendData); err != nil {
+		return errors.Wrap(err, "pbin write failed")
+	}
+
+	res, err := p.recv(ctx)
+	if err != nil {
+		return err
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+
+	return nil
+}
+
+// recv reads a response from the helper. If the context is canceled
+// before the response arrives, the helper is killed.
+func (p *helperProc) recv(ctx context.Context) (*Response, error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			// The context is also canceled once the request has
+			// completed, so check that it hasn't.
+			select {
+			case <-done:
+				return
+			default:
+			}
+			p.kill()
+		case <-done:
+		}
+	}()
+
+	recvData, err := ReadMessage(p.conn)
+	if err != nil {
+		if ctxErr := requestErr(ctx, err); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if err == io.EOF {
+			return nil, errors.Errorf("%s exited before responding", p.binPath)
+		}
+		return nil, errors.Wrap(err, "pbin read failed")
+	}
+
+	res := new(Response)
+	if err := json.Unmarshal(recvData, res); err != nil {
+		return nil, errors.Wrapf(err, "pbin failed to decode response data(%q)", recvData)
+	}
+
+	return res, nil
+}
+
+// kill closes the helper's input and kills its process group, then waits
+// for it to exit. If the helper is setuid root its process group can't be
+// killed, but it kills its own when it sees that its input was closed.
+func (p *helperProc) kill() {
+	p.conn.Close()
+	if !p.hasExited() {
+		killProcessGroup(p.log, p.cmd.Process)
+	}
+
+	select {
+	case <-p.exited:
+	case <-time.After(exitTimeout):
+		p.log.Errorf("%s (pid %d) did not exit after it was killed",
+			p.binPath, p.cmd.Process.Pid)
+	}
+}
+
+func (p *helperProc) stop(log logging.Logger) {
+	p.conn.Close()
+
+	select {
+	case <-p.exited:
+	case <-time.After(exitTimeout):
+		log.Errorf("%s (pid %d) did not exit after its input was closed; killing it",
+			p.binPath, p.cmd.Process.Pid)
+		p.kill()
+	}
+}
+
+// AbortFunc is called by a privileged binary when the context for a
+// request is done before the request has been handled. The caller can't
+// kill the privileged binary once it is running as root, so it must stop
+// anything started to handle the request, normally by exiting.
+type AbortFunc func(req *Request, err error)
+
+// ServeRequests is run by a privileged binary that has been started as a
+// persistent helper. It reads framed requests from conn until the parent
+// closes it, passing each one to handle and sending whatever handle
+// writes back as a single framed response. The context passed to handle
+// is canceled if the request's deadline passes, or if the parent closes
+// conn before the response has been sent, in which case abort is called.
+func ServeRequests(conn io.ReadWriter, handle func(context.Context, *Request, io.Writer) error, abort AbortFunc) error {
+	// Requests are read in the background so that the parent closing
+	// conn is noticed while a request is being handled.
+	msgs := make(chan []byte)
+	closed := make(chan struct{})
+	var readErr error
+	go func() {
+		defer close(closed)
+		for {
+			reqData, err := ReadMessage(conn)
+			if err != nil {
+				readErr = err
+				return
+			}
+			msgs <- reqData
+		}
+	}()
+
+	send := func(resData []byte) error {
+		return WriteMessage(conn, resData)
+	}
+
+	for {
+		var reqData []byte
+		select {
+		case reqData = <-msgs:
+		case <-closed:
+			if readErr == io.EOF {
+				return nil
+			}
+			return readErr
+		}
+
+		var resBuf bytes.Buffer
+		var req Request
+		if err := json.Unmarshal(reqData, &req); err != nil {
+			err = errors.Wrap(err, "failed to decode request")
+			if err := writeFailure(&resBuf, err); err != nil {
+				return err
+			}
+		} else if req.Method == helperPingMethod {
+			if err := json.NewEncoder(&resBuf).Encode(&Response{}); err != nil {
+				return err
+			}
+		} else {
+			if err := serveRequest(&req, closed, handle, abort, send); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := send(resBuf.Bytes()); err != nil {
+			return err
+		}
+	}
+}
+
+// ServeRequest is run by a privileged binary that has been started to
+// handle a single request. It passes the request to handle and writes
+// whatever handle writes back to conn. The context passed to handle is
+// canceled if the request's deadline passes, or if done is closed because
+// the parent has stopped waiting for the response, in which case abort is
+// called.
+func ServeRequest(conn io.Writer, req *Request, done <-chan struct{}, handle func(context.Context, *Request, io.Writer) error, abort AbortFunc) error {
+	return serveRequest(req, done, handle, abort, func(resData []byte) error {
+		_, err := conn.Write(resData)
+		return err
+	})
+}
+
+// serveRequest handles a request and sends its response. If the request's
+// deadline passes first, a response saying so is sent instead, so that the
+// parent doesn't mistake the privileged binary exiting for a failure, and
+// abort is called.
+func serveRequest(req *Request, done <-chan struct{}, handle func(context.Context, *Request, io.Writer) error, abort AbortFunc, send func([]byte) error) error {
+	ctx, cancel := RequestContext(req, done)
+	defer cancel()
+
+	// Only one response is sent for the request, either by the handler
+	// or on its behalf once the context is done.
+	var sendLock sync.Mutex
+	responded := false
+	expire := func() {
+		if ctx.Err() == context.DeadlineExceeded {
+			if resData, err := json.Marshal(&Response{
+				Expired: true,
+				Error:   &RequestFailure{Message: ctx.Err().Error()},
+			}); err == nil {
+				send(resData)
+			}
+		}
+		abort(req, ctx.Err())
+	}
+	go func() {
+		<-ctx.Done()
+
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		if !responded {
+			responded = true
+			expire()
+		}
+	}()
+
+	var resBuf bytes.Buffer
+	handleErr := handle(ctx, req, &resBuf)
+
+	sendLock.Lock()
+	defer sendLock.Unlock()
+	if responded {
+		return nil
+	}
+	responded = true
+
+	// The handler may have returned because the context is done.
+	if ctx.Err() != nil {
+		expire()
+		return nil
+	}
+
+	if handleErr != nil {
+		if resBuf.Len() > 0 {
+			return handleErr
+		}
+		if err := writeFailure(&resBuf, handleErr); err != nil {
+			return err
+		}
+	}
+
+	return send(resBuf.Bytes())
+}
+
+func writeFailure(dest io.Writer, err error) error {
+	return json.NewEncoder(dest).Encode(&Response{
+		Error: &RequestFailure{Message: err.Error()},
+	})
+}
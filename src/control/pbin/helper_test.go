@@ -0,0 +1,437 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package pbin_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/pbin"
+)
+
+// serve runs the test binary as a persistent helper.
+func serve() {
+	conn := pbin.NewStdioConn("child", "parent", os.Stdin, os.Stdout)
+	err := pbin.ServeRequests(conn, func(ctx context.Context, req *pbin.Request, dest io.Writer) error {
+		var res pbin.Response
+		switch req.Method {
+		case "pid", "claim":
+			res.Payload, _ = json.Marshal(os.Getpid())
+		case "deadline":
+			deadline, _ := ctx.Deadline()
+			res.Payload, _ = json.Marshal(deadline)
+		case "exit":
+			os.Exit(0)
+		case "hang":
+			<-ctx.Done()
+			return ctx.Err()
+		case "fail":
+			res.Error = &pbin.RequestFailure{Message: "request failed"}
+		default:
+			res.Payload = req.Payload
+		}
+		return json.NewEncoder(dest).Encode(&res)
+	}, func(*pbin.Request, error) {
+		// Exit as daos_admin does when a request is not completed.
+		os.Exit(1)
+	})
+	if err != nil {
+		childErrExit(err)
+	}
+}
+
+func helperPid(t *testing.T, h *pbin.Helper) int {
+	t.Helper()
+
+	res, err := h.SendReq(context.Background(), &pbin.Request{Method: "pid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pid int
+	if err := json.Unmarshal(res.Payload, &pid); err != nil {
+		t.Fatal(err)
+	}
+	return pid
+}
+
+func TestHelper_SendReq(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req            *pbin.Request
+		binPath        string
+		childMode      string
+		expErr         error
+		expUnavailable bool
+	}{
+		"normal request": {
+			req: &pbin.Request{
+				Method:  "ping",
+				Payload: []byte(`{"reply":"pong"}`),
+			},
+			binPath: os.Args[0],
+		},
+		"large payload": {
+			req: &pbin.Request{
+				Method:  "ping",
+				Payload: []byte(`"` + string(bytes.Repeat([]byte("x"), pbin.MaxMessageSize*4)) + `"`),
+			},
+			binPath: os.Args[0],
+		},
+		"request failure": {
+			req: &pbin.Request{
+				Method: "fail",
+			},
+			binPath: os.Args[0],
+			expErr:  errors.New("request failed"),
+		},
+		"nil request": {
+			binPath: os.Args[0],
+			expErr:  errors.New("nil request"),
+		},
+		"invalid binPath": {
+			req:            &pbin.Request{},
+			binPath:        "this is not my beautiful house",
+			expErr:         errors.New("executable file not found"),
+			expUnavailable: true,
+		},
+		"binary doesn't support persistent mode": {
+			req:            &pbin.Request{Method: "ping"},
+			binPath:        os.Args[0],
+			childMode:      childModeReqRes,
+			expErr:         errors.New("exited before responding"),
+			expUnavailable: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			if tc.childMode == "" {
+				tc.childMode = childModeServe
+			}
+			os.Setenv(childModeEnvVar, tc.childMode)
+
+			h := pbin.NewHelper(log, tc.binPath)
+			defer h.Stop()
+
+			res, err := h.SendReq(context.Background(), tc.req)
+			common.CmpErr(t, tc.expErr, err)
+			common.AssertEqual(t, tc.expUnavailable, pbin.IsHelperUnavailable(err),
+				"unexpected helper availability")
+
+			if err == nil && !bytes.Equal(tc.req.Payload, res.Payload) {
+				t.Fatalf("payloads differ: %q != %q", tc.req.Payload, res.Payload)
+			}
+		})
+	}
+}
+
+func TestHelper_Unavailable(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	os.Setenv(childModeEnvVar, childModeReqRes)
+
+	h := pbin.NewHelper(log, os.Args[0])
+	defer h.Stop()
+
+	for i := 0; i < 2; i++ {
+		_, err := h.SendReq(context.Background(), &pbin.Request{Method: "ping"})
+		if !pbin.IsHelperUnavailable(err) {
+			t.Fatalf("attempt %d: expected helper to be unavailable, got %v", i, err)
+		}
+	}
+}
+
+func TestHelper_Restart(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	os.Setenv(childModeEnvVar, childModeServe)
+
+	h := pbin.NewHelper(log, os.Args[0])
+	defer h.Stop()
+
+	firstPid := helperPid(t, h)
+	common.AssertEqual(t, firstPid, helperPid(t, h), "helper should serve multiple requests")
+
+	_, err := h.SendReq(context.Background(), &pbin.Request{Method: "exit"})
+	common.CmpErr(t, errors.New("exited before responding"), err)
+
+	secondPid := helperPid(t, h)
+	if secondPid == firstPid {
+		t.Fatal("expected helper to be restarted after it exited")
+	}
+
+	h.Stop()
+	if helperPid(t, h) == secondPid {
+		t.Fatal("expected helper to be restarted after it was stopped")
+	}
+
+	thirdPid := helperPid(t, h)
+	h.Retire()
+	if err := syscall.Kill(thirdPid, 0); err != syscall.ESRCH {
+		t.Fatalf("expected retired helper (pid %d) to have exited, got %v", thirdPid, err)
+	}
+	if helperPid(t, h) == thirdPid {
+		t.Fatal("expected helper to be restarted after it was retired")
+	}
+}
+
+func TestHelper_ContextCanceled(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	os.Setenv(childModeEnvVar, childModeServe)
+
+	h := pbin.NewHelper(log, os.Args[0])
+	defer h.Stop()
+
+	firstPid := helperPid(t, h)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := h.SendReq(ctx, &pbin.Request{Method: "hang"})
+	common.CmpErr(t, context.DeadlineExceeded, err)
+
+	if helperPid(t, h) == firstPid {
+		t.Fatal("expected a hung helper to be replaced")
+	}
+}
+
+func TestHelper_Deadline(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	os.Setenv(childModeEnvVar, childModeServe)
+
+	h := pbin.NewHelper(log, os.Args[0])
+	defer h.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	res, err := h.SendReq(ctx, &pbin.Request{Method: "deadline"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotDeadline time.Time
+	if err := json.Unmarshal(res.Payload, &gotDeadline); err != nil {
+		t.Fatal(err)
+	}
+	expDeadline, _ := ctx.Deadline()
+	if !gotDeadline.Equal(expDeadline) {
+		t.Fatalf("expected helper to be given deadline %s, got %s", expDeadline, gotDeadline)
+	}
+}
+
+func TestServeRequests_Context(t *testing.T) {
+	for name, tc := range map[string]struct {
+		deadline   time.Time
+		closeConn  bool
+		expErr     error
+		expExpired bool
+	}{
+		"deadline passes": {
+			deadline:   time.Now().Add(100 * time.Millisecond),
+			expErr:     context.DeadlineExceeded,
+			expExpired: true,
+		},
+		"parent closes conn": {
+			closeConn: true,
+			expErr:    context.Canceled,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			toChildRd, toChild := io.Pipe()
+			fromChild, fromChildWr := io.Pipe()
+			childConn := pbin.NewStdioConn("child", "parent", toChildRd, fromChildWr)
+			parentConn := pbin.NewStdioConn("parent", "child", fromChild, toChild)
+
+			aborted := make(chan error, 1)
+			served := make(chan struct{})
+			go func() {
+				defer close(served)
+				pbin.ServeRequests(childConn, func(ctx context.Context, _ *pbin.Request, _ io.Writer) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}, func(_ *pbin.Request, err error) {
+					aborted <- err
+				})
+			}()
+
+			reqData, err := json.Marshal(&pbin.Request{Method: "hang", Deadline: tc.deadline})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := pbin.WriteMessage(parentConn, reqData); err != nil {
+				t.Fatal(err)
+			}
+			if tc.closeConn {
+				toChild.Close()
+			}
+
+			if tc.expExpired {
+				resData, err := pbin.ReadMessage(parentConn)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var res pbin.Response
+				if err := json.Unmarshal(resData, &res); err != nil {
+					t.Fatal(err)
+				}
+				common.AssertTrue(t, res.Expired, "expected the response to be marked as expired")
+			}
+
+			select {
+			case err := <-aborted:
+				common.CmpErr(t, tc.expErr, err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("request was not aborted")
+			}
+
+			parentConn.Close()
+			<-served
+		})
+	}
+}
+
+// benchAdmin is run by a copy of the test binary named daos_server, as
+// daos_admin refuses requests from any other parent. It sends the given
+// number of requests to daos_admin, either running it once per request
+// or as a persistent helper.
+func benchAdmin() {
+	iters, err := strconv.Atoi(os.Getenv(benchItersEnvVar))
+	if err != nil {
+		childErrExit(err)
+	}
+	adminPath := os.Getenv(benchAdminPathEnvVar)
+	log, _ := logging.NewTestLogger("bench")
+
+	sendReq := func(req *pbin.Request) (*pbin.Response, error) {
+		return pbin.ExecReq(context.Background(), log, adminPath, req)
+	}
+	if os.Getenv(benchPersistentEnvVar) != "" {
+		h := pbin.NewHelper(log, adminPath)
+		defer h.Stop()
+		sendReq = func(req *pbin.Request) (*pbin.Response, error) {
+			return h.SendReq(context.Background(), req)
+		}
+	}
+
+	for i := 0; i < iters; i++ {
+		// A request which fails (e.g. because there is no SCM) has
+		// still made the round trip through daos_admin.
+		_, err := sendReq(&pbin.Request{
+			Method:  "ScmScan",
+			Payload: []byte(`{}`),
+		})
+		if err != nil && !pbin.IsFailedRequest(err) {
+			childErrExit(err)
+		}
+	}
+}
+
+// linkAsDaosServer makes the test binary available under the name which
+// daos_admin expects its parent to have.
+func linkAsDaosServer(b *testing.B) (string, func()) {
+	b.Helper()
+
+	self, err := os.Executable()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	tmpDir, err := ioutil.TempDir("", "pbin-bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	cleanup := func() {
+		os.RemoveAll(tmpDir)
+	}
+
+	serverPath := filepath.Join(tmpDir, "daos_server")
+	if err := os.Link(self, serverPath); err != nil {
+		data, err := ioutil.ReadFile(self)
+		if err == nil {
+			err = ioutil.WriteFile(serverPath, data, 0755)
+		}
+		if err != nil {
+			cleanup()
+			b.Fatal(err)
+		}
+	}
+
+	return serverPath, cleanup
+}
+
+// benchmarkDaosAdmin measures requests to the installed daos_admin, which
+// must be setuid root. The benchmark is skipped if it can't be found.
+func benchmarkDaosAdmin(b *testing.B, persistent bool) {
+	adminPath, err := common.FindBinary(pbin.DaosAdminName)
+	if err != nil {
+		b.Skipf("%s; skipping", err)
+	}
+
+	serverPath, cleanup := linkAsDaosServer(b)
+	defer cleanup()
+
+	child := exec.Command(serverPath)
+	child.Env = append(os.Environ(),
+		childModeEnvVar+"="+childModeBenchAdmin,
+		benchAdminPathEnvVar+"="+adminPath,
+		benchItersEnvVar+"="+strconv.Itoa(b.N),
+	)
+	if persistent {
+		child.Env = append(child.Env, benchPersistentEnvVar+"=true")
+	}
+	var stderr bytes.Buffer
+	child.Stderr = &stderr
+
+	b.ResetTimer()
+	if err := child.Run(); err != nil {
+		b.Fatalf("%s: %s", err, stderr.String())
+	}
+}
+
+func BenchmarkDaosAdminExecReq(b *testing.B) {
+	benchmarkDaosAdmin(b, false)
+}
+
+func BenchmarkDaosAdminHelperSendReq(b *testing.B) {
+	benchmarkDaosAdmin(b, true)
+}
@@ -22,6 +22,8 @@
 //
 package pbin
 
+import "time"
+
 const (
 	// DaosAdminName is the name of the daos_admin privileged helper.
 	DaosAdminName = "daos_admin"
@@ -30,7 +32,22 @@ const (
 	// can be set to disable forwarding requests to the privileged binary.
 	DisableReqFwdEnvVar = "DAOS_DISABLE_REQ_FWD"
 
-	// DaosAdminLogFileEnvVar is the name of the environment variable which
-	// can be set to enable non-ERROR logging in the privileged binary.
-	DaosAdminLogFileEnvVar = "DAOS_ADMIN_LOG_FILE"
+	// PersistentHelperEnvVar is the name of the environment variable which
+	// is set when the privileged binary is started as a persistent helper
+	// serving framed requests over stdio.
+	PersistentHelperEnvVar = "DAOS_PBIN_PERSISTENT"
+
+	// DisablePersistentHelperEnvVar is the name of the environment variable
+	// which can be set to run the privileged binary once per forwarded
+	// request instead of keeping a persistent helper.
+	DisablePersistentHelperEnvVar = "DAOS_DISABLE_PERSISTENT_HELPER"
+
+	// DaosAdminTimeoutsEnvVar is the name of the environment variable which
+	// can be set to override the time allowed for forwarded requests, as a
+	// comma-separated list of method=duration pairs.
+	DaosAdminTimeoutsEnvVar = "DAOS_ADMIN_TIMEOUTS"
+
+	// DefaultRequestTimeout is the time allowed for a forwarded request
+	// when no timeout has been set for its method.
+	DefaultRequestTimeout = 5 * time.Minute
 )
@@ -34,7 +34,13 @@ const (
 	childModeEnvVar = "GO_TESTING_CHILD_MODE"
 	childModeEcho   = "MODE_ECHO"
 	childModeReqRes = "MODE_REQ_RES"
+	childModeServe  = "MODE_SERVE"
 	testMsg         = "hello world"
+
+	childModeBenchAdmin   = "MODE_BENCH_ADMIN"
+	benchAdminPathEnvVar  = "GO_TESTING_BENCH_ADMIN_PATH"
+	benchItersEnvVar      = "GO_TESTING_BENCH_ITERS"
+	benchPersistentEnvVar = "GO_TESTING_BENCH_PERSISTENT"
 )
 
 func childErrExit(err error) {
@@ -57,6 +63,12 @@ func TestMain(m *testing.M) {
 	case childModeReqRes:
 		// for exec_test
 		reqRes()
+	case childModeServe:
+		// for helper_test
+		serve()
+	case childModeBenchAdmin:
+		// for helper_test benchmarks
+		benchAdmin()
 	default:
 		childErrExit(errors.Errorf("Unknown child mode: %q", mode))
 	}
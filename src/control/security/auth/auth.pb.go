@@ -101,6 +101,8 @@ type Sys struct {
 	Group                string   `protobuf:"bytes,4,opt,name=group,proto3" json:"group,omitempty"`
 	Groups               []string `protobuf:"bytes,5,rep,name=groups,proto3" json:"groups,omitempty"`
 	Secctx               string   `protobuf:"bytes,6,opt,name=secctx,proto3" json:"secctx,omitempty"`
+	Expires              uint64   `protobuf:"varint,7,opt,name=expires,proto3" json:"expires,omitempty"`
+	Nonce                []byte   `protobuf:"bytes,8,opt,name=nonce,proto3" json:"nonce,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -173,6 +175,20 @@ func (m *Sys) GetSecctx() string {
 	return ""
 }
 
+func (m *Sys) GetExpires() uint64 {
+	if m != nil {
+		return m.Expires
+	}
+	return 0
+}
+
+func (m *Sys) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
 // Token and verifier are expected to have the same flavor type.
 type Credential struct {
 	Token                *Token   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
@@ -381,27 +397,29 @@ func init() {
 func init() { proto.RegisterFile("auth.proto", fileDescriptor_8bbd6f3875b0e874) }
 
 var fileDescriptor_8bbd6f3875b0e874 = []byte{
-	// 348 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0x4d, 0x4b, 0xeb, 0x40,
-	0x14, 0x7d, 0x69, 0x93, 0xd0, 0xde, 0xf4, 0xbd, 0x17, 0x06, 0x91, 0x2c, 0x63, 0xa8, 0x58, 0x5c,
-	0x74, 0x51, 0x17, 0xae, 0x8b, 0xf8, 0x01, 0x62, 0x85, 0x69, 0x15, 0x5c, 0xc9, 0x98, 0x4c, 0xdb,
-	0xc1, 0x36, 0x89, 0x33, 0x93, 0xa2, 0xbf, 0xc4, 0xbf, 0x2b, 0x73, 0x67, 0xa8, 0x1f, 0x60, 0x77,
-	0xf7, 0xdc, 0x73, 0xef, 0x39, 0xe7, 0x0e, 0x03, 0xc0, 0x1a, 0xbd, 0x1c, 0xd6, 0xb2, 0xd2, 0x15,
-	0xf1, 0x4d, 0x9d, 0x8d, 0x21, 0x98, 0x55, 0xcf, 0xbc, 0x24, 0x7d, 0x08, 0xe7, 0x2b, 0xb6, 0xa9,
-	0x64, 0xe2, 0xa5, 0xde, 0xe0, 0xdf, 0xa8, 0x37, 0xc4, 0xd9, 0x0b, 0xec, 0x51, 0xc7, 0x11, 0x02,
-	0x7e, 0xc1, 0x34, 0x4b, 0x5a, 0xa9, 0x37, 0xe8, 0x51, 0xac, 0xb3, 0x77, 0x0f, 0xda, 0xd3, 0x37,
-	0x45, 0xf6, 0x20, 0x50, 0x9a, 0xad, 0x6b, 0x14, 0xf0, 0xa9, 0x05, 0x24, 0x85, 0x68, 0xcd, 0xf2,
-	0xa5, 0x28, 0x79, 0xc9, 0xd6, 0x1c, 0x17, 0xbb, 0xf4, 0x6b, 0xcb, 0x68, 0x36, 0x8a, 0xcb, 0xa4,
-	0x8d, 0x14, 0xd6, 0x46, 0x6b, 0x21, 0xab, 0xa6, 0x4e, 0x7c, 0x6c, 0x5a, 0x40, 0xf6, 0x21, 0xc4,
-	0x42, 0x25, 0x41, 0xda, 0x1e, 0x74, 0xa9, 0x43, 0xa6, 0xaf, 0x78, 0x9e, 0xeb, 0xd7, 0x24, 0xc4,
-	0x71, 0x87, 0xb2, 0x1a, 0xe0, 0x4c, 0xf2, 0x82, 0x97, 0x5a, 0xb0, 0x15, 0x39, 0x80, 0x40, 0x9b,
-	0x53, 0x31, 0x5f, 0x34, 0x8a, 0xec, 0x81, 0x78, 0x3d, 0xb5, 0x0c, 0x39, 0x82, 0xce, 0x86, 0x4b,
-	0x31, 0x17, 0x5c, 0x62, 0xd2, 0x1f, 0x53, 0x5b, 0xd2, 0x38, 0x56, 0x52, 0x2c, 0x44, 0xe9, 0x52,
-	0x3b, 0x94, 0x5d, 0x43, 0x74, 0xc9, 0xb5, 0x31, 0xa5, 0x5c, 0x61, 0x60, 0xa5, 0x99, 0x6e, 0x14,
-	0x7a, 0x06, 0xd4, 0x21, 0xd2, 0x07, 0x3f, 0x97, 0xbc, 0x70, 0x1e, 0xb1, 0xf5, 0xf8, 0x8c, 0x4a,
-	0x91, 0xcd, 0x4e, 0xe1, 0xff, 0x3d, 0x5b, 0x89, 0x82, 0x69, 0x6e, 0x15, 0x5f, 0xb6, 0x8b, 0xde,
-	0xce, 0xc5, 0x1b, 0x88, 0xbf, 0x2f, 0xee, 0x88, 0xb2, 0x7d, 0x95, 0xd6, 0x6f, 0xaf, 0x72, 0x7c,
-	0x08, 0xa1, 0xfd, 0x06, 0xe4, 0x2f, 0x74, 0xc7, 0x77, 0xb3, 0xab, 0xc7, 0xc9, 0xed, 0xe4, 0x3c,
-	0xfe, 0x43, 0x7a, 0xd0, 0x41, 0x38, 0x7d, 0x98, 0xc6, 0xde, 0x53, 0x88, 0xff, 0xea, 0xe4, 0x23,
-	0x00, 0x00, 0xff, 0xff, 0x0f, 0xe4, 0xc6, 0x1f, 0x65, 0x02, 0x00, 0x00,
+	// 370 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x52, 0x4d, 0x8f, 0xd3, 0x40,
+	0x0c, 0x25, 0x6d, 0x92, 0xb6, 0x4e, 0x81, 0x68, 0x84, 0xd0, 0x1c, 0x43, 0x54, 0x44, 0xc5, 0xa1,
+	0x87, 0x72, 0xe0, 0x5c, 0x21, 0x3e, 0x24, 0x44, 0x91, 0xa6, 0x05, 0x89, 0x13, 0x1a, 0x12, 0xb7,
+	0x1d, 0xd1, 0x26, 0x61, 0x66, 0x52, 0x95, 0x5f, 0xb8, 0x7f, 0x6b, 0x35, 0x9e, 0x6c, 0x77, 0x57,
+	0xab, 0xed, 0xcd, 0xcf, 0xcf, 0x7e, 0xb6, 0x9f, 0x0c, 0x20, 0x5b, 0xbb, 0x9b, 0x35, 0xba, 0xb6,
+	0x35, 0x0b, 0x5d, 0x9c, 0x2f, 0x20, 0x5a, 0xd7, 0x7f, 0xb1, 0x62, 0x13, 0x88, 0x37, 0x7b, 0x79,
+	0xac, 0x35, 0x0f, 0xb2, 0x60, 0xfa, 0x6c, 0x3e, 0x9e, 0x51, 0xed, 0x27, 0xca, 0x89, 0x8e, 0x63,
+	0x0c, 0xc2, 0x52, 0x5a, 0xc9, 0x7b, 0x59, 0x30, 0x1d, 0x0b, 0x8a, 0xf3, 0xab, 0x00, 0xfa, 0xab,
+	0xff, 0x86, 0xbd, 0x80, 0xc8, 0x58, 0x79, 0x68, 0x48, 0x20, 0x14, 0x1e, 0xb0, 0x0c, 0x92, 0x83,
+	0x2c, 0x76, 0xaa, 0xc2, 0x4a, 0x1e, 0x90, 0x1a, 0x47, 0xe2, 0x6e, 0xca, 0x69, 0xb6, 0x06, 0x35,
+	0xef, 0x13, 0x45, 0xb1, 0xd3, 0xda, 0xea, 0xba, 0x6d, 0x78, 0x48, 0x49, 0x0f, 0xd8, 0x4b, 0x88,
+	0x29, 0x30, 0x3c, 0xca, 0xfa, 0xd3, 0x91, 0xe8, 0x90, 0xcb, 0x1b, 0x2c, 0x0a, 0x7b, 0xe2, 0x31,
+	0x95, 0x77, 0x88, 0x71, 0x18, 0xe0, 0xa9, 0x51, 0x1a, 0x0d, 0x1f, 0xd0, 0x4e, 0x37, 0xd0, 0xe9,
+	0x57, 0x75, 0x55, 0x20, 0x1f, 0xd2, 0x21, 0x1e, 0xe4, 0x0d, 0xc0, 0x07, 0x8d, 0x25, 0x56, 0x56,
+	0xc9, 0x3d, 0x7b, 0x05, 0x91, 0x75, 0xd6, 0xd0, 0x3d, 0xc9, 0x3c, 0xf1, 0x86, 0x90, 0x5b, 0xc2,
+	0x33, 0xec, 0x0d, 0x0c, 0x8f, 0xa8, 0xd5, 0x46, 0xa1, 0xe6, 0xbd, 0x87, 0x55, 0x67, 0xd2, 0x6d,
+	0x58, 0x6b, 0xb5, 0x55, 0x55, 0x77, 0x65, 0x87, 0xf2, 0xaf, 0x90, 0x7c, 0x46, 0xeb, 0x86, 0x0a,
+	0x34, 0x74, 0xa0, 0xb1, 0xd2, 0xb6, 0x86, 0x66, 0x46, 0xa2, 0x43, 0x6c, 0x02, 0x61, 0xa1, 0xb1,
+	0xec, 0x66, 0xa4, 0x7e, 0xc6, 0xed, 0xaa, 0x82, 0xd8, 0xfc, 0x3d, 0x3c, 0xff, 0x29, 0xf7, 0xaa,
+	0x94, 0x16, 0xbd, 0xe2, 0xbf, 0x73, 0x63, 0x70, 0xb1, 0xf1, 0x1b, 0xa4, 0xf7, 0x1b, 0x2f, 0xac,
+	0x72, 0x76, 0xa5, 0xf7, 0x98, 0x2b, 0x6f, 0x5f, 0x43, 0xec, 0xdf, 0x86, 0x3d, 0x85, 0xd1, 0xe2,
+	0xc7, 0xfa, 0xcb, 0xef, 0xe5, 0xf7, 0xe5, 0xc7, 0xf4, 0x09, 0x1b, 0xc3, 0x90, 0xe0, 0xea, 0xd7,
+	0x2a, 0x0d, 0xfe, 0xc4, 0xf4, 0x87, 0xef, 0xae, 0x07, 0x00, 0xf3, 0xf2, 0x37, 0x2c, 0x95, 0x02,
+	0x00, 0x00,
 }
@@ -26,8 +26,10 @@ package auth
 import (
 	"bytes"
 	"crypto"
+	"crypto/rand"
 	"os"
 	"os/user"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -35,6 +37,18 @@ import (
 	"github.com/daos-stack/daos/src/control/security"
 )
 
+const (
+	// CredentialValidity is how long an AuthSys credential may be used
+	// after it is issued.
+	CredentialValidity = 5 * time.Minute
+	// DefaultClockSkew is the default allowance for differences between
+	// the clocks of the agent issuing a credential and the server
+	// validating it.
+	DefaultClockSkew = time.Minute
+
+	nonceLength = 16
+)
+
 // User is an interface wrapping a representation of a specific system user
 type User interface {
 	Username() string
@@ -91,17 +105,30 @@ func VerifyToken(key crypto.PublicKey, token *Token, sig []byte) error {
 	return errors.Wrap(err, "token verification Failed")
 }
 
-func sysNameToPrincipalName(name string) string {
-	return name + "@"
-}
-
 // AuthSysRequestFromCreds takes the domain info credentials gathered
 // during the dRPC request and creates an AuthSys security request to obtain
 // a handle from the management service.
-func AuthSysRequestFromCreds(ext UserExt, creds *security.DomainInfo, signing crypto.PrivateKey) (*Credential, error) {
+func AuthSysRequestFromCreds(ext UserExt, mapper *IdentityMapper, creds *security.DomainInfo, signing crypto.PrivateKey) (*Credential, error) {
+	identity, err := AuthSysFromCreds(ext, mapper, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	return CredentialFromAuthSys(identity, signing)
+}
+
+// AuthSysFromCreds resolves the user and group names for the domain info
+// credentials into an AuthSys token describing the caller's identity, using
+// the mapper to turn them into ACL principal names. The returned token is
+// not stamped or signed, so it may be reused to issue any number of
+// credentials with CredentialFromAuthSys.
+func AuthSysFromCreds(ext UserExt, mapper *IdentityMapper, creds *security.DomainInfo) (*Sys, error) {
 	if creds == nil {
 		return nil, errors.New("No credentials supplied")
 	}
+	if mapper == nil {
+		mapper = &IdentityMapper{}
+	}
 
 	userInfo, err := ext.LookupUserID(creds.Uid())
 	if err != nil {
@@ -126,29 +153,63 @@ func AuthSysRequestFromCreds(ext UserExt, creds *security.DomainInfo, signing cr
 		name = "unavailable"
 	}
 
+	userPrincipal, err := mapper.UserPrincipal(creds.Uid(), userInfo.Username())
+	if err != nil {
+		return nil, err
+	}
+
+	groupPrincipal, err := mapper.GroupPrincipal(creds.Gid(), groupInfo.Name)
+	if err != nil {
+		return nil, err
+	}
+
 	var groupList = []string{}
 
 	// Convert groups to gids
 	for _, gid := range groups {
 		gInfo, err := ext.LookupGroupID(gid)
-		if err != nil {
+		if err != nil || !mapper.IncludeGroup(gInfo.Name) {
 			// Skip this group
 			continue
 		}
-		groupList = append(groupList, sysNameToPrincipalName(gInfo.Name))
+		principal, err := mapper.GroupPrincipal(gid, gInfo.Name)
+		if err != nil {
+			return nil, err
+		}
+		groupList = append(groupList, principal)
 	}
 
-	// Craft AuthToken
-	sys := Sys{
-		Stamp:       0,
+	return &Sys{
 		Machinename: name,
-		User:        sysNameToPrincipalName(userInfo.Username()),
-		Group:       sysNameToPrincipalName(groupInfo.Name),
+		User:        userPrincipal,
+		Group:       groupPrincipal,
 		Groups:      groupList,
-		Secctx:      creds.Ctx()}
+		Secctx:      creds.Ctx()}, nil
+}
+
+// CredentialFromAuthSys issues a signed credential for the identity in the
+// AuthSys token. Each credential gets its own issue time, expiry and nonce,
+// so the identity token itself is left unmodified.
+func CredentialFromAuthSys(identity *Sys, signing crypto.PrivateKey) (*Credential, error) {
+	if identity == nil {
+		return nil, errors.New("No identity supplied")
+	}
+
+	// The nonce allows the server to detect a replayed credential
+	nonce := make([]byte, nonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "Unable to generate nonce")
+	}
+	issued := time.Now()
+
+	// Craft AuthToken
+	sys := proto.Clone(identity).(*Sys)
+	sys.Stamp = uint64(issued.Unix())
+	sys.Expires = uint64(issued.Add(CredentialValidity).Unix())
+	sys.Nonce = nonce
 
 	// Marshal our AuthSys token into a byte array
-	tokenBytes, err := proto.Marshal(&sys)
+	tokenBytes, err := proto.Marshal(sys)
 	if err != nil {
 		return nil, errors.Wrap(err, "Unable to marshal AuthSys token")
 	}
@@ -187,3 +248,24 @@ func AuthSysFromAuthToken(authToken *Token) (*Sys, error) {
 	}
 	return sysToken, nil
 }
+
+// CheckValidity returns an error if the AuthSys token is not valid at the
+// given time, allowing for the given difference between the clocks of the
+// issuer and the caller.
+func (s *Sys) CheckValidity(now time.Time, skew time.Duration) error {
+	issued := time.Unix(int64(s.GetStamp()), 0)
+	expires := time.Unix(int64(s.GetExpires()), 0)
+
+	switch {
+	case len(s.GetNonce()) == 0:
+		return errors.New("credential has no nonce")
+	case now.Add(skew).Before(issued):
+		return errors.Errorf("credential issued in the future at %s",
+			issued.Format(time.RFC3339))
+	case now.Add(-skew).After(expires):
+		return errors.Errorf("credential expired at %s",
+			expires.Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -24,13 +24,19 @@
 package auth
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"os/user"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
+	"golang.org/x/crypto/ed25519"
 
 	. "github.com/daos-stack/daos/src/control/common"
 	"github.com/daos-stack/daos/src/control/security"
@@ -157,7 +163,7 @@ func TestAuthSysFromAuthToken_SucceedsWithGoodToken(t *testing.T) {
 // AuthSysRequestFromCreds tests
 
 func TestAuthSysRequestFromCreds_failsIfDomainInfoNil(t *testing.T) {
-	result, err := AuthSysRequestFromCreds(&mockExt{}, nil, nil)
+	result, err := AuthSysRequestFromCreds(&mockExt{}, nil, nil, nil)
 
 	if result != nil {
 		t.Error("Expected a nil request")
@@ -201,7 +207,7 @@ func TestAuthSysRequestFromCreds_returnsAuthSys(t *testing.T) {
 			})
 	}
 
-	result, err := AuthSysRequestFromCreds(ext, creds, nil)
+	result, err := AuthSysRequestFromCreds(ext, nil, creds, nil)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -239,6 +245,19 @@ func TestAuthSysRequestFromCreds_returnsAuthSys(t *testing.T) {
 			t.Errorf("AuthSys had bad group in list (idx %v): %v", i, group)
 		}
 	}
+
+	if len(authsys.GetNonce()) != nonceLength {
+		t.Errorf("AuthSys had bad nonce: %v", authsys.GetNonce())
+	}
+
+	if authsys.GetExpires()-authsys.GetStamp() != uint64(CredentialValidity.Seconds()) {
+		t.Errorf("AuthSys had bad validity window: %d-%d",
+			authsys.GetStamp(), authsys.GetExpires())
+	}
+
+	if err := authsys.CheckValidity(time.Now(), 0); err != nil {
+		t.Errorf("AuthSys not valid when issued: %v", err)
+	}
 }
 
 func TestAuthSysRequestFromCreds_UidLookupFails(t *testing.T) {
@@ -250,7 +269,7 @@ func TestAuthSysRequestFromCreds_UidLookupFails(t *testing.T) {
 	expectedErr := fmt.Errorf("Failed to lookup uid %v: %v", uid,
 		ext.lookupUserIDErr)
 
-	result, err := AuthSysRequestFromCreds(ext, creds, nil)
+	result, err := AuthSysRequestFromCreds(ext, nil, creds, nil)
 
 	if result != nil {
 		t.Error("Expected a nil result")
@@ -279,7 +298,7 @@ func TestAuthSysRequestFromCreds_GidLookupFails(t *testing.T) {
 	expectedErr := fmt.Errorf("Failed to lookup gid %v: %v", gid,
 		ext.lookupGroupIDErr)
 
-	result, err := AuthSysRequestFromCreds(ext, creds, nil)
+	result, err := AuthSysRequestFromCreds(ext, nil, creds, nil)
 
 	if result != nil {
 		t.Error("Expected a nil result")
@@ -315,7 +334,7 @@ func TestAuthSysRequestFromCreds_GroupIDListFails(t *testing.T) {
 		testUser.username,
 		testUser.groupIDErr)
 
-	result, err := AuthSysRequestFromCreds(ext, creds, nil)
+	result, err := AuthSysRequestFromCreds(ext, nil, creds, nil)
 
 	if result != nil {
 		t.Error("Expected a nil result")
@@ -329,3 +348,175 @@ func TestAuthSysRequestFromCreds_GroupIDListFails(t *testing.T) {
 		t.Errorf("Expected error '%v', got '%v'", expectedErr, err)
 	}
 }
+
+// VerifierFromToken/VerifyToken tests
+
+func TestVerifyToken_KeyTypes(t *testing.T) {
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := &Token{Flavor: Flavor_AUTH_SYS, Data: []byte("some data")}
+
+	for name, key := range map[string]crypto.Signer{
+		"ECDSA-P256": p256,
+		"ECDSA-P384": p384,
+		"Ed25519":    ed,
+	} {
+		t.Run(name, func(t *testing.T) {
+			sig, err := VerifierFromToken(key, token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := VerifyToken(key.Public(), token, sig); err != nil {
+				t.Fatal(err)
+			}
+
+			// a token signed by one key fails to verify with another
+			if err := VerifyToken(other.Public(), token, sig); err == nil {
+				t.Fatal("expected verification with wrong key to fail")
+			}
+		})
+	}
+}
+
+func TestSys_CheckValidity(t *testing.T) {
+	issued := time.Date(2019, 11, 20, 10, 0, 0, 0, time.UTC)
+	sys := &Sys{
+		Stamp:   uint64(issued.Unix()),
+		Expires: uint64(issued.Add(CredentialValidity).Unix()),
+		Nonce:   []byte("nonce"),
+	}
+
+	for name, tc := range map[string]struct {
+		sys    *Sys
+		now    time.Time
+		skew   time.Duration
+		expErr error
+	}{
+		"valid": {
+			sys: sys,
+			now: issued.Add(time.Minute),
+		},
+		"no nonce": {
+			sys:    &Sys{Stamp: sys.Stamp, Expires: sys.Expires},
+			now:    issued,
+			expErr: errors.New("credential has no nonce"),
+		},
+		"not yet issued": {
+			sys:    sys,
+			now:    issued.Add(-2 * time.Second),
+			skew:   time.Second,
+			expErr: errors.New("credential issued in the future at 2019-11-20T10:00:00Z"),
+		},
+		"not yet issued within skew": {
+			sys:  sys,
+			now:  issued.Add(-2 * time.Second),
+			skew: DefaultClockSkew,
+		},
+		"expired": {
+			sys:    sys,
+			now:    issued.Add(CredentialValidity + 2*time.Second),
+			skew:   time.Second,
+			expErr: errors.New("credential expired at 2019-11-20T10:05:00Z"),
+		},
+		"expired within skew": {
+			sys:  sys,
+			now:  issued.Add(CredentialValidity + 2*time.Second),
+			skew: DefaultClockSkew,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			CmpErr(t, tc.expErr, tc.sys.CheckValidity(tc.now, tc.skew))
+		})
+	}
+}
+
+func TestCredentialFromAuthSys(t *testing.T) {
+	_, err := CredentialFromAuthSys(nil, nil)
+	ExpectError(t, err, "No identity supplied", "")
+
+	identity := &Sys{
+		Machinename: "host",
+		User:        "myuser@",
+		Group:       "mygroup@",
+		Groups:      []string{"group1@"},
+		Secctx:      "test",
+	}
+
+	var nonces [][]byte
+	for i := 0; i < 2; i++ {
+		cred, err := CredentialFromAuthSys(identity, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		authsys, err := AuthSysFromAuthToken(cred.GetToken())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if authsys.GetUser() != identity.GetUser() {
+			t.Errorf("AuthSys had bad username: %v", authsys.GetUser())
+		}
+		if err := authsys.CheckValidity(time.Now(), 0); err != nil {
+			t.Errorf("AuthSys not valid when issued: %v", err)
+		}
+		nonces = append(nonces, authsys.GetNonce())
+	}
+
+	if string(nonces[0]) == string(nonces[1]) {
+		t.Error("credentials issued from the same identity share a nonce")
+	}
+	if identity.GetStamp() != 0 || len(identity.GetNonce()) != 0 {
+		t.Errorf("identity token was modified: %+v", identity)
+	}
+}
+
+func TestAuthSysFromCreds_IdentityMapping(t *testing.T) {
+	ext := &mockExt{
+		lookupUserIDResult: &mockUser{
+			username: "alice",
+			groupIDs: []uint32{1, 2, 3},
+		},
+		lookupGroupIDResults: []*user.Group{
+			{Name: "users"},
+			{Name: "staff"},
+			{Name: "wheel"},
+			{Name: "docker"},
+		},
+	}
+	gid := uint32(3)
+	mapper, err := NewIdentityMapper(&IdentityMapConfig{
+		Domain: "CORP",
+		Groups: []PrincipalRule{
+			{ID: &gid, Principal: "containers@LOCAL"},
+		},
+		ExcludeGroups: []string{"wheel"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sys, err := AuthSysFromCreds(ext, mapper, getTestCreds(15, 2001))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	AssertEqual(t, sys.GetUser(), "alice@CORP", "bad user principal")
+	AssertEqual(t, sys.GetGroup(), "users@CORP", "bad group principal")
+	AssertStringsEqual(t, sys.GetGroups(), []string{"staff@CORP", "containers@LOCAL"},
+		"bad group principal list")
+}
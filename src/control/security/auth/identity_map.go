@@ -0,0 +1,199 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package auth
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxPrincipalLen is the longest ACL principal name accepted by the server.
+const maxPrincipalLen = 255
+
+// PrincipalRule maps local users or groups to an ACL principal name. A rule
+// matches either an explicit uid/gid or the local names matching a regular
+// expression. The principal may refer to submatches of the expression as
+// $1, ${name} etc. A principal without an "@" gets the default domain.
+type PrincipalRule struct {
+	ID        *uint32 `yaml:"id,omitempty"`
+	Match     string  `yaml:"match,omitempty"`
+	Principal string  `yaml:"principal"`
+}
+
+// IdentityMapConfig describes how the local user and group names of a client
+// are turned into the ACL principal names in its AuthSys credential.
+type IdentityMapConfig struct {
+	// Domain is appended to names that no rule gives a domain.
+	Domain string          `yaml:"domain,omitempty"`
+	Users  []PrincipalRule `yaml:"users,omitempty"`
+	Groups []PrincipalRule `yaml:"groups,omitempty"`
+	// IncludeGroups and ExcludeGroups are regular expressions matched
+	// against local supplementary group names. If any are included only
+	// matching groups are kept, and excluded groups are always dropped.
+	IncludeGroups []string `yaml:"include_groups,omitempty"`
+	ExcludeGroups []string `yaml:"exclude_groups,omitempty"`
+}
+
+type principalRule struct {
+	id        *uint32
+	re        *regexp.Regexp
+	principal string
+}
+
+// IdentityMapper turns local user and group names into ACL principal names.
+// The zero value maps "name" to "name@".
+type IdentityMapper struct {
+	domain  string
+	users   []principalRule
+	groups  []principalRule
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// compileName compiles a regular expression that must match a whole name.
+func compileName(expr string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + expr + ")$")
+}
+
+func compileRules(kind string, rules []PrincipalRule) ([]principalRule, error) {
+	compiled := make([]principalRule, 0, len(rules))
+	for i, rule := range rules {
+		if rule.Principal == "" {
+			return nil, errors.Errorf("%s rule %d has no principal", kind, i)
+		}
+		if (rule.ID == nil) == (rule.Match == "") {
+			return nil, errors.Errorf("%s rule %d must have exactly one of id or match", kind, i)
+		}
+
+		cr := principalRule{id: rule.ID, principal: rule.Principal}
+		if rule.Match != "" {
+			re, err := compileName(rule.Match)
+			if err != nil {
+				return nil, errors.Wrapf(err, "%s rule %d", kind, i)
+			}
+			cr.re = re
+		}
+		compiled = append(compiled, cr)
+	}
+
+	return compiled, nil
+}
+
+func compileFilters(kind string, exprs []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(exprs))
+	for _, expr := range exprs {
+		re, err := compileName(expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s filter", kind)
+		}
+		compiled = append(compiled, re)
+	}
+
+	return compiled, nil
+}
+
+// NewIdentityMapper validates the identity mapping configuration and returns
+// a mapper for it. A nil configuration gives the default mapping.
+func NewIdentityMapper(cfg *IdentityMapConfig) (*IdentityMapper, error) {
+	if cfg == nil {
+		return &IdentityMapper{}, nil
+	}
+	if strings.Contains(cfg.Domain, "@") {
+		return nil, errors.Errorf("domain %q must not contain '@'", cfg.Domain)
+	}
+
+	var err error
+	m := &IdentityMapper{domain: cfg.Domain}
+	if m.users, err = compileRules("user", cfg.Users); err != nil {
+		return nil, err
+	}
+	if m.groups, err = compileRules("group", cfg.Groups); err != nil {
+		return nil, err
+	}
+	if m.include, err = compileFilters("include_groups", cfg.IncludeGroups); err != nil {
+		return nil, err
+	}
+	if m.exclude, err = compileFilters("exclude_groups", cfg.ExcludeGroups); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *IdentityMapper) principal(rules []principalRule, id uint32, name string) (string, error) {
+	principal := name
+	for _, rule := range rules {
+		if rule.id != nil {
+			if *rule.id != id {
+				continue
+			}
+			principal = rule.principal
+			break
+		}
+		if match := rule.re.FindStringSubmatchIndex(name); match != nil {
+			principal = string(rule.re.ExpandString(nil, rule.principal, name, match))
+			break
+		}
+	}
+
+	if !strings.Contains(principal, "@") {
+		principal += "@" + m.domain
+	}
+	if principal[0] == '@' || len(principal) > maxPrincipalLen {
+		return "", errors.Errorf("invalid principal %q for %q (id %d)", principal, name, id)
+	}
+
+	return principal, nil
+}
+
+// UserPrincipal returns the ACL principal name for the local user.
+func (m *IdentityMapper) UserPrincipal(uid uint32, name string) (string, error) {
+	return m.principal(m.users, uid, name)
+}
+
+// GroupPrincipal returns the ACL principal name for the local group.
+func (m *IdentityMapper) GroupPrincipal(gid uint32, name string) (string, error) {
+	return m.principal(m.groups, gid, name)
+}
+
+// IncludeGroup reports whether the local supplementary group passes the
+// group filters. The primary group is never filtered.
+func (m *IdentityMapper) IncludeGroup(name string) bool {
+	for _, re := range m.exclude {
+		if re.MatchString(name) {
+			return false
+		}
+	}
+	if len(m.include) == 0 {
+		return true
+	}
+	for _, re := range m.include {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
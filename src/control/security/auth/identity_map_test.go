@@ -0,0 +1,208 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func uint32Ptr(v uint32) *uint32 {
+	return &v
+}
+
+func TestNewIdentityMapper(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg    *IdentityMapConfig
+		expErr error
+	}{
+		"nil config": {},
+		"valid": {
+			cfg: &IdentityMapConfig{
+				Domain: "CORP",
+				Users: []PrincipalRule{
+					{ID: uint32Ptr(0), Principal: "root@LOCAL"},
+					{Match: `(.*)_adm`, Principal: "$1@ADMIN"},
+				},
+				IncludeGroups: []string{"proj_.*"},
+			},
+		},
+		"domain with @": {
+			cfg:    &IdentityMapConfig{Domain: "CORP@"},
+			expErr: errors.New("domain \"CORP@\" must not contain '@'"),
+		},
+		"rule without principal": {
+			cfg: &IdentityMapConfig{
+				Users: []PrincipalRule{{Match: "alice"}},
+			},
+			expErr: errors.New("user rule 0 has no principal"),
+		},
+		"rule with id and match": {
+			cfg: &IdentityMapConfig{
+				Groups: []PrincipalRule{
+					{ID: uint32Ptr(1), Match: "staff", Principal: "staff@CORP"},
+				},
+			},
+			expErr: errors.New("group rule 0 must have exactly one of id or match"),
+		},
+		"rule with neither id nor match": {
+			cfg: &IdentityMapConfig{
+				Groups: []PrincipalRule{{Principal: "staff@CORP"}},
+			},
+			expErr: errors.New("group rule 0 must have exactly one of id or match"),
+		},
+		"bad rule regex": {
+			cfg: &IdentityMapConfig{
+				Users: []PrincipalRule{{Match: "(", Principal: "x"}},
+			},
+			expErr: errors.New("user rule 0: error parsing regexp"),
+		},
+		"bad filter regex": {
+			cfg:    &IdentityMapConfig{ExcludeGroups: []string{"["}},
+			expErr: errors.New("exclude_groups filter: error parsing regexp"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewIdentityMapper(tc.cfg)
+			common.CmpErr(t, tc.expErr, err)
+		})
+	}
+}
+
+func TestIdentityMapper_UserPrincipal(t *testing.T) {
+	cfg := &IdentityMapConfig{
+		Domain: "CORP",
+		Users: []PrincipalRule{
+			{ID: uint32Ptr(0), Principal: "root@LOCAL"},
+			{Match: `(?P<user>.*)_adm`, Principal: "${user}@ADMIN"},
+			{Match: `svc-.*`, Principal: "services"},
+			{Match: `long`, Principal: strings.Repeat("x", maxPrincipalLen)},
+		},
+	}
+
+	for name, tc := range map[string]struct {
+		cfg       *IdentityMapConfig
+		uid       uint32
+		user      string
+		expResult string
+		expErr    error
+	}{
+		"default mapping": {
+			uid:       1000,
+			user:      "alice",
+			expResult: "alice@",
+		},
+		"default domain": {
+			cfg:       cfg,
+			uid:       1000,
+			user:      "alice",
+			expResult: "alice@CORP",
+		},
+		"explicit uid": {
+			cfg:       cfg,
+			uid:       0,
+			user:      "root",
+			expResult: "root@LOCAL",
+		},
+		"regex with submatch": {
+			cfg:       cfg,
+			uid:       1001,
+			user:      "bob_adm",
+			expResult: "bob@ADMIN",
+		},
+		"regex matches whole name": {
+			cfg:       cfg,
+			uid:       1002,
+			user:      "bob_adm2",
+			expResult: "bob_adm2@CORP",
+		},
+		"rule principal gets default domain": {
+			cfg:       cfg,
+			uid:       1003,
+			user:      "svc-backup",
+			expResult: "services@CORP",
+		},
+		"principal too long": {
+			cfg:    cfg,
+			uid:    1004,
+			user:   "long",
+			expErr: errors.New("invalid principal"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mapper, err := NewIdentityMapper(tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := mapper.UserPrincipal(tc.uid, tc.user)
+			common.CmpErr(t, tc.expErr, err)
+			common.AssertEqual(t, result, tc.expResult, "bad principal")
+		})
+	}
+}
+
+func TestIdentityMapper_IncludeGroup(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg    *IdentityMapConfig
+		groups map[string]bool
+	}{
+		"no filters": {
+			groups: map[string]bool{"staff": true, "wheel": true},
+		},
+		"include": {
+			cfg: &IdentityMapConfig{
+				IncludeGroups: []string{"proj_.*", "staff"},
+			},
+			groups: map[string]bool{"staff": true, "proj_a": true, "wheel": false, "staff2": false},
+		},
+		"exclude": {
+			cfg: &IdentityMapConfig{
+				ExcludeGroups: []string{"wheel"},
+			},
+			groups: map[string]bool{"staff": true, "wheel": false},
+		},
+		"exclude overrides include": {
+			cfg: &IdentityMapConfig{
+				IncludeGroups: []string{"proj_.*"},
+				ExcludeGroups: []string{"proj_secret"},
+			},
+			groups: map[string]bool{"proj_a": true, "proj_secret": false},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mapper, err := NewIdentityMapper(tc.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for group, exp := range tc.groups {
+				common.AssertEqual(t, mapper.IncludeGroup(group), exp, group)
+			}
+		})
+	}
+}
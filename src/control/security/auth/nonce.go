@@ -0,0 +1,100 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultNonceCacheSize is the default number of nonces remembered by a
+// NonceCache.
+const DefaultNonceCacheSize = 65536
+
+// NonceCache records the nonces of recently validated credentials in order
+// to detect replayed credentials. Nonces are forgotten once the credential
+// they belong to has expired, as expired credentials are rejected anyway, or
+// when the cache is full, oldest first.
+type NonceCache struct {
+	sync.Mutex
+	maxEntries int
+	expiries   map[string]time.Time
+	order      []nonceEntry // in order of insertion
+}
+
+type nonceEntry struct {
+	key     string
+	expires time.Time
+}
+
+// NewNonceCache returns a NonceCache holding at most maxEntries nonces.
+func NewNonceCache(maxEntries int) *NonceCache {
+	return &NonceCache{
+		maxEntries: maxEntries,
+		expiries:   make(map[string]time.Time),
+	}
+}
+
+// prune removes expired nonces and makes room for a new one. Must be called
+// with the lock held.
+func (nc *NonceCache) prune(now time.Time) {
+	for len(nc.order) > 0 {
+		oldest := nc.order[0]
+		if len(nc.expiries) < nc.maxEntries && now.Before(oldest.expires) {
+			return
+		}
+		// the nonce may have been recorded again after expiring
+		if nc.expiries[oldest.key].Equal(oldest.expires) {
+			delete(nc.expiries, oldest.key)
+		}
+		nc.order = nc.order[1:]
+	}
+}
+
+// Add records the nonce of a credential valid until the given expiry time,
+// returning an error if the nonce has already been recorded.
+func (nc *NonceCache) Add(nonce []byte, expires, now time.Time) error {
+	nc.Lock()
+	defer nc.Unlock()
+
+	key := string(nonce)
+	if exp, found := nc.expiries[key]; found && now.Before(exp) {
+		return errors.New("credential nonce has already been used")
+	}
+
+	nc.prune(now)
+	nc.expiries[key] = expires
+	nc.order = append(nc.order, nonceEntry{key: key, expires: expires})
+
+	return nil
+}
+
+// Len returns the number of nonces in the cache.
+func (nc *NonceCache) Len() int {
+	nc.Lock()
+	defer nc.Unlock()
+
+	return len(nc.expiries)
+}
@@ -0,0 +1,109 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestNonceCache_Add(t *testing.T) {
+	now := time.Date(2019, 11, 20, 10, 0, 0, 0, time.UTC)
+	expires := now.Add(CredentialValidity)
+	errReplay := errors.New("credential nonce has already been used")
+
+	type add struct {
+		nonce   string
+		expires time.Time
+		now     time.Time
+		expErr  error
+	}
+
+	for name, tc := range map[string]struct {
+		maxEntries int
+		adds       []add
+		expLen     int
+	}{
+		"distinct nonces": {
+			maxEntries: 10,
+			adds: []add{
+				{nonce: "a", expires: expires, now: now},
+				{nonce: "b", expires: expires, now: now},
+			},
+			expLen: 2,
+		},
+		"replayed nonce": {
+			maxEntries: 10,
+			adds: []add{
+				{nonce: "a", expires: expires, now: now},
+				{nonce: "a", expires: expires, now: now.Add(time.Minute), expErr: errReplay},
+			},
+			expLen: 1,
+		},
+		"expired nonces forgotten": {
+			maxEntries: 10,
+			adds: []add{
+				{nonce: "a", expires: expires, now: now},
+				{nonce: "b", expires: expires, now: now},
+				{nonce: "c", expires: expires.Add(time.Hour), now: expires},
+			},
+			expLen: 1,
+		},
+		"nonce reused after expiry": {
+			maxEntries: 10,
+			adds: []add{
+				{nonce: "a", expires: expires, now: now},
+				{nonce: "a", expires: expires.Add(time.Hour), now: expires},
+				{nonce: "b", expires: expires.Add(time.Hour), now: expires},
+				{nonce: "a", expires: expires.Add(time.Hour), now: expires, expErr: errReplay},
+			},
+			expLen: 2,
+		},
+		"oldest evicted when full": {
+			maxEntries: 2,
+			adds: []add{
+				{nonce: "a", expires: expires, now: now},
+				{nonce: "b", expires: expires, now: now},
+				{nonce: "c", expires: expires, now: now},
+				// a was evicted so is no longer detected
+				{nonce: "a", expires: expires, now: now},
+				{nonce: "c", expires: expires, now: now, expErr: errReplay},
+			},
+			expLen: 2,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			nc := NewNonceCache(tc.maxEntries)
+
+			for _, a := range tc.adds {
+				common.CmpErr(t, a.expErr, nc.Add([]byte(a.nonce), a.expires, a.now))
+			}
+
+			common.AssertEqual(t, tc.expLen, nc.Len(), "unexpected cache size")
+		})
+	}
+}
@@ -0,0 +1,128 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package security
+
+import (
+	"crypto/x509"
+	"path"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// RoleAdmin is the role of administrative clients such as dmg.
+	RoleAdmin = "admin"
+	// RoleAgent is the role of the daos_agent.
+	RoleAgent = "agent"
+	// RoleServer is the role of other daos_server instances.
+	RoleServer = "server"
+
+	// allMethods matches any gRPC method.
+	allMethods = "*"
+)
+
+// AuthorizationPolicy maps roles to the full gRPC method names
+// (e.g. "/ctl.MgmtCtl/StorageFormat") that holders of the role may call.
+// Method names may contain shell patterns (e.g. "/mgmt.MgmtSvc/Pool*") and
+// "*" matches any method. A client certificate grants a role if its common
+// name or one of its organizational units matches the role name.
+type AuthorizationPolicy map[string][]string
+
+// DefaultAuthorizationPolicy returns the policy used when none is specified
+// in the server transport config. Administrators may call any method, agents
+// may only retrieve attach info and servers may only call methods used for
+// communication between servers.
+func DefaultAuthorizationPolicy() AuthorizationPolicy {
+	return AuthorizationPolicy{
+		RoleAdmin: {allMethods},
+		RoleAgent: {"/mgmt.MgmtSvc/GetAttachInfo"},
+		RoleServer: {
+			"/mgmt.MgmtSvc/Join",
+			"/mgmt.MgmtSvc/LeaderQuery",
+			"/mgmt.MgmtSvc/PrepShutdown",
+			"/mgmt.MgmtSvc/KillRank",
+			"/mgmt.MgmtSvc/PingRank",
+			"/mgmt.MgmtSvc/ReportEvent",
+			"/mgmt.MgmtSvc/StartRanks",
+		},
+	}
+}
+
+// Validate checks that all method patterns in the policy are well formed.
+func (p AuthorizationPolicy) Validate() error {
+	for role, methods := range p {
+		for _, method := range methods {
+			if _, err := path.Match(method, ""); err != nil {
+				return errors.Wrapf(err, "role %q method %q", role, method)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Authorize returns true if any of the roles may call the method.
+func (p AuthorizationPolicy) Authorize(roles []string, method string) bool {
+	for _, role := range roles {
+		for _, pattern := range p[role] {
+			if pattern == allMethods {
+				return true
+			}
+			if matched, _ := path.Match(pattern, method); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// CertificateRoles returns the ordered set of candidate roles for a
+// certificate, its common name and organizational units.
+func CertificateRoles(cert *x509.Certificate) []string {
+	seen := make(map[string]struct{})
+	var roles []string
+
+	for _, role := range append([]string{cert.Subject.CommonName},
+		cert.Subject.OrganizationalUnit...) {
+		if _, exists := seen[role]; role == "" || exists {
+			continue
+		}
+		seen[role] = struct{}{}
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+
+	return roles
+}
+
+// AuthPolicy returns the authorization policy specified in the config or the
+// default policy if none is specified.
+func (cfg *TransportConfig) AuthPolicy() AuthorizationPolicy {
+	if cfg.Authorization == nil {
+		return DefaultAuthorizationPolicy()
+	}
+
+	return cfg.Authorization
+}
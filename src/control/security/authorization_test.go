@@ -0,0 +1,135 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package security
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+func TestSecurity_AuthorizationPolicy_Authorize(t *testing.T) {
+	policy := DefaultAuthorizationPolicy()
+
+	for name, tc := range map[string]struct {
+		roles  []string
+		method string
+		exp    bool
+	}{
+		"admin any method": {
+			roles:  []string{RoleAdmin},
+			method: "/ctl.MgmtCtl/StorageFormat",
+			exp:    true,
+		},
+		"agent attach info": {
+			roles:  []string{RoleAgent},
+			method: "/mgmt.MgmtSvc/GetAttachInfo",
+			exp:    true,
+		},
+		"agent format": {
+			roles:  []string{RoleAgent},
+			method: "/ctl.MgmtCtl/StorageFormat",
+		},
+		"server join": {
+			roles:  []string{RoleServer},
+			method: "/mgmt.MgmtSvc/Join",
+			exp:    true,
+		},
+		"server pool destroy": {
+			roles:  []string{RoleServer},
+			method: "/mgmt.MgmtSvc/PoolDestroy",
+		},
+		"unknown role": {
+			roles:  []string{"intruder"},
+			method: "/mgmt.MgmtSvc/GetAttachInfo",
+		},
+		"any of multiple roles": {
+			roles:  []string{"intruder", RoleAgent},
+			method: "/mgmt.MgmtSvc/GetAttachInfo",
+			exp:    true,
+		},
+		"no roles": {
+			method: "/mgmt.MgmtSvc/GetAttachInfo",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			common.AssertEqual(t, tc.exp, policy.Authorize(tc.roles, tc.method),
+				"unexpected authorization result")
+		})
+	}
+}
+
+func TestSecurity_AuthorizationPolicy_Patterns(t *testing.T) {
+	policy := AuthorizationPolicy{"monitor": {"/ctl.MgmtCtl/System*", "/mgmt.MgmtSvc/PoolQuery"}}
+
+	common.AssertTrue(t, policy.Authorize([]string{"monitor"}, "/ctl.MgmtCtl/SystemQuery"),
+		"expected pattern match")
+	common.AssertTrue(t, policy.Authorize([]string{"monitor"}, "/mgmt.MgmtSvc/PoolQuery"),
+		"expected exact match")
+	common.AssertFalse(t, policy.Authorize([]string{"monitor"}, "/ctl.MgmtCtl/StorageScan"),
+		"unexpected match")
+
+	if err := policy.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	bad := AuthorizationPolicy{"monitor": {"/ctl.MgmtCtl/[System"}}
+	common.CmpErr(t, errors.New("syntax error in pattern"), bad.Validate())
+}
+
+func TestSecurity_CertificateRoles(t *testing.T) {
+	cert := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:         "node1",
+			OrganizationalUnit: []string{RoleServer, "", RoleAgent, RoleServer},
+		},
+	}
+
+	if diff := cmp.Diff([]string{RoleAgent, "node1", RoleServer}, CertificateRoles(cert)); diff != "" {
+		t.Fatalf("unexpected roles (-want, +got):\n%s\n", diff)
+	}
+
+	agentCert, err := LoadCertificate("testdata/certs/agent.crt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{RoleAgent}, CertificateRoles(agentCert)); diff != "" {
+		t.Fatalf("unexpected roles (-want, +got):\n%s\n", diff)
+	}
+}
+
+func TestSecurity_TransportConfig_AuthPolicy(t *testing.T) {
+	tc := DefaultServerTransportConfig()
+	if diff := cmp.Diff(DefaultAuthorizationPolicy(), tc.AuthPolicy()); diff != "" {
+		t.Fatalf("unexpected default policy (-want, +got):\n%s\n", diff)
+	}
+
+	tc.Authorization = AuthorizationPolicy{RoleAdmin: {"*"}}
+	if diff := cmp.Diff(tc.Authorization, tc.AuthPolicy()); diff != "" {
+		t.Fatalf("unexpected policy (-want, +got):\n%s\n", diff)
+	}
+}
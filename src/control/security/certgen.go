@@ -0,0 +1,176 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// CertFilePerm is the mode of generated certificate files.
+	CertFilePerm os.FileMode = 0644
+	// KeyFilePerm is the mode of generated private key files.
+	KeyFilePerm os.FileMode = 0400
+
+	certOrganization = "DAOS"
+)
+
+// CertKeyPair is a generated certificate and its private key.
+type CertKeyPair struct {
+	Cert *x509.Certificate
+	Key  *rsa.PrivateKey
+}
+
+func newSerialNumber() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	return serial, errors.Wrap(err, "generate serial number")
+}
+
+// newCertKeyPair generates a key and a certificate from the template signed
+// by the parent, or self-signed if parent is nil.
+func newCertKeyPair(template *x509.Certificate, parent *CertKeyPair, keyBits int) (*CertKeyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, errors.Wrap(err, "generate private key")
+	}
+
+	template.SerialNumber, err = newSerialNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	signer, signerCert := key, template
+	if parent != nil {
+		signer, signerCert = parent.Key, parent.Cert
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signerCert,
+		&key.PublicKey, signer)
+	if err != nil {
+		return nil, errors.Wrap(err, "create certificate")
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse certificate")
+	}
+
+	return &CertKeyPair{Cert: cert, Key: key}, nil
+}
+
+// GenerateCA returns a self-signed certificate authority for signing the
+// certificates used by DAOS components.
+func GenerateCA(commonName string, keyBits int, validity time.Duration) (*CertKeyPair, error) {
+	now := time.Now()
+
+	return newCertKeyPair(&x509.Certificate{
+		Subject: pkix.Name{
+			Organization: []string{certOrganization},
+			CommonName:   commonName,
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, nil, keyBits)
+}
+
+// Issue returns a certificate signed by the CA for a component with the
+// given role. The role is set as the organizational unit so that it is
+// recognized by the authorization policy. Server certificates may be used to
+// both accept and make connections and carry the DNS names in their SANs.
+func (ca *CertKeyPair) Issue(commonName, role string, dnsNames []string, keyBits int, validity time.Duration) (*CertKeyPair, error) {
+	if !ca.Cert.IsCA {
+		return nil, errors.New("issuing certificate is not a CA")
+	}
+
+	extKeyUsage := []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	if role == RoleServer {
+		extKeyUsage = append(extKeyUsage, x509.ExtKeyUsageServerAuth)
+	}
+
+	now := time.Now()
+
+	return newCertKeyPair(&x509.Certificate{
+		Subject: pkix.Name{
+			Organization:       []string{certOrganization},
+			OrganizationalUnit: []string{role},
+			CommonName:         commonName,
+		},
+		DNSNames:              dnsNames,
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(validity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           extKeyUsage,
+		BasicConstraintsValid: true,
+	}, ca, keyBits)
+}
+
+// writePEMFile writes a single PEM block to a new file with the given mode,
+// refusing to overwrite an existing file.
+func writePEMFile(path string, block *pem.Block, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if err := pem.Encode(f, block); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "write %s", path)
+	}
+
+	return f.Close()
+}
+
+// WriteCert writes the PEM encoded certificate to a new file.
+func (kp *CertKeyPair) WriteCert(certPath string) error {
+	return writePEMFile(certPath,
+		&pem.Block{Type: "CERTIFICATE", Bytes: kp.Cert.Raw}, CertFilePerm)
+}
+
+// WriteKey writes the PEM encoded private key to a new file.
+func (kp *CertKeyPair) WriteKey(keyPath string) error {
+	return writePEMFile(keyPath,
+		&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(kp.Key)},
+		KeyFilePerm)
+}
+
+// Write writes the PEM encoded certificate and private key to new files
+// with permissions accepted when they are loaded.
+func (kp *CertKeyPair) Write(certPath, keyPath string) error {
+	if err := kp.WriteCert(certPath); err != nil {
+		return err
+	}
+
+	return kp.WriteKey(keyPath)
+}
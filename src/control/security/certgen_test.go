@@ -0,0 +1,136 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package security
+
+import (
+	"crypto/x509"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+)
+
+// testKeyBits keeps key generation fast in tests.
+const testKeyBits = 1024
+
+func TestCertKeyPair_Issue(t *testing.T) {
+	ca, err := GenerateCA("test CA", testKeyBits, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+
+	for name, tc := range map[string]struct {
+		issuer    *CertKeyPair
+		cn        string
+		role      string
+		dnsNames  []string
+		usage     x509.ExtKeyUsage
+		verifyDNS string
+		expRoles  []string
+		expErr    error
+	}{
+		"not a CA": {
+			issuer: &CertKeyPair{Cert: &x509.Certificate{}},
+			cn:     "admin",
+			role:   RoleAdmin,
+			expErr: errors.New("not a CA"),
+		},
+		"admin": {
+			cn:       "admin",
+			role:     RoleAdmin,
+			usage:    x509.ExtKeyUsageClientAuth,
+			expRoles: []string{RoleAdmin},
+		},
+		"host server": {
+			cn:        "node1",
+			role:      RoleServer,
+			dnsNames:  []string{RoleServer, "node1"},
+			usage:     x509.ExtKeyUsageServerAuth,
+			verifyDNS: RoleServer,
+			expRoles:  []string{"node1", RoleServer},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			issuer := ca
+			if tc.issuer != nil {
+				issuer = tc.issuer
+			}
+
+			kp, err := issuer.Issue(tc.cn, tc.role, tc.dnsNames, testKeyBits, time.Hour)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			if _, err := kp.Cert.Verify(x509.VerifyOptions{
+				DNSName:   tc.verifyDNS,
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{tc.usage},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.expRoles, CertificateRoles(kp.Cert)); diff != "" {
+				t.Fatalf("unexpected roles (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestCertKeyPair_Write(t *testing.T) {
+	tmpDir, cleanup := common.CreateTestDir(t)
+	defer cleanup()
+
+	ca, err := GenerateCA("test CA", testKeyBits, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPath := filepath.Join(tmpDir, "ca.crt")
+	keyPath := filepath.Join(tmpDir, "ca.key")
+	if err := ca.Write(certPath, keyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := LoadCertificate(certPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cert.Equal(ca.Cert) {
+		t.Fatal("loaded certificate does not match generated certificate")
+	}
+	if _, err := LoadPrivateKey(keyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// existing files are never overwritten
+	if err := ca.Write(certPath, keyPath); err == nil {
+		t.Fatal("expected error when overwriting existing files")
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"crypto"
 	"crypto/tls"
 	"crypto/x509"
+	"sync"
 
 	"github.com/pkg/errors"
 )
@@ -45,7 +46,8 @@ const (
 //TransportConfig contains all the information on whether or not to use
 //certificates and their location if their use is specified.
 type TransportConfig struct {
-	AllowInsecure     bool `yaml:"allow_insecure"`
+	AllowInsecure     bool                `yaml:"allow_insecure"`
+	Authorization     AuthorizationPolicy `yaml:"authorization,omitempty"`
 	CertificateConfig `yaml:",inline"`
 }
 
@@ -58,6 +60,7 @@ type CertificateConfig struct {
 	CARootPath      string           `yaml:"ca_cert"`
 	CertificatePath string           `yaml:"cert"`
 	PrivateKeyPath  string           `yaml:"key"`
+	certLock        sync.RWMutex     `yaml:"-"`
 	tlsKeypair      *tls.Certificate `yaml:"-"`
 	caPool          *x509.CertPool   `yaml:"-"`
 }
@@ -104,34 +107,70 @@ func (cfg *TransportConfig) PreLoadCertData() error {
 	if cfg == nil {
 		return errors.New("nil TransportConfig")
 	}
-	if cfg.tlsKeypair != nil && cfg.caPool != nil || cfg.AllowInsecure == true {
+	if cfg.AllowInsecure {
+		return nil
+	}
+
+	cfg.certLock.Lock()
+	defer cfg.certLock.Unlock()
+
+	if cfg.tlsKeypair != nil && cfg.caPool != nil {
 		// In this case the data is already preloaded.
-		// In order to reload data use ReloadCertDatA
+		// In order to reload data use ReloadCertData
 		return nil
 	}
+
+	return cfg.loadCertData()
+}
+
+// loadCertData loads the certificate files and replaces the stored data only
+// if they are all valid. Must be called with certLock held for writing.
+func (cfg *TransportConfig) loadCertData() error {
 	certificate, certPool, err := loadCertWithCustomCA(cfg.CARootPath, cfg.CertificatePath, cfg.PrivateKeyPath)
 	if err != nil {
 		return err
 	}
 
-	cfg.tlsKeypair = certificate
-	cfg.caPool = certPool
-
 	// Pre-parse the Leaf Certificate
-	cfg.tlsKeypair.Leaf, err = x509.ParseCertificate(cfg.tlsKeypair.Certificate[0])
+	certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0])
 	if err != nil {
 		return err
 	}
 
+	cfg.tlsKeypair = certificate
+	cfg.caPool = certPool
+
 	return nil
 }
 
 //ReloadCertData reloads and stores the certificate data in the case when
-//certificate data has changed since initial loading.
+//certificate data has changed since initial loading. If the new data cannot be
+//loaded the previously loaded data remains in use.
 func (cfg *TransportConfig) ReloadCertData() error {
-	cfg.tlsKeypair = nil
-	cfg.caPool = nil
-	return cfg.PreLoadCertData()
+	if cfg == nil {
+		return errors.New("nil TransportConfig")
+	}
+	if cfg.AllowInsecure {
+		return nil
+	}
+
+	cfg.certLock.Lock()
+	defer cfg.certLock.Unlock()
+
+	return cfg.loadCertData()
+}
+
+// certData returns the currently loaded key pair and CA pool, loading them
+// first if necessary.
+func (cfg *TransportConfig) certData() (*tls.Certificate, *x509.CertPool, error) {
+	if err := cfg.PreLoadCertData(); err != nil {
+		return nil, nil, err
+	}
+
+	cfg.certLock.RLock()
+	defer cfg.certLock.RUnlock()
+
+	return cfg.tlsKeypair, cfg.caPool, nil
 }
 
 //PrivateKey returns the private key stored in the certificates loaded into the TransportConfig
@@ -140,13 +179,11 @@ func (cfg *TransportConfig) PrivateKey() (crypto.PrivateKey, error) {
 		return nil, nil
 	}
 	// If we don't have our keys loaded attempt to load them.
-	if cfg.tlsKeypair == nil || cfg.caPool == nil {
-		err := cfg.ReloadCertData()
-		if err != nil {
-			return nil, err
-		}
+	keypair, _, err := cfg.certData()
+	if err != nil {
+		return nil, err
 	}
-	return cfg.tlsKeypair.PrivateKey, nil
+	return keypair.PrivateKey, nil
 }
 
 //PublicKey returns the private key stored in the certificates loaded into the TransportConfig
@@ -155,11 +192,33 @@ func (cfg *TransportConfig) PublicKey() (crypto.PublicKey, error) {
 		return nil, nil
 	}
 	// If we don't have our keys loaded attempt to load them.
-	if cfg.tlsKeypair == nil || cfg.caPool == nil {
-		err := cfg.ReloadCertData()
-		if err != nil {
-			return nil, err
-		}
+	keypair, _, err := cfg.certData()
+	if err != nil {
+		return nil, err
+	}
+	return keypair.Leaf.PublicKey, nil
+}
+
+// Certificates returns the loaded certificate and the root of its verified
+// chain, i.e. the CA certificate that issued it.
+func (cfg *TransportConfig) Certificates() (cert, ca *x509.Certificate, err error) {
+	if cfg.AllowInsecure {
+		return nil, nil, nil
+	}
+
+	keypair, pool, err := cfg.certData()
+	if err != nil {
+		return nil, nil, err
 	}
-	return cfg.tlsKeypair.Leaf.PublicKey, nil
+
+	chains, err := keypair.Leaf.Verify(x509.VerifyOptions{
+		Roots:     pool,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return keypair.Leaf, nil, errors.Wrap(err, "verify certificate")
+	}
+	chain := chains[0]
+
+	return keypair.Leaf, chain[len(chain)-1], nil
 }
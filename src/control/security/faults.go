@@ -0,0 +1,51 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package security
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/daos-stack/daos/src/control/fault"
+	"github.com/daos-stack/daos/src/control/fault/code"
+)
+
+// FaultMethodUnauthorized indicates that the identity presented by the
+// caller does not map to a role permitted to call the method.
+func FaultMethodUnauthorized(method, identity string, roles []string) *fault.Fault {
+	return securityFault(
+		code.SecurityMethodUnauthorized,
+		fmt.Sprintf("%s (roles: %s) is not authorized to call %s",
+			identity, strings.Join(roles, ","), method),
+		"use a certificate for a role permitted to call the method or update the authorization policy in the server transport config",
+	)
+}
+
+func securityFault(code code.Code, desc, res string) *fault.Fault {
+	return &fault.Fault{
+		Domain:      "security",
+		Code:        code,
+		Description: desc,
+		Resolution:  res,
+	}
+}
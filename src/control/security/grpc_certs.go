@@ -25,67 +25,157 @@ package security
 
 import (
 	"crypto/tls"
+	"net"
 
 	"github.com/pkg/errors"
+	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
 
-func GetServerTransportCredentials(cfg *TransportConfig) (credentials.TransportCredentials, error) {
-	if cfg == nil {
-		return nil, errors.New("nil TransportConfig")
-	}
+// cipherSuites are the TLS 1.2 cipher suites accepted by DAOS components. The
+// ECDHE_ECDSA suites are required for ECDSA and Ed25519 certificates.
+var cipherSuites = []uint16{
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+}
 
-	if cfg.tlsKeypair == nil || cfg.caPool == nil {
-		err := cfg.PreLoadCertData()
-		if err != nil {
-			return nil, err
-		}
+// serverTLSConfig returns a TLS configuration built from the currently
+// loaded certificate data.
+func (cfg *TransportConfig) serverTLSConfig() (*tls.Config, error) {
+	keypair, pool, err := cfg.certData()
+	if err != nil {
+		return nil, err
 	}
 
-	tlsConfig := tls.Config{
+	return &tls.Config{
 		ClientAuth:               tls.RequireAndVerifyClientCert,
-		Certificates:             []tls.Certificate{*cfg.tlsKeypair},
-		ClientCAs:                cfg.caPool,
+		Certificates:             []tls.Certificate{*keypair},
+		ClientCAs:                pool,
 		MinVersion:               tls.VersionTLS12,
 		MaxVersion:               tls.VersionTLS12,
 		PreferServerCipherSuites: true,
-		CipherSuites: []uint16{
-			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
-		},
+		CipherSuites:             cipherSuites,
+		// GetConfigForClient replaces the whole configuration, so
+		// the protocol set by gRPC must be repeated here.
+		NextProtos: []string{"h2"},
+	}, nil
+}
+
+// clientTLSConfig returns a TLS configuration built from the currently
+// loaded certificate data.
+func (cfg *TransportConfig) clientTLSConfig() (*tls.Config, error) {
+	keypair, pool, err := cfg.certData()
+	if err != nil {
+		return nil, err
 	}
-	creds := credentials.NewTLS(&tlsConfig)
-	return creds, nil
+
+	return &tls.Config{
+		ServerName:               cfg.ServerName,
+		Certificates:             []tls.Certificate{*keypair},
+		RootCAs:                  pool,
+		MinVersion:               tls.VersionTLS12,
+		MaxVersion:               tls.VersionTLS12,
+		PreferServerCipherSuites: true,
+		CipherSuites:             cipherSuites,
+	}, nil
 }
 
-func GetClientTransportCredentials(cfg *TransportConfig) (credentials.TransportCredentials, error) {
+// GetServerTransportCredentials returns credentials that select the current
+// certificate data for each incoming connection, so that certificates
+// reloaded with ReloadCertData take effect without restarting the server.
+func GetServerTransportCredentials(cfg *TransportConfig) (credentials.TransportCredentials, error) {
 	if cfg == nil {
 		return nil, errors.New("nil TransportConfig")
 	}
 
-	if cfg.tlsKeypair == nil || cfg.caPool == nil {
-		err := cfg.PreLoadCertData()
-		if err != nil {
-			return nil, err
-		}
+	// Detect certificate errors before first use.
+	if _, err := cfg.serverTLSConfig(); err != nil {
+		return nil, err
 	}
 
 	tlsConfig := tls.Config{
-		ServerName:               cfg.ServerName,
-		Certificates:             []tls.Certificate{*cfg.tlsKeypair},
-		RootCAs:                  cfg.caPool,
-		MinVersion:               tls.VersionTLS12,
-		MaxVersion:               tls.VersionTLS12,
-		PreferServerCipherSuites: true,
-		CipherSuites: []uint16{
-			tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return cfg.serverTLSConfig()
 		},
 	}
 	creds := credentials.NewTLS(&tlsConfig)
 	return creds, nil
 }
+
+// reloadingClientCredentials builds TLS credentials from the current
+// certificate data of the TransportConfig for each handshake, so that new
+// connections use certificates reloaded with ReloadCertData.
+type reloadingClientCredentials struct {
+	cfg                *TransportConfig
+	serverNameOverride string
+}
+
+func (c *reloadingClientCredentials) current() (credentials.TransportCredentials, error) {
+	tlsConfig, err := c.cfg.clientTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if c.serverNameOverride != "" {
+		tlsConfig.ServerName = c.serverNameOverride
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+func (c *reloadingClientCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	creds, err := c.current()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return creds.ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *reloadingClientCredentials) ServerHandshake(rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("client credentials used for server handshake")
+}
+
+func (c *reloadingClientCredentials) Info() credentials.ProtocolInfo {
+	serverName := c.cfg.ServerName
+	if c.serverNameOverride != "" {
+		serverName = c.serverNameOverride
+	}
+
+	return credentials.ProtocolInfo{
+		SecurityProtocol: "tls",
+		SecurityVersion:  "1.2",
+		ServerName:       serverName,
+	}
+}
+
+func (c *reloadingClientCredentials) Clone() credentials.TransportCredentials {
+	clone := *c
+	return &clone
+}
+
+func (c *reloadingClientCredentials) OverrideServerName(serverNameOverride string) error {
+	c.serverNameOverride = serverNameOverride
+	return nil
+}
+
+// GetClientTransportCredentials returns credentials that use the current
+// certificate data for each outgoing connection.
+func GetClientTransportCredentials(cfg *TransportConfig) (credentials.TransportCredentials, error) {
+	if cfg == nil {
+		return nil, errors.New("nil TransportConfig")
+	}
+
+	// Detect certificate errors before first use.
+	if _, err := cfg.clientTLSConfig(); err != nil {
+		return nil, err
+	}
+
+	return &reloadingClientCredentials{cfg: cfg}, nil
+}
+
 func ServerOptionForTransportConfig(cfg *TransportConfig) (grpc.ServerOption, error) {
 	if cfg == nil {
 		return nil, errors.New("nil TransportConfig")
@@ -25,9 +25,12 @@ package security
 
 import (
 	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/pem"
 	"fmt"
 	"io/ioutil"
@@ -35,6 +38,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
 )
 
 const (
@@ -176,18 +180,77 @@ func LoadPrivateKey(keyPath string) (crypto.PrivateKey, error) {
 	// Post OpenSSL 1.0.0 default key packaging
 	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
 	if err == nil {
-		switch key := key.(type) {
-		// TODO: Support key types other than RSA
-		case *rsa.PrivateKey:
-			return key, nil
-		default:
+		if !isSupportedKey(key) {
 			return nil, fmt.Errorf("%s contains an unsupported private key type",
 				keyPath)
 		}
+		return key, nil
+	}
+
+	// PKCS #8 Ed25519 keys are not understood by crypto/x509 before Go 1.13
+	edKey, err := parseEd25519PKCS8(block.Bytes)
+	if err == nil {
+		return edKey, nil
+	}
+
+	// SEC 1 EC key packaging
+	ecKey, err := x509.ParseECPrivateKey(block.Bytes)
+	if err == nil {
+		if !isSupportedKey(ecKey) {
+			return nil, fmt.Errorf("%s contains an unsupported private key type",
+				keyPath)
+		}
+		return ecKey, nil
 	}
 	return nil, fmt.Errorf("Invalid key data in PRIVATE KEY block")
 }
 
+// oidEd25519 is the algorithm identifier of Ed25519 keys (RFC 8410).
+var oidEd25519 = asn1.ObjectIdentifier{1, 3, 101, 112}
+
+// pkcs8 is the ASN.1 structure of an unencrypted PKCS #8 private key.
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// parseEd25519PKCS8 parses a PKCS #8 encoded Ed25519 private key.
+func parseEd25519PKCS8(der []byte) (ed25519.PrivateKey, error) {
+	var privKey pkcs8
+	if _, err := asn1.Unmarshal(der, &privKey); err != nil {
+		return nil, err
+	}
+	if !privKey.Algo.Algorithm.Equal(oidEd25519) {
+		return nil, errors.New("not an Ed25519 private key")
+	}
+
+	// the key is an OCTET STRING holding the seed
+	var seed []byte
+	if _, err := asn1.Unmarshal(privKey.PrivateKey, &seed); err != nil {
+		return nil, errors.Wrap(err, "invalid Ed25519 private key")
+	}
+	if len(seed) != ed25519.SeedSize {
+		return nil, errors.Errorf("invalid Ed25519 private key length %d",
+			len(seed))
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// isSupportedKey returns true if the private key may be used by TokenSigner.
+func isSupportedKey(key crypto.PrivateKey) bool {
+	switch key := key.(type) {
+	case *rsa.PrivateKey, ed25519.PrivateKey:
+		return true
+	case *ecdsa.PrivateKey:
+		_, err := ecdsaHash(key.Curve)
+		return err == nil
+	default:
+		return false
+	}
+}
+
 // ValidateCertDirectory ensures the certificate directory has safe permissions
 // set on it.
 func ValidateCertDirectory(certDir string) error {
@@ -24,10 +24,17 @@
 package security
 
 import (
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
+
+	. "github.com/daos-stack/daos/src/control/common"
 )
 
 func TestLoadPEMData(t *testing.T) {
@@ -83,6 +90,11 @@ func TestLoadPrivateKey(t *testing.T) {
 	malformedError := fmt.Sprintf("%s does not contain PEM data", malformed)
 	toomanyError := "Only one key allowed per file"
 	notkeyError := "PEM Block is not a Private Key"
+	p256Key := "testdata/certs/ecdsa-p256.key"
+	p384Key := "testdata/certs/ecdsa-p384.key"
+	p521Key := "testdata/certs/ecdsa-p521.key"
+	ed25519Key := "testdata/certs/ed25519.key"
+	p521Error := fmt.Sprintf("%s contains an unsupported private key type", p521Key)
 
 	// Setup permissions for tests below.
 	if err := os.Chmod(goodKeyPath, MaxKeyPerm); err != nil {
@@ -101,6 +113,11 @@ func TestLoadPrivateKey(t *testing.T) {
 	if err := os.Chmod(notkey, MaxKeyPerm); err != nil {
 		t.Fatal(err)
 	}
+	for _, keyPath := range []string{p256Key, p384Key, p521Key, ed25519Key} {
+		if err := os.Chmod(keyPath, MaxKeyPerm); err != nil {
+			t.Fatal(err)
+		}
+	}
 
 	testCases := []struct {
 		filename string
@@ -112,6 +129,10 @@ func TestLoadPrivateKey(t *testing.T) {
 		{malformed, "Malformed Key", malformedError},
 		{toomany, "InvalidPEMCount", toomanyError},
 		{notkey, "NotAKey", notkeyError},
+		{p256Key, "ECDSA-P256 SEC1", ""},
+		{p384Key, "ECDSA-P384 PKCS8", ""},
+		{ed25519Key, "Ed25519 PKCS8", ""},
+		{p521Key, "UnsupportedCurve", p521Error},
 	}
 
 	for _, tc := range testCases {
@@ -127,6 +148,55 @@ func TestLoadPrivateKey(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEd25519PKCS8(t *testing.T) {
+	readDER := func(t *testing.T, keyPath string) []byte {
+		t.Helper()
+		data, err := ioutil.ReadFile(keyPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, _ := pem.Decode(data)
+		if block == nil {
+			t.Fatalf("%s does not contain PEM data", keyPath)
+		}
+		return block.Bytes
+	}
+	edDER := readDER(t, "testdata/certs/ed25519.key")
+
+	for name, tc := range map[string]struct {
+		der    []byte
+		expErr error
+	}{
+		"Ed25519": {
+			der: edDER,
+		},
+		"ECDSA key": {
+			der:    readDER(t, "testdata/certs/ecdsa-p384.key"),
+			expErr: errors.New("not an Ed25519 private key"),
+		},
+		"truncated": {
+			der:    edDER[:len(edDER)-1],
+			expErr: errors.New("asn1"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			key, err := parseEd25519PKCS8(tc.der)
+			CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			// the key should match the one parsed by the standard library
+			expKey, err := LoadPrivateKey("testdata/certs/ed25519.key")
+			if err != nil {
+				t.Fatal(err)
+			}
+			AssertEqual(t, key, expKey.(ed25519.PrivateKey), "unexpected key")
+		})
+	}
+}
+
 func TestLoadCertificate(t *testing.T) {
 	goodPath := "testdata/certs/daosCA.crt"
 	badPerm := "testdata/certs/badperms.crt"
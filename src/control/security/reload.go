@@ -0,0 +1,191 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package security
+
+import (
+	"context"
+	"crypto/x509"
+	"os"
+	"time"
+
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+const (
+	// DefaultCertCheckInterval is how often certificate files are checked
+	// for changes.
+	DefaultCertCheckInterval = time.Minute
+	// DefaultCertExpiryWarning is how long before expiry warnings about a
+	// certificate start being logged.
+	DefaultCertExpiryWarning = 30 * 24 * time.Hour
+	// certExpiryWarnInterval limits how often expiry warnings are repeated.
+	certExpiryWarnInterval = 24 * time.Hour
+)
+
+// CertReloader reloads the certificate data of a TransportConfig when
+// signalled or when the certificate files change, and warns about
+// certificates that are about to expire.
+type CertReloader struct {
+	log           logging.Logger
+	cfg           *TransportConfig
+	CheckInterval time.Duration
+	ExpiryWarning time.Duration
+	modTimes      map[string]time.Time
+	lastWarning   time.Time
+}
+
+// NewCertReloader returns a CertReloader for the given TransportConfig.
+func NewCertReloader(log logging.Logger, cfg *TransportConfig) *CertReloader {
+	r := &CertReloader{
+		log:           log,
+		cfg:           cfg,
+		CheckInterval: DefaultCertCheckInterval,
+		ExpiryWarning: DefaultCertExpiryWarning,
+	}
+	r.modTimes = r.fileModTimes()
+
+	return r
+}
+
+func (r *CertReloader) certFiles() []string {
+	return []string{r.cfg.CARootPath, r.cfg.CertificatePath, r.cfg.PrivateKeyPath}
+}
+
+func (r *CertReloader) fileModTimes() map[string]time.Time {
+	modTimes := make(map[string]time.Time)
+	for _, path := range r.certFiles() {
+		if fi, err := os.Stat(path); err == nil {
+			modTimes[path] = fi.ModTime()
+		}
+	}
+
+	return modTimes
+}
+
+// filesChanged returns true if any of the certificate files have been
+// modified, created or removed since last checked.
+func (r *CertReloader) filesChanged() bool {
+	modTimes := r.fileModTimes()
+	changed := len(modTimes) != len(r.modTimes)
+	for path, modTime := range modTimes {
+		if !modTime.Equal(r.modTimes[path]) {
+			changed = true
+		}
+	}
+	r.modTimes = modTimes
+
+	return changed
+}
+
+// Reload reloads the certificate data and checks the expiry of the newly
+// loaded certificates. On failure the previously loaded data remains in use.
+func (r *CertReloader) Reload() error {
+	if err := r.cfg.ReloadCertData(); err != nil {
+		return err
+	}
+	r.log.Infof("reloaded certificate %s", r.cfg.CertificatePath)
+
+	r.lastWarning = time.Time{}
+	r.CheckExpiry(time.Now())
+
+	return nil
+}
+
+// checkCertExpiry logs a warning and returns true if the certificate expires
+// within the expiry warning period.
+func (r *CertReloader) checkCertExpiry(now time.Time, desc string, cert *x509.Certificate) bool {
+	if cert == nil {
+		return false
+	}
+
+	remaining := cert.NotAfter.Sub(now)
+	switch {
+	case remaining <= 0:
+		r.log.Errorf("%s %q expired at %s", desc, cert.Subject,
+			cert.NotAfter.Format(time.RFC3339))
+	case remaining <= r.ExpiryWarning:
+		r.log.Errorf("%s %q expires in %s at %s", desc, cert.Subject,
+			remaining.Truncate(time.Minute), cert.NotAfter.Format(time.RFC3339))
+	default:
+		return false
+	}
+
+	return true
+}
+
+// CheckExpiry logs a warning if the loaded certificate or its CA expires
+// within the expiry warning period. Warnings are repeated at most daily.
+func (r *CertReloader) CheckExpiry(now time.Time) {
+	if r.cfg.AllowInsecure || now.Sub(r.lastWarning) < certExpiryWarnInterval {
+		return
+	}
+
+	cert, ca, err := r.cfg.Certificates()
+	if err != nil {
+		r.log.Errorf("checking certificate expiry: %s", err)
+	}
+	if cert == nil {
+		return
+	}
+	certWarned := r.checkCertExpiry(now, "certificate", cert)
+	caWarned := r.checkCertExpiry(now, "CA certificate", ca)
+	if certWarned || caWarned {
+		r.lastWarning = now
+	}
+}
+
+// Start checks for certificate expiry and then reloads the certificate data
+// whenever a value is received on the reload channel or the certificate
+// files change, until the context is cancelled.
+func (r *CertReloader) Start(ctx context.Context, reload <-chan os.Signal) {
+	if r.cfg.AllowInsecure {
+		return
+	}
+
+	r.CheckExpiry(time.Now())
+
+	ticker := time.NewTicker(r.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case sig := <-reload:
+			r.log.Debugf("reloading certificates on signal %s", sig)
+			r.filesChanged()
+			if err := r.Reload(); err != nil {
+				r.log.Errorf("reloading certificates: %s", err)
+			}
+		case now := <-ticker.C:
+			if r.filesChanged() {
+				r.log.Debugf("certificate files changed")
+				if err := r.Reload(); err != nil {
+					r.log.Errorf("reloading certificates: %s", err)
+				}
+				continue
+			}
+			r.CheckExpiry(now)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package security
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+// writeTestCerts issues a server certificate with the given validity from
+// the CA and writes both into dir, replacing any existing files.
+func writeTestCerts(t *testing.T, dir string, ca *CertKeyPair, validity time.Duration) *TransportConfig {
+	t.Helper()
+
+	kp, err := ca.Issue("server", RoleServer, []string{"server"}, testKeyBits, validity)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &TransportConfig{
+		CertificateConfig: CertificateConfig{
+			ServerName:      "server",
+			CARootPath:      filepath.Join(dir, "daosCA.crt"),
+			CertificatePath: filepath.Join(dir, "server.crt"),
+			PrivateKeyPath:  filepath.Join(dir, "server.key"),
+		},
+	}
+	for _, path := range []string{cfg.CARootPath, cfg.CertificatePath, cfg.PrivateKeyPath} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			t.Fatal(err)
+		}
+	}
+	if err := ca.WriteCert(cfg.CARootPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := kp.Write(cfg.CertificatePath, cfg.PrivateKeyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg
+}
+
+func TestCertReloader_Reload(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	tmpDir, cleanup := common.CreateTestDir(t)
+	defer cleanup()
+
+	ca, err := GenerateCA("test CA", testKeyBits, 365*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := writeTestCerts(t, tmpDir, ca, 365*24*time.Hour)
+
+	creds, err := GetServerTransportCredentials(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.Info().SecurityProtocol != "tls" {
+		t.Fatalf("unexpected security protocol %q", creds.Info().SecurityProtocol)
+	}
+	before, _, err := cfg.Certificates()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewCertReloader(log, cfg)
+	if r.filesChanged() {
+		t.Fatal("files unexpectedly reported as changed")
+	}
+
+	// replace the certificate with one close to expiry
+	writeTestCerts(t, tmpDir, ca, time.Hour)
+	// ensure the modification time differs on coarse grained filesystems
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(cfg.CertificatePath, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if !r.filesChanged() {
+		t.Fatal("expected files to be reported as changed")
+	}
+
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	after, _, err := cfg.Certificates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Equal(before) {
+		t.Fatal("certificate not reloaded")
+	}
+
+	// new connections are offered the reloaded certificate
+	tlsConfig, err := cfg.serverTLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tlsConfig.Certificates[0].Leaf.Equal(after) {
+		t.Fatal("server TLS config does not use reloaded certificate")
+	}
+
+	if !strings.Contains(buf.String(), "expires in") {
+		t.Fatalf("expected expiry warning in log:\n%s", buf.String())
+	}
+
+	// a failed reload leaves the previous certificate in use
+	if err := os.Remove(cfg.PrivateKeyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Fatal("expected reload to fail with missing key")
+	}
+	current, _, err := cfg.Certificates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !current.Equal(after) {
+		t.Fatal("certificate changed after failed reload")
+	}
+}
+
+func TestCertReloader_CheckExpiry(t *testing.T) {
+	tmpDir, cleanup := common.CreateTestDir(t)
+	defer cleanup()
+
+	ca, err := GenerateCA("test CA", testKeyBits, 365*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := writeTestCerts(t, tmpDir, ca, 60*24*time.Hour)
+	now := time.Now()
+
+	for name, tc := range map[string]struct {
+		now     time.Time
+		expWarn string
+	}{
+		"valid": {
+			now: now,
+		},
+		"expiring": {
+			now:     now.Add(45 * 24 * time.Hour),
+			expWarn: "expires in",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			r := NewCertReloader(log, cfg)
+			r.CheckExpiry(tc.now)
+
+			got := buf.String()
+			if tc.expWarn == "" {
+				if got != "" {
+					t.Fatalf("unexpected log output:\n%s", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tc.expWarn) {
+				t.Fatalf("expected %q in log output:\n%s", tc.expWarn, got)
+			}
+
+			// warnings are not repeated until the interval has passed
+			buf.Reset()
+			r.CheckExpiry(tc.now.Add(time.Hour))
+			if buf.String() != "" {
+				t.Fatalf("unexpected repeated warning:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestCertReloader_Start(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	tmpDir, cleanup := common.CreateTestDir(t)
+	defer cleanup()
+
+	ca, err := GenerateCA("test CA", testKeyBits, 365*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := writeTestCerts(t, tmpDir, ca, 365*24*time.Hour)
+	if err := cfg.PreLoadCertData(); err != nil {
+		t.Fatal(err)
+	}
+	before, _, _ := cfg.Certificates()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reload := make(chan os.Signal)
+	done := make(chan struct{})
+	r := NewCertReloader(log, cfg)
+	go func() {
+		r.Start(ctx, reload)
+		close(done)
+	}()
+
+	writeTestCerts(t, tmpDir, ca, 365*24*time.Hour)
+	reload <- os.Interrupt
+	cancel()
+	<-done
+
+	after, _, _ := cfg.Certificates()
+	if after.Equal(before) {
+		t.Fatal("certificate not reloaded on signal")
+	}
+}
+
+func TestGetClientTransportCredentials(t *testing.T) {
+	tmpDir, cleanup := common.CreateTestDir(t)
+	defer cleanup()
+
+	ca, err := GenerateCA("test CA", testKeyBits, 365*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := writeTestCerts(t, tmpDir, ca, 365*24*time.Hour)
+
+	creds, err := GetClientTransportCredentials(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := creds.Clone()
+	if err := clone.OverrideServerName("other"); err != nil {
+		t.Fatal(err)
+	}
+	if creds.Info().ServerName != "server" || clone.Info().ServerName != "other" {
+		t.Fatalf("unexpected server names %q, %q",
+			creds.Info().ServerName, clone.Info().ServerName)
+	}
+
+	// the TLS configuration is built from the certificate data at the time
+	// of each handshake
+	rc := clone.(*reloadingClientCredentials)
+	current, err := rc.current()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.Info().ServerName != "other" {
+		t.Fatalf("unexpected handshake server name %q", current.Info().ServerName)
+	}
+
+	if _, err := GetClientTransportCredentials(&TransportConfig{}); err == nil {
+		t.Fatal("expected error with missing certificates")
+	}
+}
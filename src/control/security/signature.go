@@ -25,12 +25,18 @@ package security
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/sha512"
+	"encoding/asn1"
 	"io"
+	"math/big"
 
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/ed25519"
 )
 
 //UnsupportedKeyError is a structured error used to indicate that the PublicKey
@@ -64,21 +70,67 @@ func (s *TokenSigner) Hash(data []byte) ([]byte, error) {
 	return hash.Sum(nil), nil
 }
 
-//Sign takes an unhashed set of bytes and hashes and signs the result with the
-//key passed in.
-func (s *TokenSigner) Sign(key crypto.PrivateKey, data []byte) ([]byte, error) {
-	digest, err := s.Hash(data)
+// ecdsaHash returns the hash used for ECDSA signatures on the given curve, a
+// hash matching the curve size as recommended by RFC 5656.
+func ecdsaHash(curve elliptic.Curve) (crypto.Hash, error) {
+	switch curve {
+	case elliptic.P256():
+		return crypto.SHA256, nil
+	case elliptic.P384():
+		return crypto.SHA384, nil
+	default:
+		return 0, &UnsupportedKeyError{}
+	}
+}
+
+// ecdsaDigest returns the digest of the data for an ECDSA signature on the
+// given curve.
+func ecdsaDigest(curve elliptic.Curve, data []byte) ([]byte, error) {
+	hash, err := ecdsaHash(curve)
 	if err != nil {
 		return nil, err
 	}
 
+	if hash == crypto.SHA256 {
+		digest := sha256.Sum256(data)
+		return digest[:], nil
+	}
+	digest := sha512.Sum384(data)
+	return digest[:], nil
+}
+
+// ecdsaSignature is the ASN.1 structure of an ECDSA signature.
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+//Sign takes an unhashed set of bytes and hashes and signs the result with the
+//key passed in. RSA signatures are PKCS #1 v1.5 over the SHA-512 hash, ECDSA
+//signatures are ASN.1 encoded over the SHA-256 or SHA-384 hash for P-256 and
+//P-384 keys respectively, and Ed25519 signatures are over the data itself.
+func (s *TokenSigner) Sign(key crypto.PrivateKey, data []byte) ([]byte, error) {
 	if s.randPool == nil {
 		s.randPool = rand.Reader
 	}
 	switch signingKey := key.(type) {
-	// TODO: Support key types other than RSA
 	case *rsa.PrivateKey:
+		digest, err := s.Hash(data)
+		if err != nil {
+			return nil, err
+		}
 		return rsa.SignPKCS1v15(s.randPool, signingKey, crypto.SHA512, digest)
+	case *ecdsa.PrivateKey:
+		digest, err := ecdsaDigest(signingKey.Curve, data)
+		if err != nil {
+			return nil, err
+		}
+		r, sigS, err := ecdsa.Sign(s.randPool, signingKey, digest)
+		if err != nil {
+			return nil, err
+		}
+		return asn1.Marshal(ecdsaSignature{r, sigS})
+	case ed25519.PrivateKey:
+		return ed25519.Sign(signingKey, data), nil
 	default:
 		return nil, &UnsupportedKeyError{}
 	}
@@ -87,15 +139,34 @@ func (s *TokenSigner) Sign(key crypto.PrivateKey, data []byte) ([]byte, error) {
 //Verify takes an unhashed set of bytes and hashes the data and verifies the
 //signature against the hash and the publickey passed in.
 func (s *TokenSigner) Verify(key crypto.PublicKey, data []byte, sig []byte) error {
-	digest, err := s.Hash(data)
-	if err != nil {
-		return err
-	}
-
 	switch signingKey := key.(type) {
-	// TODO: Support key types other than RSA
 	case *rsa.PublicKey:
+		digest, err := s.Hash(data)
+		if err != nil {
+			return err
+		}
 		return rsa.VerifyPKCS1v15(signingKey, crypto.SHA512, digest, sig)
+	case *ecdsa.PublicKey:
+		digest, err := ecdsaDigest(signingKey.Curve, data)
+		if err != nil {
+			return err
+		}
+		var esig ecdsaSignature
+		rest, err := asn1.Unmarshal(sig, &esig)
+		if err != nil || len(rest) != 0 ||
+			esig.R == nil || esig.S == nil ||
+			esig.R.Sign() <= 0 || esig.S.Sign() <= 0 {
+			return errors.New("crypto/ecdsa: malformed signature")
+		}
+		if !ecdsa.Verify(signingKey, digest, esig.R, esig.S) {
+			return errors.New("crypto/ecdsa: verification error")
+		}
+		return nil
+	case ed25519.PublicKey:
+		if !ed25519.Verify(signingKey, data, sig) {
+			return errors.New("crypto/ed25519: verification error")
+		}
+		return nil
 	default:
 		return &UnsupportedKeyError{}
 	}
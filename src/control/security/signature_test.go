@@ -48,7 +48,8 @@ func SignTestSetup(t *testing.T) (rsaKey, ecdsaKey crypto.PrivateKey, source []b
 	if err != nil {
 		t.Fatalf("Unable to load private key for %s: %s", keyPath, err.Error())
 	}
-	ecdsaKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	// P-521 keys are not supported for signing
+	ecdsaKey, err = ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
 	if err != nil {
 		t.Fatal("Failed to generate ecdsa key for testing")
 	}
@@ -59,18 +60,39 @@ func SignTestSetup(t *testing.T) (rsaKey, ecdsaKey crypto.PrivateKey, source []b
 	return
 }
 
+func loadTestKey(t *testing.T, keyPath string) crypto.Signer {
+	t.Helper()
+
+	if err := os.Chmod(keyPath, MaxKeyPerm); err != nil {
+		t.Fatal(err)
+	}
+	key, err := LoadPrivateKey(keyPath)
+	if err != nil {
+		t.Fatalf("Unable to load private key for %s: %s", keyPath, err.Error())
+	}
+	return key.(crypto.Signer)
+}
+
 func TestSign(t *testing.T) {
 
 	rsaKey, ecdsaKey, source := SignTestSetup(t)
 	tokenSigner := DefaultTokenSigner()
 
+	p256Key := loadTestKey(t, "testdata/certs/ecdsa-p256.key")
+	p384Key := loadTestKey(t, "testdata/certs/ecdsa-p384.key")
+	ed25519Key := loadTestKey(t, "testdata/certs/ed25519.key")
+
 	testCases := []struct {
-		name string
-		data []byte
-		key  crypto.PrivateKey
+		name       string
+		data       []byte
+		key        crypto.PrivateKey
+		randomized bool // signature differs on each call
 	}{
-		{"RSA", source, rsaKey},
-		{"ImproperKey", source, ecdsaKey},
+		{"RSA", source, rsaKey, false},
+		{"ECDSA-P256", source, p256Key, true},
+		{"ECDSA-P384", source, p384Key, true},
+		{"Ed25519", source, ed25519Key, false},
+		{"ImproperKey", source, ecdsaKey, false},
 	}
 
 	for _, tc := range testCases {
@@ -86,6 +108,13 @@ func TestSign(t *testing.T) {
 					t.Errorf("failed to update golden file %s", golden)
 				}
 			}
+			if tc.randomized {
+				pub := tc.key.(crypto.Signer).Public()
+				if err := tokenSigner.Verify(pub, tc.data, result); err != nil {
+					t.Errorf("failed to verify signature: %s", err)
+				}
+				return
+			}
 			expected, err := ioutil.ReadFile(golden)
 			if err != nil {
 				t.Errorf("unable to read golden file %s", golden)
@@ -108,7 +137,8 @@ func VerifyTestSetup(t *testing.T) (rsaKey, ecdsaKey crypto.PublicKey, source []
 		t.Fatalf("Unable to load certificate for %s: %s", certPath, err.Error())
 	}
 	rsaKey = cert.PublicKey
-	gen, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	// P-521 keys are not supported for verification
+	gen, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
 	if err != nil {
 		t.Fatal("Failed to generate ecdsa key for testing")
 	}
@@ -130,6 +160,9 @@ func TestVerify(t *testing.T) {
 		key  crypto.PublicKey
 	}{
 		{"RSA", source, rsaKey},
+		{"ECDSA-P256", source, loadTestKey(t, "testdata/certs/ecdsa-p256.key").Public()},
+		{"ECDSA-P384", source, loadTestKey(t, "testdata/certs/ecdsa-p384.key").Public()},
+		{"Ed25519", source, loadTestKey(t, "testdata/certs/ed25519.key").Public()},
 		{"ImproperKey", source, ecdsaKey},
 	}
 
@@ -150,3 +183,31 @@ func TestVerify(t *testing.T) {
 		})
 	}
 }
+
+func TestVerify_BadSignature(t *testing.T) {
+	rsaKey, _, source := VerifyTestSetup(t)
+	tokenSigner := DefaultTokenSigner()
+
+	for name, key := range map[string]crypto.PublicKey{
+		"RSA":        rsaKey,
+		"ECDSA-P256": loadTestKey(t, "testdata/certs/ecdsa-p256.key").Public(),
+		"ECDSA-P384": loadTestKey(t, "testdata/certs/ecdsa-p384.key").Public(),
+		"Ed25519":    loadTestKey(t, "testdata/certs/ed25519.key").Public(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sig, err := ioutil.ReadFile(filepath.Join("testdata", "certs", name+".golden"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tokenSigner.Verify(key, append(source, '!'), sig); err == nil {
+				t.Error("expected verification of modified data to fail")
+			}
+
+			sig[len(sig)/2] ^= 0xff
+			if err := tokenSigner.Verify(key, source, sig); err == nil {
+				t.Error("expected verification of modified signature to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+// auditedMethods are the gRPC methods that modify the state of the system
+// and are recorded in the audit log.
+var auditedMethods = map[string]struct{}{
+	"/ctl.MgmtCtl/StorageFormat":     {},
+	"/ctl.MgmtCtl/StoragePrepare":    {},
+	"/ctl.MgmtCtl/SystemStop":        {},
+	"/ctl.MgmtCtl/SystemStart":       {},
+	"/ctl.MgmtCtl/SystemExclude":     {},
+	"/ctl.MgmtCtl/SystemReintegrate": {},
+	"/ctl.MgmtCtl/SetLogLevel":       {},
+	"/mgmt.MgmtSvc/PoolCreate":       {},
+	"/mgmt.MgmtSvc/PoolDestroy":      {},
+	"/mgmt.MgmtSvc/PoolSetProp":      {},
+	"/mgmt.MgmtSvc/PoolOverwriteACL": {},
+	"/mgmt.MgmtSvc/PoolUpdateACL":    {},
+	"/mgmt.MgmtSvc/PoolDeleteACL":    {},
+	"/mgmt.MgmtSvc/StorageSetFaulty": {},
+}
+
+// auditRecord describes a single call to an audited method.
+type auditRecord struct {
+	Time    string                 `json:"time"`
+	Peer    string                 `json:"peer"`
+	Subject string                 `json:"subject,omitempty"`
+	Method  string                 `json:"method"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	Status  string                 `json:"status"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// newAuditRecord returns a record of a call to method identifying the caller
+// from the peer details in the context.
+func newAuditRecord(ctx context.Context, method string) *auditRecord {
+	rec := &auditRecord{
+		Time:   time.Now().Format(time.RFC3339Nano),
+		Peer:   "unknown",
+		Method: method,
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		rec.Peer = p.Addr.String()
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if certs := tlsInfo.State.PeerCertificates; len(certs) > 0 {
+				rec.Subject = certs[0].Subject.String()
+			}
+		}
+	}
+
+	return rec
+}
+
+// setResult records the outcome of the call from its error and the
+// responses sent.
+func (rec *auditRecord) setResult(err error, resps ...interface{}) {
+	rec.Status = "ok"
+	if err != nil {
+		rec.Status = "error"
+		rec.Error = err.Error()
+		return
+	}
+
+	var errs []string
+	for _, resp := range resps {
+		errs = append(errs, responseErrors(resp)...)
+	}
+	if len(errs) > 0 {
+		rec.Status = "error"
+		rec.Error = strings.Join(errs, "; ")
+	}
+}
+
+// responseErrors returns the failures reported in the body of an audited
+// method's response, as many of them don't fail the call itself.
+func responseErrors(resp interface{}) []string {
+	var errs []string
+	daosStatus := func(status int32) {
+		if status != 0 {
+			errs = append(errs, fmt.Sprintf("DAOS status %d", status))
+		}
+	}
+	ctlState := func(desc string, state *ctlpb.ResponseState) {
+		if state.GetStatus() != ctlpb.ResponseStatus_CTL_SUCCESS {
+			errs = append(errs, fmt.Sprintf("%s: %s: %s", desc, state.GetStatus(), state.GetError()))
+		}
+	}
+	rankResults := func(results []*ctlpb.SystemStopResp_Result) {
+		for _, result := range results {
+			if result.GetErrored() {
+				errs = append(errs, fmt.Sprintf("rank %d %s: %s",
+					result.GetRank(), result.GetAction(), result.GetMsg()))
+			}
+		}
+	}
+
+	switch r := resp.(type) {
+	case *ctlpb.StorageFormatResp:
+		for _, cret := range r.GetCrets() {
+			ctlState("nvme controller "+cret.GetPciaddr(), cret.GetState())
+		}
+		for _, mret := range r.GetMrets() {
+			ctlState("scm mount "+mret.GetMntpoint(), mret.GetState())
+		}
+	case *ctlpb.StoragePrepareResp:
+		ctlState("nvme", r.GetNvme().GetState())
+		ctlState("scm", r.GetScm().GetState())
+	case *ctlpb.SystemStopResp:
+		rankResults(r.GetResults())
+	case *ctlpb.SystemExcludeResp:
+		rankResults(r.GetResults())
+	case *ctlpb.SystemReintegrateResp:
+		rankResults(r.GetResults())
+	case *ctlpb.SetLogLevelResp:
+		for _, engine := range r.GetEngines() {
+			if engine.GetError() != "" {
+				errs = append(errs, fmt.Sprintf("rank %d: %s", engine.GetRank(), engine.GetError()))
+			}
+		}
+	case *mgmtpb.PoolCreateResp:
+		daosStatus(r.GetStatus())
+	case *mgmtpb.PoolDestroyResp:
+		daosStatus(r.GetStatus())
+	case *mgmtpb.PoolSetPropResp:
+		daosStatus(r.GetStatus())
+	case *mgmtpb.ACLResp:
+		daosStatus(r.GetStatus())
+	case *mgmtpb.DevStateResp:
+		daosStatus(r.GetStatus())
+	}
+
+	return errs
+}
+
+// auditParams summarizes the parameters of an audited request.
+func auditParams(req interface{}) map[string]interface{} {
+	switch r := req.(type) {
+	case *ctlpb.StorageFormatReq:
+		return map[string]interface{}{"reformat": r.GetReformat()}
+	case *ctlpb.StoragePrepareReq:
+		params := make(map[string]interface{})
+		if nvme := r.GetNvme(); nvme != nil {
+			params["nvme_reset"] = nvme.GetReset_()
+			params["nvme_pci_whitelist"] = nvme.GetPciwhitelist()
+			params["nvme_hugepages"] = nvme.GetNrhugepages()
+			params["nvme_target_user"] = nvme.GetTargetuser()
+		}
+		if scm := r.GetScm(); scm != nil {
+			params["scm_reset"] = scm.GetReset_()
+		}
+		return params
+	case *ctlpb.SystemStopReq:
+		return map[string]interface{}{"prep": r.GetPrep(), "kill": r.GetKill()}
+	case *ctlpb.SystemExcludeReq:
+		return map[string]interface{}{"ranks": r.GetRanks()}
+	case *ctlpb.SystemReintegrateReq:
+		return map[string]interface{}{"ranks": r.GetRanks()}
+	case *ctlpb.SetLogLevelReq:
+		return map[string]interface{}{
+			"control": r.GetControl(),
+			"engine":  r.GetEngine(),
+			"ranks":   r.GetRanks(),
+		}
+	case *mgmtpb.PoolCreateReq:
+		return map[string]interface{}{
+			"uuid":       r.GetUuid(),
+			"scm_bytes":  r.GetScmbytes(),
+			"nvme_bytes": r.GetNvmebytes(),
+			"ranks":      r.GetRanks(),
+			"svc_reps":   r.GetNumsvcreps(),
+			"user":       r.GetUser(),
+			"group":      r.GetUsergroup(),
+			"acl_count":  len(r.GetAcl()),
+		}
+	case *mgmtpb.PoolDestroyReq:
+		return map[string]interface{}{"uuid": r.GetUuid(), "force": r.GetForce()}
+	case *mgmtpb.PoolSetPropReq:
+		params := map[string]interface{}{"uuid": r.GetUuid()}
+		switch r.GetProperty().(type) {
+		case *mgmtpb.PoolSetPropReq_Name:
+			params["property"] = r.GetName()
+		case *mgmtpb.PoolSetPropReq_Number:
+			params["property"] = r.GetNumber()
+		}
+		switch r.GetValue().(type) {
+		case *mgmtpb.PoolSetPropReq_Strval:
+			params["value"] = r.GetStrval()
+		case *mgmtpb.PoolSetPropReq_Numval:
+			params["value"] = r.GetNumval()
+		}
+		return params
+	case *mgmtpb.ModifyACLReq:
+		return map[string]interface{}{"uuid": r.GetUuid(), "acl": r.GetACL()}
+	case *mgmtpb.DeleteACLReq:
+		return map[string]interface{}{"uuid": r.GetUuid(), "principal": r.GetPrincipal()}
+	case *mgmtpb.DevStateReq:
+		return map[string]interface{}{"dev_uuid": r.GetDevUuid()}
+	default:
+		return nil
+	}
+}
+
+// writeAuditRecord writes the record to the audit log, failure to do so is
+// logged but doesn't affect the outcome of the call.
+func writeAuditRecord(log logging.Logger, auditLog *logging.AuditLogger, rec *auditRecord) {
+	if err := auditLog.Audit(rec); err != nil {
+		log.Errorf("writing audit record for %s: %s", rec.Method, err)
+	}
+}
+
+// unaryAuditInterceptor records calls to audited unary methods.
+func unaryAuditInterceptor(log logging.Logger, auditLog *logging.AuditLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, audited := auditedMethods[info.FullMethod]; !audited {
+			return handler(ctx, req)
+		}
+
+		rec := newAuditRecord(ctx, info.FullMethod)
+		rec.Params = auditParams(req)
+
+		resp, err := handler(ctx, req)
+		rec.setResult(err, resp)
+		writeAuditRecord(log, auditLog, rec)
+
+		return resp, err
+	}
+}
+
+// auditServerStream captures the request received and the responses sent
+// on a server stream so that they can be recorded.
+type auditServerStream struct {
+	grpc.ServerStream
+	req   interface{}
+	resps []interface{}
+}
+
+func (s *auditServerStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.resps = append(s.resps, m)
+	}
+	return err
+}
+
+func (s *auditServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil && s.req == nil {
+		s.req = m
+	}
+	return err
+}
+
+// streamAuditInterceptor records calls to audited streaming methods.
+func streamAuditInterceptor(log logging.Logger, auditLog *logging.AuditLogger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, audited := auditedMethods[info.FullMethod]; !audited {
+			return handler(srv, ss)
+		}
+
+		rec := newAuditRecord(ss.Context(), info.FullMethod)
+		wrapped := &auditServerStream{ServerStream: ss}
+
+		err := handler(srv, wrapped)
+		rec.Params = auditParams(wrapped.req)
+		rec.setResult(err, wrapped.resps...)
+		writeAuditRecord(log, auditLog, rec)
+
+		return err
+	}
+}
@@ -0,0 +1,282 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
+)
+
+// readAuditRecords decodes the JSON lines written to the audit log.
+func readAuditRecords(t *testing.T, buf *bytes.Buffer) (records []*auditRecord) {
+	t.Helper()
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		rec := &auditRecord{}
+		if err := json.Unmarshal([]byte(line), rec); err != nil {
+			t.Fatalf("bad audit record %q: %s", line, err)
+		}
+		rec.Time = "" // not deterministic
+		records = append(records, rec)
+	}
+
+	return
+}
+
+func TestServer_UnaryAuditInterceptor(t *testing.T) {
+	for name, tc := range map[string]struct {
+		method      string
+		req         interface{}
+		handlerResp interface{}
+		handlerErr  error
+		expRecords  []*auditRecord
+	}{
+		"not audited": {
+			method: "/mgmt.MgmtSvc/PoolQuery",
+			req:    &mgmtpb.PoolQueryReq{Uuid: mockUUID},
+		},
+		"pool destroy": {
+			method: "/mgmt.MgmtSvc/PoolDestroy",
+			req:    &mgmtpb.PoolDestroyReq{Uuid: mockUUID, Force: true},
+			expRecords: []*auditRecord{
+				{
+					Peer:    "10.0.0.1:10001",
+					Subject: "CN=admin",
+					Method:  "/mgmt.MgmtSvc/PoolDestroy",
+					Params: map[string]interface{}{
+						"uuid": mockUUID, "force": true,
+					},
+					Status: "ok",
+				},
+			},
+		},
+		"set log level": {
+			method: "/ctl.MgmtCtl/SetLogLevel",
+			req:    &ctlpb.SetLogLevelReq{Control: "info", Engine: "DEBUG", Ranks: []uint32{2}},
+			expRecords: []*auditRecord{
+				{
+					Peer:    "10.0.0.1:10001",
+					Subject: "CN=admin",
+					Method:  "/ctl.MgmtCtl/SetLogLevel",
+					Params: map[string]interface{}{
+						"control": "info", "engine": "DEBUG",
+						"ranks": []interface{}{float64(2)},
+					},
+					Status: "ok",
+				},
+			},
+		},
+		"pool create fails on engine": {
+			method:      "/mgmt.MgmtSvc/PoolCreate",
+			req:         &mgmtpb.PoolCreateReq{Uuid: mockUUID},
+			handlerResp: &mgmtpb.PoolCreateResp{Status: -1007},
+			expRecords: []*auditRecord{
+				{
+					Peer:    "10.0.0.1:10001",
+					Subject: "CN=admin",
+					Method:  "/mgmt.MgmtSvc/PoolCreate",
+					Params: map[string]interface{}{
+						"uuid": mockUUID, "scm_bytes": float64(0), "nvme_bytes": float64(0),
+						"ranks": nil, "svc_reps": float64(0), "user": "", "group": "",
+						"acl_count": float64(0),
+					},
+					Status: "error",
+					Error:  "DAOS status -1007",
+				},
+			},
+		},
+		"storage prepare fails for scm": {
+			method: "/ctl.MgmtCtl/StoragePrepare",
+			req:    &ctlpb.StoragePrepareReq{Scm: &ctlpb.PrepareScmReq{}},
+			handlerResp: &ctlpb.StoragePrepareResp{
+				Scm: &ctlpb.PrepareScmResp{
+					State: &ctlpb.ResponseState{
+						Status: ctlpb.ResponseStatus_CTL_ERR_SCM,
+						Error:  "no pmem",
+					},
+				},
+			},
+			expRecords: []*auditRecord{
+				{
+					Peer:    "10.0.0.1:10001",
+					Subject: "CN=admin",
+					Method:  "/ctl.MgmtCtl/StoragePrepare",
+					Params: map[string]interface{}{
+						"scm_reset": false,
+					},
+					Status: "error",
+					Error:  "scm: CTL_ERR_SCM: no pmem",
+				},
+			},
+		},
+		"failed system stop": {
+			method:     "/ctl.MgmtCtl/SystemStop",
+			req:        &ctlpb.SystemStopReq{Prep: true},
+			handlerErr: errors.New("PrepShutdown HasErrors"),
+			expRecords: []*auditRecord{
+				{
+					Peer:    "10.0.0.1:10001",
+					Subject: "CN=admin",
+					Method:  "/ctl.MgmtCtl/SystemStop",
+					Params: map[string]interface{}{
+						"prep": true, "kill": false,
+					},
+					Status: "error",
+					Error:  "PrepShutdown HasErrors",
+				},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			var auditBuf bytes.Buffer
+			interceptor := unaryAuditInterceptor(log, logging.NewAuditLogger(&auditBuf))
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return tc.handlerResp, tc.handlerErr
+			}
+
+			_, err := interceptor(peerContext(security.RoleAdmin), tc.req,
+				&grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			common.CmpErr(t, tc.handlerErr, err)
+
+			if diff := cmp.Diff(tc.expRecords, readAuditRecords(t, &auditBuf)); diff != "" {
+				t.Fatalf("unexpected audit records (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+// mockRecvServerStream provides a server stream which receives a single
+// request message.
+type mockRecvServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req proto.Message
+}
+
+func (m *mockRecvServerStream) Context() context.Context {
+	return m.ctx
+}
+
+func (m *mockRecvServerStream) RecvMsg(msg interface{}) error {
+	proto.Merge(msg.(proto.Message), m.req)
+	return nil
+}
+
+func (m *mockRecvServerStream) SendMsg(msg interface{}) error {
+	return nil
+}
+
+func TestServer_StreamAuditInterceptor(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	var auditBuf bytes.Buffer
+	interceptor := streamAuditInterceptor(log, logging.NewAuditLogger(&auditBuf))
+	stream := &mockRecvServerStream{
+		ctx: peerContext(security.RoleAdmin),
+		req: &ctlpb.StorageFormatReq{Reformat: true},
+	}
+	handler := func(srv interface{}, ss grpc.ServerStream) error {
+		if err := ss.RecvMsg(new(ctlpb.StorageFormatReq)); err != nil {
+			return err
+		}
+		return ss.SendMsg(&ctlpb.StorageFormatResp{
+			Crets: []*ctlpb.NvmeControllerResult{
+				{Pciaddr: "0000:81:00.0", State: &ctlpb.ResponseState{}},
+				{
+					Pciaddr: "0000:82:00.0",
+					State: &ctlpb.ResponseState{
+						Status: ctlpb.ResponseStatus_CTL_ERR_NVME,
+						Error:  "format failed",
+					},
+				},
+			},
+			Mrets: []*ctlpb.ScmMountResult{
+				{Mntpoint: "/mnt/daos", State: &ctlpb.ResponseState{}},
+			},
+		})
+	}
+
+	if err := interceptor(nil, stream, &grpc.StreamServerInfo{
+		FullMethod: "/ctl.MgmtCtl/StorageFormat",
+	}, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	expRecords := []*auditRecord{
+		{
+			Peer:    "10.0.0.1:10001",
+			Subject: "CN=admin",
+			Method:  "/ctl.MgmtCtl/StorageFormat",
+			Params:  map[string]interface{}{"reformat": true},
+			Status:  "error",
+			Error:   "nvme controller 0000:82:00.0: CTL_ERR_NVME: format failed",
+		},
+	}
+	if diff := cmp.Diff(expRecords, readAuditRecords(t, &auditBuf)); diff != "" {
+		t.Fatalf("unexpected audit records (-want, +got):\n%s\n", diff)
+	}
+}
+
+func TestServer_ChainUnaryInterceptors(t *testing.T) {
+	var calls []string
+	mkInterceptor := func(name string) grpc.UnaryServerInterceptor {
+		return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			calls = append(calls, name)
+			return handler(ctx, req)
+		}
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		calls = append(calls, "handler")
+		return nil, nil
+	}
+
+	chained := chainUnaryInterceptors(mkInterceptor("first"), mkInterceptor("second"))
+	if _, err := chained(context.Background(), nil, &grpc.UnaryServerInfo{}, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([]string{"first", "second", "handler"}, calls); diff != "" {
+		t.Fatalf("unexpected call order (-want, +got):\n%s\n", diff)
+	}
+}
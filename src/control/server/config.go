@@ -26,29 +26,45 @@ package server
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 	yaml "gopkg.in/yaml.v2"
 
+	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/lib/netdetect"
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/security"
+	"github.com/daos-stack/daos/src/control/security/auth"
 	"github.com/daos-stack/daos/src/control/server/ioserver"
 )
 
 const (
-	defaultRuntimeDir        = "/var/run/daos_server"
-	defaultConfigPath        = "etc/daos_server.yml"
-	defaultSystemName        = "daos_server"
-	defaultPort              = 10001
-	configOut                = ".daos_server.active.yml"
-	relConfExamplesPath      = "utils/config/examples/"
-	msgBadConfig             = "insufficient config file, see examples in "
-	msgConfigNoProvider      = "provider not specified in config"
-	msgConfigNoPath          = "no config path set"
-	msgConfigNoServers       = "no servers specified in config"
-	msgConfigBadAccessPoints = "only a single access point is currently supported"
+	defaultRuntimeDir         = "/var/run/daos_server"
+	defaultConfigPath         = "etc/daos_server.yml"
+	defaultSystemName         = "daos_server"
+	defaultPort               = 10001
+	defaultMemberCheckIntvl   = 10 * time.Second
+	defaultMemberCheckMisses  = 3
+	defaultSocketMode         = "0770"
+	defaultDrpcTimeout        = 5 * time.Minute
+	configOut                 = ".daos_server.active.yml"
+	relConfExamplesPath       = "utils/config/examples/"
+	msgBadConfig              = "insufficient config file, see examples in "
+	msgConfigNoProvider       = "provider not specified in config"
+	msgConfigNoPath           = "no config path set"
+	msgConfigNoServers        = "no servers specified in config"
+	msgConfigBadAccessPoints  = "only a single access point is currently supported"
+	msgConfigBadMemberCheck   = "member_check_interval must not be negative and member_check_misses must be positive"
+	msgConfigBadClockSkew     = "credential_clock_skew must not be negative"
+	msgConfigBadSocketMode    = "socket_mode must be an octal file mode such as 0770"
+	msgConfigBadHelperTimeout = "helper_timeouts must be positive durations"
+	msgConfigBadLogRotation   = "log_rotation limits must not be negative"
+	msgConfigBadDrpcMaxMsg    = "drpc_max_message_size must be at least 16384 bytes"
+	msgConfigBadDrpcTimeout   = "drpc_timeout must be a positive duration"
 )
 
 type networkProviderValidation func(string, string) error
@@ -69,9 +85,22 @@ type Configuration struct {
 	ControlLogFile      string                    `yaml:"control_log_file"`
 	ControlLogJSON      bool                      `yaml:"control_log_json,omitempty"`
 	HelperLogFile       string                    `yaml:"helper_log_file"`
+	LogRotation         logging.LogRotation       `yaml:"log_rotation,omitempty"`
+	HelperTimeouts      map[string]time.Duration  `yaml:"helper_timeouts,omitempty"`
+	AllowScmReset       bool                      `yaml:"allow_scm_reset,omitempty"`
+	AuditLogFile        string                    `yaml:"audit_log_file,omitempty"`
+	DrpcCaptureFile     string                    `yaml:"drpc_capture_file,omitempty"`
+	DrpcMaxMessageSize  int                       `yaml:"drpc_max_message_size"`
+	DrpcTimeout         time.Duration             `yaml:"drpc_timeout"`
 	UserName            string                    `yaml:"user_name"`
 	GroupName           string                    `yaml:"group_name"`
 	RecreateSuperblocks bool                      `yaml:"recreate_superblocks"`
+	MemberCheckInterval time.Duration             `yaml:"member_check_interval"`
+	MemberCheckMisses   int                       `yaml:"member_check_misses"`
+	CredentialClockSkew time.Duration             `yaml:"credential_clock_skew"`
+	SocketOwner         string                    `yaml:"socket_owner"`
+	SocketGroup         string                    `yaml:"socket_group"`
+	SocketMode          string                    `yaml:"socket_mode"`
 
 	// duplicated in ioserver.Config
 	SystemName string                `yaml:"name"`
@@ -261,6 +290,33 @@ func (c *Configuration) WithControlLogJSON(enabled bool) *Configuration {
 	return c
 }
 
+// WithAuditLogFile sets the path to the audit log of modifying operations.
+func (c *Configuration) WithAuditLogFile(filePath string) *Configuration {
+	c.AuditLogFile = filePath
+	return c
+}
+
+// WithDrpcCaptureFile sets the path to the capture file of dRPC traffic
+// with the I/O servers.
+func (c *Configuration) WithDrpcCaptureFile(filePath string) *Configuration {
+	c.DrpcCaptureFile = filePath
+	return c
+}
+
+// WithDrpcMaxMessageSize sets the size of the largest dRPC message accepted
+// from the I/O servers.
+func (c *Configuration) WithDrpcMaxMessageSize(size int) *Configuration {
+	c.DrpcMaxMessageSize = size
+	return c
+}
+
+// WithDrpcTimeout sets the time allowed for a dRPC call to an I/O server
+// when the caller gives no deadline.
+func (c *Configuration) WithDrpcTimeout(timeout time.Duration) *Configuration {
+	c.DrpcTimeout = timeout
+	return c
+}
+
 // WithHelperLogFile sets the path to the daos_admin logfile.
 func (c *Configuration) WithHelperLogFile(filePath string) *Configuration {
 	c.HelperLogFile = filePath
@@ -279,6 +335,72 @@ func (c *Configuration) WithGroupName(name string) *Configuration {
 	return c
 }
 
+// WithMemberCheckInterval sets the interval between member liveness checks.
+func (c *Configuration) WithMemberCheckInterval(interval time.Duration) *Configuration {
+	c.MemberCheckInterval = interval
+	return c
+}
+
+// WithMemberCheckMisses sets the number of consecutive failed liveness checks
+// after which a member is marked as unresponsive.
+func (c *Configuration) WithMemberCheckMisses(misses int) *Configuration {
+	c.MemberCheckMisses = misses
+	return c
+}
+
+// WithLogRotation sets the rotation of the control and helper log files.
+func (c *Configuration) WithLogRotation(rotation logging.LogRotation) *Configuration {
+	c.LogRotation = rotation
+	return c
+}
+
+// WithHelperTimeout sets the time allowed for requests to the given
+// daos_admin method.
+func (c *Configuration) WithHelperTimeout(method string, timeout time.Duration) *Configuration {
+	if c.HelperTimeouts == nil {
+		c.HelperTimeouts = make(map[string]time.Duration)
+	}
+	c.HelperTimeouts[method] = timeout
+	return c
+}
+
+// WithAllowScmReset permits the privileged helper to reset SCM modules.
+func (c *Configuration) WithAllowScmReset(allow bool) *Configuration {
+	c.AllowScmReset = allow
+	return c
+}
+
+// WithCredentialClockSkew sets the allowed difference between the clocks of
+// agents issuing credentials and this server.
+func (c *Configuration) WithCredentialClockSkew(skew time.Duration) *Configuration {
+	c.CredentialClockSkew = skew
+	return c
+}
+
+// WithSocketOwner sets the user and group owning the dRPC socket and allowed
+// to call the server's dRPC modules.
+func (c *Configuration) WithSocketOwner(owner, group string) *Configuration {
+	c.SocketOwner = owner
+	c.SocketGroup = group
+	return c
+}
+
+// WithSocketMode sets the permissions of the dRPC socket as an octal string.
+func (c *Configuration) WithSocketMode(mode string) *Configuration {
+	c.SocketMode = mode
+	return c
+}
+
+// socketFileMode returns the permissions of the dRPC socket.
+func (c *Configuration) socketFileMode() (os.FileMode, error) {
+	mode, err := strconv.ParseUint(c.SocketMode, 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, errors.New(msgConfigBadSocketMode)
+	}
+
+	return os.FileMode(mode), nil
+}
+
 // parse decodes YAML representation of configuration
 func (c *Configuration) parse(data []byte) error {
 	return yaml.Unmarshal(data, c)
@@ -288,17 +410,23 @@ func (c *Configuration) parse(data []byte) error {
 // populated with defaults.
 func newDefaultConfiguration(ext External) *Configuration {
 	return &Configuration{
-		SystemName:         defaultSystemName,
-		SocketDir:          defaultRuntimeDir,
-		AccessPoints:       []string{fmt.Sprintf("localhost:%d", defaultPort)},
-		ControlPort:        defaultPort,
-		TransportConfig:    security.DefaultServerTransportConfig(),
-		Hyperthreads:       false,
-		Path:               defaultConfigPath,
-		ControlLogMask:     ControlLogLevel(logging.LogLevelInfo),
-		ext:                ext,
-		validateProviderFn: netdetect.ValidateProviderStub,
-		validateNUMAFn:     netdetect.ValidateNUMAStub,
+		SystemName:          defaultSystemName,
+		SocketDir:           defaultRuntimeDir,
+		AccessPoints:        []string{fmt.Sprintf("localhost:%d", defaultPort)},
+		ControlPort:         defaultPort,
+		TransportConfig:     security.DefaultServerTransportConfig(),
+		Hyperthreads:        false,
+		Path:                defaultConfigPath,
+		ControlLogMask:      ControlLogLevelInfo,
+		MemberCheckInterval: defaultMemberCheckIntvl,
+		MemberCheckMisses:   defaultMemberCheckMisses,
+		CredentialClockSkew: auth.DefaultClockSkew,
+		SocketMode:          defaultSocketMode,
+		DrpcMaxMessageSize:  drpc.DefaultMaxMessageSize,
+		DrpcTimeout:         defaultDrpcTimeout,
+		ext:                 ext,
+		validateProviderFn:  netdetect.ValidateProviderStub,
+		validateNUMAFn:      netdetect.ValidateNUMAStub,
 	}
 }
 
@@ -403,6 +531,42 @@ func (c *Configuration) Validate() (err error) {
 		return errors.New(msgConfigNoServers)
 	}
 
+	if c.MemberCheckInterval < 0 || c.MemberCheckMisses < 1 {
+		return errors.New(msgConfigBadMemberCheck)
+	}
+
+	if c.CredentialClockSkew < 0 {
+		return errors.New(msgConfigBadClockSkew)
+	}
+
+	if err := c.LogRotation.Validate(); err != nil {
+		return errors.New(msgConfigBadLogRotation)
+	}
+
+	if c.DrpcMaxMessageSize < drpc.MaxMsgSize {
+		return errors.New(msgConfigBadDrpcMaxMsg)
+	}
+
+	if c.DrpcTimeout <= 0 {
+		return errors.New(msgConfigBadDrpcTimeout)
+	}
+
+	for _, timeout := range c.HelperTimeouts {
+		if timeout <= 0 {
+			return errors.New(msgConfigBadHelperTimeout)
+		}
+	}
+
+	if _, err := c.socketFileMode(); err != nil {
+		return err
+	}
+
+	if c.TransportConfig != nil {
+		if err := c.TransportConfig.Authorization.Validate(); err != nil {
+			return errors.Wrap(err, "invalid authorization policy")
+		}
+	}
+
 	for i, srv := range c.Servers {
 		srv.Fabric.Update(c.Fabric)
 		if err := srv.Validate(); err != nil {
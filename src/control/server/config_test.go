@@ -31,13 +31,16 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/pkg/errors"
 
 	. "github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/lib/netdetect"
+	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/security"
 	"github.com/daos-stack/daos/src/control/server/ioserver"
 )
@@ -208,6 +211,10 @@ func TestConstructedConfig(t *testing.T) {
 	var numaNode0 uint = 0
 	var numaNode1 uint = 1
 
+	transportCfg := security.DefaultServerTransportConfig()
+	// the sample policy is the default one spelled out
+	transportCfg.Authorization = security.DefaultAuthorizationPolicy()
+
 	// Next, construct a config to compare against the first one. It should be
 	// possible to construct an identical configuration with the helpers.
 	constructed := NewConfiguration().
@@ -215,17 +222,41 @@ func TestConstructedConfig(t *testing.T) {
 		WithBdevInclude("0000:81:00.1", "0000:81:00.2", "0000:81:00.3").
 		WithBdevExclude("0000:81:00.1").
 		WithNrHugePages(4096).
-		WithControlLogMask(ControlLogLevelError).
+		WithControlLogMask(ControlLogLevel{
+			Level: logging.LogLevelError,
+			Subsystems: map[string]logging.LogLevel{
+				logSubsystemDrpc: logging.LogLevelDebug,
+			},
+		}).
 		WithControlLogFile("/tmp/daos_control.log").
+		WithAuditLogFile("/tmp/daos_audit.log").
+		WithDrpcCaptureFile("/tmp/daos_drpc_capture.json").
+		WithDrpcMaxMessageSize(128*1024*1024).
+		WithDrpcTimeout(10*time.Minute).
+		WithLogRotation(logging.LogRotation{
+			MaxSizeMB:  100,
+			MaxAge:     168 * time.Hour,
+			MaxBackups: 5,
+			Compress:   true,
+		}).
+		WithHelperTimeout("ScmFormat", 20*time.Minute).
+		WithHelperTimeout("BdevFormat", time.Hour).
+		WithAllowScmReset(true).
 		WithUserName("daosuser").
 		WithGroupName("daosgroup").
 		WithSystemName("daos").
 		WithSocketDir("./.daos/daos_server").
+		WithSocketOwner("daosuser", "daosgroup").
+		WithSocketMode("0760").
 		WithFabricProvider("ofi+verbs;ofi_rxm").
 		WithAccessPoints("hostname1:10001").
 		WithFaultCb("./.daos/fd_callback").
 		WithFaultPath("/vcdu0/rack1/hostname").
 		WithHyperthreads(true).
+		WithMemberCheckInterval(5*time.Second).
+		WithMemberCheckMisses(5).
+		WithCredentialClockSkew(2*time.Minute).
+		WithTransportConfig(transportCfg).
 		WithProviderValidator(netdetect.ValidateProviderStub).
 		WithNUMAValidator(netdetect.ValidateNUMAStub).
 		WithServers(
@@ -306,6 +337,76 @@ func TestConfigValidation(t *testing.T) {
 			},
 			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadAccessPoints,
 		},
+		"member checks disabled": {
+			func(c *Configuration) *Configuration {
+				return c.WithMemberCheckInterval(0)
+			},
+			"",
+		},
+		"negative member check interval": {
+			func(c *Configuration) *Configuration {
+				return c.WithMemberCheckInterval(-time.Second)
+			},
+			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadMemberCheck,
+		},
+		"zero member check misses": {
+			func(c *Configuration) *Configuration {
+				return c.WithMemberCheckMisses(0)
+			},
+			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadMemberCheck,
+		},
+		"negative credential clock skew": {
+			func(c *Configuration) *Configuration {
+				return c.WithCredentialClockSkew(-time.Second)
+			},
+			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadClockSkew,
+		},
+		"dRPC max message size too small": {
+			func(c *Configuration) *Configuration {
+				return c.WithDrpcMaxMessageSize(drpc.MaxMsgSize - 1)
+			},
+			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadDrpcMaxMsg,
+		},
+		"zero dRPC timeout": {
+			func(c *Configuration) *Configuration {
+				return c.WithDrpcTimeout(0)
+			},
+			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadDrpcTimeout,
+		},
+		"negative log rotation size": {
+			func(c *Configuration) *Configuration {
+				return c.WithLogRotation(logging.LogRotation{MaxSizeMB: -1})
+			},
+			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadLogRotation,
+		},
+		"zero helper timeout": {
+			func(c *Configuration) *Configuration {
+				return c.WithHelperTimeout("ScmScan", 0)
+			},
+			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadHelperTimeout,
+		},
+		"bad socket mode": {
+			func(c *Configuration) *Configuration {
+				return c.WithSocketMode("0780")
+			},
+			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadSocketMode,
+		},
+		"socket mode too large": {
+			func(c *Configuration) *Configuration {
+				return c.WithSocketMode("1777")
+			},
+			msgBadConfig + relConfExamplesPath + ": " + msgConfigBadSocketMode,
+		},
+		"bad authorization method pattern": {
+			func(c *Configuration) *Configuration {
+				tc := security.DefaultServerTransportConfig()
+				tc.Authorization = security.AuthorizationPolicy{
+					"admin": {"/ctl.MgmtCtl/[Storage*"},
+				}
+				return c.WithTransportConfig(tc)
+			},
+			msgBadConfig + relConfExamplesPath + ": invalid authorization policy: role \"admin\" method \"/ctl.MgmtCtl/[Storage*\": syntax error in pattern",
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			testDir, err := ioutil.TempDir("", strings.Replace(t.Name(), "/", "-", -1))
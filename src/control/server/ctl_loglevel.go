@@ -0,0 +1,106 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package server
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/server/ioserver"
+)
+
+// logMaskSetter is implemented by loggers whose levels can be changed
+// while the server is running.
+type logMaskSetter interface {
+	LogMask() logging.LogMask
+	SetLogMask(logging.LogMask)
+}
+
+// instanceRank returns the rank of the instance, or NilRank if it has
+// not yet been assigned one.
+func instanceRank(srv *IOServerInstance) uint32 {
+	if !srv.hasSuperblock() {
+		return uint32(ioserver.NilRank)
+	}
+	return srv.getSuperblock().Rank.Uint32()
+}
+
+// SetLogLevel implements the method defined for the Management Service.
+//
+// Change the control plane log level and the log masks of the I/O server
+// instances on this server without restarting them, reporting the values
+// previously in effect so that the change can be reverted.
+func (svc *ControlService) SetLogLevel(ctx context.Context, req *ctlpb.SetLogLevelReq) (*ctlpb.SetLogLevelResp, error) {
+	svc.log.Debugf("Received SetLogLevel RPC: %+v", req)
+
+	if req.Control == "" && req.Engine == "" {
+		return nil, errors.New("no log level specified")
+	}
+
+	resp := new(ctlpb.SetLogLevelResp)
+	if req.Control != "" {
+		mask, err := logging.ParseLogMask(req.Control)
+		if err != nil {
+			return nil, err
+		}
+		setter, ok := svc.log.(logMaskSetter)
+		if !ok {
+			return nil, errors.New("control plane log level can't be changed")
+		}
+
+		resp.Prevcontrol = setter.LogMask().String()
+		setter.SetLogMask(mask)
+		svc.log.Infof("control plane log level changed from %s to %s",
+			resp.Prevcontrol, mask)
+	}
+
+	if req.Engine != "" {
+		ranks := make(map[uint32]struct{}, len(req.Ranks))
+		for _, rank := range req.Ranks {
+			ranks[rank] = struct{}{}
+		}
+
+		for _, srv := range svc.harness.Instances() {
+			engine := &ctlpb.SetLogLevelResp_Engine{Rank: instanceRank(srv)}
+			if _, found := ranks[engine.Rank]; len(ranks) > 0 && !found {
+				continue
+			}
+
+			prev, err := srv.SetLogMask(ctx, req.Engine)
+			engine.Prevmask = prev
+			if err != nil {
+				engine.Error = err.Error()
+			} else {
+				svc.log.Infof("%s log mask changed from %s to %s",
+					srv.rankDescription(), prev, req.Engine)
+			}
+			resp.Engines = append(resp.Engines, engine)
+		}
+	}
+
+	svc.log.Debug("Responding to SetLogLevel RPC")
+
+	return resp, nil
+}
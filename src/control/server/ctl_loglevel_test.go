@@ -0,0 +1,259 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package server
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
+	"github.com/daos-stack/daos/src/control/drpc"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/server/ioserver"
+)
+
+func TestControlService_SetLogLevel(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req          *ctlpb.SetLogLevelReq
+		drpcStatus   int32
+		drpcErr      error
+		expResp      *ctlpb.SetLogLevelResp
+		expCtlLevel  string
+		expDrpcCalls int
+		expErr       error
+	}{
+		"nothing to change": {
+			req:    &ctlpb.SetLogLevelReq{},
+			expErr: errors.New("no log level specified"),
+		},
+		"bad control level": {
+			req:    &ctlpb.SetLogLevelReq{Control: "loud"},
+			expErr: errors.New("loud"),
+		},
+		"control only": {
+			req: &ctlpb.SetLogLevelReq{Control: "error,drpc=debug"},
+			expResp: &ctlpb.SetLogLevelResp{
+				Prevcontrol: "DEBUG",
+			},
+			expCtlLevel: "ERROR,drpc=DEBUG",
+		},
+		"all engines": {
+			req: &ctlpb.SetLogLevelReq{Engine: "DEBUG,MEM=ERR"},
+			expResp: &ctlpb.SetLogLevelResp{
+				Engines: []*ctlpb.SetLogLevelResp_Engine{
+					{Rank: 1, Prevmask: "WARN"},
+					{Rank: 2, Prevmask: defaultEngineLogMask},
+				},
+			},
+			expCtlLevel:  "DEBUG",
+			expDrpcCalls: 2,
+		},
+		"selected ranks": {
+			req: &ctlpb.SetLogLevelReq{Engine: "DEBUG", Ranks: []uint32{2, 3}},
+			expResp: &ctlpb.SetLogLevelResp{
+				Engines: []*ctlpb.SetLogLevelResp_Engine{
+					{Rank: 2, Prevmask: defaultEngineLogMask},
+				},
+			},
+			expCtlLevel:  "DEBUG",
+			expDrpcCalls: 1,
+		},
+		"control and engines": {
+			req: &ctlpb.SetLogLevelReq{Control: "info", Engine: "INFO", Ranks: []uint32{1}},
+			expResp: &ctlpb.SetLogLevelResp{
+				Prevcontrol: "DEBUG",
+				Engines: []*ctlpb.SetLogLevelResp_Engine{
+					{Rank: 1, Prevmask: "WARN"},
+				},
+			},
+			expCtlLevel:  "INFO",
+			expDrpcCalls: 1,
+		},
+		"engine rejects mask": {
+			req:        &ctlpb.SetLogLevelReq{Engine: "BOGUS", Ranks: []uint32{1}},
+			drpcStatus: -1003,
+			expResp: &ctlpb.SetLogLevelResp{
+				Engines: []*ctlpb.SetLogLevelResp_Engine{
+					{Rank: 1, Prevmask: "WARN", Error: `SetLogMasks "BOGUS": -1003`},
+				},
+			},
+			expCtlLevel:  "DEBUG",
+			expDrpcCalls: 1,
+		},
+		"dRPC failure": {
+			req:     &ctlpb.SetLogLevelReq{Engine: "DEBUG", Ranks: []uint32{2}},
+			drpcErr: errors.New("socket closed"),
+			expResp: &ctlpb.SetLogLevelResp{
+				Engines: []*ctlpb.SetLogLevelResp_Engine{
+					{Rank: 2, Prevmask: defaultEngineLogMask, Error: "send message: socket closed"},
+				},
+			},
+			expCtlLevel:  "DEBUG",
+			expDrpcCalls: 1,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cs := defaultMockControlService(t, log)
+			cs.harness = NewIOServerHarness(log)
+
+			respBytes, err := proto.Marshal(&mgmtpb.DaosResp{Status: tc.drpcStatus})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var clients []*mockDrpcClient
+			for i, logMask := range []string{"WARN", ""} {
+				cfg := ioserver.NewConfig().WithLogMask(logMask)
+				srv := NewIOServerInstance(log, nil, nil, nil, ioserver.NewRunner(log, cfg))
+				srv.setSuperblock(&Superblock{Rank: ioserver.NewRankPtr(uint32(i + 1))})
+
+				dcc := &mockDrpcClientConfig{}
+				dcc.setSendMsgResponse(drpc.Status_SUCCESS, respBytes, tc.drpcErr)
+				dc := newMockDrpcClient(dcc)
+				srv.setDrpcClient(dc)
+				clients = append(clients, dc)
+
+				cs.harness.instances = append(cs.harness.instances, srv)
+			}
+
+			resp, err := cs.SetLogLevel(context.TODO(), tc.req)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expResp, resp); diff != "" {
+				t.Fatalf("unexpected response (-want, +got):\n%s\n", diff)
+			}
+			common.AssertEqual(t, log.LogMask().String(), tc.expCtlLevel,
+				"unexpected control log level")
+
+			var calls int
+			for _, dc := range clients {
+				if dc.SendMsgInputCall == nil {
+					continue
+				}
+				calls++
+				common.AssertEqual(t, dc.SendMsgInputCall.Method,
+					int32(drpc.MethodSetLogMasks), "unexpected dRPC method")
+			}
+			common.AssertEqual(t, calls, tc.expDrpcCalls, "unexpected dRPC calls")
+
+			// a successful change is reported as the previous value
+			// next time around so that it can be reverted
+			for i, srv := range cs.harness.instances {
+				expMask := []string{"WARN", defaultEngineLogMask}[i]
+				if clients[i].SendMsgInputCall != nil && tc.drpcErr == nil && tc.drpcStatus == 0 {
+					expMask = tc.req.Engine
+				}
+				common.AssertEqual(t, srv.LogMask(), expMask,
+					"unexpected instance log mask")
+			}
+		})
+	}
+}
+
+func TestIOServerInstance_LogMask(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg     *ioserver.Config
+		envMask string
+		expMask string
+	}{
+		"default": {
+			cfg:     ioserver.NewConfig(),
+			expMask: defaultEngineLogMask,
+		},
+		"server environment": {
+			cfg:     ioserver.NewConfig(),
+			envMask: "INFO",
+			expMask: "INFO",
+		},
+		"env_vars": {
+			cfg:     ioserver.NewConfig().WithEnvVars("D_LOG_MASK=DEBUG"),
+			envMask: "INFO",
+			expMask: "DEBUG",
+		},
+		"log_mask": {
+			cfg: ioserver.NewConfig().
+				WithEnvVars("D_LOG_MASK=DEBUG").
+				WithLogMask("WARN"),
+			envMask: "INFO",
+			expMask: "WARN",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			if tc.envMask != "" {
+				os.Setenv(engineLogMaskEnvVar, tc.envMask)
+				defer os.Unsetenv(engineLogMaskEnvVar)
+			}
+
+			srv := NewIOServerInstance(log, nil, nil, nil, ioserver.NewRunner(log, tc.cfg))
+			common.AssertEqual(t, srv.LogMask(), tc.expMask, "unexpected log mask")
+		})
+	}
+}
+
+func TestIOServerInstance_SetLogMask_Concurrent(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	respBytes, err := proto.Marshal(&mgmtpb.DaosResp{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := ioserver.NewConfig().WithLogMask("WARN")
+	srv := NewIOServerInstance(log, nil, nil, nil, ioserver.NewRunner(log, cfg))
+	dcc := &mockDrpcClientConfig{}
+	dcc.setSendMsgResponse(drpc.Status_SUCCESS, respBytes, nil)
+	srv.setDrpcClient(newMockDrpcClient(dcc))
+
+	masks := []string{"DEBUG", "INFO"}
+	prevs := make(chan string, len(masks))
+	for _, mask := range masks {
+		go func(mask string) {
+			prev, err := srv.SetLogMask(context.TODO(), mask)
+			if err != nil {
+				t.Error(err)
+			}
+			prevs <- prev
+		}(mask)
+	}
+
+	// Each change must report the mask in effect before it was made.
+	got := map[string]bool{<-prevs: true, <-prevs: true}
+	if !got["WARN"] || len(got) != 2 {
+		t.Fatalf("expected changes to be serialized, got previous masks %v", got)
+	}
+}
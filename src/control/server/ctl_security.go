@@ -0,0 +1,70 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package server
+
+import (
+	"crypto/x509"
+	"time"
+
+	"golang.org/x/net/context"
+
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+)
+
+func certInfoToPB(cert *x509.Certificate) *ctlpb.CertInfo {
+	if cert == nil {
+		return nil
+	}
+
+	return &ctlpb.CertInfo{
+		Subject:   cert.Subject.String(),
+		Issuer:    cert.Issuer.String(),
+		Notbefore: cert.NotBefore.Format(time.RFC3339),
+		Notafter:  cert.NotAfter.Format(time.RFC3339),
+		Dnsnames:  cert.DNSNames,
+	}
+}
+
+// CertStatus implements the method defined for the Management Service.
+//
+// Report details of the certificates currently in use by this server.
+func (svc *ControlService) CertStatus(ctx context.Context, req *ctlpb.CertStatusReq) (*ctlpb.CertStatusResp, error) {
+	svc.log.Debug("Received CertStatus RPC")
+
+	resp := new(ctlpb.CertStatusResp)
+	if svc.transportCfg == nil || svc.transportCfg.AllowInsecure {
+		resp.Insecure = true
+		return resp, nil
+	}
+
+	cert, ca, err := svc.transportCfg.Certificates()
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	resp.Cert = certInfoToPB(cert)
+	resp.Ca = certInfoToPB(ca)
+
+	svc.log.Debug("Responding to CertStatus RPC")
+
+	return resp, nil
+}
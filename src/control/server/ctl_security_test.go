@@ -0,0 +1,96 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package server
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
+)
+
+func TestControlService_CertStatus(t *testing.T) {
+	tmpDir, cleanup := common.CreateTestDir(t)
+	defer cleanup()
+
+	validity := 365 * 24 * time.Hour
+	ca, err := security.GenerateCA("test CA", 1024, validity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kp, err := ca.Issue("server", security.RoleServer, []string{"server"}, 1024, validity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secureCfg := &security.TransportConfig{
+		CertificateConfig: security.CertificateConfig{
+			CARootPath:      filepath.Join(tmpDir, "daosCA.crt"),
+			CertificatePath: filepath.Join(tmpDir, "server.crt"),
+			PrivateKeyPath:  filepath.Join(tmpDir, "server.key"),
+		},
+	}
+	if err := ca.WriteCert(secureCfg.CARootPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := kp.Write(secureCfg.CertificatePath, secureCfg.PrivateKeyPath); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		transportCfg *security.TransportConfig
+		expResp      *ctlpb.CertStatusResp
+	}{
+		"insecure": {
+			transportCfg: &security.TransportConfig{AllowInsecure: true},
+			expResp:      &ctlpb.CertStatusResp{Insecure: true},
+		},
+		"secure": {
+			transportCfg: secureCfg,
+			expResp: &ctlpb.CertStatusResp{
+				Cert: certInfoToPB(kp.Cert),
+				Ca:   certInfoToPB(ca.Cert),
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cs := mockSystemControlService(t, log)
+			cs.transportCfg = tc.transportCfg
+
+			gotResp, err := cs.CertStatus(context.TODO(), &ctlpb.CertStatusReq{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			common.AssertEqual(t, tc.expResp, gotResp, name)
+		})
+	}
+}
@@ -24,6 +24,8 @@
 package server
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/logging"
@@ -95,8 +97,8 @@ func (c *StorageControlService) canAccessBdevs(sr *bdev.ScanResponse) (missing [
 }
 
 // Setup delegates to Storage implementation's Setup methods.
-func (c *StorageControlService) Setup() error {
-	sr, err := c.bdev.Scan(bdev.ScanRequest{})
+func (c *StorageControlService) Setup(ctx context.Context) error {
+	sr, err := c.bdev.Scan(ctx, bdev.ScanRequest{})
 	if err != nil {
 		c.log.Debugf("%s\n", errors.Wrap(err, "Warning, NVMe Scan"))
 	} else {
@@ -108,7 +110,7 @@ func (c *StorageControlService) Setup() error {
 		}
 	}
 
-	if _, err := c.scm.Scan(scm.ScanRequest{}); err != nil {
+	if _, err := c.scm.Scan(ctx, scm.ScanRequest{}); err != nil {
 		c.log.Debugf("%s\n", errors.Wrap(err, "Warning, SCM Scan"))
 	}
 
@@ -118,35 +120,35 @@ func (c *StorageControlService) Setup() error {
 // NvmePrepare preps locally attached SSDs and returns error.
 //
 // Suitable for commands invoked directly on server, not over gRPC.
-func (c *StorageControlService) NvmePrepare(req bdev.PrepareRequest) (*bdev.PrepareResponse, error) {
-	return c.bdev.Prepare(req)
+func (c *StorageControlService) NvmePrepare(ctx context.Context, req bdev.PrepareRequest) (*bdev.PrepareResponse, error) {
+	return c.bdev.Prepare(ctx, req)
 }
 
 // GetScmState performs required initialisation and returns current state
 // of SCM module preparation.
-func (c *StorageControlService) GetScmState() (storage.ScmState, error) {
-	return c.scm.GetState()
+func (c *StorageControlService) GetScmState(ctx context.Context) (storage.ScmState, error) {
+	return c.scm.GetState(ctx)
 }
 
 // ScmPrepare preps locally attached modules and returns need to reboot message,
 // list of pmem device files and error directly.
 //
 // Suitable for commands invoked directly on server, not over gRPC.
-func (c *StorageControlService) ScmPrepare(req scm.PrepareRequest) (*scm.PrepareResponse, error) {
+func (c *StorageControlService) ScmPrepare(ctx context.Context, req scm.PrepareRequest) (*scm.PrepareResponse, error) {
 	// transition to the next state in SCM preparation
-	return c.scm.Prepare(req)
+	return c.scm.Prepare(ctx, req)
 }
 
 // NvmeScan scans locally attached SSDs and returns list directly.
 //
 // Suitable for commands invoked directly on server, not over gRPC.
-func (c *StorageControlService) NvmeScan() (*bdev.ScanResponse, error) {
-	return c.bdev.Scan(bdev.ScanRequest{})
+func (c *StorageControlService) NvmeScan(ctx context.Context) (*bdev.ScanResponse, error) {
+	return c.bdev.Scan(ctx, bdev.ScanRequest{})
 }
 
 // ScmScan scans locally attached modules, namespaces and state of DCPM config.
 //
 // Suitable for commands invoked directly on server, not over gRPC.
-func (c *StorageControlService) ScmScan() (*scm.ScanResponse, error) {
-	return c.scm.Scan(scm.ScanRequest{})
+func (c *StorageControlService) ScmScan(ctx context.Context) (*scm.ScanResponse, error) {
+	return c.scm.Scan(ctx, scm.ScanRequest{})
 }
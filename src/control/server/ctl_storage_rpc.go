@@ -25,6 +25,7 @@ package server
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -37,6 +38,7 @@ import (
 	"github.com/daos-stack/daos/src/control/server/storage"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
 	"github.com/daos-stack/daos/src/control/server/storage/scm"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
 // newState creates, populates and returns ResponseState in addition
@@ -88,10 +90,10 @@ func scmNamespacesToPB(nss []storage.ScmNamespace) (pbNss proto.ScmNamespaces) {
 	return
 }
 
-func (c *StorageControlService) doNvmePrepare(req *ctlpb.PrepareNvmeReq) (resp *ctlpb.PrepareNvmeResp) {
+func (c *StorageControlService) doNvmePrepare(ctx context.Context, req *ctlpb.PrepareNvmeReq) (resp *ctlpb.PrepareNvmeResp) {
 	resp = &ctlpb.PrepareNvmeResp{}
 	msg := "Storage Prepare NVMe"
-	_, err := c.NvmePrepare(bdev.PrepareRequest{
+	_, err := c.NvmePrepare(ctx, bdev.PrepareRequest{
 		HugePageCount: int(req.GetNrhugepages()),
 		TargetUser:    req.GetTargetuser(),
 		PCIWhitelist:  req.GetPciwhitelist(),
@@ -107,18 +109,18 @@ func (c *StorageControlService) doNvmePrepare(req *ctlpb.PrepareNvmeReq) (resp *
 	return
 }
 
-func (c *StorageControlService) doScmPrepare(pbReq *ctlpb.PrepareScmReq) (pbResp *ctlpb.PrepareScmResp) {
+func (c *StorageControlService) doScmPrepare(ctx context.Context, pbReq *ctlpb.PrepareScmReq) (pbResp *ctlpb.PrepareScmResp) {
 	pbResp = &ctlpb.PrepareScmResp{}
 	msg := "Storage Prepare SCM"
 
-	scmState, err := c.GetScmState()
+	scmState, err := c.GetScmState(ctx)
 	if err != nil {
 		pbResp.State = newState(c.log, ctlpb.ResponseStatus_CTL_ERR_SCM, err.Error(), "", msg)
 		return
 	}
 	c.log.Debugf("SCM state before prep: %s", scmState)
 
-	resp, err := c.ScmPrepare(scm.PrepareRequest{Reset: pbReq.Reset_})
+	resp, err := c.ScmPrepare(ctx, scm.PrepareRequest{Reset: pbReq.Reset_})
 	if err != nil {
 		pbResp.State = newState(c.log, ctlpb.ResponseStatus_CTL_ERR_SCM, err.Error(), "", msg)
 		return
@@ -145,10 +147,10 @@ func (c *StorageControlService) StoragePrepare(ctx context.Context, req *ctlpb.S
 	resp := &ctlpb.StoragePrepareResp{}
 
 	if req.Nvme != nil {
-		resp.Nvme = c.doNvmePrepare(req.Nvme)
+		resp.Nvme = c.doNvmePrepare(ctx, req.Nvme)
 	}
 	if req.Scm != nil {
-		resp.Scm = c.doScmPrepare(req.Scm)
+		resp.Scm = c.doScmPrepare(ctx, req.Scm)
 	}
 
 	return resp, nil
@@ -161,7 +163,7 @@ func (c *StorageControlService) StorageScan(ctx context.Context, req *ctlpb.Stor
 	msg := "Storage Scan "
 	resp := new(ctlpb.StorageScanResp)
 
-	bsr, err := c.bdev.Scan(bdev.ScanRequest{})
+	bsr, err := c.bdev.Scan(ctx, bdev.ScanRequest{})
 	if err != nil {
 		resp.Nvme = &ctlpb.ScanNvmeResp{
 			State: newState(c.log, ctlpb.ResponseStatus_CTL_ERR_NVME, err.Error(), "", msg+"NVMe"),
@@ -177,7 +179,7 @@ func (c *StorageControlService) StorageScan(ctx context.Context, req *ctlpb.Stor
 		}
 	}
 
-	ssr, err := c.scm.Scan(scm.ScanRequest{})
+	ssr, err := c.scm.Scan(ctx, scm.ScanRequest{})
 	if err != nil {
 		resp.Scm = &ctlpb.ScanScmResp{
 			State: newState(c.log, ctlpb.ResponseStatus_CTL_ERR_SCM, err.Error(), "", msg+"SCM"),
@@ -222,7 +224,7 @@ func newCret(log logging.Logger, op, pciAddr string, status ctlpb.ResponseStatus
 	}
 }
 
-func (c *ControlService) scmFormat(scmCfg storage.ScmConfig, reformat bool) (*ctlpb.ScmMountResult, error) {
+func (c *ControlService) scmFormat(ctx context.Context, scmCfg storage.ScmConfig, reformat bool) (*ctlpb.ScmMountResult, error) {
 	var eMsg, iMsg string
 	status := ctlpb.ResponseStatus_CTL_SUCCESS
 
@@ -233,7 +235,7 @@ func (c *ControlService) scmFormat(scmCfg storage.ScmConfig, reformat bool) (*ct
 
 	scmStr := fmt.Sprintf("SCM (%s:%s)", scmCfg.Class, scmCfg.MountPoint)
 	c.log.Infof("Starting format of %s", scmStr)
-	res, err := c.scm.Format(*req)
+	res, err := c.scm.Format(ctx, *req)
 	if err != nil {
 		eMsg = err.Error()
 		iMsg = fault.ShowResolutionFor(err)
@@ -255,7 +257,7 @@ func (c *ControlService) scmFormat(scmCfg storage.ScmConfig, reformat bool) (*ct
 
 // doFormat performs format on storage subsystems, populates response results
 // in storage subsystem routines and broadcasts (closes channel) if successful.
-func (c *ControlService) doFormat(i *IOServerInstance, reformat bool, resp *ctlpb.StorageFormatResp) error {
+func (c *ControlService) doFormat(ctx context.Context, i *IOServerInstance, reformat bool, resp *ctlpb.StorageFormatResp) error {
 	const msgFormatErr = "failure formatting storage, check RPC response for details"
 	needsSuperblock := true
 	needsScmFormat := reformat
@@ -268,7 +270,7 @@ func (c *ControlService) doFormat(i *IOServerInstance, reformat bool, resp *ctlp
 	// If not reformatting, check if SCM is already formatted.
 	if !reformat {
 		var err error
-		needsScmFormat, err = i.NeedsScmFormat()
+		needsScmFormat, err = i.NeedsScmFormat(ctx)
 		if err != nil {
 			return errors.Wrap(err, "unable to check storage formatting")
 		}
@@ -285,7 +287,7 @@ func (c *ControlService) doFormat(i *IOServerInstance, reformat bool, resp *ctlp
 	// When SCM format is required, format and populate response with result.
 	if needsScmFormat {
 		results := proto.ScmMountResults{}
-		result, err := c.scmFormat(scmConfig, true)
+		result, err := c.scmFormat(ctx, scmConfig, true)
 		if err != nil {
 			return errors.Wrap(err, "scm format") // return unexpected errors
 		}
@@ -299,7 +301,7 @@ func (c *ControlService) doFormat(i *IOServerInstance, reformat bool, resp *ctlp
 	} else {
 		var err error
 		// If SCM was already formatted, verify if superblock exists.
-		needsSuperblock, err = i.NeedsSuperblock()
+		needsSuperblock, err = i.NeedsSuperblock(ctx)
 		if err != nil {
 			return errors.Wrap(err, "unable to check instance superblock")
 		}
@@ -316,7 +318,7 @@ func (c *ControlService) doFormat(i *IOServerInstance, reformat bool, resp *ctlp
 			bdevListStr := strings.Join(bdevConfig.DeviceList, ",")
 			c.log.Infof("Starting format of %s block devices (%s)", bdevConfig.Class, bdevListStr)
 
-			res, err := c.bdev.Format(bdev.FormatRequest{
+			res, err := c.bdev.Format(ctx, bdev.FormatRequest{
 				Class:      bdevConfig.Class,
 				DeviceList: bdevConfig.DeviceList,
 			})
@@ -374,13 +376,22 @@ func (c *ControlService) StorageFormat(req *ctlpb.StorageFormatReq, stream ctlpb
 		return errors.New("cannot format storage with running I/O server instances")
 	}
 
+	ctx := stream.Context()
+
 	// temporary scaffolding
 	for _, i := range c.harness.Instances() {
-		if err := c.doFormat(i, req.Reformat, resp); err != nil {
+		if err := c.doFormat(ctx, i, req.Reformat, resp); err != nil {
 			return errors.WithMessage(err, "formatting storage")
 		}
 	}
 
+	hostname, _ := os.Hostname()
+	event := system.NewEvent(system.EventTypeStorageFormat, system.NoRank,
+		fmt.Sprintf("format of %d I/O server instances on %s (reformat %t)",
+			len(c.harness.Instances()), hostname, req.Reformat))
+	event.Identity = requestIdentity(stream.Context())
+	c.reportEvent(event)
+
 	if resp.Crets == nil {
 		// indicate that NVMe not yet formatted
 		resp.Crets = proto.NvmeControllerResults{
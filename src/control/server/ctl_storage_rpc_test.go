@@ -86,6 +86,10 @@ func (m *mockStorageFormatServer) Send(resp *StorageFormatResp) error {
 	return nil
 }
 
+func (m *mockStorageFormatServer) Context() context.Context {
+	return context.Background()
+}
+
 // return config reference with customised storage config behaviour and params
 func newMockStorageConfig(
 	mountRet error, unmountRet error, mkdirRet error, removeRet error,
@@ -254,7 +258,7 @@ func TestStorageScan(t *testing.T) {
 				cs := mockControlService(t, log, config, tc.bmbc, tc.smbc, nil)
 
 				// runs discovery for nvme & scm
-				err := cs.Setup()
+				err := cs.Setup(context.Background())
 				if err != nil {
 					common.CmpErr(t, tc.expSetupErr, err)
 				} else {
@@ -390,7 +394,7 @@ func TestStoragePrepare(t *testing.T) {
 			_ = new(StoragePrepareResp)
 
 			// runs discovery for nvme & scm
-			if err := cs.Setup(); err != nil {
+			if err := cs.Setup(context.Background()); err != nil {
 				t.Fatal(err.Error() + name)
 			}
 
@@ -683,7 +687,7 @@ func TestStorageFormat(t *testing.T) {
 			cs := mockControlService(t, log, config, tc.bmbc, nil, msc)
 
 			// runs discovery for nvme & scm
-			if err := cs.Setup(); err != nil {
+			if err := cs.Setup(context.Background()); err != nil {
 				t.Fatal(err.Error() + name)
 			}
 
@@ -702,7 +706,7 @@ func TestStorageFormat(t *testing.T) {
 
 				// if the instance is expected to have a valid superblock, create one
 				if tc.superblockExists {
-					if err := i.CreateSuperblock(&mgmtInfo{}); err != nil {
+					if err := i.CreateSuperblock(context.Background(), &mgmtInfo{}); err != nil {
 						t.Fatal(err)
 					}
 				}
@@ -25,6 +25,7 @@ package server
 
 import (
 	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
 	"github.com/daos-stack/daos/src/control/server/storage/scm"
 	"github.com/daos-stack/daos/src/control/system"
@@ -35,15 +36,17 @@ import (
 // ctlpb.MgmtCtlServer, and is the data container for the service.
 type ControlService struct {
 	StorageControlService
-	harness    *IOServerHarness
-	membership *system.Membership
+	harness      *IOServerHarness
+	membership   *system.Membership
+	events       *system.EventLog
+	transportCfg *security.TransportConfig
 }
 
 // NewControlService returns ControlService to be used as gRPC control service
 // datastore. Initialised with sensible defaults and provided components.
 func NewControlService(l logging.Logger, h *IOServerHarness,
 	bp *bdev.Provider, sp *scm.Provider,
-	cfg *Configuration, m *system.Membership) (*ControlService, error) {
+	cfg *Configuration, m *system.Membership, e *system.EventLog) (*ControlService, error) {
 	scs, err := DefaultStorageControlService(l, cfg)
 	if err != nil {
 		return nil, err
@@ -55,5 +58,7 @@ func NewControlService(l logging.Logger, h *IOServerHarness,
 		StorageControlService: *scs,
 		harness:               h,
 		membership:            m,
+		events:                e,
+		transportCfg:          cfg.TransportConfig,
 	}, nil
 }
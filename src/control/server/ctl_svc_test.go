@@ -30,6 +30,7 @@ import (
 	"github.com/daos-stack/daos/src/control/server/ioserver"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
 	"github.com/daos-stack/daos/src/control/server/storage/scm"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
 func defaultMockControlService(t *testing.T, log logging.Logger) *ControlService {
@@ -51,6 +52,7 @@ func mockControlService(t *testing.T, log logging.Logger, cfg *Configuration, bm
 		harness: &IOServerHarness{
 			log: log,
 		},
+		events: system.NewEventLog(log, system.DefaultEventLogSize),
 	}
 
 	scmProvider := cs.StorageControlService.scm
@@ -24,6 +24,9 @@
 package server
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
 
@@ -38,6 +41,39 @@ const (
 	prepShutdownTimeout = 10 * retryDelay
 )
 
+// reportEvent records the event in the system event log. Hosts which aren't
+// running the MS leader forward the event to it, keeping it in the local log
+// only if it can't be delivered.
+func (svc *ControlService) reportEvent(event *system.Event) {
+	instances := svc.harness.Instances()
+	if len(instances) == 0 || instances[0].msClient == nil {
+		svc.events.Add(event)
+		return
+	}
+	if _, err := svc.harness.GetMSLeaderInstance(); err == nil {
+		svc.events.Add(event)
+		return
+	}
+	msClient := instances[0].msClient
+
+	req := &mgmtpb.ReportEventReq{
+		Rank:     event.Rank,
+		Type:     uint32(event.Type),
+		Reason:   event.Reason,
+		Identity: event.Identity,
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reportEventTimeout)
+		defer cancel()
+
+		if err := msClient.ReportEvent(ctx, req); err != nil {
+			svc.log.Errorf("reporting %s event to MS leader: %s", event.Type, err)
+			svc.events.Add(event)
+		}
+	}()
+}
+
 // SystemQuery implements the method defined for the Management Service.
 //
 // Return system membership list including member state.
@@ -64,6 +100,14 @@ func (svc *ControlService) SystemQuery(ctx context.Context, req *ctlpb.SystemQue
 	return resp, nil
 }
 
+// memberStateReason describes why member state changed as a result of action.
+func memberStateReason(action string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("%s failed: %s", action, err)
+	}
+	return action
+}
+
 // prepShutdown sends multicast PrepShutdown gRPC requests to system membership list.
 func (svc *ControlService) prepShutdown(ctx context.Context, leader *IOServerInstance) system.MemberResults {
 	members := svc.membership.Members()
@@ -90,7 +134,8 @@ func (svc *ControlService) prepShutdown(ctx context.Context, leader *IOServerIns
 			state = system.MemberStateErrored
 			svc.log.Errorf("MgmtSvc.prepShutdown error %s\n", result.Err)
 		}
-		if err := svc.membership.SetMemberState(member.Rank, state); err != nil {
+		if err := svc.membership.SetMemberState(member.Rank, state,
+			memberStateReason("prep shutdown", result.Err)); err != nil {
 			svc.log.Errorf("setting member state: %s", err)
 		}
 
@@ -118,7 +163,8 @@ func (svc *ControlService) stopMember(ctx context.Context, leader *IOServerInsta
 		state = system.MemberStateErrored
 		svc.log.Errorf("MgmtSvc.stopMember error %s\n", result.Err)
 	}
-	if err := svc.membership.SetMemberState(member.Rank, state); err != nil {
+	if err := svc.membership.SetMemberState(member.Rank, state,
+		memberStateReason("stop", result.Err)); err != nil {
 		svc.log.Errorf("setting member state: %s", err)
 	}
 
@@ -167,6 +213,11 @@ func (svc *ControlService) SystemStop(ctx context.Context, req *ctlpb.SystemStop
 
 	svc.log.Debug("Received SystemStop RPC")
 
+	event := system.NewEvent(system.EventTypeSystemStop, system.NoRank,
+		fmt.Sprintf("prep %t, kill %t", req.Prep, req.Kill))
+	event.Identity = requestIdentity(ctx)
+	svc.events.Add(event)
+
 	// TODO: consider locking to prevent join attempts when shutting down
 
 	if req.Prep {
@@ -314,3 +365,135 @@ func (svc *ControlService) SystemStart(ctx context.Context, req *ctlpb.SystemSta
 
 	return resp, nil
 }
+
+// changeExclusion applies the membership exclusion change function to each
+// of the requested ranks, returning a result for each rank.
+func (svc *ControlService) changeExclusion(ctx context.Context, action string, ranks []uint32, changeFn func(uint32, string) error) system.MemberResults {
+	reason := fmt.Sprintf("%s by %s", action, requestIdentity(ctx))
+	results := make(system.MemberResults, 0, len(ranks))
+
+	for _, rank := range ranks {
+		err := changeFn(rank, reason)
+		if err != nil {
+			svc.log.Errorf("MgmtSvc.%s rank %d: %s", action, rank, err)
+		}
+		results = append(results, system.NewMemberResult(rank, action, err))
+	}
+
+	return results
+}
+
+// SystemExclude implements the method defined for the Management Service.
+//
+// Exclude ranks from DAOS system, marking them as evicted and refusing join
+// attempts until they are reintegrated.
+func (svc *ControlService) SystemExclude(ctx context.Context, req *ctlpb.SystemExcludeReq) (*ctlpb.SystemExcludeResp, error) {
+	// verify we are running on a host with the MS leader and therefore will
+	// have membership list.
+	if _, err := svc.harness.GetMSLeaderInstance(); err != nil {
+		return nil, err
+	}
+
+	svc.log.Debugf("Received SystemExclude RPC: %+v", req)
+
+	if len(req.Ranks) == 0 {
+		return nil, errors.New("no ranks specified")
+	}
+
+	results := svc.changeExclusion(ctx, "exclude", req.Ranks,
+		svc.membership.Exclude)
+
+	svc.log.Debug("Responding to SystemExclude RPC")
+
+	return &ctlpb.SystemExcludeResp{
+		Results: proto.MemberResultsToPB(results),
+	}, nil
+}
+
+// SystemReintegrate implements the method defined for the Management Service.
+//
+// Reintegrate excluded ranks into DAOS system, allowing them to rejoin.
+func (svc *ControlService) SystemReintegrate(ctx context.Context, req *ctlpb.SystemReintegrateReq) (*ctlpb.SystemReintegrateResp, error) {
+	// verify we are running on a host with the MS leader and therefore will
+	// have membership list.
+	if _, err := svc.harness.GetMSLeaderInstance(); err != nil {
+		return nil, err
+	}
+
+	svc.log.Debugf("Received SystemReintegrate RPC: %+v", req)
+
+	if len(req.Ranks) == 0 {
+		return nil, errors.New("no ranks specified")
+	}
+
+	results := svc.changeExclusion(ctx, "reintegrate", req.Ranks,
+		svc.membership.Reintegrate)
+
+	svc.log.Debug("Responding to SystemReintegrate RPC")
+
+	return &ctlpb.SystemReintegrateResp{
+		Results: proto.MemberResultsToPB(results),
+	}, nil
+}
+
+// SystemEvents implements the method defined for the Management Service.
+//
+// Stream events recorded in the system event log that match the request
+// parameters, if requested keep streaming new events until the client
+// disconnects.
+func (svc *ControlService) SystemEvents(req *ctlpb.SystemEventsReq, stream ctlpb.MgmtCtl_SystemEventsServer) error {
+	// verify we are running on a host with the MS leader and therefore will
+	// have the system event log.
+	if _, err := svc.harness.GetMSLeaderInstance(); err != nil {
+		return err
+	}
+
+	svc.log.Debugf("Received SystemEvents RPC: %+v", req)
+
+	filter := system.EventFilter{Ranks: req.Ranks}
+	if req.Since != "" {
+		since, err := time.Parse(time.RFC3339Nano, req.Since)
+		if err != nil {
+			return errors.Wrap(err, "parse since time")
+		}
+		filter.Since = since
+	}
+
+	// subscribe before retrieving recorded events so none are missed
+	var newEvents <-chan *system.Event
+	if req.Follow {
+		var unsubscribe func()
+		newEvents, unsubscribe = svc.events.Subscribe()
+		defer unsubscribe()
+	}
+
+	var lastSeq uint64
+	for _, event := range svc.events.Events(filter) {
+		if err := stream.Send(proto.EventToPB(event)); err != nil {
+			return errors.Wrap(err, "sending system event")
+		}
+		lastSeq = event.Sequence
+	}
+
+	for req.Follow {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-newEvents:
+			if !ok {
+				return errors.New("system event stream fell behind, events were dropped")
+			}
+			if event.Sequence <= lastSeq || !filter.Matches(event) {
+				continue
+			}
+			if err := stream.Send(proto.EventToPB(event)); err != nil {
+				return errors.Wrap(err, "sending system event")
+			}
+			lastSeq = event.Sequence
+		}
+	}
+
+	svc.log.Debug("Responding to SystemEvents RPC")
+
+	return nil
+}
@@ -0,0 +1,273 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/daos-stack/daos/src/control/common"
+	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/system"
+)
+
+// mockSystemEventsServer provides mocking for server side streaming of system
+// events, recording sent events and signalling each send.
+type mockSystemEventsServer struct {
+	grpc.ServerStream
+	ctx    context.Context
+	sent   chan *ctlpb.SystemEvent
+	Events []*ctlpb.SystemEvent
+}
+
+func (m *mockSystemEventsServer) Send(event *ctlpb.SystemEvent) error {
+	m.Events = append(m.Events, event)
+	if m.sent != nil {
+		m.sent <- event
+	}
+	return nil
+}
+
+func (m *mockSystemEventsServer) Context() context.Context {
+	return m.ctx
+}
+
+func mockSystemControlService(t *testing.T, log logging.Logger) *ControlService {
+	cs := defaultMockControlService(t, log)
+	cs.harness = newTestMgmtSvc(log).harness
+	cs.membership = system.NewMembership(log, cs.events)
+
+	return cs
+}
+
+func TestControlService_reportEvent_Leader(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	cs := mockSystemControlService(t, log)
+	cs.reportEvent(system.NewEvent(system.EventTypeBioError, 1, "local"))
+
+	events := cs.events.Events(system.EventFilter{})
+	common.AssertEqual(t, 1, len(events), "expected event recorded on MS leader")
+	common.AssertEqual(t, "local", events[0].Reason, "unexpected event")
+}
+
+func TestControlService_SystemEvents(t *testing.T) {
+	start := time.Date(2019, 11, 20, 10, 0, 0, 0, time.UTC)
+
+	for name, tc := range map[string]struct {
+		notLeader bool
+		req       *ctlpb.SystemEventsReq
+		expReason []string
+		expErr    error
+	}{
+		"not MS leader": {
+			notLeader: true,
+			req:       &ctlpb.SystemEventsReq{},
+			expErr:    errors.New("not an access point"),
+		},
+		"all events": {
+			req:       &ctlpb.SystemEventsReq{},
+			expReason: []string{"a", "b", "c"},
+		},
+		"filtered events": {
+			req: &ctlpb.SystemEventsReq{
+				Since: start.Add(time.Second).Format(time.RFC3339),
+				Ranks: []uint32{2},
+			},
+			expReason: []string{"c"},
+		},
+		"bad since": {
+			req:    &ctlpb.SystemEventsReq{Since: "yesterday"},
+			expErr: errors.New("parse since time"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cs := mockSystemControlService(t, log)
+			if tc.notLeader {
+				cs.harness.instances[0]._superblock.MS = false
+			}
+			for i, rank := range []uint32{1, 2, 2} {
+				e := system.NewEvent(system.EventTypeBioError, rank,
+					[]string{"a", "b", "c"}[i])
+				e.Time = start.Add(time.Duration(i) * time.Second)
+				cs.events.Add(e)
+			}
+
+			stream := &mockSystemEventsServer{ctx: context.Background()}
+			err := cs.SystemEvents(tc.req, stream)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			var reasons []string
+			for _, e := range stream.Events {
+				reasons = append(reasons, e.Reason)
+			}
+			if diff := cmp.Diff(tc.expReason, reasons); diff != "" {
+				t.Fatalf("unexpected events (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestControlService_SystemEvents_Follow(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	cs := mockSystemControlService(t, log)
+	cs.events.Add(system.NewEvent(system.EventTypeBioError, 1, "before"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &mockSystemEventsServer{
+		ctx:  ctx,
+		sent: make(chan *ctlpb.SystemEvent, 10),
+	}
+	done := make(chan error)
+	go func() {
+		done <- cs.SystemEvents(&ctlpb.SystemEventsReq{
+			Ranks:  []uint32{1},
+			Follow: true,
+		}, stream)
+	}()
+
+	for _, exp := range []string{"before", "after"} {
+		if exp == "after" {
+			cs.events.Add(system.NewEvent(system.EventTypeBioError, 2, "other rank"))
+			cs.events.Add(system.NewEvent(system.EventTypeBioError, 1, "after"))
+		}
+		select {
+		case e := <-stream.sent:
+			common.AssertEqual(t, exp, e.Reason, "unexpected event")
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for event %q", exp)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, 2, len(stream.Events), "unexpected number of events")
+}
+
+func TestControlService_SystemExclude(t *testing.T) {
+	for name, tc := range map[string]struct {
+		notLeader   bool
+		reintegrate bool
+		ranks       []uint32
+		expResults  []*ctlpb.SystemStopResp_Result
+		expExcluded []uint32
+		expErr      error
+	}{
+		"not MS leader": {
+			notLeader: true,
+			ranks:     []uint32{1},
+			expErr:    errors.New("not an access point"),
+		},
+		"no ranks": {
+			expErr: errors.New("no ranks specified"),
+		},
+		"exclude": {
+			ranks: []uint32{1, 3},
+			expResults: []*ctlpb.SystemStopResp_Result{
+				{Rank: 1, Action: "exclude"},
+				{Rank: 3, Action: "exclude"},
+			},
+			expExcluded: []uint32{0, 1, 3},
+		},
+		"reintegrate": {
+			reintegrate: true,
+			ranks:       []uint32{0, 1},
+			expResults: []*ctlpb.SystemStopResp_Result{
+				{Rank: 0, Action: "reintegrate"},
+				{
+					Rank: 1, Action: "reintegrate", Errored: true,
+					Msg: errors.Wrap(system.FaultMemberNotExcluded, "rank 1").Error(),
+				},
+			},
+			expExcluded: []uint32{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			cs := mockSystemControlService(t, log)
+			if tc.notLeader {
+				cs.harness.instances[0]._superblock.MS = false
+			}
+			addr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:10001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, rank := range []uint32{0, 1, 2} {
+				cs.membership.AddOrUpdate(system.NewMember(rank, "", addr,
+					system.MemberStateStarted), "joined")
+			}
+			if err := cs.membership.Exclude(0, "excluded"); err != nil {
+				t.Fatal(err)
+			}
+
+			var results []*ctlpb.SystemStopResp_Result
+			if tc.reintegrate {
+				var resp *ctlpb.SystemReintegrateResp
+				resp, err = cs.SystemReintegrate(context.TODO(),
+					&ctlpb.SystemReintegrateReq{Ranks: tc.ranks})
+				if resp != nil {
+					results = resp.Results
+				}
+			} else {
+				var resp *ctlpb.SystemExcludeResp
+				resp, err = cs.SystemExclude(context.TODO(),
+					&ctlpb.SystemExcludeReq{Ranks: tc.ranks})
+				if resp != nil {
+					results = resp.Results
+				}
+			}
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expResults, results); diff != "" {
+				t.Fatalf("unexpected results (-want, +got):\n%s\n", diff)
+			}
+			if diff := cmp.Diff(tc.expExcluded, cs.membership.ExcludedRanks()); diff != "" {
+				t.Fatalf("unexpected excluded ranks (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
@@ -26,14 +26,15 @@ package server
 import (
 	"context"
 	"os"
+	"os/user"
 	"path/filepath"
+	"strconv"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/logging"
-	"github.com/daos-stack/daos/src/control/security"
 )
 
 func getDrpcServerSocketPath(sockDir string) string {
@@ -81,8 +82,42 @@ func checkSocketDir(sockDir string) error {
 	return nil
 }
 
+// lookupSocketOwner resolves the user and group names or IDs for the dRPC
+// socket owner, returning -1 for either if not set.
+func lookupSocketOwner(owner, group string) (uid, gid int, err error) {
+	uid, gid = -1, -1
+
+	if owner != "" {
+		u, err := user.Lookup(owner)
+		if err != nil {
+			if u, err = user.LookupId(owner); err != nil {
+				return -1, -1, errors.Wrapf(err, "unable to find socket_owner %q", owner)
+			}
+		}
+		if uid, err = strconv.Atoi(u.Uid); err != nil {
+			return -1, -1, errors.Wrapf(err, "bad uid for socket_owner %q", owner)
+		}
+	}
+
+	if group != "" {
+		g, err := user.LookupGroup(group)
+		if err != nil {
+			if g, err = user.LookupGroupId(group); err != nil {
+				return -1, -1, errors.Wrapf(err, "unable to find socket_group %q", group)
+			}
+		}
+		if gid, err = strconv.Atoi(g.Gid); err != nil {
+			return -1, -1, errors.Wrapf(err, "bad gid for socket_group %q", group)
+		}
+	}
+
+	return uid, gid, nil
+}
+
 // drpcSetup specifies socket path and starts drpc server.
-func drpcSetup(ctx context.Context, log logging.Logger, sockDir string, iosrvs []*IOServerInstance, tc *security.TransportConfig) error {
+func drpcSetup(ctx context.Context, log logging.Logger, cfg *Configuration, iosrvs []*IOServerInstance, rec *drpc.Recorder) error {
+	sockDir := cfg.SocketDir
+
 	// Clean up any previous execution's sockets before we create any new sockets
 	if err := drpcCleanup(sockDir); err != nil {
 		return err
@@ -94,10 +129,39 @@ func drpcSetup(ctx context.Context, log logging.Logger, sockDir string, iosrvs [
 		return errors.Wrap(err, "unable to create socket server")
 	}
 
+	sockMode, err := cfg.socketFileMode()
+	if err != nil {
+		return err
+	}
+	sockUID, sockGID, err := lookupSocketOwner(cfg.SocketOwner, cfg.SocketGroup)
+	if err != nil {
+		return err
+	}
+	drpcServer.WithSocketMode(sockMode).WithSocketOwner(sockUID, sockGID).
+		WithMaxMessageSize(cfg.DrpcMaxMessageSize)
+	if rec != nil {
+		drpcServer.WithRecorder(rec)
+	}
+
+	// Only the I/O servers started by this process, and the socket owner
+	// and group if set, may call the server's modules.
+	peers := drpc.PeerAllowList{
+		UIDs: []uint32{uint32(os.Getuid())},
+		GIDs: []uint32{uint32(os.Getgid())},
+	}
+	if sockUID != -1 {
+		peers.UIDs = append(peers.UIDs, uint32(sockUID))
+	}
+	if sockGID != -1 {
+		peers.GIDs = append(peers.GIDs, uint32(sockGID))
+	}
+
 	// Create and add our modules
-	drpcServer.RegisterRPCModule(NewSecurityModule(log, tc))
-	drpcServer.RegisterRPCModule(&mgmtModule{})
-	drpcServer.RegisterRPCModule(&srvModule{iosrvs})
+	drpcServer.RegisterRPCModule(NewSecurityModule(log, cfg.TransportConfig).
+		WithClockSkew(cfg.CredentialClockSkew).
+		WithAllowedPeers(peers))
+	drpcServer.RegisterRPCModule(&mgmtModule{PeerAllowList: peers})
+	drpcServer.RegisterRPCModule(&srvModule{PeerAllowList: peers, iosrvs: iosrvs})
 
 	if err := drpcServer.Start(); err != nil {
 		return errors.Wrapf(err, "unable to start socket server on %s", sockPath)
@@ -161,10 +225,14 @@ func newDrpcCall(module int32, method int32, bodyMessage proto.Message) (*drpc.C
 	}, nil
 }
 
-// makeDrpcCall opens a drpc connection, sends a message with the
-// protobuf message marshalled in the body, and closes the connection.
+// makeDrpcCall sends a message with the protobuf message marshalled in the
+// body over the client's drpc connection, opening it if necessary. Calls
+// share the connection, so a slow call doesn't block others, and it is
+// reopened on the next call after a failure. The context's deadline bounds
+// the call; one that times out fails with drpc.FaultTimeout, leaving the
+// connection open for the calls still in flight on it.
 // drpc response is returned after basic checks.
-func makeDrpcCall(client drpc.DomainSocketClient, module int32, method int32,
+func makeDrpcCall(ctx context.Context, client drpc.DomainSocketClient, module int32, method int32,
 	body proto.Message) (drpcResp *drpc.Response, err error) {
 
 	drpcCall, err := newDrpcCall(module, method, body)
@@ -172,16 +240,15 @@ func makeDrpcCall(client drpc.DomainSocketClient, module int32, method int32,
 		return drpcResp, errors.Wrap(err, "build drpc call")
 	}
 
-	client.Lock()
-	defer client.Unlock()
-
 	// Forward the request to the I/O server via dRPC
 	if err = client.Connect(); err != nil {
 		return drpcResp, errors.Wrap(err, "connect to client")
 	}
-	defer client.Close()
 
-	if drpcResp, err = client.SendMsg(drpcCall); err != nil {
+	if drpcResp, err = client.SendMsg(ctx, drpcCall); err != nil {
+		if !drpc.FaultTimeout.Equals(err) && errors.Cause(err) != context.Canceled {
+			client.Close()
+		}
 		return drpcResp, errors.Wrap(err, "send message")
 	}
 
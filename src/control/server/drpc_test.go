@@ -24,6 +24,7 @@
 package server
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -246,6 +247,7 @@ func TestDrpc_Errors(t *testing.T) {
 		sendError    error
 		resp         *drpc.Response
 		expErr       error
+		expClosed    bool
 	}{
 		"connect fails": {
 			connectError: errors.New("connect"),
@@ -254,6 +256,11 @@ func TestDrpc_Errors(t *testing.T) {
 		"send msg fails": {
 			sendError: errors.New("send"),
 			expErr:    errors.New("send"),
+			expClosed: true,
+		},
+		"send msg times out": {
+			sendError: drpc.FaultTimeout,
+			expErr:    drpc.FaultTimeout,
 		},
 		"nil resp": {
 			expErr: errors.New("no response"),
@@ -276,8 +283,61 @@ func TestDrpc_Errors(t *testing.T) {
 			}
 			mc := newMockDrpcClient(cfg)
 
-			_, err := makeDrpcCall(mc, drpc.ModuleMgmt, drpc.MethodPoolCreate, &mgmtpb.PoolCreateReq{})
+			_, err := makeDrpcCall(context.Background(), mc, drpc.ModuleMgmt, drpc.MethodPoolCreate, &mgmtpb.PoolCreateReq{})
+			common.CmpErr(t, tc.expErr, err)
+			common.AssertEqual(t, mc.CloseCallCount == 1, tc.expClosed,
+				"connection should only be closed after a failed call which didn't time out")
+		})
+	}
+}
+
+func TestLookupSocketOwner(t *testing.T) {
+	for name, tc := range map[string]struct {
+		owner  string
+		group  string
+		expUID int
+		expGID int
+		expErr error
+	}{
+		"not set": {
+			expUID: -1,
+			expGID: -1,
+		},
+		"names": {
+			owner:  "root",
+			group:  "root",
+			expUID: 0,
+			expGID: 0,
+		},
+		"numeric IDs": {
+			owner:  "0",
+			group:  "0",
+			expUID: 0,
+			expGID: 0,
+		},
+		"group only": {
+			group:  "0",
+			expUID: -1,
+			expGID: 0,
+		},
+		"unknown owner": {
+			owner:  "no-such-daos-user",
+			expUID: -1,
+			expGID: -1,
+			expErr: errors.New("unable to find socket_owner \"no-such-daos-user\""),
+		},
+		"unknown group": {
+			group:  "no-such-daos-group",
+			expUID: -1,
+			expGID: -1,
+			expErr: errors.New("unable to find socket_group \"no-such-daos-group\""),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			uid, gid, err := lookupSocketOwner(tc.owner, tc.group)
 			common.CmpErr(t, tc.expErr, err)
+			common.AssertEqual(t, uid, tc.expUID, "bad uid")
+			common.AssertEqual(t, gid, tc.expGID, "bad gid")
 		})
 	}
 }
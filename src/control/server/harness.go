@@ -31,6 +31,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/system"
 )
@@ -39,23 +40,39 @@ import (
 type IOServerHarness struct {
 	sync.RWMutex
 	log         logging.Logger
+	drpcLog     logging.Logger
 	instances   []*IOServerInstance
 	started     uint32
 	restartable uint32
 	restart     chan struct{}
 	errChan     chan error
+	recorder    *drpc.Recorder
 }
 
 // NewHarness returns an initialized *IOServerHarness
 func NewIOServerHarness(log logging.Logger) *IOServerHarness {
 	return &IOServerHarness{
 		log:       log,
+		drpcLog:   log,
 		instances: make([]*IOServerInstance, 0, maxIoServers),
 		restart:   make(chan struct{}, 1),
 		errChan:   make(chan error, maxIoServers),
 	}
 }
 
+// WithDrpcRecorder records the dRPC calls made to the harness by its
+// instances.
+func (h *IOServerHarness) WithDrpcRecorder(rec *drpc.Recorder) *IOServerHarness {
+	h.recorder = rec
+	return h
+}
+
+// WithDrpcLogger sets the logger used by the harness's dRPC server.
+func (h *IOServerHarness) WithDrpcLogger(log logging.Logger) *IOServerHarness {
+	h.drpcLog = log
+	return h
+}
+
 func (h *IOServerHarness) Instances() []*IOServerInstance {
 	h.RLock()
 	defer h.RUnlock()
@@ -98,7 +115,7 @@ func (h *IOServerHarness) GetMSLeaderInstance() (*IOServerInstance, error) {
 }
 
 // CreateSuperblocks creates instance superblocks as needed.
-func (h *IOServerHarness) CreateSuperblocks(recreate bool) error {
+func (h *IOServerHarness) CreateSuperblocks(ctx context.Context, recreate bool) error {
 	if h.IsStarted() {
 		return errors.Errorf("Can't create superblocks with running instances")
 	}
@@ -107,7 +124,7 @@ func (h *IOServerHarness) CreateSuperblocks(recreate bool) error {
 	toCreate := make([]*IOServerInstance, 0, len(instances))
 
 	for _, instance := range instances {
-		needsSuperblock, err := instance.NeedsSuperblock()
+		needsSuperblock, err := instance.NeedsSuperblock(ctx)
 		if !needsSuperblock {
 			continue
 		}
@@ -128,11 +145,11 @@ func (h *IOServerHarness) CreateSuperblocks(recreate bool) error {
 			if err != nil {
 				return err
 			}
-			if err := instance.CreateSuperblock(mInfo); err != nil {
+			if err := instance.CreateSuperblock(ctx, mInfo); err != nil {
 				return err
 			}
 		} else {
-			if err := instance.CreateSuperblock(&mgmtInfo{}); err != nil {
+			if err := instance.CreateSuperblock(ctx, &mgmtInfo{}); err != nil {
 				return err
 			}
 		}
@@ -153,7 +170,7 @@ func (h *IOServerHarness) AwaitStorageReady(ctx context.Context, skipMissingSupe
 
 	h.log.Infof("Waiting for %s instance storage to be ready...", DataPlaneName)
 	for _, instance := range h.instances {
-		needsScmFormat, err := instance.NeedsScmFormat()
+		needsScmFormat, err := instance.NeedsScmFormat(ctx)
 		if err != nil {
 			h.log.Error(errors.Wrap(err, "failed to check storage formatting").Error())
 			needsScmFormat = true
@@ -164,7 +181,7 @@ func (h *IOServerHarness) AwaitStorageReady(ctx context.Context, skipMissingSupe
 				continue
 			}
 			h.log.Debug("no SCM format required; checking for superblock")
-			needsSuperblock, err := instance.NeedsSuperblock()
+			needsSuperblock, err := instance.NeedsSuperblock(ctx)
 			if err != nil {
 				h.log.Errorf("failed to check instance superblock: %s", err)
 			}
@@ -186,7 +203,7 @@ func (h *IOServerHarness) registerNewMember(membership *system.Membership, insta
 		return errors.Wrap(err, "failed to get member from instance")
 	}
 
-	created, oldState := membership.AddOrUpdate(m)
+	created, oldState := membership.AddOrUpdate(m, "bootstrapped")
 	if created {
 		h.log.Debugf("bootstrapping system member: rank %d, addr %s",
 			m.Rank, m.Addr)
@@ -243,12 +260,12 @@ func (h *IOServerHarness) waitInstancesReady(ctx context.Context) error {
 		}
 
 		if instance.IsMSReplica() {
-			if err := instance.StartManagementService(); err != nil {
+			if err := instance.StartManagementService(ctx); err != nil {
 				return errors.Wrap(err, "failed to start management service")
 			}
 		}
 
-		if err := instance.LoadModules(); err != nil {
+		if err := instance.LoadModules(ctx); err != nil {
 			return errors.Wrap(err, "failed to load I/O server modules")
 		}
 	}
@@ -301,7 +318,7 @@ func (h *IOServerHarness) Start(parent context.Context, membership *system.Membe
 	for {
 		if cfg != nil {
 			// Single daos_server dRPC server to handle all iosrv requests
-			if err := drpcSetup(ctx, h.log, cfg.SocketDir, h.Instances(), cfg.TransportConfig); err != nil {
+			if err := drpcSetup(ctx, h.drpcLog, cfg, h.Instances(), h.recorder); err != nil {
 				return errors.WithMessage(err, "dRPC setup")
 			}
 		}
@@ -359,7 +376,7 @@ func (h *IOServerHarness) StartManagementService(ctx context.Context) error {
 	defer h.RUnlock()
 
 	for _, instance := range h.instances {
-		if err := instance.StartManagementService(); err != nil {
+		if err := instance.StartManagementService(ctx); err != nil {
 			return err
 		}
 	}
@@ -106,7 +106,7 @@ func TestHarnessCreateSuperblocks(t *testing.T) {
 		return addrs, nil
 	}
 
-	if err := h.CreateSuperblocks(false); err != nil {
+	if err := h.CreateSuperblocks(context.Background(), false); err != nil {
 		t.Fatal(err)
 	}
 
@@ -311,7 +311,7 @@ func TestHarnessIOServerStart(t *testing.T) {
 				}
 			}
 
-			if err := harness.CreateSuperblocks(false); err != nil {
+			if err := harness.CreateSuperblocks(context.Background(), false); err != nil {
 				t.Fatal(err)
 			}
 
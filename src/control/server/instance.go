@@ -25,9 +25,12 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -67,13 +70,28 @@ type IOServerInstance struct {
 	instanceReady     chan *srvpb.NotifyReadyReq
 	storageReady      chan struct{}
 	fsRoot            string
+	drpcRecorder      *drpc.Recorder
+	drpcMaxMsgSize    int
+	drpcTimeout       time.Duration
+	logMaskLock       sync.Mutex // serializes changes to the log mask
 
 	sync.RWMutex
 	// these must be protected by a mutex in order to
 	// avoid racy access.
-	_drpcClient   drpc.DomainSocketClient
-	_scmStorageOk bool // cache positive result of NeedsStorageFormat()
-	_superblock   *Superblock
+	_drpcClient     drpc.DomainSocketClient
+	_slowDrpcClient drpc.DomainSocketClient // for slowDrpcMethods, if set
+	_scmStorageOk   bool                    // cache positive result of NeedsStorageFormat()
+	_superblock     *Superblock
+	_logMask        string // mask set at runtime, config applies if empty
+}
+
+// slowDrpcMethods are the management calls which may keep the I/O server
+// busy for long enough to hold up the calls sent after them on the same
+// connection, e.g. health queries. They are made on a connection of their
+// own.
+var slowDrpcMethods = map[int32]bool{
+	drpc.MethodPoolCreate:  true,
+	drpc.MethodPoolDestroy: true,
 }
 
 // NewIOServerInstance returns an *IOServerInstance initialized with
@@ -93,6 +111,26 @@ func NewIOServerInstance(log logging.Logger,
 	}
 }
 
+// WithDrpcRecorder records the dRPC calls made to the instance.
+func (srv *IOServerInstance) WithDrpcRecorder(rec *drpc.Recorder) *IOServerInstance {
+	srv.drpcRecorder = rec
+	return srv
+}
+
+// WithDrpcMaxMessageSize sets the size of the largest dRPC response accepted
+// from the instance.
+func (srv *IOServerInstance) WithDrpcMaxMessageSize(size int) *IOServerInstance {
+	srv.drpcMaxMsgSize = size
+	return srv
+}
+
+// WithDrpcTimeout sets the time allowed for a dRPC call to the instance
+// when the caller's context has no deadline.
+func (srv *IOServerInstance) WithDrpcTimeout(timeout time.Duration) *IOServerInstance {
+	srv.drpcTimeout = timeout
+	return srv
+}
+
 // scmConfig returns the scm configuration assigned to this instance.
 func (srv *IOServerInstance) scmConfig() storage.ScmConfig {
 	return srv.runner.GetConfig().Storage.SCM
@@ -106,7 +144,26 @@ func (srv *IOServerInstance) bdevConfig() storage.BdevConfig {
 func (srv *IOServerInstance) setDrpcClient(c drpc.DomainSocketClient) {
 	srv.Lock()
 	defer srv.Unlock()
+	// close connections to a previous run of the instance
+	if srv._drpcClient != nil {
+		srv._drpcClient.Close()
+	}
+	if srv._slowDrpcClient != nil {
+		srv._slowDrpcClient.Close()
+	}
 	srv._drpcClient = c
+	srv._slowDrpcClient = nil
+}
+
+// setSlowDrpcClient sets the client used for slowDrpcMethods. Until it is
+// set, they are made on the instance's main dRPC client.
+func (srv *IOServerInstance) setSlowDrpcClient(c drpc.DomainSocketClient) {
+	srv.Lock()
+	defer srv.Unlock()
+	if srv._slowDrpcClient != nil {
+		srv._slowDrpcClient.Close()
+	}
+	srv._slowDrpcClient = c
 }
 
 func (srv *IOServerInstance) getDrpcClient() (drpc.DomainSocketClient, error) {
@@ -118,6 +175,19 @@ func (srv *IOServerInstance) getDrpcClient() (drpc.DomainSocketClient, error) {
 	return srv._drpcClient, nil
 }
 
+// getDrpcClientForMethod returns the client on which to make a call to the
+// given dRPC method.
+func (srv *IOServerInstance) getDrpcClientForMethod(method int32) (drpc.DomainSocketClient, error) {
+	srv.RLock()
+	slow := srv._slowDrpcClient
+	srv.RUnlock()
+	if slowDrpcMethods[method] && slow != nil {
+		return slow, nil
+	}
+
+	return srv.getDrpcClient()
+}
+
 // SetIndex sets the server index assigned by the harness.
 func (srv *IOServerInstance) SetIndex(idx uint32) {
 	srv.runner.GetConfig().Index = idx
@@ -131,7 +201,7 @@ func (srv *IOServerInstance) Index() uint32 {
 // MountScmDevice mounts the configured SCM device (DCPM or ramdisk emulation)
 // at the mountpoint specified in the configuration. If the device is already
 // mounted, the function returns nil, indicating success.
-func (srv *IOServerInstance) MountScmDevice() error {
+func (srv *IOServerInstance) MountScmDevice(ctx context.Context) error {
 	scmCfg := srv.scmConfig()
 
 	isMount, err := srv.scmProvider.IsMounted(scmCfg.MountPoint)
@@ -147,13 +217,13 @@ func (srv *IOServerInstance) MountScmDevice() error {
 	var res *scm.MountResponse
 	switch scmCfg.Class {
 	case storage.ScmClassRAM:
-		res, err = srv.scmProvider.MountRamdisk(scmCfg.MountPoint, uint(scmCfg.RamdiskSize))
+		res, err = srv.scmProvider.MountRamdisk(ctx, scmCfg.MountPoint, uint(scmCfg.RamdiskSize))
 	case storage.ScmClassDCPM:
 		if len(scmCfg.DeviceList) != 1 {
 			err = scm.FaultFormatInvalidDeviceCount
 			break
 		}
-		res, err = srv.scmProvider.MountDcpm(scmCfg.DeviceList[0], scmCfg.MountPoint)
+		res, err = srv.scmProvider.MountDcpm(ctx, scmCfg.DeviceList[0], scmCfg.MountPoint)
 	default:
 		err = errors.New(scm.MsgScmClassNotSupported)
 	}
@@ -167,7 +237,7 @@ func (srv *IOServerInstance) MountScmDevice() error {
 
 // NeedsScmFormat probes the configured instance storage and determines whether
 // or not it requires a format operation before it can be used.
-func (srv *IOServerInstance) NeedsScmFormat() (bool, error) {
+func (srv *IOServerInstance) NeedsScmFormat(ctx context.Context) (bool, error) {
 	srv.RLock()
 	if srv._scmStorageOk {
 		srv.RUnlock()
@@ -189,7 +259,7 @@ func (srv *IOServerInstance) NeedsScmFormat() (bool, error) {
 		return false, err
 	}
 
-	res, err := srv.scmProvider.CheckFormat(*req)
+	res, err := srv.scmProvider.CheckFormat(ctx, *req)
 	if err != nil {
 		return false, err
 	}
@@ -204,7 +274,7 @@ func (srv *IOServerInstance) NeedsScmFormat() (bool, error) {
 // daos_io_server instance.
 func (srv *IOServerInstance) Start(ctx context.Context, errChan chan<- error) error {
 	if !srv.hasSuperblock() {
-		if err := srv.ReadSuperblock(); err != nil {
+		if err := srv.ReadSuperblock(ctx); err != nil {
 			return errors.Wrap(err, "start failed; no superblock")
 		}
 	}
@@ -219,6 +289,11 @@ func (srv *IOServerInstance) Start(ctx context.Context, errChan chan<- error) er
 		srv.log.Errorf("unable to log SCM storage stats: %s", err)
 	}
 
+	// a restarted instance picks up the configured mask again
+	srv.logMaskLock.Lock()
+	srv.setRuntimeLogMask("")
+	srv.logMaskLock.Unlock()
+
 	return srv.runner.Start(ctx, errChan)
 }
 
@@ -231,8 +306,14 @@ func (srv *IOServerInstance) IsStarted() bool {
 func (srv *IOServerInstance) NotifyReady(msg *srvpb.NotifyReadyReq) {
 	srv.log.Debugf("%s instance %d ready: %v", DataPlaneName, srv.Index(), msg)
 
-	// Activate the dRPC client connection to this iosrv
-	srv.setDrpcClient(drpc.NewClientConnection(msg.DrpcListenerSock))
+	// Activate the dRPC client connections to this iosrv
+	newClient := func() drpc.DomainSocketClient {
+		return drpc.NewClientConnection(msg.DrpcListenerSock).
+			WithRecorder(srv.drpcRecorder).
+			WithMaxMessageSize(srv.drpcMaxMsgSize)
+	}
+	srv.setDrpcClient(newClient())
+	srv.setSlowDrpcClient(newClient())
 
 	go func() {
 		srv.instanceReady <- msg
@@ -278,11 +359,22 @@ func (srv *IOServerInstance) SetRank(ctx context.Context, ready *srvpb.NotifyRea
 	}
 
 	if !superblock.ValidRank || !superblock.MS {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrap(err, "get hostname")
+		}
+		cfg := srv.runner.GetConfig()
+
 		resp, err := srv.msClient.Join(ctx, &mgmtpb.JoinReq{
-			Uuid:  superblock.UUID,
-			Rank:  r.Uint32(),
-			Uri:   ready.Uri,
-			Nctxs: ready.Nctxs,
+			Uuid:           superblock.UUID,
+			Rank:           r.Uint32(),
+			Uri:            ready.Uri,
+			Nctxs:          ready.Nctxs,
+			Hostname:       hostname,
+			Idx:            srv.Index(),
+			Fabriciface:    cfg.Fabric.Interface,
+			Fabricprovider: cfg.Fabric.Provider,
+			Ntgts:          uint32(cfg.TargetCount),
 			// Addr member populated in msClient
 		})
 		if err != nil {
@@ -303,15 +395,15 @@ func (srv *IOServerInstance) SetRank(ctx context.Context, ready *srvpb.NotifyRea
 		}
 	}
 
-	if err := srv.callSetRank(r); err != nil {
+	if err := srv.callSetRank(ctx, r); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (srv *IOServerInstance) callSetRank(rank ioserver.Rank) error {
-	dresp, err := srv.CallDrpc(drpc.ModuleMgmt, drpc.MethodSetRank, &mgmtpb.SetRankReq{Rank: rank.Uint32()})
+func (srv *IOServerInstance) callSetRank(ctx context.Context, rank ioserver.Rank) error {
+	dresp, err := srv.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodSetRank, &mgmtpb.SetRankReq{Rank: rank.Uint32()})
 	if err != nil {
 		return err
 	}
@@ -330,7 +422,7 @@ func (srv *IOServerInstance) callSetRank(rank ioserver.Rank) error {
 // StartManagementService starts the DAOS management service replica associated
 // with this instance. If no replica is associated with this instance, this
 // function is a no-op.
-func (srv *IOServerInstance) StartManagementService() error {
+func (srv *IOServerInstance) StartManagementService(ctx context.Context) error {
 	superblock := srv.getSuperblock()
 
 	// should have been loaded by now
@@ -340,7 +432,7 @@ func (srv *IOServerInstance) StartManagementService() error {
 
 	if superblock.CreateMS {
 		srv.log.Debugf("create MS (bootstrap=%t)", superblock.BootstrapMS)
-		if err := srv.callCreateMS(superblock); err != nil {
+		if err := srv.callCreateMS(ctx, superblock); err != nil {
 			return err
 		}
 		superblock.CreateMS = false
@@ -353,7 +445,7 @@ func (srv *IOServerInstance) StartManagementService() error {
 
 	if superblock.MS {
 		srv.log.Debug("start MS")
-		if err := srv.callStartMS(); err != nil {
+		if err := srv.callStartMS(ctx); err != nil {
 			return err
 		}
 
@@ -374,11 +466,11 @@ func (srv *IOServerInstance) StartManagementService() error {
 }
 
 // LoadModules initiates the I/O server startup sequence.
-func (srv *IOServerInstance) LoadModules() error {
-	return srv.callSetUp()
+func (srv *IOServerInstance) LoadModules(ctx context.Context) error {
+	return srv.callSetUp(ctx)
 }
 
-func (srv *IOServerInstance) callCreateMS(superblock *Superblock) error {
+func (srv *IOServerInstance) callCreateMS(ctx context.Context, superblock *Superblock) error {
 	msAddr, err := srv.msClient.LeaderAddress()
 	if err != nil {
 		return err
@@ -390,7 +482,7 @@ func (srv *IOServerInstance) callCreateMS(superblock *Superblock) error {
 		req.Addr = msAddr
 	}
 
-	dresp, err := srv.CallDrpc(drpc.ModuleMgmt, drpc.MethodCreateMS, req)
+	dresp, err := srv.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodCreateMS, req)
 	if err != nil {
 		return err
 	}
@@ -406,8 +498,8 @@ func (srv *IOServerInstance) callCreateMS(superblock *Superblock) error {
 	return nil
 }
 
-func (srv *IOServerInstance) callStartMS() error {
-	dresp, err := srv.CallDrpc(drpc.ModuleMgmt, drpc.MethodStartMS, nil)
+func (srv *IOServerInstance) callStartMS(ctx context.Context) error {
+	dresp, err := srv.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodStartMS, nil)
 	if err != nil {
 		return err
 	}
@@ -423,8 +515,8 @@ func (srv *IOServerInstance) callStartMS() error {
 	return nil
 }
 
-func (srv *IOServerInstance) callSetUp() error {
-	dresp, err := srv.CallDrpc(drpc.ModuleMgmt, drpc.MethodSetUp, nil)
+func (srv *IOServerInstance) callSetUp(ctx context.Context) error {
+	dresp, err := srv.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodSetUp, nil)
 	if err != nil {
 		return err
 	}
@@ -440,25 +532,141 @@ func (srv *IOServerInstance) callSetUp() error {
 	return nil
 }
 
+func (srv *IOServerInstance) setRuntimeLogMask(mask string) {
+	srv.Lock()
+	defer srv.Unlock()
+	srv._logMask = mask
+}
+
+// startupLogMask returns the log mask the instance was started with. The
+// log_mask setting takes precedence over D_LOG_MASK in env_vars, which in
+// turn takes precedence over the server's own environment.
+func (srv *IOServerInstance) startupLogMask() string {
+	cfg := srv.runner.GetConfig()
+	if cfg.LogMask != "" {
+		return cfg.LogMask
+	}
+	for _, pair := range cfg.EnvVars {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 2 && kv[0] == engineLogMaskEnvVar && kv[1] != "" {
+			return kv[1]
+		}
+	}
+	if mask := os.Getenv(engineLogMaskEnvVar); mask != "" {
+		return mask
+	}
+
+	return defaultEngineLogMask
+}
+
+// LogMask returns the log mask currently in effect for the instance.
+func (srv *IOServerInstance) LogMask() string {
+	srv.RLock()
+	mask := srv._logMask
+	srv.RUnlock()
+	if mask != "" {
+		return mask
+	}
+
+	return srv.startupLogMask()
+}
+
+// SetLogMask changes the log mask of the running instance without
+// restarting it and returns the mask that was previously in effect.
+// The configured mask is applied again when the instance is restarted.
+func (srv *IOServerInstance) SetLogMask(ctx context.Context, mask string) (string, error) {
+	// Concurrent changes must not both report the same previous mask.
+	srv.logMaskLock.Lock()
+	defer srv.logMaskLock.Unlock()
+
+	prev := srv.LogMask()
+
+	dresp, err := srv.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodSetLogMasks, &mgmtpb.SetLogMasksReq{Masks: mask})
+	if err != nil {
+		return prev, err
+	}
+
+	resp := &mgmtpb.DaosResp{}
+	if err := proto.Unmarshal(dresp.Body, resp); err != nil {
+		return prev, errors.Wrap(err, "unmarshal SetLogMasks response")
+	}
+	if resp.Status != 0 {
+		return prev, errors.Errorf("SetLogMasks %q: %d", mask, resp.Status)
+	}
+	srv.setRuntimeLogMask(mask)
+
+	return prev, nil
+}
+
 // IsMSReplica indicates whether or not this instance is a management service replica.
 func (srv *IOServerInstance) IsMSReplica() bool {
 	return srv.hasSuperblock() && srv.getSuperblock().MS
 }
 
 // CallDrpc makes the supplied dRPC call via this instance's dRPC client.
-func (srv *IOServerInstance) CallDrpc(module, method int32, body proto.Message) (*drpc.Response, error) {
-	dc, err := srv.getDrpcClient()
+// A call that isn't answered before the context's deadline, or the
+// instance's dRPC timeout if the context has none, fails with
+// drpc.FaultTimeout, annotated with the rank that didn't respond.
+func (srv *IOServerInstance) CallDrpc(ctx context.Context, module, method int32, body proto.Message) (*drpc.Response, error) {
+	dc, err := srv.getDrpcClientForMethod(method)
 	if err != nil {
 		return nil, err
 	}
 
-	return makeDrpcCall(dc, module, method, body)
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline && srv.drpcTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, srv.drpcTimeout)
+		defer cancel()
+	}
+
+	resp, err := makeDrpcCall(ctx, dc, module, method, body)
+	if drpc.FaultTimeout.Equals(err) {
+		return nil, errors.Wrapf(err, "%s not responding", srv.rankDescription())
+	}
+
+	return resp, err
+}
+
+// rankDescription identifies the instance by rank for use in messages,
+// falling back to its index if no rank has been assigned.
+func (srv *IOServerInstance) rankDescription() string {
+	if srv.hasSuperblock() {
+		if rank := srv.getSuperblock().Rank; rank != nil {
+			return fmt.Sprintf("rank %s", rank)
+		}
+	}
+
+	return fmt.Sprintf("%s instance %d", DataPlaneName, srv.Index())
 }
 
+// BioErrorNotify logs a blob I/O error detected by the instance and forwards
+// it to the MS leader to be recorded in the system event log.
 func (srv *IOServerInstance) BioErrorNotify(bio *srvpb.BioErrorReq) {
 
 	srv.log.Errorf("I/O server instance %d (target %d) has detected blob I/O error! %v",
 		srv.Index(), bio.TgtId, bio)
+
+	if !srv.hasSuperblock() || srv.msClient == nil {
+		srv.log.Debugf("instance %d rank unknown, blob I/O error not reported",
+			srv.Index())
+		return
+	}
+
+	req := &mgmtpb.ReportEventReq{
+		Rank: srv.getSuperblock().Rank.Uint32(),
+		Type: uint32(system.EventTypeBioError),
+		Reason: fmt.Sprintf("target %d: unmap error %t, read error %t, write error %t",
+			bio.TgtId, bio.UnmapErr, bio.ReadErr, bio.WriteErr),
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), reportEventTimeout)
+		defer cancel()
+
+		if err := srv.msClient.ReportEvent(ctx, req); err != nil {
+			srv.log.Errorf("reporting blob I/O error: %s", err)
+		}
+	}()
 }
 
 // newMember returns reference to a new member struct if one can be retrieved
@@ -479,6 +687,19 @@ func (srv *IOServerInstance) newMember() (*system.Member, error) {
 		return nil, err
 	}
 
-	return system.NewMember(sb.Rank.Uint32(), sb.UUID, addr,
-		system.MemberStateStarted), nil
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, errors.Wrap(err, "get hostname")
+	}
+	cfg := srv.runner.GetConfig()
+
+	member := system.NewMember(sb.Rank.Uint32(), sb.UUID, addr,
+		system.MemberStateStarted)
+	member.Hostname = hostname
+	member.InstanceIdx = srv.Index()
+	member.FabricIface = cfg.Fabric.Interface
+	member.FabricProvider = cfg.Fabric.Provider
+	member.TargetCount = uint32(cfg.TargetCount)
+
+	return member, nil
 }
@@ -24,7 +24,9 @@
 package server
 
 import (
+	"context"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -109,9 +111,13 @@ func TestIOServerInstance_BioError(t *testing.T) {
 }
 
 func TestIOServerInstance_CallDrpc(t *testing.T) {
+	rank := ioserver.Rank(3)
+
 	for name, tc := range map[string]struct {
 		notReady bool
+		rank     *ioserver.Rank
 		resp     *drpc.Response
+		sendErr  error
 		expErr   error
 	}{
 		"not ready": {
@@ -121,6 +127,15 @@ func TestIOServerInstance_CallDrpc(t *testing.T) {
 		"success": {
 			resp: &drpc.Response{},
 		},
+		"timed out": {
+			rank:    &rank,
+			sendErr: drpc.FaultTimeout,
+			expErr:  errors.New("rank 3 not responding"),
+		},
+		"timed out before rank assigned": {
+			sendErr: drpc.FaultTimeout,
+			expErr:  errors.New("instance 0 not responding"),
+		},
 	} {
 		t.Run(name, func(t *testing.T) {
 			log, buf := logging.NewTestLogger(t.Name())
@@ -129,12 +144,135 @@ func TestIOServerInstance_CallDrpc(t *testing.T) {
 			if !tc.notReady {
 				cfg := &mockDrpcClientConfig{
 					SendMsgResponse: tc.resp,
+					SendMsgError:    tc.sendErr,
 				}
 				instance.setDrpcClient(newMockDrpcClient(cfg))
 			}
+			if tc.rank != nil {
+				instance.setSuperblock(&Superblock{Rank: tc.rank, ValidRank: true})
+			}
 
-			_, err := instance.CallDrpc(drpc.ModuleMgmt, drpc.MethodPoolCreate, &mgmtpb.PoolCreateReq{})
+			_, err := instance.CallDrpc(context.Background(), drpc.ModuleMgmt, drpc.MethodPoolCreate, &mgmtpb.PoolCreateReq{})
 			common.CmpErr(t, tc.expErr, err)
+			if tc.sendErr != nil && !drpc.FaultTimeout.Equals(err) {
+				t.Fatalf("expected timeout fault, got %v", err)
+			}
+		})
+	}
+}
+
+func TestIOServerInstance_CallDrpc_SlowMethods(t *testing.T) {
+	for name, tc := range map[string]struct {
+		method     int32
+		noSlowConn bool
+		expSlow    bool
+	}{
+		"pool create": {
+			method:  drpc.MethodPoolCreate,
+			expSlow: true,
+		},
+		"pool destroy": {
+			method:  drpc.MethodPoolDestroy,
+			expSlow: true,
+		},
+		"health query": {
+			method: drpc.MethodBioHealth,
+		},
+		"pool create without slow connection": {
+			method:     drpc.MethodPoolCreate,
+			noSlowConn: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			instance := getTestIOServerInstance(log)
+			mainClient := newMockDrpcClient(&mockDrpcClientConfig{SendMsgResponse: &drpc.Response{}})
+			slowClient := newMockDrpcClient(&mockDrpcClientConfig{SendMsgResponse: &drpc.Response{}})
+			instance.setDrpcClient(mainClient)
+			if !tc.noSlowConn {
+				instance.setSlowDrpcClient(slowClient)
+			}
+
+			if _, err := instance.CallDrpc(context.Background(), drpc.ModuleMgmt, tc.method, nil); err != nil {
+				t.Fatal(err)
+			}
+
+			common.AssertEqual(t, slowClient.SendMsgInputCall != nil, tc.expSlow,
+				"call made on the wrong connection")
+			common.AssertEqual(t, mainClient.SendMsgInputCall != nil, !tc.expSlow,
+				"call made on the wrong connection")
+		})
+	}
+}
+
+func TestIOServerInstance_CallDrpc_StalledServer(t *testing.T) {
+	tmpDir, cleanup := common.CreateTestDir(t)
+	defer cleanup()
+
+	// the server accepts the connection and reads calls but never responds
+	sockPath := filepath.Join(tmpDir, "stalled.sock")
+	sock, sockCleanup := common.CreateTestSocket(t, sockPath)
+	defer sockCleanup()
+	go func() {
+		conn, err := sock.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, drpc.MaxMsgSize)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	rank := ioserver.Rank(2)
+	for name, tc := range map[string]struct {
+		ctxTimeout time.Duration
+	}{
+		"default timeout": {},
+		"context deadline": {
+			ctxTimeout: 50 * time.Millisecond,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			instance := getTestIOServerInstance(log).
+				WithDrpcTimeout(50 * time.Millisecond)
+			if tc.ctxTimeout != 0 {
+				// the context's deadline takes precedence
+				instance.WithDrpcTimeout(time.Hour)
+			}
+			instance.setSuperblock(&Superblock{Rank: &rank, ValidRank: true})
+			instance.setDrpcClient(drpc.NewClientConnection(sockPath))
+
+			ctx := context.Background()
+			if tc.ctxTimeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tc.ctxTimeout)
+				defer cancel()
+			}
+
+			done := make(chan error, 1)
+			go func() {
+				_, err := instance.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPoolCreate, &mgmtpb.PoolCreateReq{})
+				done <- err
+			}()
+
+			select {
+			case err := <-done:
+				common.CmpErr(t, errors.New("rank 2 not responding"), err)
+				if !drpc.FaultTimeout.Equals(err) {
+					t.Fatalf("expected timeout fault, got %v", err)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("call to stalled server did not time out")
+			}
 		})
 	}
 }
@@ -227,7 +365,7 @@ func TestIOServerInstance_MountScmDevice(t *testing.T) {
 			mp := scm.NewMockProvider(log, nil, tc.msCfg)
 			instance := NewIOServerInstance(log, nil, mp, nil, runner)
 
-			gotErr := instance.MountScmDevice()
+			gotErr := instance.MountScmDevice(context.Background())
 			common.CmpErr(t, tc.expErr, gotErr)
 		})
 	}
@@ -353,7 +491,7 @@ func TestIOServerInstance_NeedsScmFormat(t *testing.T) {
 			mp := scm.NewMockProvider(log, tc.mbCfg, tc.msCfg)
 			instance := NewIOServerInstance(log, nil, mp, nil, runner)
 
-			gotNeedsFormat, gotErr := instance.NeedsScmFormat()
+			gotNeedsFormat, gotErr := instance.NeedsScmFormat(context.Background())
 			common.CmpErr(t, tc.expErr, gotErr)
 			if diff := cmp.Diff(tc.expNeedsFormat, gotNeedsFormat); diff != "" {
 				t.Fatalf("unexpected needs format (-want, +got):\n%s\n", diff)
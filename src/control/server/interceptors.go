@@ -0,0 +1,150 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package server
+
+import (
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
+)
+
+// authorizeMethod checks that the client certificate presented with the
+// request grants a role that the policy permits to call the method.
+func authorizeMethod(ctx context.Context, policy security.AuthorizationPolicy, method string) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return errors.New("peer details not found in context")
+	}
+
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.PeerCertificates) == 0 {
+		return security.FaultMethodUnauthorized(method, p.Addr.String(), nil)
+	}
+
+	roles := security.CertificateRoles(tlsInfo.State.PeerCertificates[0])
+	if !policy.Authorize(roles, method) {
+		return security.FaultMethodUnauthorized(method, requestIdentity(ctx), roles)
+	}
+
+	return nil
+}
+
+// unaryAuthInterceptor rejects unary calls from clients not authorized to
+// call the method.
+func unaryAuthInterceptor(log logging.Logger, policy security.AuthorizationPolicy) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := authorizeMethod(ctx, policy, info.FullMethod); err != nil {
+			log.Errorf("denied gRPC call: %s", err)
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor rejects streaming calls from clients not authorized
+// to call the method.
+func streamAuthInterceptor(log logging.Logger, policy security.AuthorizationPolicy) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := authorizeMethod(ss.Context(), policy, info.FullMethod); err != nil {
+			log.Errorf("denied gRPC call: %s", err)
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// chainUnaryInterceptors combines interceptors into a single interceptor,
+// the first interceptor is the outermost.
+func chainUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(ctx context.Context, req interface{}) (interface{}, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+
+		return chained(ctx, req)
+	}
+}
+
+// chainStreamInterceptors combines interceptors into a single interceptor,
+// the first interceptor is the outermost.
+func chainStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		chained := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], chained
+			chained = func(srv interface{}, ss grpc.ServerStream) error {
+				return interceptor(srv, ss, info, next)
+			}
+		}
+
+		return chained(srv, ss)
+	}
+}
+
+// serverOptions returns the options for the gRPC server securing connections
+// as specified in the transport config and, if secured, authorizing calls
+// against the configured policy. Calls to methods which modify the system
+// are recorded in the audit log if supplied, including denied calls.
+func serverOptions(log logging.Logger, cfg *security.TransportConfig, auditLog *logging.AuditLogger) ([]grpc.ServerOption, error) {
+	tcOpt, err := security.ServerOptionForTransportConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts := []grpc.ServerOption{tcOpt}
+
+	var unary []grpc.UnaryServerInterceptor
+	var stream []grpc.StreamServerInterceptor
+
+	if auditLog != nil {
+		unary = append(unary, unaryAuditInterceptor(log, auditLog))
+		stream = append(stream, streamAuditInterceptor(log, auditLog))
+	}
+
+	if cfg.AllowInsecure {
+		log.Debug("insecure transport, gRPC method authorization disabled")
+	} else {
+		policy := cfg.AuthPolicy()
+		unary = append(unary, unaryAuthInterceptor(log, policy))
+		stream = append(stream, streamAuthInterceptor(log, policy))
+	}
+
+	if len(unary) > 0 {
+		opts = append(opts,
+			grpc.UnaryInterceptor(chainUnaryInterceptors(unary...)),
+			grpc.StreamInterceptor(chainStreamInterceptors(stream...)),
+		)
+	}
+
+	return opts, nil
+}
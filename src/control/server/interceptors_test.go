@@ -0,0 +1,109 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
+)
+
+func peerContext(certCN string) context.Context {
+	p := &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 10001}}
+	if certCN != "" {
+		p.AuthInfo = credentials.TLSInfo{
+			State: tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{
+					{Subject: pkix.Name{CommonName: certCN}},
+				},
+			},
+		}
+	}
+
+	return peer.NewContext(context.Background(), p)
+}
+
+func TestServer_UnaryAuthInterceptor(t *testing.T) {
+	for name, tc := range map[string]struct {
+		ctx        context.Context
+		method     string
+		expHandled bool
+		expErr     error
+	}{
+		"admin format": {
+			ctx:        peerContext(security.RoleAdmin),
+			method:     "/ctl.MgmtCtl/StorageFormat",
+			expHandled: true,
+		},
+		"agent attach info": {
+			ctx:        peerContext(security.RoleAgent),
+			method:     "/mgmt.MgmtSvc/GetAttachInfo",
+			expHandled: true,
+		},
+		"agent format": {
+			ctx:    peerContext(security.RoleAgent),
+			method: "/ctl.MgmtCtl/StorageFormat",
+			expErr: security.FaultMethodUnauthorized("/ctl.MgmtCtl/StorageFormat",
+				"agent@10.0.0.1:10001", []string{security.RoleAgent}),
+		},
+		"server pool destroy": {
+			ctx:    peerContext(security.RoleServer),
+			method: "/mgmt.MgmtSvc/PoolDestroy",
+			expErr: security.FaultMethodUnauthorized("/mgmt.MgmtSvc/PoolDestroy",
+				"server@10.0.0.1:10001", []string{security.RoleServer}),
+		},
+		"no certificate": {
+			ctx:    peerContext(""),
+			method: "/mgmt.MgmtSvc/GetAttachInfo",
+			expErr: security.FaultMethodUnauthorized("/mgmt.MgmtSvc/GetAttachInfo",
+				"10.0.0.1:10001", nil),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			var handled bool
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handled = true
+				return nil, nil
+			}
+
+			interceptor := unaryAuthInterceptor(log, security.DefaultAuthorizationPolicy())
+			_, err := interceptor(tc.ctx, nil, &grpc.UnaryServerInfo{FullMethod: tc.method}, handler)
+			common.CmpErr(t, tc.expErr, err)
+			common.AssertEqual(t, tc.expHandled, handled, "unexpected handler invocation")
+		})
+	}
+}
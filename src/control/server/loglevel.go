@@ -24,15 +24,34 @@ package server
 
 import "github.com/daos-stack/daos/src/control/logging"
 
-// ControlLogLevel is a type that specifies log levels
-type ControlLogLevel logging.LogLevel
+const (
+	// logSubsystemDrpc is the subsystem for messages from the
+	// server's dRPC listener.
+	logSubsystemDrpc = "drpc"
+	// logSubsystemStorage is the subsystem for messages from the
+	// SCM and NVMe storage providers.
+	logSubsystemStorage = "storage"
+
+	// defaultEngineLogMask is the log mask (D_LOG_MASK) used by an
+	// I/O server instance which has none configured.
+	defaultEngineLogMask = "ERR"
+	// engineLogMaskEnvVar sets the log mask of an I/O server instance
+	// when log_mask is not configured.
+	engineLogMaskEnvVar = "D_LOG_MASK"
+)
+
+// ControlLogLevel is a type that specifies the control plane log level,
+// optionally followed by levels for individual subsystems which override
+// it, e.g. "info,drpc=debug,storage=warn".
+type ControlLogLevel logging.LogMask
 
 // TODO(mjmac): Evaluate whether or not this layer of indirection
 // adds any value.
-const (
-	ControlLogLevelDebug = ControlLogLevel(logging.LogLevelDebug)
-	ControlLogLevelInfo  = ControlLogLevel(logging.LogLevelInfo)
-	ControlLogLevelError = ControlLogLevel(logging.LogLevelError)
+var (
+	ControlLogLevelDebug = ControlLogLevel{Level: logging.LogLevelDebug}
+	ControlLogLevelInfo  = ControlLogLevel{Level: logging.LogLevelInfo}
+	ControlLogLevelWarn  = ControlLogLevel{Level: logging.LogLevelWarn}
+	ControlLogLevelError = ControlLogLevel{Level: logging.LogLevelError}
 )
 
 // UnmarshalYAML implements yaml.Unmarshaler on ControlLogMask struct
@@ -42,11 +61,11 @@ func (c *ControlLogLevel) UnmarshalYAML(unmarshal func(interface{}) error) error
 		return err
 	}
 
-	var level logging.LogLevel
-	if err := level.SetString(strLevel); err != nil {
+	mask, err := logging.ParseLogMask(strLevel)
+	if err != nil {
 		return err
 	}
-	*c = ControlLogLevel(level)
+	*c = ControlLogLevel(mask)
 	return nil
 }
 
@@ -55,5 +74,5 @@ func (c ControlLogLevel) MarshalYAML() (interface{}, error) {
 }
 
 func (c ControlLogLevel) String() string {
-	return logging.LogLevel(c).String()
+	return logging.LogMask(c).String()
 }
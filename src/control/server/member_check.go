@@ -0,0 +1,191 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/system"
+)
+
+// memberPingFn checks whether a system member is responsive.
+type memberPingFn func(context.Context, *IOServerInstance, *system.Member) error
+
+// memberChecker periodically checks the liveness of system members when
+// running on the MS leader and updates membership to reflect the results.
+//
+// Started members missing maxMisses consecutive checks are marked as
+// unresponsive, unresponsive members that answer a check are marked as
+// started again. Members in an unknown state, such as those which have just
+// been reintegrated, are resolved to started or unresponsive in the same way.
+type memberChecker struct {
+	log        logging.Logger
+	harness    *IOServerHarness
+	membership *system.Membership
+	interval   time.Duration
+	maxMisses  int
+	misses     map[uint32]int
+	ping       memberPingFn
+}
+
+// pingMember issues a PingRank request to the control plane managing the
+// member's rank.
+func pingMember(ctx context.Context, leader *IOServerInstance, member *system.Member) error {
+	_, err := leader.msClient.PingRank(ctx, member.Addr.String(),
+		&mgmtpb.PingRankReq{Rank: member.Rank})
+
+	return err
+}
+
+func newMemberChecker(log logging.Logger, h *IOServerHarness, m *system.Membership, cfg *Configuration) *memberChecker {
+	return &memberChecker{
+		log:        log,
+		harness:    h,
+		membership: m,
+		interval:   cfg.MemberCheckInterval,
+		maxMisses:  cfg.MemberCheckMisses,
+		misses:     make(map[uint32]int),
+		ping:       pingMember,
+	}
+}
+
+// isChecked returns true if liveness checks apply to members in given state.
+func isChecked(state system.MemberState) bool {
+	return state == system.MemberStateStarted ||
+		state == system.MemberStateUnresponsive ||
+		state == system.MemberStateUnknown
+}
+
+// memberCheckResult is the outcome of a liveness check on a member in the
+// given state.
+type memberCheckResult struct {
+	state system.MemberState
+	err   error
+}
+
+// checkMembers pings all started, unresponsive or unknown members
+// concurrently and updates member state based on the results.
+func (mc *memberChecker) checkMembers(ctx context.Context) {
+	leader, err := mc.harness.GetMSLeaderInstance()
+	if err != nil {
+		// only the MS leader checks members, forget stale results in
+		// case leadership is regained later
+		mc.misses = make(map[uint32]int)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mc.interval)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[uint32]memberCheckResult)
+	for _, member := range mc.membership.Members() {
+		if !isChecked(member.State()) {
+			delete(mc.misses, member.Rank)
+			continue
+		}
+
+		wg.Add(1)
+		go func(m *system.Member) {
+			defer wg.Done()
+
+			err := mc.ping(ctx, leader, m)
+
+			mu.Lock()
+			results[m.Rank] = memberCheckResult{m.State(), err}
+			mu.Unlock()
+		}(member)
+	}
+	wg.Wait()
+
+	for rank, res := range results {
+		mc.updateMember(rank, res.state, res.err)
+	}
+}
+
+// updateMember applies the result of a liveness check to a member which was
+// in the given state when checked, logging any resulting state transition.
+func (mc *memberChecker) updateMember(rank uint32, from system.MemberState, pingErr error) {
+	to := system.MemberStateStarted
+	reason := "answered liveness check"
+
+	if pingErr == nil {
+		delete(mc.misses, rank)
+		if from == system.MemberStateStarted {
+			return
+		}
+	} else {
+		mc.misses[rank]++
+		mc.log.Debugf("rank %d missed liveness check %d/%d: %s", rank,
+			mc.misses[rank], mc.maxMisses, pingErr)
+		if mc.misses[rank] < mc.maxMisses {
+			return
+		}
+		if from == system.MemberStateUnresponsive {
+			return
+		}
+		to = system.MemberStateUnresponsive
+		reason = fmt.Sprintf("missed %d liveness checks: %s",
+			mc.misses[rank], pingErr)
+	}
+
+	changed, err := mc.membership.CompareAndSetMemberState(rank, from, to, reason)
+	if err != nil {
+		mc.log.Error(errors.WithMessage(err, "liveness check").Error())
+		return
+	}
+	if changed {
+		mc.log.Infof("rank %d state %s->%s at %s", rank, from, to,
+			time.Now().Format(time.RFC3339))
+	}
+}
+
+// Start runs liveness checks at the configured interval until the context is
+// canceled. Checks are disabled if the interval is zero.
+func (mc *memberChecker) Start(ctx context.Context) {
+	if mc.interval == 0 {
+		mc.log.Debug("member liveness checks disabled")
+		return
+	}
+
+	ticker := time.NewTicker(mc.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			mc.checkMembers(ctx)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/system"
+)
+
+func TestServer_MemberChecker(t *testing.T) {
+	addr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:10001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pingErr := errors.New("no response")
+
+	for name, tc := range map[string]struct {
+		notLeader bool
+		state     system.MemberState
+		pingErrs  []error // one per check
+		expPings  int
+		expState  system.MemberState
+	}{
+		"started member answers": {
+			state:    system.MemberStateStarted,
+			pingErrs: []error{nil, nil, nil},
+			expPings: 3,
+			expState: system.MemberStateStarted,
+		},
+		"started member misses below threshold": {
+			state:    system.MemberStateStarted,
+			pingErrs: []error{pingErr, pingErr},
+			expPings: 2,
+			expState: system.MemberStateStarted,
+		},
+		"started member misses are not consecutive": {
+			state:    system.MemberStateStarted,
+			pingErrs: []error{pingErr, pingErr, nil, pingErr},
+			expPings: 4,
+			expState: system.MemberStateStarted,
+		},
+		"started member becomes unresponsive": {
+			state:    system.MemberStateStarted,
+			pingErrs: []error{pingErr, pingErr, pingErr},
+			expPings: 3,
+			expState: system.MemberStateUnresponsive,
+		},
+		"unresponsive member answers": {
+			state:    system.MemberStateUnresponsive,
+			pingErrs: []error{nil},
+			expPings: 1,
+			expState: system.MemberStateStarted,
+		},
+		"unknown member answers": {
+			state:    system.MemberStateUnknown,
+			pingErrs: []error{nil},
+			expPings: 1,
+			expState: system.MemberStateStarted,
+		},
+		"unknown member becomes unresponsive": {
+			state:    system.MemberStateUnknown,
+			pingErrs: []error{pingErr, pingErr, pingErr},
+			expPings: 3,
+			expState: system.MemberStateUnresponsive,
+		},
+		"stopped member not checked": {
+			state:    system.MemberStateStopped,
+			pingErrs: []error{pingErr, pingErr, pingErr},
+			expState: system.MemberStateStopped,
+		},
+		"not MS leader": {
+			notLeader: true,
+			state:     system.MemberStateStarted,
+			pingErrs:  []error{pingErr, pingErr, pingErr},
+			expState:  system.MemberStateStarted,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			harness := newTestMgmtSvc(log).harness
+			if tc.notLeader {
+				harness.instances[0]._superblock.MS = false
+			}
+
+			membership := system.NewMembership(log, nil)
+			if _, err := membership.Add(system.NewMember(1, "", addr, tc.state)); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := NewConfiguration().
+				WithMemberCheckInterval(time.Second).
+				WithMemberCheckMisses(3)
+			mc := newMemberChecker(log, harness, membership, cfg)
+
+			var pings int
+			var nextErr error
+			mc.ping = func(_ context.Context, _ *IOServerInstance, m *system.Member) error {
+				pings++
+				return nextErr
+			}
+
+			for _, err := range tc.pingErrs {
+				nextErr = err
+				mc.checkMembers(context.TODO())
+			}
+
+			common.AssertEqual(t, tc.expPings, pings, "unexpected number of pings")
+
+			member, err := membership.Get(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			common.AssertEqual(t, tc.expState, member.State(), "unexpected member state")
+		})
+	}
+}
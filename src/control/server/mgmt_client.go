@@ -37,7 +37,8 @@ import (
 )
 
 const (
-	retryDelay = 3 * time.Second
+	retryDelay         = 3 * time.Second
+	reportEventTimeout = 10 * time.Second
 )
 
 type (
@@ -238,6 +239,64 @@ func (msc *mgmtSvcClient) Stop(ctx context.Context, destAddr string, req *mgmtpb
 	return
 }
 
+// PingRank calls function remotely over gRPC on server listening at destAddr.
+//
+// Shipped function issues a single PingRank request using MgmtSvcClient to
+// check the designated rank is running, no retries are attempted so that the
+// liveness of the rank is reflected in the result.
+func (msc *mgmtSvcClient) PingRank(ctx context.Context, destAddr string, req *mgmtpb.PingRankReq) (resp *mgmtpb.DaosResp, pingErr error) {
+	pingErr = msc.withConnection(ctx, destAddr,
+		func(ctx context.Context, pbClient mgmtpb.MgmtSvcClient) error {
+
+			prefix := fmt.Sprintf("ping(%s, %+v)", destAddr, *req)
+			msc.log.Debugf(prefix + " begin")
+			defer msc.log.Debugf(prefix + " end")
+
+			var err error
+			resp, err = pbClient.PingRank(ctx, req)
+			if err != nil {
+				return errors.Wrap(err, prefix)
+			}
+			if resp == nil {
+				return errors.New("unexpected nil response status")
+			}
+			if resp.Status != 0 {
+				return errors.Errorf("%s: status %d", prefix, resp.Status)
+			}
+
+			return nil
+		})
+
+	return
+}
+
+// ReportEvent forwards an event detected on this server to the MS leader to be
+// recorded in the system event log, no retries are attempted.
+func (msc *mgmtSvcClient) ReportEvent(ctx context.Context, req *mgmtpb.ReportEventReq) error {
+	ap, err := msc.LeaderAddress()
+	if err != nil {
+		return err
+	}
+
+	return msc.withConnection(ctx, ap,
+		func(ctx context.Context, pbClient mgmtpb.MgmtSvcClient) error {
+
+			prefix := fmt.Sprintf("report event(%s, %+v)", ap, *req)
+			msc.log.Debugf(prefix + " begin")
+			defer msc.log.Debugf(prefix + " end")
+
+			resp, err := pbClient.ReportEvent(ctx, req)
+			if err != nil {
+				return errors.Wrap(err, prefix)
+			}
+			if resp.GetStatus() != 0 {
+				return errors.Errorf("%s: status %d", prefix, resp.GetStatus())
+			}
+
+			return nil
+		})
+}
+
 // Start calls function remotely over gRPC on server listening at destAddr.
 //
 // Shipped function issues StartRanks requests using MgmtSvcClient to
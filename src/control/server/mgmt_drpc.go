@@ -33,7 +33,9 @@ import (
 
 // mgmtModule represents the daos_server mgmt dRPC module. It sends dRPCs to
 // the daos_io_server iosrv module (src/iosrv).
-type mgmtModule struct{}
+type mgmtModule struct {
+	drpc.PeerAllowList
+}
 
 // HandleCall is the handler for calls to the mgmtModule
 func (m *mgmtModule) HandleCall(session *drpc.Session, method int32, body []byte) ([]byte, error) {
@@ -48,6 +50,7 @@ func (m *mgmtModule) ID() int32 {
 // srvModule represents the daos_server dRPC module. It handles dRPCs sent by
 // the daos_io_server iosrv module (src/iosrv).
 type srvModule struct {
+	drpc.PeerAllowList
 	iosrvs []*IOServerInstance
 }
 
@@ -24,6 +24,7 @@
 package server
 
 import (
+	"fmt"
 	"net"
 	"os/exec"
 	"strconv"
@@ -32,6 +33,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
 
 	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
@@ -152,13 +154,15 @@ type mgmtSvc struct {
 	log        logging.Logger
 	harness    *IOServerHarness
 	membership *system.Membership // if MS leader, system membership list
+	events     *system.EventLog   // if MS leader, system event log
 }
 
-func newMgmtSvc(h *IOServerHarness, m *system.Membership) *mgmtSvc {
+func newMgmtSvc(h *IOServerHarness, m *system.Membership, e *system.EventLog) *mgmtSvc {
 	return &mgmtSvc{
 		log:        h.log,
 		harness:    h,
 		membership: m,
+		events:     e,
 	}
 }
 
@@ -168,7 +172,7 @@ func (svc *mgmtSvc) GetAttachInfo(ctx context.Context, req *mgmtpb.GetAttachInfo
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodGetAttachInfo, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodGetAttachInfo, req)
 	if err != nil {
 		return nil, err
 	}
@@ -178,6 +182,18 @@ func (svc *mgmtSvc) GetAttachInfo(ctx context.Context, req *mgmtpb.GetAttachInfo
 		return nil, errors.Wrap(err, "unmarshal GetAttachInfo response")
 	}
 
+	// clients should not attempt to contact excluded ranks
+	psrs := make([]*mgmtpb.GetAttachInfoResp_Psr, 0, len(resp.Psrs))
+	for _, psr := range resp.Psrs {
+		if svc.membership.IsExcluded(psr.GetRank()) {
+			svc.log.Debugf("omitting excluded rank %d from attach info",
+				psr.GetRank())
+			continue
+		}
+		psrs = append(psrs, psr)
+	}
+	resp.Psrs = psrs
+
 	return resp, nil
 }
 
@@ -204,6 +220,24 @@ func getPeerListenAddr(ctx context.Context, listenAddrStr string) (net.Addr, err
 		net.JoinHostPort(tcpAddr.IP.String(), portStr))
 }
 
+// requestIdentity returns the identity of the sender of a gRPC request, the
+// common name from the peer certificate qualified by the peer address if the
+// connection is secured with TLS or the peer address otherwise.
+func requestIdentity(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+		if certs := tlsInfo.State.PeerCertificates; len(certs) > 0 {
+			return fmt.Sprintf("%s@%s", certs[0].Subject.CommonName, p.Addr)
+		}
+	}
+
+	return p.Addr.String()
+}
+
 func (svc *mgmtSvc) Join(ctx context.Context, req *mgmtpb.JoinReq) (*mgmtpb.JoinResp, error) {
 	// combine peer (sender) IP (from context) with listening port (from
 	// joining instance's host addr, in request params) as addr to reply to.
@@ -218,7 +252,20 @@ func (svc *mgmtSvc) Join(ctx context.Context, req *mgmtpb.JoinReq) (*mgmtpb.Join
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodJoin, req)
+	// Excluded ranks may not rejoin until reintegrated. The data plane
+	// registers the rank as part of the join, so the check must be made
+	// first; an instance without a rank is matched to a member by UUID.
+	rank := req.GetRank()
+	if rank == uint32(ioserver.NilRank) {
+		if member, err := svc.membership.GetByUUID(req.GetUuid()); err == nil {
+			rank = member.Rank
+		}
+	}
+	if svc.membership.IsExcluded(rank) {
+		return nil, errors.Wrapf(system.FaultMemberExcluded, "rank %d", rank)
+	}
+
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodJoin, req)
 	if err != nil {
 		return nil, err
 	}
@@ -228,6 +275,16 @@ func (svc *mgmtSvc) Join(ctx context.Context, req *mgmtpb.JoinReq) (*mgmtpb.Join
 		return nil, errors.Wrap(err, "unmarshal Join response")
 	}
 
+	// The data plane only assigns an excluded rank to an instance it has
+	// already registered under that rank, which the MS didn't know about
+	// (e.g. after restarting). Refuse to record it as a member.
+	if resp.GetStatus() == 0 && svc.membership.IsExcluded(resp.GetRank()) {
+		svc.log.Errorf("excluded rank %d joined with unknown uuid %s",
+			resp.GetRank(), req.GetUuid())
+		return nil, errors.Wrapf(system.FaultMemberExcluded, "rank %d",
+			resp.GetRank())
+	}
+
 	// if join successful, record membership
 	if resp.GetStatus() == 0 {
 		newState := system.MemberStateEvicted
@@ -236,8 +293,13 @@ func (svc *mgmtSvc) Join(ctx context.Context, req *mgmtpb.JoinReq) (*mgmtpb.Join
 		}
 
 		member := system.NewMember(resp.GetRank(), req.GetUuid(), replyAddr, newState)
+		member.Hostname = req.GetHostname()
+		member.InstanceIdx = req.GetIdx()
+		member.FabricIface = req.GetFabriciface()
+		member.FabricProvider = req.GetFabricprovider()
+		member.TargetCount = req.GetNtgts()
 
-		created, oldState := svc.membership.AddOrUpdate(member)
+		created, oldState := svc.membership.AddOrUpdate(member, "joined")
 		if created {
 			svc.log.Debugf("new system member: rank %d, addr %s",
 				resp.GetRank(), replyAddr)
@@ -283,7 +345,7 @@ func (svc *mgmtSvc) PoolCreate(ctx context.Context, req *mgmtpb.PoolCreateReq) (
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodPoolCreate, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPoolCreate, req)
 	if err != nil {
 		return nil, err
 	}
@@ -307,7 +369,7 @@ func (svc *mgmtSvc) PoolDestroy(ctx context.Context, req *mgmtpb.PoolDestroyReq)
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodPoolDestroy, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPoolDestroy, req)
 	if err != nil {
 		return nil, err
 	}
@@ -335,7 +397,7 @@ func (svc *mgmtSvc) PoolQuery(ctx context.Context, req *mgmtpb.PoolQueryReq) (*m
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodPoolQuery, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPoolQuery, req)
 	if err != nil {
 		return nil, err
 	}
@@ -395,7 +457,7 @@ func (svc *mgmtSvc) PoolSetProp(ctx context.Context, req *mgmtpb.PoolSetPropReq)
 
 	svc.log.Debugf("MgmtSvc.PoolSetProp dispatch, req (converted):%+v", *newReq)
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodPoolSetProp, newReq)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPoolSetProp, newReq)
 	if err != nil {
 		return nil, err
 	}
@@ -437,7 +499,7 @@ func (svc *mgmtSvc) PoolGetACL(ctx context.Context, req *mgmtpb.GetACLReq) (*mgm
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodPoolGetACL, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPoolGetACL, req)
 	if err != nil {
 		return nil, err
 	}
@@ -461,7 +523,7 @@ func (svc *mgmtSvc) PoolOverwriteACL(ctx context.Context, req *mgmtpb.ModifyACLR
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodPoolOverwriteACL, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPoolOverwriteACL, req)
 	if err != nil {
 		return nil, err
 	}
@@ -486,7 +548,7 @@ func (svc *mgmtSvc) PoolUpdateACL(ctx context.Context, req *mgmtpb.ModifyACLReq)
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodPoolUpdateACL, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPoolUpdateACL, req)
 	if err != nil {
 		return nil, err
 	}
@@ -511,7 +573,7 @@ func (svc *mgmtSvc) PoolDeleteACL(ctx context.Context, req *mgmtpb.DeleteACLReq)
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodPoolDeleteACL, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPoolDeleteACL, req)
 	if err != nil {
 		return nil, err
 	}
@@ -535,7 +597,7 @@ func (svc *mgmtSvc) BioHealthQuery(ctx context.Context, req *mgmtpb.BioHealthReq
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodBioHealth, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodBioHealth, req)
 	if err != nil {
 		return nil, err
 	}
@@ -557,7 +619,7 @@ func (svc *mgmtSvc) SmdListDevs(ctx context.Context, req *mgmtpb.SmdDevReq) (*mg
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodSmdDevs, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodSmdDevs, req)
 	if err != nil {
 		return nil, err
 	}
@@ -579,7 +641,7 @@ func (svc *mgmtSvc) SmdListPools(ctx context.Context, req *mgmtpb.SmdPoolReq) (*
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodSmdPools, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodSmdPools, req)
 	if err != nil {
 		return nil, err
 	}
@@ -601,7 +663,7 @@ func (svc *mgmtSvc) DevStateQuery(ctx context.Context, req *mgmtpb.DevStateReq)
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodDevStateQuery, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodDevStateQuery, req)
 	if err != nil {
 		return nil, err
 	}
@@ -623,7 +685,7 @@ func (svc *mgmtSvc) StorageSetFaulty(ctx context.Context, req *mgmtpb.DevStateRe
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodSetFaultyState, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodSetFaultyState, req)
 	if err != nil {
 		return nil, err
 	}
@@ -655,7 +717,7 @@ func (svc *mgmtSvc) PrepShutdown(ctx context.Context, req *mgmtpb.PrepShutdownRe
 		return nil, errors.Errorf("rank %d not found on this server", req.Rank)
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodPrepShutdown, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodPrepShutdown, req)
 	if err != nil {
 		return nil, err
 	}
@@ -688,7 +750,7 @@ func (svc *mgmtSvc) KillRank(ctx context.Context, req *mgmtpb.KillRankReq) (*mgm
 		return nil, errors.Errorf("rank %d not found on this server", req.Rank)
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodKillRank, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodKillRank, req)
 	if err != nil {
 		return nil, err
 	}
@@ -703,6 +765,66 @@ func (svc *mgmtSvc) KillRank(ctx context.Context, req *mgmtpb.KillRankReq) (*mgm
 	return resp, nil
 }
 
+// PingRank implements the method defined for the Management Service.
+//
+// Check that the data-plane instance managed by control-plane and identified
+// by unique rank is running. Used by the MS leader to detect unresponsive
+// system members.
+func (svc *mgmtSvc) PingRank(ctx context.Context, req *mgmtpb.PingRankReq) (*mgmtpb.DaosResp, error) {
+	svc.log.Debugf("MgmtSvc.PingRank dispatch, req:%+v\n", *req)
+
+	var mi *IOServerInstance
+	for _, i := range svc.harness.Instances() {
+		if i.hasSuperblock() && i.getSuperblock().Rank.Equals(ioserver.NewRankPtr(req.Rank)) {
+			mi = i
+			break
+		}
+	}
+
+	if mi == nil {
+		return nil, errors.Errorf("rank %d not found on this server", req.Rank)
+	}
+
+	if !mi.IsStarted() {
+		return nil, errors.Errorf("rank %d not running", req.Rank)
+	}
+
+	resp := &mgmtpb.DaosResp{}
+
+	svc.log.Debugf("MgmtSvc.PingRank dispatch, resp:%+v\n", *resp)
+
+	return resp, nil
+}
+
+// ReportEvent implements the method defined for the Management Service.
+//
+// Record an event detected on a remote control-plane in the system event log.
+func (svc *mgmtSvc) ReportEvent(ctx context.Context, req *mgmtpb.ReportEventReq) (*mgmtpb.DaosResp, error) {
+	svc.log.Debugf("MgmtSvc.ReportEvent dispatch, req:%+v\n", *req)
+
+	if _, err := svc.harness.GetMSLeaderInstance(); err != nil {
+		return nil, err
+	}
+
+	evtType := system.EventType(req.Type)
+	if !evtType.IsValid() {
+		return nil, errors.Errorf("unknown event type %d", req.Type)
+	}
+
+	// The requester named by the forwarding server can't be verified,
+	// so it is kept apart from the forwarding server's own identity.
+	event := system.NewEvent(evtType, req.Rank, req.Reason)
+	event.Identity = requestIdentity(ctx)
+	event.Origin = req.Identity
+	svc.events.Add(event)
+
+	resp := &mgmtpb.DaosResp{}
+
+	svc.log.Debugf("MgmtSvc.ReportEvent dispatch, resp:%+v\n", *resp)
+
+	return resp, nil
+}
+
 // StartRanks implements the method defined for the Management Service.
 //
 // Restart data-plane instances (DAOS system members) managed by harness.
@@ -735,7 +857,7 @@ func (svc *mgmtSvc) ListPools(ctx context.Context, req *mgmtpb.ListPoolsReq) (*m
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodListPools, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodListPools, req)
 	if err != nil {
 		return nil, err
 	}
@@ -790,7 +912,7 @@ func (svc *mgmtSvc) ListContainers(ctx context.Context, req *mgmtpb.ListContReq)
 		return nil, err
 	}
 
-	dresp, err := mi.CallDrpc(drpc.ModuleMgmt, drpc.MethodListContainers, req)
+	dresp, err := mi.CallDrpc(ctx, drpc.ModuleMgmt, drpc.MethodListContainers, req)
 	if err != nil {
 		return nil, err
 	}
@@ -26,17 +26,22 @@ package server
 import (
 	"context"
 	"net"
+	"path/filepath"
 	"strconv"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/peer"
 
 	"github.com/daos-stack/daos/src/control/common"
 	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
 	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/logging"
+	"github.com/daos-stack/daos/src/control/security"
+	"github.com/daos-stack/daos/src/control/server/ioserver"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
 const (
@@ -163,7 +168,7 @@ func TestListPools_NoMS(t *testing.T) {
 	log, buf := logging.NewTestLogger(t.Name())
 	defer common.ShowBufferOnFailure(t, buf)
 
-	svc := newMgmtSvc(NewIOServerHarness(log), nil)
+	svc := newMgmtSvc(NewIOServerHarness(log), nil, nil)
 
 	resp, err := svc.ListPools(context.TODO(), newTestListPoolsReq())
 
@@ -246,7 +251,7 @@ func TestListCont_NoMS(t *testing.T) {
 	log, buf := logging.NewTestLogger(t.Name())
 	defer common.ShowBufferOnFailure(t, buf)
 
-	svc := newMgmtSvc(NewIOServerHarness(log), nil)
+	svc := newMgmtSvc(NewIOServerHarness(log), nil, nil)
 
 	resp, err := svc.ListContainers(context.TODO(), newTestListContReq())
 
@@ -352,7 +357,7 @@ func TestPoolGetACL_NoMS(t *testing.T) {
 	log, buf := logging.NewTestLogger(t.Name())
 	defer common.ShowBufferOnFailure(t, buf)
 
-	svc := newMgmtSvc(NewIOServerHarness(log), nil)
+	svc := newMgmtSvc(NewIOServerHarness(log), nil, nil)
 
 	resp, err := svc.PoolGetACL(context.TODO(), newTestGetACLReq())
 
@@ -436,7 +441,7 @@ func TestPoolOverwriteACL_NoMS(t *testing.T) {
 	log, buf := logging.NewTestLogger(t.Name())
 	defer common.ShowBufferOnFailure(t, buf)
 
-	svc := newMgmtSvc(NewIOServerHarness(log), nil)
+	svc := newMgmtSvc(NewIOServerHarness(log), nil, nil)
 
 	resp, err := svc.PoolOverwriteACL(context.TODO(), newTestModifyACLReq())
 
@@ -495,7 +500,7 @@ func TestPoolOverwriteACL_Success(t *testing.T) {
 	}
 	setupMockDrpcClient(svc, expectedResp, nil)
 
-	resp, err := svc.PoolOverwriteACL(nil, newTestModifyACLReq())
+	resp, err := svc.PoolOverwriteACL(context.TODO(), newTestModifyACLReq())
 
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
@@ -511,7 +516,7 @@ func TestPoolUpdateACL_NoMS(t *testing.T) {
 	log, buf := logging.NewTestLogger(t.Name())
 	defer common.ShowBufferOnFailure(t, buf)
 
-	svc := newMgmtSvc(NewIOServerHarness(log), nil)
+	svc := newMgmtSvc(NewIOServerHarness(log), nil, nil)
 
 	resp, err := svc.PoolUpdateACL(context.TODO(), newTestModifyACLReq())
 
@@ -570,7 +575,7 @@ func TestPoolUpdateACL_Success(t *testing.T) {
 	}
 	setupMockDrpcClient(svc, expectedResp, nil)
 
-	resp, err := svc.PoolUpdateACL(nil, newTestModifyACLReq())
+	resp, err := svc.PoolUpdateACL(context.TODO(), newTestModifyACLReq())
 
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
@@ -593,7 +598,7 @@ func TestPoolDeleteACL_NoMS(t *testing.T) {
 	log, buf := logging.NewTestLogger(t.Name())
 	defer common.ShowBufferOnFailure(t, buf)
 
-	svc := newMgmtSvc(NewIOServerHarness(log), nil)
+	svc := newMgmtSvc(NewIOServerHarness(log), nil, nil)
 
 	resp, err := svc.PoolDeleteACL(context.TODO(), newTestDeleteACLReq())
 
@@ -686,7 +691,7 @@ func TestMgmtSvc_LeaderQuery(t *testing.T) {
 			expErr: errors.New("wrong system"),
 		},
 		"no i/o servers": {
-			mgmtSvc: newMgmtSvc(NewIOServerHarness(nil), nil),
+			mgmtSvc: newMgmtSvc(NewIOServerHarness(nil), nil, nil),
 			req:     &mgmtpb.LeaderQueryReq{},
 			expErr:  errors.New("no I/O servers"),
 		},
@@ -983,3 +988,290 @@ func TestMgmtSvc_PoolSetProp(t *testing.T) {
 		})
 	}
 }
+
+func TestMgmtSvc_PingRank(t *testing.T) {
+	for name, tc := range map[string]struct {
+		sbRank  uint32
+		started bool
+		req     *mgmtpb.PingRankReq
+		expResp *mgmtpb.DaosResp
+		expErr  error
+	}{
+		"rank not found": {
+			sbRank: 1,
+			req:    &mgmtpb.PingRankReq{Rank: 2},
+			expErr: errors.New("rank 2 not found on this server"),
+		},
+		"rank not running": {
+			sbRank: 1,
+			req:    &mgmtpb.PingRankReq{Rank: 1},
+			expErr: errors.New("rank 1 not running"),
+		},
+		"rank running": {
+			sbRank:  1,
+			started: true,
+			req:     &mgmtpb.PingRankReq{Rank: 1},
+			expResp: &mgmtpb.DaosResp{},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			svc := newTestMgmtSvc(log)
+			srv := svc.harness.instances[0]
+			srv.setSuperblock(&Superblock{
+				MS:   true,
+				Rank: ioserver.NewRankPtr(tc.sbRank),
+			})
+			if tc.started {
+				srv.runner = ioserver.NewTestRunner(nil, ioserver.NewConfig())
+			}
+
+			gotResp, gotErr := svc.PingRank(context.TODO(), tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.expResp, gotResp); diff != "" {
+				t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestMgmtSvc_ReportEvent(t *testing.T) {
+	for name, tc := range map[string]struct {
+		notLeader bool
+		req       *mgmtpb.ReportEventReq
+		expErr    error
+	}{
+		"not MS leader": {
+			notLeader: true,
+			req:       &mgmtpb.ReportEventReq{Rank: 1},
+			expErr:    errors.New("not an access point"),
+		},
+		"unknown event type": {
+			req:    &mgmtpb.ReportEventReq{Rank: 1, Type: 99},
+			expErr: errors.New("unknown event type 99"),
+		},
+		"identity forwarded": {
+			req: &mgmtpb.ReportEventReq{
+				Rank:     1,
+				Type:     uint32(system.EventTypeBioError),
+				Reason:   "target 0: write error",
+				Identity: "admin",
+			},
+		},
+		"event recorded": {
+			req: &mgmtpb.ReportEventReq{
+				Rank:   1,
+				Type:   uint32(system.EventTypeBioError),
+				Reason: "target 0: write error",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			svc := newTestMgmtSvc(log)
+			if tc.notLeader {
+				svc.harness.instances[0]._superblock.MS = false
+			}
+
+			_, gotErr := svc.ReportEvent(peerContext(security.RoleServer), tc.req)
+			common.CmpErr(t, tc.expErr, gotErr)
+			if tc.expErr != nil {
+				return
+			}
+
+			events := svc.events.Events(system.EventFilter{})
+			if len(events) != 1 {
+				t.Fatalf("expected 1 event, got %d", len(events))
+			}
+			common.AssertEqual(t, system.EventTypeBioError, events[0].Type, "unexpected type")
+			common.AssertEqual(t, tc.req.Rank, events[0].Rank, "unexpected rank")
+			common.AssertEqual(t, tc.req.Reason, events[0].Reason, "unexpected reason")
+			common.AssertEqual(t, security.RoleServer+"@10.0.0.1:10001", events[0].Identity,
+				"event should be attributed to the forwarding server")
+			common.AssertEqual(t, tc.req.Identity, events[0].Origin, "unexpected origin")
+		})
+	}
+}
+
+func TestMgmtSvc_Join_Excluded(t *testing.T) {
+	for name, tc := range map[string]struct {
+		req     *mgmtpb.JoinReq
+		drpcRes *mgmtpb.JoinResp
+		expJoin bool // join dRPC sent to the data plane
+		expErr  error
+	}{
+		"requested rank excluded": {
+			req:    &mgmtpb.JoinReq{Rank: 1, Addr: "127.0.0.1:10001"},
+			expErr: errors.Wrap(system.FaultMemberExcluded, "rank 1"),
+		},
+		"excluded member without rank": {
+			req: &mgmtpb.JoinReq{Rank: uint32(ioserver.NilRank), Uuid: "uuid-1",
+				Addr: "127.0.0.1:10001"},
+			expErr: errors.Wrap(system.FaultMemberExcluded, "rank 1"),
+		},
+		"assigned rank excluded": {
+			req: &mgmtpb.JoinReq{Rank: uint32(ioserver.NilRank), Uuid: "uuid-2",
+				Addr: "127.0.0.1:10001"},
+			drpcRes: &mgmtpb.JoinResp{Rank: 1},
+			expJoin: true,
+			expErr:  errors.Wrap(system.FaultMemberExcluded, "rank 1"),
+		},
+		"rank not excluded": {
+			req:     &mgmtpb.JoinReq{Rank: 2, Addr: "127.0.0.1:10001"},
+			drpcRes: &mgmtpb.JoinResp{Rank: 2},
+			expJoin: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			svc := newTestMgmtSvc(log)
+			addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 10001}
+			svc.membership.AddOrUpdate(system.NewMember(1, "uuid-1", addr,
+				system.MemberStateStarted), "joined")
+			if err := svc.membership.Exclude(1, "excluded"); err != nil {
+				t.Fatal(err)
+			}
+			setupMockDrpcClient(svc, tc.drpcRes, nil)
+
+			ctx := peer.NewContext(context.TODO(), &peer.Peer{Addr: addr})
+			_, err := svc.Join(ctx, tc.req)
+			common.CmpErr(t, tc.expErr, err)
+
+			mi, _ := svc.harness.GetMSLeaderInstance()
+			mdc := mi._drpcClient.(*mockDrpcClient)
+			common.AssertEqual(t, tc.expJoin, mdc.SendMsgInputCall != nil,
+				"unexpected join dRPC")
+			if tc.expErr != nil {
+				return
+			}
+
+			member, err := svc.membership.Get(tc.drpcRes.Rank)
+			if err != nil {
+				t.Fatal(err)
+			}
+			common.AssertEqual(t, system.MemberStateStarted, member.State(),
+				"unexpected member state")
+		})
+	}
+}
+
+func TestMgmtSvc_GetAttachInfo_Excluded(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	svc := newTestMgmtSvc(log)
+	addr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 10001}
+	for _, rank := range []uint32{0, 1, 2} {
+		svc.membership.AddOrUpdate(system.NewMember(rank, "", addr,
+			system.MemberStateStarted), "joined")
+	}
+	if err := svc.membership.Exclude(1, "excluded"); err != nil {
+		t.Fatal(err)
+	}
+	setupMockDrpcClient(svc, &mgmtpb.GetAttachInfoResp{
+		Psrs: []*mgmtpb.GetAttachInfoResp_Psr{
+			{Rank: 0, Uri: "uri0"},
+			{Rank: 1, Uri: "uri1"},
+			{Rank: 2, Uri: "uri2"},
+		},
+	}, nil)
+
+	resp, err := svc.GetAttachInfo(context.TODO(), &mgmtpb.GetAttachInfoReq{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expResp := &mgmtpb.GetAttachInfoResp{
+		Psrs: []*mgmtpb.GetAttachInfoResp_Psr{
+			{Rank: 0, Uri: "uri0"},
+			{Rank: 2, Uri: "uri2"},
+		},
+	}
+	if diff := cmp.Diff(expResp, resp); diff != "" {
+		t.Fatalf("unexpected response (-want, +got)\n%s\n", diff)
+	}
+}
+
+// setupReplayDrpcClient connects the mgmtSvc's MS instance to a dRPC server
+// replaying the responses in the capture file, in place of an I/O server.
+func setupReplayDrpcClient(t *testing.T, log logging.Logger, svc *mgmtSvc, capture string) ([]*drpc.ReplayModule, func()) {
+	t.Helper()
+
+	records, err := drpc.LoadCaptureFile(capture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpDir, tmpCleanup := common.CreateTestDir(t)
+	sock := filepath.Join(tmpDir, "replay.sock")
+	dss, mods, err := drpc.NewReplayServer(context.Background(), log, sock, records)
+	if err != nil {
+		tmpCleanup()
+		t.Fatal(err)
+	}
+	if err := dss.Start(); err != nil {
+		tmpCleanup()
+		t.Fatal(err)
+	}
+
+	mi, _ := svc.harness.GetMSLeaderInstance()
+	mi.setDrpcClient(drpc.NewClientConnection(sock))
+
+	return mods, func() {
+		mi.setDrpcClient(nil)
+		dss.Shutdown()
+		tmpCleanup()
+	}
+}
+
+func TestMgmtSvc_ReplayCapture(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	svc := newTestMgmtSvc(log)
+	mods, cleanup := setupReplayDrpcClient(t, log, svc, "testdata/drpc_capture_mgmt.json")
+	defer cleanup()
+
+	lpResp, err := svc.ListPools(context.TODO(), newTestListPoolsReq())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expLPResp := &mgmtpb.ListPoolsResp{
+		Pools: []*mgmtpb.ListPoolsResp_Pool{
+			{Uuid: "12345678-1234-1234-1234-123456789abc", Svcreps: []uint32{0, 1, 2}},
+			{Uuid: "87654321-4321-4321-4321-cba987654321", Svcreps: []uint32{1}},
+		},
+	}
+	if diff := cmp.Diff(expLPResp, lpResp, common.DefaultCmpOpts()...); diff != "" {
+		t.Fatalf("bad response (-want, +got): \n%s\n", diff)
+	}
+
+	aclResp, err := svc.PoolGetACL(context.TODO(), newTestGetACLReq())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expACLResp := &mgmtpb.ACLResp{
+		ACL: []string{"A::OWNER@:rw", "A:g:GROUP@:r"},
+	}
+	if diff := cmp.Diff(expACLResp, aclResp, common.DefaultCmpOpts()...); diff != "" {
+		t.Fatalf("bad response (-want, +got): \n%s\n", diff)
+	}
+
+	// The engine failed the repeated call in the capture.
+	_, err = svc.PoolGetACL(context.TODO(), newTestGetACLReq())
+	common.CmpErr(t, errors.New("status: FAILURE"), err)
+
+	for _, mod := range mods {
+		common.AssertEqual(t, mod.Remaining(), 0, "expected all responses to be replayed")
+	}
+}
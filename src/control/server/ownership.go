@@ -81,6 +81,8 @@ func chownAll(config *Configuration, usr *user.User, grp *user.Group) error {
 	paths := []string{
 		config.SocketDir,
 		config.ControlLogFile,
+		config.AuditLogFile,
+		config.DrpcCaptureFile,
 	}
 
 	for _, srv := range config.Servers {
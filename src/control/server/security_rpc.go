@@ -28,6 +28,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"path/filepath"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 
@@ -39,19 +40,57 @@ import (
 
 // SecurityModule is the security drpc module struct
 type SecurityModule struct {
-	log    logging.Logger
-	config *security.TransportConfig
+	drpc.PeerAllowList
+	log       logging.Logger
+	config    *security.TransportConfig
+	clockSkew time.Duration
+	nonces    *auth.NonceCache
 }
 
 // NewSecurityModule creates a new security module with a transport config
 func NewSecurityModule(log logging.Logger, tc *security.TransportConfig) *SecurityModule {
 	mod := &SecurityModule{
-		log:    log,
-		config: tc,
+		log:       log,
+		config:    tc,
+		clockSkew: auth.DefaultClockSkew,
+		nonces:    auth.NewNonceCache(auth.DefaultNonceCacheSize),
 	}
 	return mod
 }
 
+// WithClockSkew sets the allowed difference between the clocks of the agents
+// issuing credentials and this server.
+func (m *SecurityModule) WithClockSkew(skew time.Duration) *SecurityModule {
+	m.clockSkew = skew
+	return m
+}
+
+// WithAllowedPeers restricts the local peers that may call the module.
+func (m *SecurityModule) WithAllowedPeers(peers drpc.PeerAllowList) *SecurityModule {
+	m.PeerAllowList = peers
+	return m
+}
+
+// checkReplay rejects credentials outside their validity window or that have
+// already been presented.
+func (m *SecurityModule) checkReplay(token *auth.Token) error {
+	sys, err := auth.AuthSysFromAuthToken(token)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := sys.CheckValidity(now, m.clockSkew); err != nil {
+		return err
+	}
+
+	// The credential remains acceptable until its expiry plus the
+	// allowed skew, so its nonce must be remembered for as long.
+	expires := time.Unix(int64(sys.GetExpires()), 0).Add(m.clockSkew)
+
+	return m.nonces.Add(sys.GetNonce(), expires, now)
+}
+
 func (m *SecurityModule) processValidateCredentials(body []byte) ([]byte, error) {
 	req := &auth.ValidateCredReq{}
 	err := proto.Unmarshal(body, req)
@@ -86,6 +125,12 @@ func (m *SecurityModule) processValidateCredentials(body []byte) ([]byte, error)
 		return m.validateRespWithStatus(drpc.DaosNoPermission)
 	}
 
+	// Only a verified credential can be trusted to be fresh
+	if err := m.checkReplay(cred.GetToken()); err != nil {
+		m.log.Errorf("cred rejected from %s: %v", cred.GetOrigin(), err)
+		return m.validateRespWithStatus(drpc.DaosNoPermission)
+	}
+
 	resp := &auth.ValidateCredResp{Token: cred.Token}
 	responseBytes, err := proto.Marshal(resp)
 	if err != nil {
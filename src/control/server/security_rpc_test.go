@@ -196,10 +196,16 @@ func TestSrvSecurityModule_ValidateCred_NoVerifier(t *testing.T) {
 }
 
 func getValidToken(t *testing.T) *auth.Token {
+	return getTokenIssuedAt(t, time.Now(), []byte("nonce"))
+}
+
+func getTokenIssuedAt(t *testing.T, issued time.Time, nonce []byte) *auth.Token {
 	tokenData := &auth.Sys{
-		Stamp: uint64(time.Now().Unix()),
-		User:  "gooduser@",
-		Group: "goodgroup@",
+		Stamp:   uint64(issued.Unix()),
+		Expires: uint64(issued.Add(auth.CredentialValidity).Unix()),
+		Nonce:   nonce,
+		User:    "gooduser@",
+		Group:   "goodgroup@",
 	}
 	return &auth.Token{
 		Flavor: auth.Flavor_AUTH_SYS,
@@ -361,3 +367,80 @@ func TestSrvSecurityModule_ValidateCred_Secure_BadVerifier(t *testing.T) {
 		Status: int32(drpc.DaosNoPermission),
 	})
 }
+
+func TestSrvSecurityModule_ValidateCred_Replay(t *testing.T) {
+	now := time.Now()
+
+	for name, tc := range map[string]struct {
+		issued    time.Time
+		nonce     []byte
+		skew      time.Duration
+		repeat    bool
+		expStatus drpc.DaosStatus
+	}{
+		"valid": {
+			issued: now,
+			nonce:  []byte("nonce"),
+		},
+		"no nonce": {
+			issued:    now,
+			expStatus: drpc.DaosNoPermission,
+		},
+		"replayed": {
+			issued:    now,
+			nonce:     []byte("nonce"),
+			repeat:    true,
+			expStatus: drpc.DaosNoPermission,
+		},
+		"expired": {
+			issued:    now.Add(-auth.CredentialValidity - 2*auth.DefaultClockSkew),
+			nonce:     []byte("nonce"),
+			expStatus: drpc.DaosNoPermission,
+		},
+		"expired within skew": {
+			issued: now.Add(-auth.CredentialValidity - time.Minute),
+			nonce:  []byte("nonce"),
+			skew:   2 * time.Minute,
+		},
+		"issued in future": {
+			issued:    now.Add(2 * auth.DefaultClockSkew),
+			nonce:     []byte("nonce"),
+			expStatus: drpc.DaosNoPermission,
+		},
+		"issued in future within skew": {
+			issued: now.Add(time.Minute),
+			nonce:  []byte("nonce"),
+			skew:   2 * time.Minute,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			log, buf := logging.NewTestLogger(t.Name())
+			defer common.ShowBufferOnFailure(t, buf)
+
+			mod := NewSecurityModule(log, insecureTransportConfig())
+			if tc.skew != 0 {
+				mod = mod.WithClockSkew(tc.skew)
+			}
+
+			token := getTokenIssuedAt(t, tc.issued, tc.nonce)
+			reqBytes := getMarshaledValidateCredReq(t, token, getVerifierForToken(t, token, nil))
+
+			if tc.repeat {
+				if _, err := callValidateCreds(mod, reqBytes); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			resp, err := callValidateCreds(mod, reqBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expResp := &auth.ValidateCredResp{Status: int32(tc.expStatus)}
+			if tc.expStatus == drpc.DaosSuccess {
+				expResp.Token = token
+			}
+			expectValidateResp(t, resp, expResp)
+		})
+	}
+}
@@ -36,12 +36,15 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 
+	"github.com/daos-stack/daos/src/control/common"
 	ctlpb "github.com/daos-stack/daos/src/control/common/proto/ctl"
 	mgmtpb "github.com/daos-stack/daos/src/control/common/proto/mgmt"
+	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/pbin"
 	"github.com/daos-stack/daos/src/control/security"
 	"github.com/daos-stack/daos/src/control/server/ioserver"
+	"github.com/daos-stack/daos/src/control/server/storage"
 	"github.com/daos-stack/daos/src/control/server/storage/bdev"
 	"github.com/daos-stack/daos/src/control/server/storage/scm"
 	"github.com/daos-stack/daos/src/control/system"
@@ -62,6 +65,33 @@ func cfgHasBdev(cfg *Configuration) bool {
 	return false
 }
 
+// cfgNvmeWhitelist returns the PCI addresses of the configured NVMe devices,
+// along with any in the bdev_include list.
+func cfgNvmeWhitelist(cfg *Configuration) []string {
+	var addrs []string
+	seen := make(map[string]bool)
+	add := func(addr string) {
+		if !seen[addr] {
+			seen[addr] = true
+			addrs = append(addrs, addr)
+		}
+	}
+
+	for _, srvCfg := range cfg.Servers {
+		if srvCfg.Storage.Bdev.Class != storage.BdevClassNvme {
+			continue
+		}
+		for _, addr := range srvCfg.Storage.Bdev.DeviceList {
+			add(addr)
+		}
+	}
+	for _, addr := range cfg.BdevInclude {
+		add(addr)
+	}
+
+	return addrs
+}
+
 func instanceShmID(idx int) int {
 	return os.Getpid() + idx + 1
 }
@@ -79,9 +109,10 @@ func Start(log *logging.LeveledLogger, cfg *Configuration) error {
 	// Backup active config.
 	saveActiveConfig(log, cfg)
 
-	if cfg.HelperLogFile != "" {
-		if err := os.Setenv(pbin.DaosAdminLogFileEnvVar, cfg.HelperLogFile); err != nil {
-			return errors.Wrap(err, "unable to configure privileged helper logging")
+	if len(cfg.HelperTimeouts) > 0 {
+		timeouts := pbin.FormatTimeouts(cfg.HelperTimeouts)
+		if err := os.Setenv(pbin.DaosAdminTimeoutsEnvVar, timeouts); err != nil {
+			return errors.Wrap(err, "unable to configure privileged helper timeouts")
 		}
 	}
 
@@ -91,12 +122,19 @@ func Start(log *logging.LeveledLogger, cfg *Configuration) error {
 	ctx, shutdown := context.WithCancel(context.Background())
 	defer shutdown()
 
+	// Stop the privileged helper on the way out rather than leaving
+	// it to notice that its input has gone away.
+	defer pbin.StopHelpers()
+
 	controlAddr, err := net.ResolveTCPAddr("tcp", fmt.Sprintf("0.0.0.0:%d", cfg.ControlPort))
 	if err != nil {
 		return errors.Wrap(err, "unable to resolve daos_server control address")
 	}
 
-	bdevProvider := bdev.DefaultProvider(log)
+	// Subsystem levels may be set separately with control_log_mask.
+	storageLog := log.WithSubsystem(logSubsystemStorage)
+
+	bdevProvider := bdev.DefaultProvider(storageLog)
 	runningUser, err := user.Current()
 	if err != nil {
 		return errors.Wrap(err, "unable to lookup current user")
@@ -109,14 +147,20 @@ func Start(log *logging.LeveledLogger, cfg *Configuration) error {
 	}
 
 	// Perform an automatic prepare based on the values in the config file.
-	prepReq := bdev.PrepareRequest{
-		HugePageCount: cfg.NrHugepages,
-		TargetUser:    runningUser.Username,
-		PCIWhitelist:  strings.Join(cfg.BdevInclude, ","),
-	}
-	log.Debugf("automatic NVMe prepare req: %+v", prepReq)
-	if _, err := bdevProvider.Prepare(prepReq); err != nil {
-		log.Errorf("automatic NVMe prepare failed (check configuration?)\n%s", err)
+	// An empty whitelist would prepare every NVMe device in the host, so
+	// nothing is prepared unless NVMe devices are configured.
+	if whitelist := cfgNvmeWhitelist(cfg); len(whitelist) > 0 {
+		prepReq := bdev.PrepareRequest{
+			HugePageCount: cfg.NrHugepages,
+			TargetUser:    runningUser.Username,
+			PCIWhitelist:  strings.Join(whitelist, ","),
+		}
+		log.Debugf("automatic NVMe prepare req: %+v", prepReq)
+		if _, err := bdevProvider.Prepare(ctx, prepReq); err != nil {
+			log.Errorf("automatic NVMe prepare failed (check configuration?)\n%s", err)
+		}
+	} else {
+		log.Debug("no NVMe devices configured; skipping automatic NVMe prepare")
 	}
 
 	hugePages, err := getHugePageInfo()
@@ -141,9 +185,26 @@ func Start(log *logging.LeveledLogger, cfg *Configuration) error {
 
 	// If this daos_server instance ends up being the MS leader,
 	// this will record the DAOS system membership.
-	membership := system.NewMembership(log)
-	scmProvider := scm.DefaultProvider(log)
-	harness := NewIOServerHarness(log)
+	events := system.NewEventLog(log, system.DefaultEventLogSize)
+	membership := system.NewMembership(log, events)
+	scmProvider := scm.DefaultProvider(storageLog)
+
+	// Captured calls may include credentials, so only the server user may
+	// read the capture file.
+	var drpcRecorder *drpc.Recorder
+	if cfg.DrpcCaptureFile != "" {
+		f, err := os.OpenFile(cfg.DrpcCaptureFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+		if err != nil {
+			return errors.Wrap(err, "open dRPC capture file")
+		}
+		defer f.Close()
+		drpcRecorder = drpc.NewRecorder(f)
+		log.Infof("capturing dRPC traffic to %s", cfg.DrpcCaptureFile)
+	}
+
+	harness := NewIOServerHarness(log).
+		WithDrpcLogger(log.WithSubsystem(logSubsystemDrpc)).
+		WithDrpcRecorder(drpcRecorder)
 	for i, srvCfg := range cfg.Servers {
 		if i+1 > maxIoServers {
 			break
@@ -164,7 +225,7 @@ func Start(log *logging.LeveledLogger, cfg *Configuration) error {
 		// have access to the instance configuration.
 		srvCfg.Storage.Bdev.ShmID = instanceShmID(i)
 
-		bp, err := bdev.NewClassProvider(log, srvCfg.Storage.SCM.MountPoint, &srvCfg.Storage.Bdev)
+		bp, err := bdev.NewClassProvider(storageLog, srvCfg.Storage.SCM.MountPoint, &srvCfg.Storage.Bdev)
 		if err != nil {
 			return err
 		}
@@ -175,18 +236,21 @@ func Start(log *logging.LeveledLogger, cfg *Configuration) error {
 			TransportConfig: cfg.TransportConfig,
 		})
 
-		srv := NewIOServerInstance(log, bp, scmProvider, msClient, ioserver.NewRunner(log, srvCfg))
+		srv := NewIOServerInstance(log, bp, scmProvider, msClient, ioserver.NewRunner(log, srvCfg)).
+			WithDrpcRecorder(drpcRecorder).
+			WithDrpcMaxMessageSize(cfg.DrpcMaxMessageSize).
+			WithDrpcTimeout(cfg.DrpcTimeout)
 		if err := harness.AddInstance(srv); err != nil {
 			return err
 		}
 	}
 
 	// Create and setup control service.
-	controlService, err := NewControlService(log, harness, bdevProvider, scmProvider, cfg, membership)
+	controlService, err := NewControlService(log, harness, bdevProvider, scmProvider, cfg, membership, events)
 	if err != nil {
 		return errors.Wrap(err, "init control service")
 	}
-	if err := controlService.Setup(); err != nil {
+	if err := controlService.Setup(ctx); err != nil {
 		return errors.Wrap(err, "setup control service")
 	}
 
@@ -197,14 +261,24 @@ func Start(log *logging.LeveledLogger, cfg *Configuration) error {
 	}
 
 	// Create new grpc server, register services and start serving.
-	tcOpt, err := security.ServerOptionForTransportConfig(cfg.TransportConfig)
+	var auditLog *logging.AuditLogger
+	if cfg.AuditLogFile != "" {
+		f, err := common.AppendFile(cfg.AuditLogFile)
+		if err != nil {
+			return errors.Wrap(err, "open audit log file")
+		}
+		defer f.Close()
+		auditLog = logging.NewAuditLogger(f)
+	}
+
+	opts, err := serverOptions(log, cfg.TransportConfig, auditLog)
 	if err != nil {
 		return err
 	}
 
-	grpcServer := grpc.NewServer(tcOpt)
+	grpcServer := grpc.NewServer(opts...)
 	ctlpb.RegisterMgmtCtlServer(grpcServer, controlService)
-	mgmtpb.RegisterMgmtSvcServer(grpcServer, newMgmtSvc(harness, membership))
+	mgmtpb.RegisterMgmtSvcServer(grpcServer, newMgmtSvc(harness, membership, events))
 
 	go func() {
 		_ = grpcServer.Serve(lis)
@@ -224,13 +298,50 @@ func Start(log *logging.LeveledLogger, cfg *Configuration) error {
 		shutdown()
 	}()
 
+	// Reload certificates on SIGHUP or when the files are replaced.
+	reloadSignals := make(chan os.Signal, 1)
+	signal.Notify(reloadSignals, syscall.SIGHUP)
+	go security.NewCertReloader(log, cfg.TransportConfig).Start(ctx, reloadSignals)
+
+	// The privileged helper reopens its log file on SIGHUP by being
+	// replaced with a new one for the next request.
+	if cfg.HelperLogFile != "" {
+		retireSignals := make(chan os.Signal, 1)
+		signal.Notify(retireSignals, syscall.SIGHUP)
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-retireSignals:
+					log.Debug("retiring privileged helper to reopen its log file")
+					pbin.RetireHelpers()
+				}
+			}
+		}()
+	}
+
+	// Liveness checks are only performed when this server is MS leader.
+	go newMemberChecker(log, harness, membership, cfg).Start(ctx)
+
 	if err := harness.AwaitStorageReady(ctx, cfg.RecreateSuperblocks); err != nil {
 		return err
 	}
 
-	if err := harness.CreateSuperblocks(cfg.RecreateSuperblocks); err != nil {
+	if err := harness.CreateSuperblocks(ctx, cfg.RecreateSuperblocks); err != nil {
 		return err
 	}
 
+	// The system event log and excluded ranks are persisted alongside the
+	// MS replica superblock.
+	if mi, err := harness.GetMSLeaderInstance(); err == nil {
+		if err := events.Load(mi.msStatePath(eventLogFile)); err != nil {
+			log.Errorf("loading system event log: %s", err)
+		}
+		if err := membership.LoadExcluded(mi.msStatePath(excludedRanksFile)); err != nil {
+			log.Errorf("loading excluded ranks: %s", err)
+		}
+	}
+
 	return errors.Wrapf(harness.Start(ctx, membership, cfg), "%s exited with error", DataPlaneName)
 }
@@ -23,60 +23,77 @@
 package bdev
 
 import (
+	"context"
+	"time"
+
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/pbin"
 )
 
+// defaultTimeouts are the times allowed for each forwarded request, which
+// can be overridden with pbin.DaosAdminTimeoutsEnvVar. Devices are formatted
+// one after another, so formatting needs the most time.
+var defaultTimeouts = map[string]time.Duration{
+	"BdevInit":    2 * time.Minute,
+	"BdevScan":    2 * time.Minute,
+	"BdevPrepare": 10 * time.Minute,
+	"BdevFormat":  30 * time.Minute,
+}
+
 type Forwarder struct {
 	pbin.Forwarder
 }
 
 func NewForwarder(log logging.Logger) *Forwarder {
-	pf := pbin.NewForwarder(log, pbin.DaosAdminName)
+	// SPDK keeps devices and hugepages claimed until the process
+	// exits, so they must be released before the I/O servers start.
+	pf := pbin.NewForwarder(log, pbin.DaosAdminName).
+		WithDefaultTimeouts(defaultTimeouts).
+		WithRetireAfter("BdevPrepare", "BdevFormat")
 
 	return &Forwarder{
 		Forwarder: *pf,
 	}
 }
 
-func (f *Forwarder) Init(req InitRequest) error {
+func (f *Forwarder) Init(ctx context.Context, req InitRequest) error {
 	req.Forwarded = true
 
 	res := new(InitResponse)
-	if err := f.SendReq("BdevInit", req, res); err != nil {
+	if err := f.SendReq(ctx, "BdevInit", req, res); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (f *Forwarder) Scan(req ScanRequest) (*ScanResponse, error) {
+func (f *Forwarder) Scan(ctx context.Context, req ScanRequest) (*ScanResponse, error) {
 	req.Forwarded = true
 
 	res := new(ScanResponse)
-	if err := f.SendReq("BdevScan", req, res); err != nil {
+	if err := f.SendReq(ctx, "BdevScan", req, res); err != nil {
 		return nil, err
 	}
 
 	return res, nil
 }
 
-func (f *Forwarder) Prepare(req PrepareRequest) (*PrepareResponse, error) {
+func (f *Forwarder) Prepare(ctx context.Context, req PrepareRequest) (*PrepareResponse, error) {
 	req.Forwarded = true
 
 	res := new(PrepareResponse)
-	if err := f.SendReq("BdevPrepare", req, res); err != nil {
+	if err := f.SendReq(ctx, "BdevPrepare", req, res); err != nil {
 		return nil, err
 	}
 
 	return res, nil
 }
 
-func (f *Forwarder) Format(req FormatRequest) (*FormatResponse, error) {
+func (f *Forwarder) Format(ctx context.Context, req FormatRequest) (*FormatResponse, error) {
 	req.Forwarded = true
 
 	res := new(FormatResponse)
-	if err := f.SendReq("BdevFormat", req, res); err != nil {
+	if err := f.SendReq(ctx, "BdevFormat", req, res); err != nil {
 		return nil, err
 	}
 
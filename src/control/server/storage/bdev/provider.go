@@ -23,6 +23,8 @@
 package bdev
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 
 	"github.com/daos-stack/daos/src/control/fault"
@@ -127,17 +129,17 @@ func (p *Provider) shouldForward(req pbin.ForwardChecker) bool {
 }
 
 // Init performs any initialization steps required by the provider.
-func (p *Provider) Init(req InitRequest) error {
+func (p *Provider) Init(ctx context.Context, req InitRequest) error {
 	if p.shouldForward(req) {
-		return p.fwd.Init(req)
+		return p.fwd.Init(ctx, req)
 	}
 	return p.backend.Init(req.SPDKShmID)
 }
 
 // Scan attempts to perform a scan to discover NVMe components in the system.
-func (p *Provider) Scan(req ScanRequest) (*ScanResponse, error) {
+func (p *Provider) Scan(ctx context.Context, req ScanRequest) (*ScanResponse, error) {
 	if p.shouldForward(req) {
-		return p.fwd.Scan(req)
+		return p.fwd.Scan(ctx, req)
 	}
 
 	cs, err := p.backend.Scan()
@@ -152,9 +154,9 @@ func (p *Provider) Scan(req ScanRequest) (*ScanResponse, error) {
 
 // Prepare attempts to perform all actions necessary to make NVMe components available for
 // use by DAOS.
-func (p *Provider) Prepare(req PrepareRequest) (*PrepareResponse, error) {
+func (p *Provider) Prepare(ctx context.Context, req PrepareRequest) (*PrepareResponse, error) {
 	if p.shouldForward(req) {
-		return p.fwd.Prepare(req)
+		return p.fwd.Prepare(ctx, req)
 	}
 
 	// run reset first to ensure reallocation of hugepages
@@ -176,13 +178,13 @@ func (p *Provider) Prepare(req PrepareRequest) (*PrepareResponse, error) {
 }
 
 // Format attempts to initialize NVMe devices for use by DAOS (NB: no-op for non-NVMe devices).
-func (p *Provider) Format(req FormatRequest) (*FormatResponse, error) {
+func (p *Provider) Format(ctx context.Context, req FormatRequest) (*FormatResponse, error) {
 	if len(req.DeviceList) == 0 {
 		return nil, errors.New("empty DeviceList in FormatRequest")
 	}
 
 	if p.shouldForward(req) {
-		return p.fwd.Format(req)
+		return p.fwd.Format(ctx, req)
 	}
 
 	// TODO (DAOS-3844): Kick off device formats in goroutines? Serially formatting a large
@@ -23,6 +23,7 @@
 package bdev
 
 import (
+	"context"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -84,7 +85,7 @@ func TestBdevScan(t *testing.T) {
 
 			p := NewMockProvider(log, tc.mbc)
 
-			gotRes, gotErr := p.Scan(tc.req)
+			gotRes, gotErr := p.Scan(context.Background(), tc.req)
 			common.CmpErr(t, tc.expErr, gotErr)
 			if gotErr != nil {
 				return
@@ -138,7 +139,7 @@ func TestBdevPrepare(t *testing.T) {
 
 			p := NewMockProvider(log, tc.mbc)
 
-			gotRes, gotErr := p.Prepare(tc.req)
+			gotRes, gotErr := p.Prepare(context.Background(), tc.req)
 			common.CmpErr(t, tc.expErr, gotErr)
 			if gotErr != nil {
 				return
@@ -293,7 +294,7 @@ func TestBdevFormat(t *testing.T) {
 
 			p := NewMockProvider(log, tc.mbc)
 
-			gotRes, gotErr := p.Format(tc.req)
+			gotRes, gotErr := p.Format(context.Background(), tc.req)
 			common.CmpErr(t, tc.expErr, gotErr)
 			if gotErr != nil {
 				return
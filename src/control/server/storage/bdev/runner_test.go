@@ -23,6 +23,7 @@
 package bdev
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"testing"
@@ -111,7 +112,7 @@ func TestBdevRunnerPrepare(t *testing.T) {
 			b := newBackend(log, s)
 			p := NewProvider(log, b).WithForwardingDisabled()
 
-			_, gotErr := p.Prepare(tc.req)
+			_, gotErr := p.Prepare(context.Background(), tc.req)
 			common.CmpErr(t, tc.expErr, gotErr)
 			if gotErr != nil {
 				return
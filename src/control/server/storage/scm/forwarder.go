@@ -23,82 +23,97 @@
 package scm
 
 import (
+	"context"
+	"time"
+
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/pbin"
 )
 
+// defaultTimeouts are the times allowed for each forwarded request, which
+// can be overridden with pbin.DaosAdminTimeoutsEnvVar.
+var defaultTimeouts = map[string]time.Duration{
+	"ScmMount":       time.Minute,
+	"ScmUnmount":     time.Minute,
+	"ScmFormat":      10 * time.Minute,
+	"ScmCheckFormat": time.Minute,
+	"ScmScan":        2 * time.Minute,
+	"ScmPrepare":     10 * time.Minute,
+}
+
 type Forwarder struct {
 	pbin.Forwarder
 }
 
 func NewForwarder(log logging.Logger) *Forwarder {
-	pf := pbin.NewForwarder(log, pbin.DaosAdminName)
+	pf := pbin.NewForwarder(log, pbin.DaosAdminName).
+		WithDefaultTimeouts(defaultTimeouts)
 
 	return &Forwarder{
 		Forwarder: *pf,
 	}
 }
 
-func (f *Forwarder) Mount(req MountRequest) (*MountResponse, error) {
+func (f *Forwarder) Mount(ctx context.Context, req MountRequest) (*MountResponse, error) {
 	req.Forwarded = true
 
 	res := new(MountResponse)
-	if err := f.SendReq("ScmMount", req, res); err != nil {
+	if err := f.SendReq(ctx, "ScmMount", req, res); err != nil {
 		return nil, err
 	}
 
 	return res, nil
 }
 
-func (f *Forwarder) Unmount(req MountRequest) (*MountResponse, error) {
+func (f *Forwarder) Unmount(ctx context.Context, req MountRequest) (*MountResponse, error) {
 	req.Forwarded = true
 
 	res := new(MountResponse)
-	if err := f.SendReq("ScmUnmount", req, res); err != nil {
+	if err := f.SendReq(ctx, "ScmUnmount", req, res); err != nil {
 		return nil, err
 	}
 
 	return res, nil
 }
 
-func (f *Forwarder) Format(req FormatRequest) (*FormatResponse, error) {
+func (f *Forwarder) Format(ctx context.Context, req FormatRequest) (*FormatResponse, error) {
 	req.Forwarded = true
 
 	res := new(FormatResponse)
-	if err := f.SendReq("ScmFormat", req, res); err != nil {
+	if err := f.SendReq(ctx, "ScmFormat", req, res); err != nil {
 		return nil, err
 	}
 
 	return res, nil
 }
 
-func (f *Forwarder) CheckFormat(req FormatRequest) (*FormatResponse, error) {
+func (f *Forwarder) CheckFormat(ctx context.Context, req FormatRequest) (*FormatResponse, error) {
 	req.Forwarded = true
 
 	res := new(FormatResponse)
-	if err := f.SendReq("ScmCheckFormat", req, res); err != nil {
+	if err := f.SendReq(ctx, "ScmCheckFormat", req, res); err != nil {
 		return nil, err
 	}
 
 	return res, nil
 }
 
-func (f *Forwarder) Scan(req ScanRequest) (*ScanResponse, error) {
+func (f *Forwarder) Scan(ctx context.Context, req ScanRequest) (*ScanResponse, error) {
 	req.Forwarded = true
 
 	res := new(ScanResponse)
-	if err := f.SendReq("ScmScan", req, res); err != nil {
+	if err := f.SendReq(ctx, "ScmScan", req, res); err != nil {
 		return nil, err
 	}
 
 	return res, nil
 }
 
-func (f *Forwarder) Prepare(req PrepareRequest) (*PrepareResponse, error) {
+func (f *Forwarder) Prepare(ctx context.Context, req PrepareRequest) (*PrepareResponse, error) {
 	req.Forwarded = true
 
 	res := new(PrepareResponse)
-	if err := f.SendReq("ScmPrepare", req, res); err != nil {
+	if err := f.SendReq(ctx, "ScmPrepare", req, res); err != nil {
 		return nil, err
 	}
 
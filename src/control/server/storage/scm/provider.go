@@ -23,6 +23,7 @@
 package scm
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -367,9 +368,9 @@ func (p *Provider) updateState() (state storage.ScmState, err error) {
 }
 
 // GetState returns the current state of DCPM namespaces, if available.
-func (p *Provider) GetState() (storage.ScmState, error) {
+func (p *Provider) GetState(ctx context.Context) (storage.ScmState, error) {
 	if !p.isInitialized() {
-		if _, err := p.Scan(ScanRequest{}); err != nil {
+		if _, err := p.Scan(ctx, ScanRequest{}); err != nil {
 			return p.lastState, err
 		}
 	}
@@ -389,13 +390,13 @@ func (p *Provider) createScanResponse() *ScanResponse {
 }
 
 // Scan attempts to scan the system for SCM storage components.
-func (p *Provider) Scan(req ScanRequest) (*ScanResponse, error) {
+func (p *Provider) Scan(ctx context.Context, req ScanRequest) (*ScanResponse, error) {
 	if p.isInitialized() && !req.Rescan {
 		return p.createScanResponse(), nil
 	}
 
 	if p.shouldForward(req) {
-		res, err := p.fwd.Scan(req)
+		res, err := p.fwd.Scan(ctx, req)
 		if err != nil {
 			return nil, err
 		}
@@ -443,9 +444,9 @@ func (p *Provider) Scan(req ScanRequest) (*ScanResponse, error) {
 }
 
 // Prepare attempts to fulfill a SCM Prepare request.
-func (p *Provider) Prepare(req PrepareRequest) (res *PrepareResponse, err error) {
+func (p *Provider) Prepare(ctx context.Context, req PrepareRequest) (res *PrepareResponse, err error) {
 	if !p.isInitialized() {
-		if _, err := p.Scan(ScanRequest{}); err != nil {
+		if _, err := p.Scan(ctx, ScanRequest{}); err != nil {
 			return nil, err
 		}
 	}
@@ -459,7 +460,7 @@ func (p *Provider) Prepare(req PrepareRequest) (res *PrepareResponse, err error)
 	}
 
 	if p.shouldForward(req) {
-		return p.fwd.Prepare(req)
+		return p.fwd.Prepare(ctx, req)
 	}
 
 	if req.Reset {
@@ -512,9 +513,9 @@ func (p *Provider) Prepare(req PrepareRequest) (res *PrepareResponse, err error)
 // request is already formatted. If it is mounted, it is assumed to be formatted.
 // In the case of DCPM, the device is checked directly for the presence of a
 // filesystem.
-func (p *Provider) CheckFormat(req FormatRequest) (*FormatResponse, error) {
+func (p *Provider) CheckFormat(ctx context.Context, req FormatRequest) (*FormatResponse, error) {
 	if !p.isInitialized() {
-		if _, err := p.Scan(ScanRequest{}); err != nil {
+		if _, err := p.Scan(ctx, ScanRequest{}); err != nil {
 			return nil, err
 		}
 	}
@@ -524,7 +525,7 @@ func (p *Provider) CheckFormat(req FormatRequest) (*FormatResponse, error) {
 	}
 
 	if p.shouldForward(req) {
-		return p.fwd.CheckFormat(req)
+		return p.fwd.CheckFormat(ctx, req)
 	}
 
 	res := &FormatResponse{
@@ -590,8 +591,8 @@ func (p *Provider) clearMount(req FormatRequest) error {
 }
 
 // Format attempts to fulfill the specified SCM format request.
-func (p *Provider) Format(req FormatRequest) (*FormatResponse, error) {
-	check, err := p.CheckFormat(req)
+func (p *Provider) Format(ctx context.Context, req FormatRequest) (*FormatResponse, error) {
+	check, err := p.CheckFormat(ctx, req)
 	if err != nil {
 		return nil, err
 	}
@@ -602,7 +603,7 @@ func (p *Provider) Format(req FormatRequest) (*FormatResponse, error) {
 	}
 
 	if p.shouldForward(req) {
-		return p.fwd.Format(req)
+		return p.fwd.Format(ctx, req)
 	}
 
 	if err := p.clearMount(req); err != nil {
@@ -611,20 +612,20 @@ func (p *Provider) Format(req FormatRequest) (*FormatResponse, error) {
 
 	switch {
 	case req.Ramdisk != nil:
-		return p.formatRamdisk(req)
+		return p.formatRamdisk(ctx, req)
 	case req.Dcpm != nil:
-		return p.formatDcpm(req)
+		return p.formatDcpm(ctx, req)
 	default:
 		return nil, FaultFormatMissingParam
 	}
 }
 
-func (p *Provider) formatRamdisk(req FormatRequest) (*FormatResponse, error) {
+func (p *Provider) formatRamdisk(ctx context.Context, req FormatRequest) (*FormatResponse, error) {
 	if req.Ramdisk == nil {
 		return nil, FaultFormatMissingParam
 	}
 
-	res, err := p.MountRamdisk(req.Mountpoint, req.Ramdisk.Size)
+	res, err := p.MountRamdisk(ctx, req.Mountpoint, req.Ramdisk.Size)
 	if err != nil {
 		return nil, err
 	}
@@ -646,7 +647,7 @@ func (p *Provider) formatRamdisk(req FormatRequest) (*FormatResponse, error) {
 	}, nil
 }
 
-func (p *Provider) formatDcpm(req FormatRequest) (*FormatResponse, error) {
+func (p *Provider) formatDcpm(ctx context.Context, req FormatRequest) (*FormatResponse, error) {
 	if req.Dcpm == nil {
 		return nil, FaultFormatMissingParam
 	}
@@ -664,7 +665,7 @@ func (p *Provider) formatDcpm(req FormatRequest) (*FormatResponse, error) {
 		return nil, errors.Wrapf(err, "failed to format %s", req.Dcpm.Device)
 	}
 
-	res, err := p.MountDcpm(req.Dcpm.Device, req.Mountpoint)
+	res, err := p.MountDcpm(ctx, req.Dcpm.Device, req.Mountpoint)
 	if err != nil {
 		return nil, err
 	}
@@ -687,7 +688,7 @@ func (p *Provider) formatDcpm(req FormatRequest) (*FormatResponse, error) {
 }
 
 // MountDcpm attempts to mount a DCPM device at the specified mountpoint.
-func (p *Provider) MountDcpm(device, target string) (*MountResponse, error) {
+func (p *Provider) MountDcpm(ctx context.Context, device, target string) (*MountResponse, error) {
 	// make sure the source device is not already mounted somewhere else
 	devMounted, err := p.sys.IsMounted(device)
 	if err != nil {
@@ -704,12 +705,12 @@ func (p *Provider) MountDcpm(device, target string) (*MountResponse, error) {
 		Data:   dcpmMountOpts,
 	}
 
-	return p.Mount(req)
+	return p.Mount(ctx, req)
 }
 
 // MountRamdisk attempts to mount a tmpfs-based ramdisk of the specified size at
 // the specified mountpoint.
-func (p *Provider) MountRamdisk(target string, size uint) (*MountResponse, error) {
+func (p *Provider) MountRamdisk(ctx context.Context, target string, size uint) (*MountResponse, error) {
 	var opts string
 	if size > 0 {
 		opts = fmt.Sprintf("size=%dg", size)
@@ -722,13 +723,13 @@ func (p *Provider) MountRamdisk(target string, size uint) (*MountResponse, error
 		Data:   opts,
 	}
 
-	return p.Mount(req)
+	return p.Mount(ctx, req)
 }
 
 // Mount attempts to mount the target specified in the supplied request.
-func (p *Provider) Mount(req MountRequest) (*MountResponse, error) {
+func (p *Provider) Mount(ctx context.Context, req MountRequest) (*MountResponse, error) {
 	if p.shouldForward(req) {
-		return p.fwd.Mount(req)
+		return p.fwd.Mount(ctx, req)
 	}
 	return p.mount(req.Source, req.Target, req.FsType, req.Flags, req.Data)
 }
@@ -759,9 +760,9 @@ func (p *Provider) mount(src, target, fsType string, flags uintptr, opts string)
 }
 
 // Unmount attempts to unmount the target specified in the supplied request.
-func (p *Provider) Unmount(req MountRequest) (*MountResponse, error) {
+func (p *Provider) Unmount(ctx context.Context, req MountRequest) (*MountResponse, error) {
 	if p.shouldForward(req) {
-		return p.fwd.Unmount(req)
+		return p.fwd.Unmount(ctx, req)
 	}
 	return p.unmount(req.Target, int(req.Flags))
 }
@@ -23,6 +23,7 @@
 package scm
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -117,7 +118,7 @@ func TestProviderScan(t *testing.T) {
 				}
 			}
 
-			res, err := p.Scan(ScanRequest{})
+			res, err := p.Scan(context.Background(), ScanRequest{})
 			if err != nil {
 				switch err {
 				case FaultMissingNdctl:
@@ -133,7 +134,7 @@ func TestProviderScan(t *testing.T) {
 
 			// TODO: Try to simulate finding something new?
 			// For now, just make sure nothing breaks.
-			res, err = p.Scan(ScanRequest{Rescan: tc.rescan})
+			res, err = p.Scan(context.Background(), ScanRequest{Rescan: tc.rescan})
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -249,7 +250,7 @@ func TestProviderPrepare(t *testing.T) {
 				}
 			}
 
-			res, err := p.Prepare(PrepareRequest{Reset: tc.reset})
+			res, err := p.Prepare(context.Background(), PrepareRequest{Reset: tc.reset})
 			if err != nil {
 				switch err {
 				case FaultMissingNdctl:
@@ -305,7 +306,7 @@ func TestProviderGetState(t *testing.T) {
 				}
 			}
 
-			res, err := p.GetState()
+			res, err := p.GetState(context.Background())
 			if err != nil {
 				switch err {
 				case tc.discoverErr, tc.getNamespaceErr:
@@ -475,7 +476,7 @@ func TestProviderCheckFormat(t *testing.T) {
 					},
 				}
 			}
-			res, err := p.CheckFormat(*req)
+			res, err := p.CheckFormat(context.Background(), *req)
 			if err != nil {
 				switch errors.Cause(err) {
 				case tc.discoverErr, tc.getNamespaceErr, tc.isMountedErr, tc.getFsErr,
@@ -821,7 +822,7 @@ func TestProviderFormat(t *testing.T) {
 				tc.expResponse.Mountpoint = filepath.Join(testDir, tc.expResponse.Mountpoint)
 			}
 
-			res, err := p.Format(*req)
+			res, err := p.Format(context.Background(), *req)
 			if err != nil {
 				switch errors.Cause(err) {
 				case tc.discoverErr, tc.getNamespaceErr, tc.getFsErr,
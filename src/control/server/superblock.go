@@ -24,6 +24,7 @@
 package server
 
 import (
+	"context"
 	"io/ioutil"
 	"os"
 	"path"
@@ -43,6 +44,8 @@ const (
 	defaultStoragePath = "/mnt/daos"
 	defaultGroupName   = "daos_io_server"
 	superblockVersion  = 0
+	eventLogFile       = "system_events.json"
+	excludedRanksFile  = "system_excluded_ranks.json"
 )
 
 // Superblock is the per-Instance superblock
@@ -73,13 +76,23 @@ func (sb *Superblock) Unmarshal(raw []byte) error {
 	return yaml.Unmarshal(raw, sb)
 }
 
-func (srv *IOServerInstance) superblockPath() string {
+func (srv *IOServerInstance) storagePath() string {
 	scmConfig := srv.scmConfig()
 	storagePath := scmConfig.MountPoint
 	if storagePath == "" {
 		storagePath = defaultStoragePath
 	}
-	return filepath.Join(srv.fsRoot, storagePath, "superblock")
+	return filepath.Join(srv.fsRoot, storagePath)
+}
+
+func (srv *IOServerInstance) superblockPath() string {
+	return filepath.Join(srv.storagePath(), "superblock")
+}
+
+// msStatePath returns the path of a file used by an MS replica instance to
+// persist management service state alongside its superblock.
+func (srv *IOServerInstance) msStatePath(name string) string {
+	return filepath.Join(srv.storagePath(), name)
 }
 
 func (srv *IOServerInstance) setSuperblock(sb *Superblock) {
@@ -100,7 +113,7 @@ func (srv *IOServerInstance) hasSuperblock() bool {
 
 // NeedsSuperblock indicates whether or not the instance appears
 // to need a superblock to be created in order to start.
-func (srv *IOServerInstance) NeedsSuperblock() (bool, error) {
+func (srv *IOServerInstance) NeedsSuperblock(ctx context.Context) (bool, error) {
 	if srv.hasSuperblock() {
 		return false, nil
 	}
@@ -109,7 +122,7 @@ func (srv *IOServerInstance) NeedsSuperblock() (bool, error) {
 
 	srv.log.Debugf("%s: checking superblock", scmCfg.MountPoint)
 
-	err := srv.ReadSuperblock()
+	err := srv.ReadSuperblock(ctx)
 	if os.IsNotExist(errors.Cause(err)) {
 		srv.log.Debugf("%s: needs superblock (doesn't exist)", scmCfg.MountPoint)
 		return true, nil
@@ -123,8 +136,8 @@ func (srv *IOServerInstance) NeedsSuperblock() (bool, error) {
 }
 
 // CreateSuperblock creates the superblock for this instance.
-func (srv *IOServerInstance) CreateSuperblock(msInfo *mgmtInfo) error {
-	if err := srv.MountScmDevice(); err != nil {
+func (srv *IOServerInstance) CreateSuperblock(ctx context.Context, msInfo *mgmtInfo) error {
+	if err := srv.MountScmDevice(ctx); err != nil {
 		return err
 	}
 
@@ -186,8 +199,8 @@ func (srv *IOServerInstance) WriteSuperblock() error {
 
 // ReadSuperblock reads the instance's superblock
 // from storage.
-func (srv *IOServerInstance) ReadSuperblock() error {
-	needsFormat, err := srv.NeedsScmFormat()
+func (srv *IOServerInstance) ReadSuperblock(ctx context.Context) error {
+	needsFormat, err := srv.NeedsScmFormat(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to check storage formatting")
 	}
@@ -195,7 +208,7 @@ func (srv *IOServerInstance) ReadSuperblock() error {
 		return errors.New("can't read superblock from unformatted storage")
 	}
 
-	if err := srv.MountScmDevice(); err != nil {
+	if err := srv.MountScmDevice(ctx); err != nil {
 		return errors.Wrap(err, "failed to mount SCM device")
 	}
 
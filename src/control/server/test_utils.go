@@ -32,6 +32,7 @@ import (
 	"github.com/daos-stack/daos/src/control/drpc"
 	"github.com/daos-stack/daos/src/control/logging"
 	"github.com/daos-stack/daos/src/control/server/ioserver"
+	"github.com/daos-stack/daos/src/control/system"
 )
 
 // Utilities for internal server package tests
@@ -74,7 +75,7 @@ func (c *mockDrpcClient) Close() error {
 	return c.cfg.CloseError
 }
 
-func (c *mockDrpcClient) SendMsg(call *drpc.Call) (*drpc.Response, error) {
+func (c *mockDrpcClient) SendMsg(_ context.Context, call *drpc.Call) (*drpc.Response, error) {
 	c.SendMsgInputCall = call
 	return c.cfg.SendMsgResponse, c.cfg.SendMsgError
 }
@@ -120,5 +121,7 @@ func newTestMgmtSvc(log logging.Logger) *mgmtSvc {
 	harness := NewIOServerHarness(log)
 	harness.instances = append(harness.instances, srv)
 
-	return newMgmtSvc(harness, nil)
+	events := system.NewEventLog(log, system.DefaultEventLogSize)
+
+	return newMgmtSvc(harness, system.NewMembership(log, events), events)
 }
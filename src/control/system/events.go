@@ -0,0 +1,375 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+
+package system
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+const (
+	// DefaultEventLogSize is the default maximum number of events retained.
+	DefaultEventLogSize = 1024
+
+	// NoRank indicates that an event is not associated with a system member.
+	NoRank uint32 = math.MaxUint32
+)
+
+// EventType describes the kind of a system event.
+type EventType int
+
+const (
+	EventTypeUnknown EventType = iota
+	EventTypeMemberJoined
+	EventTypeStateChange
+	EventTypeBioError
+	EventTypeStorageFormat
+	EventTypeSystemStop
+)
+
+var eventTypeNames = [...]string{
+	"Unknown",
+	"MemberJoined",
+	"StateChange",
+	"BioError",
+	"StorageFormat",
+	"SystemStop",
+}
+
+func (et EventType) String() string {
+	if !et.IsValid() {
+		return eventTypeNames[EventTypeUnknown]
+	}
+	return eventTypeNames[et]
+}
+
+// IsValid returns true if the event type is one of the known types.
+func (et EventType) IsValid() bool {
+	return et > EventTypeUnknown && int(et) < len(eventTypeNames)
+}
+
+// Event describes an occurrence of note in the DAOS system, as recorded by the
+// management service.
+type Event struct {
+	Sequence uint64      `json:"seq"`
+	Time     time.Time   `json:"time"`
+	Type     EventType   `json:"type"`
+	Rank     uint32      `json:"rank"`
+	OldState MemberState `json:"old_state,omitempty"`
+	NewState MemberState `json:"new_state,omitempty"`
+	Reason   string      `json:"reason,omitempty"`
+	Identity string      `json:"identity,omitempty"`
+	// Origin is the original requester claimed by the server which
+	// forwarded the event, whose own identity is then in Identity.
+	Origin string `json:"origin,omitempty"`
+}
+
+// NewEvent returns a reference to a new event of given type. Use NoRank if
+// event is not associated with a specific rank.
+func NewEvent(evtType EventType, rank uint32, reason string) *Event {
+	return &Event{Type: evtType, Rank: rank, Reason: reason}
+}
+
+// HasRank returns true if event is associated with a specific rank.
+func (e *Event) HasRank() bool {
+	return e.Rank != NoRank
+}
+
+func (e *Event) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%s %s", e.Time.Format(time.RFC3339), e.Type)
+	if e.HasRank() {
+		fmt.Fprintf(&b, " rank %d", e.Rank)
+	}
+	if e.Type == EventTypeStateChange {
+		fmt.Fprintf(&b, " %s->%s", e.OldState, e.NewState)
+	}
+	if e.Reason != "" {
+		fmt.Fprintf(&b, ": %s", e.Reason)
+	}
+	switch {
+	case e.Origin != "":
+		fmt.Fprintf(&b, " (requested by %s via %s)", e.Origin, e.Identity)
+	case e.Identity != "":
+		fmt.Fprintf(&b, " (requested by %s)", e.Identity)
+	}
+
+	return b.String()
+}
+
+// EventFilter selects events to be retrieved from EventLog.
+type EventFilter struct {
+	Since time.Time // only events recorded after this time, all if zero
+	Ranks []uint32  // only events for these ranks, all if empty
+}
+
+// Matches returns true if the event is selected by the filter.
+func (ef *EventFilter) Matches(e *Event) bool {
+	if !ef.Since.IsZero() && !e.Time.After(ef.Since) {
+		return false
+	}
+	if len(ef.Ranks) == 0 {
+		return true
+	}
+	for _, rank := range ef.Ranks {
+		if e.Rank == rank {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EventLog is a bounded record of system events, optionally persisted to a
+// file so that history survives a restart of the management service.
+//
+// Events are appended to the file one JSON object per line as they are
+// added, the file is compacted to the retained events when it grows to
+// twice the size bound.
+type EventLog struct {
+	sync.RWMutex
+	log        logging.Logger
+	path       string
+	file       *os.File
+	fileEvents int
+	maxEvents  int
+	nextSeq    uint64
+	events     []*Event
+	subs       map[chan *Event]struct{}
+}
+
+// NewEventLog returns a reference to a new event log retaining at most
+// maxEvents events.
+func NewEventLog(log logging.Logger, maxEvents int) *EventLog {
+	return &EventLog{
+		log:       log,
+		maxEvents: maxEvents,
+		nextSeq:   1,
+		subs:      make(map[chan *Event]struct{}),
+	}
+}
+
+// trim discards the oldest events when the log exceeds its size bound.
+func (el *EventLog) trim() {
+	if excess := len(el.events) - el.maxEvents; excess > 0 {
+		el.events = append([]*Event(nil), el.events[excess:]...)
+	}
+}
+
+// compact replaces the backing file atomically with one containing only the
+// retained events and reopens it for appending.
+func (el *EventLog) compact() error {
+	tmpPath := el.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "create %s", tmpPath)
+	}
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, e := range el.events {
+		if err := enc.Encode(e); err != nil {
+			tmp.Close()
+			return errors.Wrap(err, "marshal system events")
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return errors.Wrapf(err, "write %s", tmpPath)
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrapf(err, "close %s", tmpPath)
+	}
+	if err := os.Rename(tmpPath, el.path); err != nil {
+		return errors.Wrapf(err, "rename %s", tmpPath)
+	}
+
+	if el.file != nil {
+		el.file.Close()
+	}
+	el.file, err = os.OpenFile(el.path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		el.file = nil
+		return errors.Wrapf(err, "open %s", el.path)
+	}
+	el.fileEvents = len(el.events)
+
+	return nil
+}
+
+// persist appends the event to the backing file, if set, compacting the
+// file instead once it holds twice as many events as are retained.
+func (el *EventLog) persist(e *Event) error {
+	if el.path == "" {
+		return nil
+	}
+	if el.file == nil || el.fileEvents >= 2*el.maxEvents {
+		return el.compact()
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return errors.Wrap(err, "marshal system event")
+	}
+	if _, err := el.file.Write(append(data, '\n')); err != nil {
+		return errors.Wrapf(err, "write %s", el.path)
+	}
+	el.fileEvents++
+
+	return nil
+}
+
+// readEvents returns the events persisted in the file at path, skipping a
+// final line left incomplete by an interrupted write.
+func readEvents(path string) ([]*Event, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []*Event
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		if line == "" {
+			continue
+		}
+		e := new(Event)
+		if err := json.Unmarshal([]byte(line), e); err != nil {
+			if i == len(lines)-1 && !strings.HasSuffix(string(data), "\n") {
+				break
+			}
+			return nil, errors.Wrapf(err, "unmarshal system event on line %d", i+1)
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// Load sets the file used to persist the log and reads any previously
+// persisted events from it. Events recorded before Load is called are kept
+// and ordered after the loaded events.
+func (el *EventLog) Load(path string) error {
+	el.Lock()
+	defer el.Unlock()
+
+	loaded, err := readEvents(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return errors.Wrapf(err, "read %s", path)
+	}
+
+	el.path = path
+	if len(loaded) > 0 {
+		seq := loaded[len(loaded)-1].Sequence
+		for _, e := range el.events {
+			seq++
+			e.Sequence = seq
+		}
+		el.events = append(loaded, el.events...)
+		el.nextSeq = seq + 1
+		el.trim()
+	}
+
+	return el.compact()
+}
+
+// Add records a new event in the log, setting its sequence number and time,
+// and notifies any subscribers.
+func (el *EventLog) Add(e *Event) {
+	el.Lock()
+	defer el.Unlock()
+
+	e.Sequence = el.nextSeq
+	el.nextSeq++
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	el.events = append(el.events, e)
+	el.trim()
+
+	if err := el.persist(e); err != nil {
+		el.log.Errorf("persisting system event log: %s", err)
+	}
+
+	for sub := range el.subs {
+		select {
+		case sub <- e:
+		default:
+			// end the subscription rather than leave a gap in it
+			el.log.Errorf("system event subscriber not ready, dropping event %d and ending subscription",
+				e.Sequence)
+			delete(el.subs, sub)
+			close(sub)
+		}
+	}
+}
+
+// Events returns the recorded events selected by the filter in the order
+// they were recorded.
+func (el *EventLog) Events(filter EventFilter) []*Event {
+	el.RLock()
+	defer el.RUnlock()
+
+	var events []*Event
+	for _, e := range el.events {
+		if filter.Matches(e) {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// Subscribe returns a channel on which events will be delivered as they are
+// added to the log and a function which must be called to unsubscribe. The
+// channel is closed if the subscriber falls too far behind to keep up.
+func (el *EventLog) Subscribe() (<-chan *Event, func()) {
+	el.Lock()
+	defer el.Unlock()
+
+	sub := make(chan *Event, el.maxEvents)
+	el.subs[sub] = struct{}{}
+
+	return sub, func() {
+		el.Lock()
+		defer el.Unlock()
+
+		delete(el.subs, sub)
+	}
+}
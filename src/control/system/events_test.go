@@ -0,0 +1,305 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+
+package system
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+func eventSummary(events []*Event) (summary []string) {
+	for _, e := range events {
+		summary = append(summary, e.Type.String()+":"+e.Reason)
+	}
+	return
+}
+
+func TestSystem_EventType_String(t *testing.T) {
+	for et, expStr := range map[EventType]string{
+		EventTypeUnknown:    "Unknown",
+		EventTypeBioError:   "BioError",
+		EventTypeSystemStop: "SystemStop",
+		EventType(-1):       "Unknown",
+		EventType(99):       "Unknown",
+	} {
+		common.AssertEqual(t, expStr, et.String(), "unexpected string")
+	}
+}
+
+func TestSystem_EventLog_Bounded(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	el := NewEventLog(log, 2)
+	for _, reason := range []string{"a", "b", "c"} {
+		el.Add(NewEvent(EventTypeBioError, 0, reason))
+	}
+
+	events := el.Events(EventFilter{})
+	if diff := cmp.Diff([]string{"BioError:b", "BioError:c"}, eventSummary(events)); diff != "" {
+		t.Fatalf("unexpected events (-want, +got):\n%s\n", diff)
+	}
+	common.AssertEqual(t, uint64(3), events[1].Sequence, "unexpected sequence")
+}
+
+func TestSystem_EventLog_Filter(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	start := time.Date(2019, 11, 20, 10, 0, 0, 0, time.UTC)
+	el := NewEventLog(log, DefaultEventLogSize)
+	for i, rank := range []uint32{0, 1, NoRank, 1} {
+		e := NewEvent(EventTypeBioError, rank, []string{"a", "b", "c", "d"}[i])
+		e.Time = start.Add(time.Duration(i) * time.Minute)
+		el.Add(e)
+	}
+
+	for name, tc := range map[string]struct {
+		filter    EventFilter
+		expEvents []string
+	}{
+		"all": {
+			expEvents: []string{"BioError:a", "BioError:b", "BioError:c", "BioError:d"},
+		},
+		"since": {
+			filter:    EventFilter{Since: start.Add(time.Minute)},
+			expEvents: []string{"BioError:c", "BioError:d"},
+		},
+		"ranks": {
+			filter:    EventFilter{Ranks: []uint32{1}},
+			expEvents: []string{"BioError:b", "BioError:d"},
+		},
+		"since and ranks": {
+			filter:    EventFilter{Since: start.Add(time.Minute), Ranks: []uint32{0, 1}},
+			expEvents: []string{"BioError:d"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got := eventSummary(el.Events(tc.filter))
+			if diff := cmp.Diff(tc.expEvents, got); diff != "" {
+				t.Fatalf("unexpected events (-want, +got):\n%s\n", diff)
+			}
+		})
+	}
+}
+
+func TestSystem_EventLog_Persist(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	testDir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+	path := filepath.Join(testDir, "events.json")
+
+	first := NewEventLog(log, DefaultEventLogSize)
+	if err := first.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	first.Add(NewEvent(EventTypeMemberJoined, 0, "a"))
+	first.Add(NewEvent(EventTypeMemberJoined, 1, "b"))
+
+	// events recorded before loading are ordered after persisted ones
+	second := NewEventLog(log, DefaultEventLogSize)
+	second.Add(NewEvent(EventTypeStorageFormat, NoRank, "c"))
+	if err := second.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	second.Add(NewEvent(EventTypeSystemStop, NoRank, "d"))
+
+	third := NewEventLog(log, DefaultEventLogSize)
+	if err := third.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	events := third.Events(EventFilter{})
+	expEvents := []string{
+		"MemberJoined:a", "MemberJoined:b", "StorageFormat:c", "SystemStop:d",
+	}
+	if diff := cmp.Diff(expEvents, eventSummary(events)); diff != "" {
+		t.Fatalf("unexpected events (-want, +got):\n%s\n", diff)
+	}
+	for i, e := range events {
+		common.AssertEqual(t, uint64(i+1), e.Sequence, "unexpected sequence")
+	}
+
+	// a write interrupted part way through the last event is ignored
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"seq":5,"ty`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	fourth := NewEventLog(log, DefaultEventLogSize)
+	if err := fourth.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expEvents, eventSummary(fourth.Events(EventFilter{}))); diff != "" {
+		t.Fatalf("unexpected events after torn write (-want, +got):\n%s\n", diff)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("garbage\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewEventLog(log, DefaultEventLogSize).Load(path); err == nil {
+		t.Fatal("expected error loading corrupt event log")
+	}
+}
+
+func TestSystem_EventLog_Compact(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	testDir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+	path := filepath.Join(testDir, "events.json")
+
+	el := NewEventLog(log, 2)
+	if err := el.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	for _, reason := range []string{"a", "b", "c", "d", "e", "f"} {
+		el.Add(NewEvent(EventTypeBioError, 0, reason))
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Count(string(data), "\n"); lines > 4 {
+			t.Fatalf("event log file not compacted, %d lines", lines)
+		}
+	}
+
+	loaded := NewEventLog(log, 2)
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"BioError:e", "BioError:f"}, eventSummary(loaded.Events(EventFilter{}))); diff != "" {
+		t.Fatalf("unexpected events (-want, +got):\n%s\n", diff)
+	}
+}
+
+func TestSystem_EventLog_Subscribe(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	el := NewEventLog(log, DefaultEventLogSize)
+	sub, unsubscribe := el.Subscribe()
+
+	el.Add(NewEvent(EventTypeBioError, 2, "a"))
+	select {
+	case e := <-sub:
+		common.AssertEqual(t, "a", e.Reason, "unexpected event")
+	default:
+		t.Fatal("expected event on subscription")
+	}
+
+	unsubscribe()
+	el.Add(NewEvent(EventTypeBioError, 2, "b"))
+	select {
+	case e := <-sub:
+		t.Fatalf("unexpected event after unsubscribe: %s", e)
+	default:
+	}
+}
+
+func TestSystem_EventLog_SubscriberFallsBehind(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	el := NewEventLog(log, 2)
+	sub, unsubscribe := el.Subscribe()
+	defer unsubscribe()
+
+	for _, reason := range []string{"a", "b", "c"} {
+		el.Add(NewEvent(EventTypeBioError, 2, reason))
+	}
+
+	var reasons []string
+	for e := range sub {
+		reasons = append(reasons, e.Reason)
+	}
+	if diff := cmp.Diff([]string{"a", "b"}, reasons); diff != "" {
+		t.Fatalf("unexpected events (-want, +got):\n%s\n", diff)
+	}
+
+	// adding more events after the subscription has ended must not panic
+	el.Add(NewEvent(EventTypeBioError, 2, "d"))
+}
+
+func TestSystem_Membership_Events(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	addr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:10001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	el := NewEventLog(log, DefaultEventLogSize)
+	ms := NewMembership(log, el)
+
+	ms.AddOrUpdate(NewMember(0, "", addr, MemberStateStarted), "joined")
+	if err := ms.SetMemberState(0, MemberStateStopping, "prep shutdown"); err != nil {
+		t.Fatal(err)
+	}
+	// no event expected if state doesn't change
+	if err := ms.SetMemberState(0, MemberStateStopping, "prep shutdown"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ms.CompareAndSetMemberState(0, MemberStateStarted,
+		MemberStateUnresponsive, "missed checks"); err != nil {
+		t.Fatal(err)
+	}
+	ms.AddOrUpdate(NewMember(0, "", addr, MemberStateStarted), "joined")
+
+	events := el.Events(EventFilter{})
+	expEvents := []string{
+		"MemberJoined:joined from 127.0.0.1:10001",
+		"StateChange:prep shutdown",
+		"StateChange:joined",
+	}
+	if diff := cmp.Diff(expEvents, eventSummary(events)); diff != "" {
+		t.Fatalf("unexpected events (-want, +got):\n%s\n", diff)
+	}
+	common.AssertEqual(t, MemberStateStopping, events[2].OldState, "unexpected old state")
+	common.AssertEqual(t, MemberStateStarted, events[2].NewState, "unexpected new state")
+}
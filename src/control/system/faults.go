@@ -38,6 +38,16 @@ var (
 		"system member with given rank doesn't exists",
 		"",
 	)
+	FaultMemberExcluded = systemFault(
+		code.SystemMemberExcluded,
+		"system member with given rank has been excluded from the system",
+		"reintegrate the rank with 'dmg system reintegrate' before it rejoins",
+	)
+	FaultMemberNotExcluded = systemFault(
+		code.SystemMemberNotExcluded,
+		"system member with given rank has not been excluded from the system",
+		"",
+	)
 )
 
 func systemFault(code code.Code, desc, res string) *fault.Fault {
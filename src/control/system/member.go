@@ -24,10 +24,14 @@
 package system
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -62,10 +66,18 @@ func (ms MemberState) String() string {
 // Member refers to a data-plane instance that is a member of this DAOS
 // system running on host with the control-plane listening at "Addr".
 type Member struct {
-	Rank  uint32
-	UUID  string
-	Addr  net.Addr
-	state MemberState
+	Rank              uint32
+	UUID              string
+	Addr              net.Addr
+	Hostname          string
+	InstanceIdx       uint32 // index of instance on host
+	FabricIface       string
+	FabricProvider    string
+	TargetCount       uint32
+	StartTime         time.Time // time member last joined the system
+	StateChangeTime   time.Time // time of last state change
+	StateChangeReason string    // reason for last state change
+	state             MemberState
 }
 
 func (sm *Member) String() string {
@@ -87,6 +99,25 @@ func NewMember(rank uint32, uuid string, addr net.Addr, state MemberState) *Memb
 	return &Member{Rank: rank, UUID: uuid, Addr: addr, state: state}
 }
 
+// updateDetails copies details provided on join from another member struct.
+func (sm *Member) updateDetails(other *Member) {
+	sm.UUID = other.UUID
+	sm.Addr = other.Addr
+	sm.Hostname = other.Hostname
+	sm.InstanceIdx = other.InstanceIdx
+	sm.FabricIface = other.FabricIface
+	sm.FabricProvider = other.FabricProvider
+	sm.TargetCount = other.TargetCount
+	sm.StartTime = other.StartTime
+}
+
+// copy returns a reference to a copy of the member so that its details can be
+// read without holding the membership lock.
+func (sm *Member) copy() *Member {
+	c := *sm
+	return &c
+}
+
 // Members is a type alias for a slice of member references
 type Members []*Member
 
@@ -120,7 +151,39 @@ func (smr MemberResults) HasErrors() bool {
 type Membership struct {
 	sync.RWMutex
 	log     logging.Logger
+	events  *EventLog
 	members map[uint32]*Member
+	// ranks excluded from the system by the administrator, persisted to
+	// excludedPath if set
+	excluded     map[uint32]struct{}
+	excludedPath string
+}
+
+// changeMemberState updates member state if it has changed, recording the
+// time and reason for the change in the member and the event log if set.
+//
+// Excluded members remain evicted until they are reintegrated.
+func (m *Membership) changeMemberState(member *Member, state MemberState, reason string) {
+	oldState := member.State()
+	if oldState == state {
+		return
+	}
+	if _, excluded := m.excluded[member.Rank]; excluded && state != MemberStateEvicted {
+		m.log.Debugf("rank %d excluded, ignoring state change to %s (%s)",
+			member.Rank, state, reason)
+		return
+	}
+
+	member.SetState(state)
+	member.StateChangeTime = time.Now()
+	member.StateChangeReason = reason
+
+	if m.events != nil {
+		event := NewEvent(EventTypeStateChange, member.Rank, reason)
+		event.OldState = oldState
+		event.NewState = state
+		m.events.Add(event)
+	}
 }
 
 // Add adds member to membership, returns member count.
@@ -137,40 +200,188 @@ func (m *Membership) Add(member *Member) (int, error) {
 	return len(m.members), nil
 }
 
-// SetMemberState updates existing member state in membership.
-func (m *Membership) SetMemberState(rank uint32, state MemberState) error {
+// SetMemberState updates existing member state in membership, recording the
+// reason for the change in the event log.
+func (m *Membership) SetMemberState(rank uint32, state MemberState, reason string) error {
 	m.Lock()
 	defer m.Unlock()
 
-	if _, found := m.members[rank]; !found {
+	member, found := m.members[rank]
+	if !found {
 		return errors.Wrapf(FaultMemberMissing, "rank %d", rank)
 	}
 
-	m.members[rank].SetState(state)
+	m.changeMemberState(member, state, reason)
 
 	return nil
 }
 
-// AddOrUpdate adds member to membership or updates member state if member
-// already exists in membership. Returns flag for whether member was created and
-// the previous state if updated.
-func (m *Membership) AddOrUpdate(member *Member) (bool, *MemberState) {
+// CompareAndSetMemberState updates existing member state in membership only if
+// the member is currently in the expected state. Returns true if the state was
+// changed, the reason for the change is recorded in the event log.
+func (m *Membership) CompareAndSetMemberState(rank uint32, expected, state MemberState, reason string) (bool, error) {
 	m.Lock()
 	defer m.Unlock()
 
+	member, found := m.members[rank]
+	if !found {
+		return false, errors.Wrapf(FaultMemberMissing, "rank %d", rank)
+	}
+
+	if member.State() != expected {
+		return false, nil
+	}
+	m.changeMemberState(member, state, reason)
+
+	return true, nil
+}
+
+// AddOrUpdate adds member to membership or updates member details and state if
+// member already exists in membership. Returns flag for whether member was
+// created and the previous state if updated. The join or state change is
+// recorded in the event log with the given reason.
+func (m *Membership) AddOrUpdate(member *Member, reason string) (bool, *MemberState) {
+	m.Lock()
+	defer m.Unlock()
+
+	member.StartTime = time.Now()
+
 	oldMember, found := m.members[member.Rank]
 	if found {
 		os := oldMember.State()
-		m.members[member.Rank].SetState(member.State())
+		oldMember.updateDetails(member)
+		m.changeMemberState(oldMember, member.State(), reason)
 
 		return false, &os
 	}
 
+	if _, excluded := m.excluded[member.Rank]; excluded {
+		member.SetState(MemberStateEvicted)
+	}
+	member.StateChangeTime = member.StartTime
+	member.StateChangeReason = reason
 	m.members[member.Rank] = member
+	if m.events != nil {
+		m.events.Add(NewEvent(EventTypeMemberJoined, member.Rank,
+			fmt.Sprintf("%s from %s", reason, member.Addr)))
+	}
 
 	return true, nil
 }
 
+// persistExcluded writes the set of excluded ranks to the backing file, if
+// set, replacing the file atomically.
+func (m *Membership) persistExcluded() error {
+	if m.excludedPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(m.excludedRanks())
+	if err != nil {
+		return errors.Wrap(err, "marshal excluded ranks")
+	}
+
+	tmpPath := m.excludedPath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return errors.Wrapf(err, "write %s", tmpPath)
+	}
+
+	return errors.Wrapf(os.Rename(tmpPath, m.excludedPath), "rename %s", tmpPath)
+}
+
+// LoadExcluded sets the file used to persist the set of excluded ranks and
+// reads any previously persisted ranks from it. Ranks excluded before
+// LoadExcluded is called are kept.
+func (m *Membership) LoadExcluded(path string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	var loaded []uint32
+	data, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return errors.Wrapf(err, "read %s", path)
+	default:
+		if err := json.Unmarshal(data, &loaded); err != nil {
+			return errors.Wrapf(err, "unmarshal excluded ranks from %s", path)
+		}
+	}
+
+	m.excludedPath = path
+	for _, rank := range loaded {
+		m.excluded[rank] = struct{}{}
+		if member, found := m.members[rank]; found {
+			m.changeMemberState(member, MemberStateEvicted, "excluded")
+		}
+	}
+
+	return m.persistExcluded()
+}
+
+// Exclude fences a member off from the system, marking it as evicted until
+// it is reintegrated. The exclusion is persisted and subsequent join
+// attempts by the rank should be refused. Ranks which are not yet members,
+// for example because the management service has just restarted, can be
+// excluded and are evicted when they join.
+func (m *Membership) Exclude(rank uint32, reason string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.excluded[rank] = struct{}{}
+	if member, found := m.members[rank]; found {
+		m.changeMemberState(member, MemberStateEvicted, reason)
+	}
+
+	return m.persistExcluded()
+}
+
+// Reintegrate removes a rank from the set of excluded ranks so that it may
+// rejoin the system. The state of the member is unknown until it rejoins or
+// answers a liveness check.
+func (m *Membership) Reintegrate(rank uint32, reason string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if _, excluded := m.excluded[rank]; !excluded {
+		return errors.Wrapf(FaultMemberNotExcluded, "rank %d", rank)
+	}
+
+	delete(m.excluded, rank)
+	if member, found := m.members[rank]; found {
+		m.changeMemberState(member, MemberStateUnknown, reason)
+	}
+
+	return m.persistExcluded()
+}
+
+// IsExcluded returns true if the rank has been excluded from the system.
+func (m *Membership) IsExcluded(rank uint32) bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	_, excluded := m.excluded[rank]
+	return excluded
+}
+
+func (m *Membership) excludedRanks() []uint32 {
+	ranks := make([]uint32, 0, len(m.excluded))
+	for rank := range m.excluded {
+		ranks = append(ranks, rank)
+	}
+	sort.Slice(ranks, func(i, j int) bool { return ranks[i] < ranks[j] })
+
+	return ranks
+}
+
+// ExcludedRanks returns slice of ordered ranks excluded from the system.
+func (m *Membership) ExcludedRanks() []uint32 {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.excludedRanks()
+}
+
 // Remove removes member from membership, idempotent.
 func (m *Membership) Remove(rank uint32) {
 	m.Lock()
@@ -179,7 +390,8 @@ func (m *Membership) Remove(rank uint32) {
 	delete(m.members, rank)
 }
 
-// Get retrieves member reference from membership based on Rank.
+// Get retrieves a copy of the member with the given rank from membership,
+// later changes to the member are not reflected in the copy.
 func (m *Membership) Get(rank uint32) (*Member, error) {
 	m.RLock()
 	defer m.RUnlock()
@@ -189,15 +401,25 @@ func (m *Membership) Get(rank uint32) (*Member, error) {
 		return nil, errors.Wrapf(FaultMemberMissing, "rank %d", rank)
 	}
 
-	return member, nil
+	return member.copy(), nil
 }
 
-// Ranks returns slice of ordered member ranks.
-func (m *Membership) Ranks() (ranks []uint32) {
+// GetByUUID returns a copy of the member with the given UUID.
+func (m *Membership) GetByUUID(uuid string) (*Member, error) {
 	m.RLock()
 	defer m.RUnlock()
 
-	for rank, _ := range m.members {
+	for _, member := range m.members {
+		if uuid != "" && member.UUID == uuid {
+			return member.copy(), nil
+		}
+	}
+
+	return nil, errors.Wrapf(FaultMemberMissing, "uuid %s", uuid)
+}
+
+func (m *Membership) ranks() (ranks []uint32) {
+	for rank := range m.members {
 		ranks = append(ranks, rank)
 	}
 
@@ -206,23 +428,34 @@ func (m *Membership) Ranks() (ranks []uint32) {
 	return
 }
 
-// Members returns slice of references to all system members.
+// Ranks returns slice of ordered member ranks.
+func (m *Membership) Ranks() []uint32 {
+	m.RLock()
+	defer m.RUnlock()
+
+	return m.ranks()
+}
+
+// Members returns slice of copies of all system members ordered by rank,
+// taken together so that they are consistent with each other.
 func (m *Membership) Members() (ms Members) {
 	m.RLock()
 	defer m.RUnlock()
 
-	for _, r := range m.Ranks() {
-		m, err := m.Get(r)
-		if err != nil {
-			panic(err) // should never happen
-		}
-		ms = append(ms, m)
+	for _, rank := range m.ranks() {
+		ms = append(ms, m.members[rank].copy())
 	}
 
 	return ms
 }
 
-// NewMembership returns a reference to a new DAOS system membership.
-func NewMembership(log logging.Logger) *Membership {
-	return &Membership{members: make(map[uint32]*Member), log: log}
+// NewMembership returns a reference to a new DAOS system membership. Member
+// joins and state changes are recorded in the supplied event log if not nil.
+func NewMembership(log logging.Logger, events *EventLog) *Membership {
+	return &Membership{
+		members:  make(map[uint32]*Member),
+		excluded: make(map[uint32]struct{}),
+		log:      log,
+		events:   events,
+	}
 }
@@ -0,0 +1,214 @@
+//
+// (C) Copyright 2019 Intel Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// GOVERNMENT LICENSE RIGHTS-OPEN SOURCE SOFTWARE
+// The Government's rights to use, modify, reproduce, release, perform, display,
+// or disclose this software are subject to the terms of the Apache License as
+// provided in Contract No. 8F-30005.
+// Any reproduction of computer software, computer software documentation, or
+// portions thereof marked with this legend must also reproduce the markings.
+//
+
+package system
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/daos-stack/daos/src/control/common"
+	"github.com/daos-stack/daos/src/control/logging"
+)
+
+func TestSystem_Membership_Exclude(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	testDir, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(testDir)
+	path := filepath.Join(testDir, "excluded.json")
+
+	addr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:10001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := NewMembership(log, NewEventLog(log, DefaultEventLogSize))
+	if err := ms.LoadExcluded(path); err != nil {
+		t.Fatal(err)
+	}
+	for _, rank := range []uint32{0, 1, 2} {
+		ms.AddOrUpdate(NewMember(rank, "", addr, MemberStateStarted), "joined")
+	}
+
+	common.CmpErr(t, errors.Wrap(FaultMemberNotExcluded, "rank 1"), ms.Reintegrate(1, "reintegrated"))
+
+	for _, rank := range []uint32{1, 2} {
+		if err := ms.Exclude(rank, "excluded"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if diff := cmp.Diff([]uint32{1, 2}, ms.ExcludedRanks()); diff != "" {
+		t.Fatalf("unexpected excluded ranks (-want, +got):\n%s\n", diff)
+	}
+
+	// ranks can be excluded before they join
+	if err := ms.Exclude(5, "excluded"); err != nil {
+		t.Fatal(err)
+	}
+	ms.AddOrUpdate(NewMember(5, "", addr, MemberStateStarted), "joined")
+	member, err := ms.Get(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, MemberStateEvicted, member.State(), "unexpected state")
+	if err := ms.Reintegrate(5, "reintegrated"); err != nil {
+		t.Fatal(err)
+	}
+
+	// excluded members stay evicted until reintegrated
+	if err := ms.SetMemberState(1, MemberStateStopped, "stop"); err != nil {
+		t.Fatal(err)
+	}
+	ms.AddOrUpdate(NewMember(1, "", addr, MemberStateStarted), "joined")
+	member, err = ms.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, MemberStateEvicted, member.State(), "unexpected state")
+
+	if err := ms.Reintegrate(2, "reintegrated"); err != nil {
+		t.Fatal(err)
+	}
+	member, err = ms.Get(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, MemberStateUnknown, member.State(), "unexpected state")
+	common.AssertEqual(t, "reintegrated", member.StateChangeReason, "unexpected reason")
+
+	// exclusions persist and apply to members joining after load
+	restarted := NewMembership(log, nil)
+	restarted.AddOrUpdate(NewMember(0, "", addr, MemberStateStarted), "joined")
+	if err := restarted.LoadExcluded(path); err != nil {
+		t.Fatal(err)
+	}
+	restarted.AddOrUpdate(NewMember(1, "", addr, MemberStateStarted), "joined")
+	if !restarted.IsExcluded(1) || restarted.IsExcluded(2) {
+		t.Fatalf("unexpected excluded ranks after load: %v", restarted.ExcludedRanks())
+	}
+	member, err = restarted.Get(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, MemberStateEvicted, member.State(), "unexpected state")
+
+	if err := ioutil.WriteFile(path, []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewMembership(log, nil).LoadExcluded(path); err == nil {
+		t.Fatal("expected error loading corrupt excluded ranks")
+	}
+}
+
+func TestSystem_Membership_MembersAreCopies(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	addr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:10001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := NewMembership(log, nil)
+	ms.AddOrUpdate(NewMember(0, "", addr, MemberStateStarted), "joined")
+
+	members := ms.Members()
+	member, err := ms.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := ms.SetMemberState(0, MemberStateUnresponsive, "no ping"); err != nil {
+			t.Error(err)
+		}
+	}()
+	// reading details concurrently with the update must be safe
+	_ = members[0].StateChangeReason
+	_ = member.State()
+	<-done
+
+	common.AssertEqual(t, MemberStateStarted, members[0].State(), "copy changed")
+	common.AssertEqual(t, MemberStateStarted, member.State(), "copy changed")
+
+	member, err = ms.Get(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	common.AssertEqual(t, MemberStateUnresponsive, member.State(), "state not changed")
+	common.AssertEqual(t, "no ping", member.StateChangeReason, "reason not changed")
+}
+
+func TestSystem_Membership_GetByUUID(t *testing.T) {
+	log, buf := logging.NewTestLogger(t.Name())
+	defer common.ShowBufferOnFailure(t, buf)
+
+	addr, err := net.ResolveTCPAddr("tcp", "127.0.0.1:10001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms := NewMembership(log, nil)
+	ms.AddOrUpdate(NewMember(0, "", addr, MemberStateStarted), "joined")
+	ms.AddOrUpdate(NewMember(1, "uuid-1", addr, MemberStateStarted), "joined")
+
+	for name, tc := range map[string]struct {
+		uuid    string
+		expRank uint32
+		expErr  error
+	}{
+		"found": {
+			uuid:    "uuid-1",
+			expRank: 1,
+		},
+		"unknown uuid": {
+			uuid:   "uuid-2",
+			expErr: errors.Wrap(FaultMemberMissing, "uuid uuid-2"),
+		},
+		"empty uuid": {
+			expErr: FaultMemberMissing,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			member, err := ms.GetByUUID(tc.uuid)
+			common.CmpErr(t, tc.expErr, err)
+			if tc.expErr != nil {
+				return
+			}
+			common.AssertEqual(t, tc.expRank, member.Rank, "unexpected rank")
+		})
+	}
+}
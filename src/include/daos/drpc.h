@@ -37,6 +37,33 @@
  */
 #define UNIXCOMM_MAXMSGSIZE 16384
 
+/*
+ * Messages larger than UNIXCOMM_MAXMSGSIZE are split into frames, each sent
+ * as one packet made up of a frame header followed by part of the message.
+ * The first byte of a frame is never the first byte of a valid protobuf
+ * message, so frames can be told apart from messages sent whole. The format
+ * is shared with the golang implementation in framing.go and must be changed
+ * in both places.
+ *
+ * Frame header layout (little endian):
+ *	0: DRPC_FRAME_MAGIC
+ *	1: DRPC_FRAME_VERSION
+ *	2: reserved (2 bytes)
+ *	4: message ID, unique among the sender's messages (4 bytes)
+ *	8: total message length (4 bytes)
+ *	12: offset of the frame's data in the message (4 bytes)
+ */
+#define DRPC_FRAME_MAGIC	0xDF
+#define DRPC_FRAME_VERSION	1
+#define DRPC_FRAME_HDR_SIZE	16
+#define DRPC_FRAME_DATA_SIZE	(UNIXCOMM_MAXMSGSIZE - DRPC_FRAME_HDR_SIZE)
+
+/*
+ * Largest message, split into frames or not, accepted from a peer. Matches
+ * the default limit of the golang implementation.
+ */
+#define DRPC_MAX_MSG_SIZE	(64 * 1024 * 1024)
+
 struct unixcomm {
 	int fd; /** File descriptor of the unix domain socket */
 	int flags; /** Flags set on unix domain socket */
@@ -51,6 +78,7 @@ typedef void (*drpc_handler_t)(Drpc__Call *, Drpc__Response *);
 struct drpc {
 	struct unixcomm	*comm; /** unix domain socket communication context */
 	int		sequence; /** sequence number of latest message sent */
+	uint32_t	last_msg_id; /** ID of latest framed message sent */
 	uint32_t	ref_count; /** open refs to this ctx */
 
 	/**
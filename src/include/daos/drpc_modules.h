@@ -73,6 +73,7 @@ enum drpc_mgmt_method {
 	DRPC_METHOD_MGMT_LIST_CONTAINERS	= 221,
 	DRPC_METHOD_MGMT_POOL_QUERY		= 222,
 	DRPC_METHOD_MGMT_POOL_SET_PROP		= 223,
+	DRPC_METHOD_MGMT_SET_LOG_MASKS		= 224,
 
 	NUM_DRPC_MGMT_METHODS			/* Must be last */
 };
@@ -81,6 +81,9 @@ extern int close_call_count; /* number of times it was called */
 extern int close_return; /* value to be returned by close() */
 extern int close_fd; /* saved input */
 
+/* Max number of packets saved by sendmsg() or returned by recvmsg() */
+#define MOCK_MAX_PACKETS 8
+
 void mock_sendmsg_setup(void);
 extern int sendmsg_call_count; /* how many times it was called */
 extern ssize_t sendmsg_return; /* to be returned by sendmsg() */
@@ -89,6 +92,8 @@ extern const struct msghdr *sendmsg_msg_ptr; /* saved address */
 extern void *sendmsg_msg_iov_base_ptr; /* saved ptr address */
 extern size_t sendmsg_msg_iov_len; /* saved iov len */
 extern uint8_t sendmsg_msg_content[UNIXCOMM_MAXMSGSIZE]; /* copied into iov */
+extern uint8_t sendmsg_packets[MOCK_MAX_PACKETS][UNIXCOMM_MAXMSGSIZE];
+extern size_t sendmsg_packet_lens[MOCK_MAX_PACKETS];
 extern int sendmsg_flags; /* saved input */
 
 void mock_recvmsg_setup(void);
@@ -99,6 +104,9 @@ extern struct msghdr *recvmsg_msg_ptr; /* saved ptr address */
 extern void *recvmsg_msg_iov_base_ptr; /* saved ptr address */
 extern size_t recvmsg_msg_iov_len; /* saved iov len */
 extern uint8_t recvmsg_msg_content[UNIXCOMM_MAXMSGSIZE]; /* copied into iov */
+extern uint8_t recvmsg_packets[MOCK_MAX_PACKETS][UNIXCOMM_MAXMSGSIZE];
+extern size_t recvmsg_packet_lens[MOCK_MAX_PACKETS];
+extern int recvmsg_packet_count; /* packets to return instead of content */
 extern int recvmsg_flags; /* saved input */
 
 void mock_poll_setup(void);
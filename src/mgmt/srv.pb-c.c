@@ -598,6 +598,51 @@ void   mgmt__create_ms_req__free_unpacked
   assert(message->base.descriptor == &mgmt__create_ms_req__descriptor);
   protobuf_c_message_free_unpacked ((ProtobufCMessage*)message, allocator);
 }
+void   mgmt__set_log_masks_req__init
+                     (Mgmt__SetLogMasksReq         *message)
+{
+  static const Mgmt__SetLogMasksReq init_value = MGMT__SET_LOG_MASKS_REQ__INIT;
+  *message = init_value;
+}
+size_t mgmt__set_log_masks_req__get_packed_size
+                     (const Mgmt__SetLogMasksReq *message)
+{
+  assert(message->base.descriptor == &mgmt__set_log_masks_req__descriptor);
+  return protobuf_c_message_get_packed_size ((const ProtobufCMessage*)(message));
+}
+size_t mgmt__set_log_masks_req__pack
+                     (const Mgmt__SetLogMasksReq *message,
+                      uint8_t       *out)
+{
+  assert(message->base.descriptor == &mgmt__set_log_masks_req__descriptor);
+  return protobuf_c_message_pack ((const ProtobufCMessage*)message, out);
+}
+size_t mgmt__set_log_masks_req__pack_to_buffer
+                     (const Mgmt__SetLogMasksReq *message,
+                      ProtobufCBuffer *buffer)
+{
+  assert(message->base.descriptor == &mgmt__set_log_masks_req__descriptor);
+  return protobuf_c_message_pack_to_buffer ((const ProtobufCMessage*)message, buffer);
+}
+Mgmt__SetLogMasksReq *
+       mgmt__set_log_masks_req__unpack
+                     (ProtobufCAllocator  *allocator,
+                      size_t               len,
+                      const uint8_t       *data)
+{
+  return (Mgmt__SetLogMasksReq *)
+     protobuf_c_message_unpack (&mgmt__set_log_masks_req__descriptor,
+                                allocator, len, data);
+}
+void   mgmt__set_log_masks_req__free_unpacked
+                     (Mgmt__SetLogMasksReq *message,
+                      ProtobufCAllocator *allocator)
+{
+  if(!message)
+    return;
+  assert(message->base.descriptor == &mgmt__set_log_masks_req__descriptor);
+  protobuf_c_message_free_unpacked ((ProtobufCMessage*)message, allocator);
+}
 static const ProtobufCFieldDescriptor mgmt__daos_resp__field_descriptors[1] =
 {
   {
@@ -1274,3 +1319,41 @@ const ProtobufCMessageDescriptor mgmt__create_ms_req__descriptor =
   (ProtobufCMessageInit) mgmt__create_ms_req__init,
   NULL,NULL,NULL    /* reserved[123] */
 };
+static const ProtobufCFieldDescriptor mgmt__set_log_masks_req__field_descriptors[1] =
+{
+  {
+    "masks",
+    1,
+    PROTOBUF_C_LABEL_NONE,
+    PROTOBUF_C_TYPE_STRING,
+    0,   /* quantifier_offset */
+    offsetof(Mgmt__SetLogMasksReq, masks),
+    NULL,
+    &protobuf_c_empty_string,
+    0,             /* flags */
+    0,NULL,NULL    /* reserved1,reserved2, etc */
+  },
+};
+static const unsigned mgmt__set_log_masks_req__field_indices_by_name[] = {
+  0,   /* field[0] = masks */
+};
+static const ProtobufCIntRange mgmt__set_log_masks_req__number_ranges[1 + 1] =
+{
+  { 1, 0 },
+  { 0, 1 }
+};
+const ProtobufCMessageDescriptor mgmt__set_log_masks_req__descriptor =
+{
+  PROTOBUF_C__MESSAGE_DESCRIPTOR_MAGIC,
+  "mgmt.SetLogMasksReq",
+  "SetLogMasksReq",
+  "Mgmt__SetLogMasksReq",
+  "mgmt",
+  sizeof(Mgmt__SetLogMasksReq),
+  1,
+  mgmt__set_log_masks_req__field_descriptors,
+  mgmt__set_log_masks_req__field_indices_by_name,
+  1,  mgmt__set_log_masks_req__number_ranges,
+  (ProtobufCMessageInit) mgmt__set_log_masks_req__init,
+  NULL,NULL,NULL    /* reserved[123] */
+};
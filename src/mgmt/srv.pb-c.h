@@ -29,6 +29,7 @@ typedef struct _Mgmt__RestartRanksReq Mgmt__RestartRanksReq;
 typedef struct _Mgmt__RestartRanksResp Mgmt__RestartRanksResp;
 typedef struct _Mgmt__SetRankReq Mgmt__SetRankReq;
 typedef struct _Mgmt__CreateMsReq Mgmt__CreateMsReq;
+typedef struct _Mgmt__SetLogMasksReq Mgmt__SetLogMasksReq;
 
 
 /* --- enums --- */
@@ -262,6 +263,19 @@ struct  _Mgmt__CreateMsReq
     , 0, (char *)protobuf_c_empty_string, (char *)protobuf_c_empty_string }
 
 
+struct  _Mgmt__SetLogMasksReq
+{
+  ProtobufCMessage base;
+  /*
+   * New log mask, D_LOG_MASK syntax.
+   */
+  char *masks;
+};
+#define MGMT__SET_LOG_MASKS_REQ__INIT \
+ { PROTOBUF_C_MESSAGE_INIT (&mgmt__set_log_masks_req__descriptor) \
+    , (char *)protobuf_c_empty_string }
+
+
 /* Mgmt__DaosResp methods */
 void   mgmt__daos_resp__init
                      (Mgmt__DaosResp         *message);
@@ -512,6 +526,25 @@ Mgmt__CreateMsReq *
 void   mgmt__create_ms_req__free_unpacked
                      (Mgmt__CreateMsReq *message,
                       ProtobufCAllocator *allocator);
+/* Mgmt__SetLogMasksReq methods */
+void   mgmt__set_log_masks_req__init
+                     (Mgmt__SetLogMasksReq         *message);
+size_t mgmt__set_log_masks_req__get_packed_size
+                     (const Mgmt__SetLogMasksReq   *message);
+size_t mgmt__set_log_masks_req__pack
+                     (const Mgmt__SetLogMasksReq   *message,
+                      uint8_t             *out);
+size_t mgmt__set_log_masks_req__pack_to_buffer
+                     (const Mgmt__SetLogMasksReq   *message,
+                      ProtobufCBuffer     *buffer);
+Mgmt__SetLogMasksReq *
+       mgmt__set_log_masks_req__unpack
+                     (ProtobufCAllocator  *allocator,
+                      size_t               len,
+                      const uint8_t       *data);
+void   mgmt__set_log_masks_req__free_unpacked
+                     (Mgmt__SetLogMasksReq *message,
+                      ProtobufCAllocator *allocator);
 /* --- per-message closures --- */
 
 typedef void (*Mgmt__DaosResp_Closure)
@@ -556,6 +589,9 @@ typedef void (*Mgmt__SetRankReq_Closure)
 typedef void (*Mgmt__CreateMsReq_Closure)
                  (const Mgmt__CreateMsReq *message,
                   void *closure_data);
+typedef void (*Mgmt__SetLogMasksReq_Closure)
+                 (const Mgmt__SetLogMasksReq *message,
+                  void *closure_data);
 
 /* --- services --- */
 
@@ -577,6 +613,7 @@ extern const ProtobufCMessageDescriptor mgmt__restart_ranks_req__descriptor;
 extern const ProtobufCMessageDescriptor mgmt__restart_ranks_resp__descriptor;
 extern const ProtobufCMessageDescriptor mgmt__set_rank_req__descriptor;
 extern const ProtobufCMessageDescriptor mgmt__create_ms_req__descriptor;
+extern const ProtobufCMessageDescriptor mgmt__set_log_masks_req__descriptor;
 
 PROTOBUF_C__END_DECLS
 
@@ -148,6 +148,34 @@ ds_mgmt_drpc_set_rank(Drpc__Call *drpc_req, Drpc__Response *drpc_resp)
 	mgmt__set_rank_req__free_unpacked(req, NULL);
 }
 
+void
+ds_mgmt_drpc_set_log_masks(Drpc__Call *drpc_req, Drpc__Response *drpc_resp)
+{
+	Mgmt__SetLogMasksReq	*req = NULL;
+	Mgmt__DaosResp		 resp = MGMT__DAOS_RESP__INIT;
+	int			 rc = 0;
+
+	/* Unpack the inner request from the drpc call body */
+	req = mgmt__set_log_masks_req__unpack(
+		NULL, drpc_req->body.len, drpc_req->body.data);
+	if (req == NULL) {
+		drpc_resp->status = DRPC__STATUS__FAILED_UNMARSHAL_PAYLOAD;
+		D_ERROR("Failed to unpack req (set log masks)\n");
+		return;
+	}
+
+	D_INFO("Received request to set log masks to '%s'\n", req->masks);
+
+	if (d_log_setmasks(req->masks, -1) < 0) {
+		D_ERROR("Failed to set log masks '%s'\n", req->masks);
+		rc = -DER_INVAL;
+	}
+
+	resp.status = rc;
+	pack_daos_response(&resp, drpc_resp);
+	mgmt__set_log_masks_req__free_unpacked(req, NULL);
+}
+
 /*
  * TODO: Make sure the MS doesn't accept any requests until StartMS completes.
  * See also process_startms_request.
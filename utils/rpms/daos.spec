@@ -244,6 +244,7 @@ getent group daos_admins >/dev/null || groupadd -r daos_admins
 # set daos_server to be setgid daos_admins in order to invoke daos_admin
 %attr(2755,root,daos_admins) %{_bindir}/daos_server
 %{_bindir}/daos_io_server
+%{_bindir}/drpc_cli
 %dir %{_libdir}/daos_srv
 %{_libdir}/daos_srv/libcont.so
 %{_libdir}/daos_srv/libdtx.so